@@ -0,0 +1,207 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/journal"
+	"github.com/dogmatiq/persistencekit/kv"
+	"github.com/dogmatiq/persistencekit/set"
+)
+
+// Option is an option that selects the stores to back up or restore.
+type Option func(*options)
+
+// Journals selects the journals with the given names.
+func Journals(names ...string) Option {
+	return func(o *options) {
+		o.add(Journal, names)
+	}
+}
+
+// Keyspaces selects the keyspaces with the given names.
+func Keyspaces(names ...string) Option {
+	return func(o *options) {
+		o.add(Keyspace, names)
+	}
+}
+
+// Sets selects the sets with the given names.
+func Sets(names ...string) Option {
+	return func(o *options) {
+		o.add(Set, names)
+	}
+}
+
+type options struct {
+	Selected []Entry
+}
+
+func resolveOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func (o *options) add(k Kind, names []string) {
+	for _, n := range names {
+		if !o.includes(k, n) {
+			o.Selected = append(o.Selected, Entry{Kind: k, Name: n})
+		}
+	}
+}
+
+func (o *options) includes(k Kind, name string) bool {
+	return slices.ContainsFunc(
+		o.Selected,
+		func(e Entry) bool {
+			return e.Kind == k && e.Name == name
+		},
+	)
+}
+
+// Backup writes an archive containing the journals, keyspaces and sets of d
+// that are selected by opts to w.
+//
+// Drivers do not provide a way to enumerate their stores, so the stores to back
+// up must be selected explicitly using [Journals], [Keyspaces] and [Sets].
+//
+// The bounds of each journal are read before any data is written, and only
+// the records within those bounds are included in the archive. Keyspaces and
+// sets are read in turn as the archive is written, so changes made to them
+// during the backup may or may not be included.
+func Backup(
+	ctx context.Context,
+	d driver.Driver,
+	w io.Writer,
+	opts ...Option,
+) error {
+	o := resolveOptions(opts)
+
+	m := Manifest{
+		CreatedAt: time.Now(),
+	}
+
+	journals := map[string]journal.BinaryJournal{}
+
+	for _, e := range o.Selected {
+		if e.Kind == Journal {
+			j, err := d.JournalStore().Open(ctx, e.Name)
+			if err != nil {
+				return fmt.Errorf("unable to back up journal %q: %w", e.Name, err)
+			}
+			defer j.Close()
+
+			e.Bounds, err = j.Bounds(ctx)
+			if err != nil {
+				return fmt.Errorf("unable to back up journal %q: %w", e.Name, err)
+			}
+
+			journals[e.Name] = j
+		}
+
+		m.Stores = append(m.Stores, e)
+	}
+
+	aw, err := newWriter(w)
+	if err != nil {
+		return err
+	}
+
+	if err := aw.frame(manifestFrame, marshalManifest(m)); err != nil {
+		return err
+	}
+
+	for _, e := range m.Stores {
+		if err := aw.beginSection(e.Kind, e.Name); err != nil {
+			return err
+		}
+
+		switch e.Kind {
+		case Journal:
+			err = backupJournal(ctx, journals[e.Name], e.Bounds, aw)
+		case Keyspace:
+			err = backupKeyspace(ctx, d.KVStore(), e.Name, aw)
+		case Set:
+			err = backupSet(ctx, d.SetStore(), e.Name, aw)
+		}
+
+		if err != nil {
+			return fmt.Errorf("unable to back up %s %q: %w", e.Kind, e.Name, err)
+		}
+
+		if err := aw.endSection(); err != nil {
+			return err
+		}
+	}
+
+	return aw.close(len(m.Stores))
+}
+
+func backupJournal(
+	ctx context.Context,
+	j journal.BinaryJournal,
+	bounds journal.Interval,
+	w *writer,
+) error {
+	if bounds.IsEmpty() {
+		return nil
+	}
+
+	return j.Range(
+		ctx,
+		bounds.Begin,
+		func(_ context.Context, pos journal.Position, rec []byte) (bool, error) {
+			if err := w.data(recordFrame, marshalRecord(pos, rec)); err != nil {
+				return false, err
+			}
+			return pos+1 < bounds.End, nil
+		},
+	)
+}
+
+func backupKeyspace(
+	ctx context.Context,
+	s kv.BinaryStore,
+	name string,
+	w *writer,
+) error {
+	ks, err := s.Open(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer ks.Close()
+
+	return ks.Range(
+		ctx,
+		func(_ context.Context, k, v []byte, _ kv.Revision) (bool, error) {
+			return true, w.data(pairFrame, marshalPair(k, v))
+		},
+	)
+}
+
+func backupSet(
+	ctx context.Context,
+	s set.BinaryStore,
+	name string,
+	w *writer,
+) error {
+	x, err := s.Open(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer x.Close()
+
+	return x.Range(
+		ctx,
+		func(_ context.Context, v []byte) (bool, error) {
+			return true, w.data(memberFrame, v)
+		},
+	)
+}
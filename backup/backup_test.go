@@ -0,0 +1,186 @@
+package backup_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/dogmatiq/persistencekit/backup"
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/driver/memory"
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+	"github.com/dogmatiq/persistencekit/journal"
+)
+
+func TestBackup(t *testing.T) {
+	setup := func(t *testing.T) (source, target driver.Driver, archive []byte) {
+		source = memory.New(&memory.Config{Silo: xtesting.UniqueName("backup-source")})
+		target = memory.New(&memory.Config{Silo: xtesting.UniqueName("backup-target")})
+		t.Cleanup(func() {
+			source.Close()
+			target.Close()
+		})
+
+		ctx := t.Context()
+
+		j, err := source.JournalStore().Open(ctx, "<journal>")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer j.Close()
+
+		for pos := range journal.Position(3) {
+			if err := j.Append(ctx, pos, []byte("<record>")); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		ks, err := source.KVStore().Open(ctx, "<keyspace>")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ks.Close()
+
+		if err := ks.SetUnconditional(ctx, []byte("<key>"), []byte("<value>")); err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := Backup(
+			ctx,
+			source,
+			&buf,
+			Journals("<journal>"),
+			Keyspaces("<keyspace>"),
+			Sets("<set>"),
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		return source, target, buf.Bytes()
+	}
+
+	t.Run("it writes a manifest describing each store", func(t *testing.T) {
+		_, _, archive := setup(t)
+
+		m, err := ReadManifest(bytes.NewReader(archive))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if m.CreatedAt.IsZero() {
+			t.Fatal("expected the creation time to be recorded")
+		}
+
+		want := []Entry{
+			{Kind: Journal, Name: "<journal>", Bounds: journal.Interval{Begin: 0, End: 3}},
+			{Kind: Keyspace, Name: "<keyspace>"},
+			{Kind: Set, Name: "<set>"},
+		}
+
+		if len(m.Stores) != len(want) {
+			t.Fatalf("unexpected number of stores: got %d, want %d", len(m.Stores), len(want))
+		}
+
+		for i, e := range want {
+			if m.Stores[i] != e {
+				t.Fatalf("unexpected entry at index %d: got %+v, want %+v", i, m.Stores[i], e)
+			}
+		}
+	})
+
+	t.Run("it returns an error if the archive is corrupt", func(t *testing.T) {
+		cases := []struct {
+			Desc    string
+			Corrupt func([]byte) []byte
+			Want    string
+		}{
+			{
+				"not an archive",
+				func([]byte) []byte { return []byte("<not an archive>") },
+				"not a backup archive",
+			},
+			{
+				"unsupported version",
+				func(data []byte) []byte { data[8] = 99; return data },
+				"unsupported archive format version (99)",
+			},
+			{
+				"modified payload",
+				func(data []byte) []byte { data[len(data)/2] ^= 0xff; return data },
+				"frame checksum mismatch",
+			},
+			{
+				"truncated",
+				func(data []byte) []byte { return data[:len(data)-5] },
+				"unexpected EOF",
+			},
+			{
+				"corrupt frame length",
+				func(data []byte) []byte {
+					// Replace the length of the manifest frame with the
+					// largest accepted length.
+					_, n := binary.Uvarint(data[10:])
+					corrupt := binary.AppendUvarint(slices.Clone(data[:10]), 8<<30)
+					return append(corrupt, data[10+n:]...)
+				},
+				"unexpected EOF",
+			},
+			{
+				"missing trailer",
+				func(data []byte) []byte { return data[:len(data)-7] },
+				"archive is truncated",
+			},
+			{
+				"trailing data",
+				func(data []byte) []byte { return append(data, 0) },
+				"unexpected data after the archive trailer",
+			},
+		}
+
+		for _, c := range cases {
+			t.Run(c.Desc, func(t *testing.T) {
+				_, target, archive := setup(t)
+
+				err := Restore(t.Context(), bytes.NewReader(c.Corrupt(archive)), target)
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				if !strings.Contains(err.Error(), c.Want) {
+					t.Fatalf("unexpected error: got %q, want it to contain %q", err, c.Want)
+				}
+			})
+		}
+	})
+
+	t.Run("it returns an error if a selected store is not in the archive", func(t *testing.T) {
+		_, target, archive := setup(t)
+
+		err := Restore(t.Context(), bytes.NewReader(archive), target, Sets("<unknown>"))
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+
+		want := `archive does not contain set "<unknown>"`
+		if err.Error() != want {
+			t.Fatalf("unexpected error: got %q, want %q", err, want)
+		}
+	})
+
+	t.Run("it returns an error if a store is not empty", func(t *testing.T) {
+		source, _, archive := setup(t)
+
+		err := Restore(t.Context(), bytes.NewReader(archive), source, Keyspaces("<keyspace>"))
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+
+		want := `unable to restore keyspace "<keyspace>": keyspace must be empty`
+		if err.Error() != want {
+			t.Fatalf("unexpected error: got %q, want %q", err, want)
+		}
+	})
+}
@@ -0,0 +1,31 @@
+// Package backup writes journals, keyspaces and sets to a portable archive
+// that can be restored to any persistence driver.
+//
+// # Archive format
+//
+// An archive begins with the 8-byte magic string "PKBACKUP" followed by a
+// single byte containing the format version, currently 1. The remainder of the
+// archive is a sequence of frames. Each frame consists of:
+//
+//   - a single byte identifying the type of the frame
+//   - the length of the payload, encoded as an unsigned varint
+//   - the payload
+//   - the CRC-32C checksum of the preceding fields, as a big-endian uint32
+//
+// Within payloads, variable-length fields are prefixed with their length,
+// encoded as an unsigned varint. The final field of a payload is not
+// length-prefixed.
+//
+// The first frame is the manifest, which records the time at which the backup
+// was started and, for each store, its kind, name and, for journals, bounds.
+// Each store listed in the manifest is followed by a section, in the same
+// order. A section begins with a store frame identifying the store, followed by
+// one frame per journal record, key/value pair or set member, and ends with a
+// frame containing the number of data frames in the section and the SHA-256
+// digest of those frames. The final frame is a trailer containing the number
+// of sections, which distinguishes a complete archive from a truncated one.
+//
+// Journal records are stored with their positions, which are preserved when
+// the archive is restored. Key/value revisions are specific to each driver and
+// are not stored.
+package backup
@@ -0,0 +1,504 @@
+package backup
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/journal"
+)
+
+// magic is the byte sequence at the beginning of every archive.
+const magic = "PKBACKUP"
+
+// formatVersion is the version of the archive format written by [Backup].
+const formatVersion = 1
+
+// maxFrameSize is the largest frame payload that is written to or accepted from
+// an archive.
+const maxFrameSize = 8 << 30
+
+// frameChunkSize is the number of bytes of a frame payload that are read at a
+// time. Reading in chunks means that the memory allocated for a frame is
+// bounded by the amount of data actually present in the archive, rather than
+// by a (potentially corrupt) frame length.
+const frameChunkSize = 1 << 20
+
+// frameType identifies the type of a frame within an archive.
+type frameType byte
+
+const (
+	manifestFrame frameType = iota + 1
+	storeFrame
+	recordFrame
+	pairFrame
+	memberFrame
+	endFrame
+	trailerFrame
+)
+
+// crcTable is the CRC-32C table used to checksum each frame.
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Kind identifies the type of a store within an archive.
+type Kind byte
+
+// The kinds of store that are stored in an archive.
+const (
+	Journal Kind = iota + 1
+	Keyspace
+	Set
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Journal:
+		return "journal"
+	case Keyspace:
+		return "keyspace"
+	case Set:
+		return "set"
+	default:
+		return fmt.Sprintf("Kind(%d)", byte(k))
+	}
+}
+
+// dataFrame returns the type of frame used to store the contents of a store
+// of kind k.
+func (k Kind) dataFrame() frameType {
+	switch k {
+	case Journal:
+		return recordFrame
+	case Keyspace:
+		return pairFrame
+	default:
+		return memberFrame
+	}
+}
+
+// Manifest describes the contents of an archive.
+type Manifest struct {
+	// CreatedAt is the time at which the backup was started.
+	CreatedAt time.Time
+
+	// Stores describes each store in the archive, in the order they appear.
+	Stores []Entry
+}
+
+// Entry describes a single store within an archive.
+type Entry struct {
+	// Kind is the kind of the store.
+	Kind Kind
+
+	// Name is the name of the store.
+	Name string
+
+	// Bounds is the bounds of the journal at the time of the backup. It is
+	// empty for keyspaces and sets.
+	Bounds journal.Interval
+}
+
+// ReadManifest reads the manifest from the beginning of an archive, without
+// reading the remainder of the archive.
+func ReadManifest(r io.Reader) (Manifest, error) {
+	ar, err := newReader(r)
+	if err != nil {
+		return Manifest{}, err
+	}
+	return ar.manifest()
+}
+
+func marshalManifest(m Manifest) []byte {
+	data := binary.AppendVarint(nil, m.CreatedAt.UnixNano())
+	data = binary.AppendUvarint(data, uint64(len(m.Stores)))
+
+	for _, e := range m.Stores {
+		data = append(data, byte(e.Kind))
+		data = appendField(data, []byte(e.Name))
+		data = binary.AppendUvarint(data, uint64(e.Bounds.Begin))
+		data = binary.AppendUvarint(data, uint64(e.Bounds.End))
+	}
+
+	return data
+}
+
+func unmarshalManifest(data []byte) (Manifest, error) {
+	p := parser{data: data}
+
+	m := Manifest{
+		CreatedAt: time.Unix(0, p.varint()),
+	}
+
+	n := p.uvarint()
+	for range n {
+		if p.err != nil {
+			break
+		}
+
+		m.Stores = append(
+			m.Stores,
+			Entry{
+				Kind: Kind(p.byte()),
+				Name: string(p.field()),
+				Bounds: journal.Interval{
+					Begin: journal.Position(p.uvarint()),
+					End:   journal.Position(p.uvarint()),
+				},
+			},
+		)
+	}
+
+	if err := p.done(); err != nil {
+		return Manifest{}, fmt.Errorf("malformed manifest: %w", err)
+	}
+
+	return m, nil
+}
+
+func marshalStore(k Kind, name string) []byte {
+	return append([]byte{byte(k)}, name...)
+}
+
+func unmarshalStore(data []byte) (Kind, string, error) {
+	if len(data) == 0 {
+		return 0, "", errors.New("malformed store frame: payload is empty")
+	}
+	return Kind(data[0]), string(data[1:]), nil
+}
+
+func marshalRecord(pos journal.Position, rec []byte) []byte {
+	data := binary.AppendUvarint(nil, uint64(pos))
+	return append(data, rec...)
+}
+
+func unmarshalRecord(data []byte) (journal.Position, []byte, error) {
+	p := parser{data: data}
+	pos := journal.Position(p.uvarint())
+	rec := p.rest()
+	if err := p.done(); err != nil {
+		return 0, nil, fmt.Errorf("malformed record frame: %w", err)
+	}
+	return pos, rec, nil
+}
+
+func marshalPair(k, v []byte) []byte {
+	data := appendField(nil, k)
+	return append(data, v...)
+}
+
+func unmarshalPair(data []byte) ([]byte, []byte, error) {
+	p := parser{data: data}
+	k := p.field()
+	v := p.rest()
+	if err := p.done(); err != nil {
+		return nil, nil, fmt.Errorf("malformed key/value frame: %w", err)
+	}
+	return k, v, nil
+}
+
+func marshalEnd(count uint64, digest []byte) []byte {
+	data := binary.AppendUvarint(nil, count)
+	return append(data, digest...)
+}
+
+func unmarshalEnd(data []byte) (uint64, []byte, error) {
+	p := parser{data: data}
+	count := p.uvarint()
+	digest := p.rest()
+	if err := p.done(); err != nil {
+		return 0, nil, fmt.Errorf("malformed end frame: %w", err)
+	}
+	if len(digest) != sha256.Size {
+		return 0, nil, fmt.Errorf("malformed end frame: digest is %d bytes, expected %d", len(digest), sha256.Size)
+	}
+	return count, digest, nil
+}
+
+// appendField appends v to data, prefixed with its length.
+func appendField(data, v []byte) []byte {
+	data = binary.AppendUvarint(data, uint64(len(v)))
+	return append(data, v...)
+}
+
+// parser reads the fields of a frame payload. The first error encountered is
+// retained and returned by [parser.done].
+type parser struct {
+	data []byte
+	err  error
+}
+
+func (p *parser) fail() {
+	if p.err == nil {
+		p.err = errors.New("payload is too short")
+	}
+	p.data = nil
+}
+
+func (p *parser) byte() byte {
+	if len(p.data) == 0 {
+		p.fail()
+		return 0
+	}
+	b := p.data[0]
+	p.data = p.data[1:]
+	return b
+}
+
+func (p *parser) uvarint() uint64 {
+	v, n := binary.Uvarint(p.data)
+	if n <= 0 {
+		p.fail()
+		return 0
+	}
+	p.data = p.data[n:]
+	return v
+}
+
+func (p *parser) varint() int64 {
+	v, n := binary.Varint(p.data)
+	if n <= 0 {
+		p.fail()
+		return 0
+	}
+	p.data = p.data[n:]
+	return v
+}
+
+func (p *parser) field() []byte {
+	n := p.uvarint()
+	if n > uint64(len(p.data)) {
+		p.fail()
+		return nil
+	}
+	v := p.data[:n]
+	p.data = p.data[n:]
+	return v
+}
+
+func (p *parser) rest() []byte {
+	v := p.data
+	p.data = nil
+	return v
+}
+
+func (p *parser) done() error {
+	if p.err != nil {
+		return p.err
+	}
+	if len(p.data) != 0 {
+		return fmt.Errorf("payload has %d unexpected trailing bytes", len(p.data))
+	}
+	return nil
+}
+
+// writer writes frames to an archive.
+type writer struct {
+	w *bufio.Writer
+
+	// section is the digest of the data frames written since the most recent
+	// store frame.
+	section hash.Hash
+	count   uint64
+}
+
+func newWriter(w io.Writer) (*writer, error) {
+	aw := &writer{
+		w:       bufio.NewWriter(w),
+		section: sha256.New(),
+	}
+
+	if _, err := aw.w.WriteString(magic); err != nil {
+		return nil, err
+	}
+
+	if err := aw.w.WriteByte(formatVersion); err != nil {
+		return nil, err
+	}
+
+	return aw, nil
+}
+
+// frame writes a single frame.
+func (w *writer) frame(t frameType, payload []byte) error {
+	if len(payload) > maxFrameSize {
+		return fmt.Errorf("frame length (%d) exceeds the maximum of %d bytes", len(payload), maxFrameSize)
+	}
+
+	header := binary.AppendUvarint([]byte{byte(t)}, uint64(len(payload)))
+
+	crc := crc32.Update(0, crcTable, header)
+	crc = crc32.Update(crc, crcTable, payload)
+
+	if _, err := w.w.Write(header); err != nil {
+		return err
+	}
+
+	if _, err := w.w.Write(payload); err != nil {
+		return err
+	}
+
+	_, err := w.w.Write(binary.BigEndian.AppendUint32(nil, crc))
+	return err
+}
+
+// beginSection writes a store frame.
+func (w *writer) beginSection(k Kind, name string) error {
+	w.section.Reset()
+	w.count = 0
+	return w.frame(storeFrame, marshalStore(k, name))
+}
+
+// data writes a data frame and adds it to the digest of the current section.
+func (w *writer) data(t frameType, payload []byte) error {
+	writeDigest(w.section, t, payload)
+	w.count++
+	return w.frame(t, payload)
+}
+
+// endSection writes an end frame for the current section.
+func (w *writer) endSection() error {
+	return w.frame(endFrame, marshalEnd(w.count, w.section.Sum(nil)))
+}
+
+// close writes the trailer and flushes any buffered data.
+func (w *writer) close(sections int) error {
+	if err := w.frame(trailerFrame, binary.AppendUvarint(nil, uint64(sections))); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+// writeDigest adds a data frame to the digest of a section.
+func writeDigest(h hash.Hash, t frameType, payload []byte) {
+	h.Write([]byte{byte(t)})
+	h.Write(binary.AppendUvarint(nil, uint64(len(payload))))
+	h.Write(payload)
+}
+
+// reader reads frames from an archive.
+type reader struct {
+	r *bufio.Reader
+}
+
+func newReader(r io.Reader) (*reader, error) {
+	ar := &reader{bufio.NewReader(r)}
+
+	header := make([]byte, len(magic)+1)
+	if _, err := io.ReadFull(ar.r, header); err != nil {
+		return nil, fmt.Errorf("unable to read archive header: %w", err)
+	}
+
+	if string(header[:len(magic)]) != magic {
+		return nil, errors.New("not a backup archive")
+	}
+
+	if v := header[len(magic)]; v != formatVersion {
+		return nil, fmt.Errorf("unsupported archive format version (%d)", v)
+	}
+
+	return ar, nil
+}
+
+// frame reads the next frame and verifies its checksum.
+func (r *reader) frame() (frameType, []byte, error) {
+	t, err := r.r.ReadByte()
+	if err == io.EOF {
+		return 0, nil, errors.New("archive is truncated")
+	} else if err != nil {
+		return 0, nil, err
+	}
+
+	n, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to read frame length: %w", unexpectedEOF(err))
+	}
+
+	if n > maxFrameSize {
+		return 0, nil, fmt.Errorf("frame length (%d) exceeds the maximum of %d bytes", n, maxFrameSize)
+	}
+
+	payload, err := r.payload(int(n))
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to read frame payload: %w", unexpectedEOF(err))
+	}
+
+	var checksum [4]byte
+	if _, err := io.ReadFull(r.r, checksum[:]); err != nil {
+		return 0, nil, fmt.Errorf("unable to read frame checksum: %w", unexpectedEOF(err))
+	}
+
+	header := binary.AppendUvarint([]byte{t}, n)
+	crc := crc32.Update(0, crcTable, header)
+	crc = crc32.Update(crc, crcTable, payload)
+
+	if crc != binary.BigEndian.Uint32(checksum[:]) {
+		return 0, nil, errors.New("frame checksum mismatch")
+	}
+
+	return frameType(t), payload, nil
+}
+
+// payload reads a frame payload of n bytes, growing the buffer as each chunk
+// is read.
+func (r *reader) payload(n int) ([]byte, error) {
+	payload := make([]byte, 0, min(n, frameChunkSize))
+
+	for len(payload) < n {
+		size := min(n-len(payload), frameChunkSize)
+		payload = slices.Grow(payload, size)
+
+		if _, err := io.ReadFull(r.r, payload[len(payload):len(payload)+size]); err != nil {
+			return nil, err
+		}
+
+		payload = payload[:len(payload)+size]
+	}
+
+	return payload, nil
+}
+
+// expect reads the next frame, and returns an error if it is not of type t.
+func (r *reader) expect(t frameType) ([]byte, error) {
+	actual, payload, err := r.frame()
+	if err != nil {
+		return nil, err
+	}
+	if actual != t {
+		return nil, fmt.Errorf("unexpected frame type (%d), expected %d", actual, t)
+	}
+	return payload, nil
+}
+
+// manifest reads the manifest frame.
+func (r *reader) manifest() (Manifest, error) {
+	payload, err := r.expect(manifestFrame)
+	if err != nil {
+		return Manifest{}, err
+	}
+	return unmarshalManifest(payload)
+}
+
+// eof returns an error if there is any data after the trailer.
+func (r *reader) eof() error {
+	if _, err := r.r.ReadByte(); err != io.EOF {
+		if err != nil {
+			return err
+		}
+		return errors.New("unexpected data after the archive trailer")
+	}
+	return nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,359 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/journal"
+	"github.com/dogmatiq/persistencekit/kv"
+	"github.com/dogmatiq/persistencekit/set"
+)
+
+// Restore restores the journals, keyspaces and sets in the archive read from r
+// to d.
+//
+// If any stores are selected using [Journals], [Keyspaces] and [Sets], only
+// those stores are restored, and it is an error if the archive does not
+// contain them. Otherwise, every store in the archive is restored.
+//
+// Each store that is restored must be empty. Journals that were truncated
+// before the backup was made must not have any records beyond the position
+// at which they begin, so that each record can be restored to its original
+// position.
+//
+// The archive is read as a stream, and each frame is verified against its
+// checksum before it is applied. The digest of each store is verified once
+// the store has been restored; if it does not match, or an error occurs, the
+// stores restored thus far remain in place.
+func Restore(
+	ctx context.Context,
+	r io.Reader,
+	d driver.Driver,
+	opts ...Option,
+) error {
+	o := resolveOptions(opts)
+
+	ar, err := newReader(r)
+	if err != nil {
+		return err
+	}
+
+	m, err := ar.manifest()
+	if err != nil {
+		return err
+	}
+
+	for _, sel := range o.Selected {
+		if !slices.ContainsFunc(
+			m.Stores,
+			func(e Entry) bool {
+				return e.Kind == sel.Kind && e.Name == sel.Name
+			},
+		) {
+			return fmt.Errorf("archive does not contain %s %q", sel.Kind, sel.Name)
+		}
+	}
+
+	for _, e := range m.Stores {
+		var rs restorer
+		if len(o.Selected) == 0 || o.includes(e.Kind, e.Name) {
+			rs, err = newRestorer(ctx, d, e)
+			if err != nil {
+				return fmt.Errorf("unable to restore %s %q: %w", e.Kind, e.Name, err)
+			}
+		}
+
+		err := restoreSection(ctx, ar, e, rs)
+
+		if rs != nil {
+			err = errors.Join(err, rs.Close())
+		}
+
+		if err != nil {
+			return fmt.Errorf("unable to restore %s %q: %w", e.Kind, e.Name, err)
+		}
+	}
+
+	payload, err := ar.expect(trailerFrame)
+	if err != nil {
+		return err
+	}
+
+	p := parser{data: payload}
+	n := p.uvarint()
+	if err := p.done(); err != nil {
+		return fmt.Errorf("malformed trailer frame: %w", err)
+	}
+
+	if n != uint64(len(m.Stores)) {
+		return fmt.Errorf("archive trailer reports %d stores, but the manifest lists %d", n, len(m.Stores))
+	}
+
+	return ar.eof()
+}
+
+// restoreSection reads the section of the archive that contains the store
+// described by e, passing each data frame to rs. If rs is nil, the section is
+// verified but not restored.
+func restoreSection(
+	ctx context.Context,
+	ar *reader,
+	e Entry,
+	rs restorer,
+) error {
+	payload, err := ar.expect(storeFrame)
+	if err != nil {
+		return err
+	}
+
+	k, name, err := unmarshalStore(payload)
+	if err != nil {
+		return err
+	}
+
+	if k != e.Kind || name != e.Name {
+		return fmt.Errorf("archive contains %s %q where the manifest lists %s %q", k, name, e.Kind, e.Name)
+	}
+
+	digest := sha256.New()
+	var count uint64
+
+	for {
+		t, payload, err := ar.frame()
+		if err != nil {
+			return err
+		}
+
+		if t == endFrame {
+			n, sum, err := unmarshalEnd(payload)
+			if err != nil {
+				return err
+			}
+
+			if n != count {
+				return fmt.Errorf("archive contains %d entries, but the store's end frame reports %d", count, n)
+			}
+
+			if !bytes.Equal(sum, digest.Sum(nil)) {
+				return errors.New("digest mismatch")
+			}
+
+			if rs != nil {
+				return rs.Finish(ctx)
+			}
+
+			return nil
+		}
+
+		if t != e.Kind.dataFrame() {
+			return fmt.Errorf("unexpected frame type (%d), expected %d", t, e.Kind.dataFrame())
+		}
+
+		writeDigest(digest, t, payload)
+		count++
+
+		if rs != nil {
+			if err := rs.Apply(ctx, payload); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// restorer restores the contents of a single store.
+type restorer interface {
+	// Apply restores the content of a single data frame.
+	Apply(ctx context.Context, payload []byte) error
+
+	// Finish completes the restoration after all data frames are applied.
+	Finish(ctx context.Context) error
+
+	// Close releases the resources used by the restorer.
+	Close() error
+}
+
+func newRestorer(ctx context.Context, d driver.Driver, e Entry) (restorer, error) {
+	switch e.Kind {
+	case Journal:
+		return newJournalRestorer(ctx, d.JournalStore(), e)
+	case Keyspace:
+		return newKeyspaceRestorer(ctx, d.KVStore(), e.Name)
+	case Set:
+		return newSetRestorer(ctx, d.SetStore(), e.Name)
+	default:
+		return nil, fmt.Errorf("unsupported store kind (%d)", byte(e.Kind))
+	}
+}
+
+type journalRestorer struct {
+	journal journal.BinaryJournal
+	bounds  journal.Interval
+	next    journal.Position
+}
+
+func newJournalRestorer(ctx context.Context, s journal.BinaryStore, e Entry) (*journalRestorer, error) {
+	j, err := s.Open(ctx, e.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds, err := j.Bounds(ctx)
+	if err != nil {
+		j.Close()
+		return nil, err
+	}
+
+	if !bounds.IsEmpty() || bounds.End > e.Bounds.Begin {
+		j.Close()
+		return nil, fmt.Errorf(
+			"journal must be empty and end at or before position %d, its bounds are %s",
+			e.Bounds.Begin,
+			bounds,
+		)
+	}
+
+	// Pad the journal with empty records so that each restored record is
+	// stored at its original position. They are removed by Finish().
+	for pos := bounds.End; pos < e.Bounds.Begin; pos++ {
+		if err := j.Append(ctx, pos, nil); err != nil {
+			j.Close()
+			return nil, err
+		}
+	}
+
+	return &journalRestorer{
+		journal: j,
+		bounds:  e.Bounds,
+		next:    e.Bounds.Begin,
+	}, nil
+}
+
+func (r *journalRestorer) Apply(ctx context.Context, payload []byte) error {
+	pos, rec, err := unmarshalRecord(payload)
+	if err != nil {
+		return err
+	}
+
+	if pos != r.next || pos >= r.bounds.End {
+		return fmt.Errorf("archive contains a record at position %d, expected position %d", pos, r.next)
+	}
+
+	if err := r.journal.Append(ctx, pos, rec); err != nil {
+		return err
+	}
+
+	r.next++
+
+	return nil
+}
+
+func (r *journalRestorer) Finish(ctx context.Context) error {
+	if r.next != r.bounds.End {
+		return fmt.Errorf("archive ends at position %d, expected position %d", r.next, r.bounds.End)
+	}
+
+	if r.bounds.Begin == 0 {
+		return nil
+	}
+
+	return r.journal.Truncate(ctx, r.bounds.Begin)
+}
+
+func (r *journalRestorer) Close() error {
+	return r.journal.Close()
+}
+
+type keyspaceRestorer struct {
+	keyspace kv.BinaryKeyspace
+}
+
+func newKeyspaceRestorer(ctx context.Context, s kv.BinaryStore, name string) (*keyspaceRestorer, error) {
+	ks, err := s.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	empty := true
+	if err := kv.RangeKeys(
+		ctx,
+		ks,
+		func(context.Context, []byte, kv.Revision) (bool, error) {
+			empty = false
+			return false, nil
+		},
+	); err != nil {
+		ks.Close()
+		return nil, err
+	}
+
+	if !empty {
+		ks.Close()
+		return nil, errors.New("keyspace must be empty")
+	}
+
+	return &keyspaceRestorer{ks}, nil
+}
+
+func (r *keyspaceRestorer) Apply(ctx context.Context, payload []byte) error {
+	k, v, err := unmarshalPair(payload)
+	if err != nil {
+		return err
+	}
+	return r.keyspace.SetUnconditional(ctx, k, v)
+}
+
+func (r *keyspaceRestorer) Finish(context.Context) error {
+	return nil
+}
+
+func (r *keyspaceRestorer) Close() error {
+	return r.keyspace.Close()
+}
+
+type setRestorer struct {
+	set set.BinarySet
+}
+
+func newSetRestorer(ctx context.Context, s set.BinaryStore, name string) (*setRestorer, error) {
+	x, err := s.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	empty := true
+	if err := x.Range(
+		ctx,
+		func(context.Context, []byte) (bool, error) {
+			empty = false
+			return false, nil
+		},
+	); err != nil {
+		x.Close()
+		return nil, err
+	}
+
+	if !empty {
+		x.Close()
+		return nil, errors.New("set must be empty")
+	}
+
+	return &setRestorer{x}, nil
+}
+
+func (r *setRestorer) Apply(ctx context.Context, payload []byte) error {
+	return r.set.Add(ctx, payload)
+}
+
+func (r *setRestorer) Finish(context.Context) error {
+	return nil
+}
+
+func (r *setRestorer) Close() error {
+	return r.set.Close()
+}
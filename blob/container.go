@@ -0,0 +1,49 @@
+package blob
+
+import (
+	"context"
+	"io"
+)
+
+// A Container is a named collection of content-addressed blobs.
+type Container interface {
+	// Name returns the name of the container.
+	Name() string
+
+	// Put stores the content read from r until EOF, and returns a reference to
+	// the resulting blob.
+	//
+	// If the container already contains a blob with the same content, no
+	// additional content is stored. In either case, a reference is added to
+	// the blob, which must eventually be released by a call to Release.
+	Put(ctx context.Context, r io.Reader) (Ref, error)
+
+	// Get returns a reader that reads the content of the blob with the given
+	// hash.
+	//
+	// ok is false if the container does not contain the blob. The caller must
+	// close the reader when it is no longer needed.
+	Get(ctx context.Context, h Hash) (r io.ReadCloser, ok bool, err error)
+
+	// Has returns true if the container contains the blob with the given hash.
+	Has(ctx context.Context, h Hash) (bool, error)
+
+	// Release removes a reference to the blob with the given hash, as added by
+	// a prior call to Put.
+	//
+	// The blob is removed from the container when its last reference is
+	// released. It is not an error to release a blob that does not exist.
+	Release(ctx context.Context, h Hash) error
+
+	// Close closes the container.
+	Close() error
+}
+
+// Ref is a reference to a blob.
+type Ref struct {
+	// Hash is the SHA-256 hash of the blob's content.
+	Hash Hash
+
+	// Size is the size of the blob's content, in bytes.
+	Size int64
+}
@@ -0,0 +1,8 @@
+// Package blob provides an abstraction of a persisted, content-addressed store
+// of large binary objects (blobs).
+//
+// Each blob is identified by the SHA-256 hash of its content, such that storing
+// the same content more than once does not consume additional storage. Instead,
+// each store operation adds a reference to the existing blob. A blob is removed
+// once every reference to it has been released.
+package blob
@@ -0,0 +1,37 @@
+package blob
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Hash is the SHA-256 hash of a blob's content, which uniquely identifies the
+// blob within its container.
+type Hash [sha256.Size]byte
+
+// HashOf returns the hash of the given content.
+func HashOf(content []byte) Hash {
+	return sha256.Sum256(content)
+}
+
+// ParseHash parses the hexadecimal representation of a hash, as returned by
+// [Hash.String].
+func ParseHash(s string) (Hash, error) {
+	var h Hash
+
+	if hex.DecodedLen(len(s)) != len(h) {
+		return Hash{}, fmt.Errorf("invalid blob hash %q: expected %d hexadecimal digits", s, hex.EncodedLen(len(h)))
+	}
+
+	if _, err := hex.Decode(h[:], []byte(s)); err != nil {
+		return Hash{}, fmt.Errorf("invalid blob hash %q: %w", s, err)
+	}
+
+	return h, nil
+}
+
+// String returns the hexadecimal representation of the hash.
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
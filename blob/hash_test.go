@@ -0,0 +1,41 @@
+package blob_test
+
+import (
+	"testing"
+
+	. "github.com/dogmatiq/persistencekit/blob"
+)
+
+func TestParseHash(t *testing.T) {
+	t.Run("it parses the string representation of a hash", func(t *testing.T) {
+		want := HashOf([]byte("<content>"))
+
+		got, err := ParseHash(want.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != want {
+			t.Fatalf("unexpected hash: got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("it returns an error if the string is malformed", func(t *testing.T) {
+		cases := []struct {
+			Desc  string
+			Input string
+		}{
+			{"empty", ""},
+			{"too short", "abcd"},
+			{"not hexadecimal", HashOf(nil).String()[1:] + "z"},
+		}
+
+		for _, c := range cases {
+			t.Run(c.Desc, func(t *testing.T) {
+				if _, err := ParseHash(c.Input); err == nil {
+					t.Fatal("expected an error")
+				}
+			})
+		}
+	})
+}
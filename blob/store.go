@@ -0,0 +1,15 @@
+package blob
+
+import (
+	"context"
+)
+
+// Store is a collection of containers of blobs.
+type Store interface {
+	// Open returns the container with the given name.
+	Open(ctx context.Context, name string) (Container, error)
+
+	// Provision creates the infrastructure used by the store if it does not
+	// already exist.
+	Provision(ctx context.Context) error
+}
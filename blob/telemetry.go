@@ -0,0 +1,224 @@
+package blob
+
+import (
+	"context"
+	"io"
+
+	"github.com/dogmatiq/enginekit/telemetry"
+	"github.com/dogmatiq/persistencekit/internal/x/xtelemetry"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithTelemetry returns a [Store] that adds telemetry to s.
+func WithTelemetry(
+	s Store,
+	p trace.TracerProvider,
+	m metric.MeterProvider,
+	l log.LoggerProvider,
+) Store {
+	return &instrumentedStore{
+		Next: s,
+		Telemetry: telemetry.Provider{
+			TracerProvider: p,
+			MeterProvider:  m,
+			LoggerProvider: l,
+		},
+	}
+}
+
+// instrumentedStore is a decorator that adds instrumentation to a [Store].
+type instrumentedStore struct {
+	Next      Store
+	Telemetry telemetry.Provider
+}
+
+func (s *instrumentedStore) Provision(ctx context.Context) error {
+	return s.Next.Provision(ctx)
+}
+
+// Open returns the container with the given name.
+func (s *instrumentedStore) Open(ctx context.Context, name string) (Container, error) {
+	telem := s.Telemetry.Recorder(
+		"github.com/dogmatiq/persistencekit/blob",
+		telemetry.Type("container.store", s.Next),
+		telemetry.String("container.name", name),
+		telemetry.String("container.handle", xtelemetry.HandleID()),
+	)
+
+	c := &instrumentedContainer{
+		Telemetry:      telem,
+		OpenContainers: telem.UpDownCounter("persistence.blob.open_containers", "{container}", "The number of blob containers that are currently open."),
+		ContentIO:      telem.Counter("persistence.blob.content.io", "By", "The cumulative size of the blob content that has been read and written."),
+		ContentSize:    telem.Histogram("persistence.blob.content.size", "By", "The sizes of the blobs that have been written."),
+	}
+
+	ctx, span := telem.StartSpan(ctx, "blob.open")
+	defer span.End()
+
+	next, err := s.Next.Open(ctx, name)
+	if err != nil {
+		telem.Error(ctx, "blob.open.error", "unable to open blob container", err)
+		return nil, err
+	}
+
+	c.Next = next
+
+	c.OpenContainers(ctx, 1)
+	c.Telemetry.Info(ctx, "blob.open.ok", "opened blob container")
+
+	return c, nil
+}
+
+type instrumentedContainer struct {
+	Next      Container
+	Telemetry *telemetry.Recorder
+
+	OpenContainers telemetry.Instrument[int64]
+	ContentIO      telemetry.Instrument[int64]
+	ContentSize    telemetry.Instrument[int64]
+}
+
+func (c *instrumentedContainer) Name() string {
+	return c.Next.Name()
+}
+
+func (c *instrumentedContainer) Put(ctx context.Context, r io.Reader) (Ref, error) {
+	ctx, span := c.Telemetry.StartSpan(ctx, "blob.put")
+	defer span.End()
+
+	ref, err := c.Next.Put(ctx, r)
+	if err != nil {
+		c.Telemetry.Error(ctx, "blob.put.error", "unable to store blob", err)
+		return Ref{}, err
+	}
+
+	span.SetAttributes(
+		telemetry.String("blob_hash", ref.Hash.String()),
+		telemetry.Int("blob_size", ref.Size),
+	)
+
+	c.ContentIO(ctx, ref.Size, telemetry.WriteDirection)
+	c.ContentSize(ctx, ref.Size, telemetry.WriteDirection)
+
+	c.Telemetry.Info(ctx, "blob.put.ok", "stored blob")
+
+	return ref, nil
+}
+
+func (c *instrumentedContainer) Get(ctx context.Context, h Hash) (io.ReadCloser, bool, error) {
+	ctx, span := c.Telemetry.StartSpan(
+		ctx,
+		"blob.get",
+		telemetry.String("blob_hash", h.String()),
+	)
+	defer span.End()
+
+	r, ok, err := c.Next.Get(ctx, h)
+	if err != nil {
+		c.Telemetry.Error(ctx, "blob.get.error", "unable to open blob", err)
+		return nil, false, err
+	}
+
+	span.SetAttributes(
+		telemetry.Bool("blob_found", ok),
+	)
+
+	if !ok {
+		c.Telemetry.Info(ctx, "blob.get.not-found", "blob not found")
+		return nil, false, nil
+	}
+
+	c.Telemetry.Info(ctx, "blob.get.ok", "opened blob")
+
+	return &instrumentedReader{
+		Next:      r,
+		Context:   context.WithoutCancel(ctx),
+		ContentIO: c.ContentIO,
+	}, true, nil
+}
+
+func (c *instrumentedContainer) Has(ctx context.Context, h Hash) (bool, error) {
+	ctx, span := c.Telemetry.StartSpan(
+		ctx,
+		"blob.has",
+		telemetry.String("blob_hash", h.String()),
+	)
+	defer span.End()
+
+	ok, err := c.Next.Has(ctx, h)
+	if err != nil {
+		c.Telemetry.Error(ctx, "blob.has.error", "unable to determine if blob exists", err)
+		return false, err
+	}
+
+	span.SetAttributes(
+		telemetry.Bool("blob_found", ok),
+	)
+
+	return ok, nil
+}
+
+func (c *instrumentedContainer) Release(ctx context.Context, h Hash) error {
+	ctx, span := c.Telemetry.StartSpan(
+		ctx,
+		"blob.release",
+		telemetry.String("blob_hash", h.String()),
+	)
+	defer span.End()
+
+	if err := c.Next.Release(ctx, h); err != nil {
+		c.Telemetry.Error(ctx, "blob.release.error", "unable to release blob", err)
+		return err
+	}
+
+	c.Telemetry.Info(ctx, "blob.release.ok", "released blob")
+
+	return nil
+}
+
+func (c *instrumentedContainer) Close() error {
+	if c.Next == nil {
+		// If the resource has already been closed don't do anything at all,
+		// even log a warning, because we want to allow the caller to defer
+		// closing for safety _and_ close explicitly elsewhere for error
+		// checking.
+		return nil
+	}
+
+	ctx, span := c.Telemetry.StartSpan(context.Background(), "blob.close")
+	defer span.End()
+
+	defer func() {
+		c.Next = nil
+		c.OpenContainers(ctx, -1)
+	}()
+
+	if err := c.Next.Close(); err != nil {
+		c.Telemetry.Error(ctx, "blob.close.error", "unable to close blob container cleanly", err)
+		return err
+	}
+
+	c.Telemetry.Info(ctx, "blob.close.ok", "closed blob container")
+
+	return nil
+}
+
+// instrumentedReader is a decorator that records the amount of blob content
+// read from an [io.ReadCloser].
+type instrumentedReader struct {
+	Next      io.ReadCloser
+	Context   context.Context
+	ContentIO telemetry.Instrument[int64]
+}
+
+func (r *instrumentedReader) Read(p []byte) (int, error) {
+	n, err := r.Next.Read(p)
+	r.ContentIO(r.Context, int64(n), telemetry.ReadDirection)
+	return n, err
+}
+
+func (r *instrumentedReader) Close() error {
+	return r.Next.Close()
+}
@@ -0,0 +1,23 @@
+package blob_test
+
+import (
+	"testing"
+
+	. "github.com/dogmatiq/persistencekit/blob"
+	"github.com/dogmatiq/persistencekit/driver/memory/memoryblob"
+	nooplog "go.opentelemetry.io/otel/log/noop"
+	noopmetric "go.opentelemetry.io/otel/metric/noop"
+	nooptrace "go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestWithTelemetry(t *testing.T) {
+	RunTests(
+		t,
+		WithTelemetry(
+			&memoryblob.Store{},
+			nooptrace.NewTracerProvider(),
+			noopmetric.NewMeterProvider(),
+			nooplog.NewLoggerProvider(),
+		),
+	)
+}
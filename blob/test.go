@@ -0,0 +1,351 @@
+package blob
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+	"testing/iotest"
+
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+)
+
+// RunTests runs tests that confirm a [Store] implementation behaves correctly.
+func RunTests(
+	t *testing.T,
+	store Store,
+) {
+	open := func(t *testing.T, name string) Container {
+		t.Helper()
+
+		c, err := store.Open(t.Context(), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() {
+			if err := c.Close(); err != nil {
+				t.Error(err)
+			}
+		})
+
+		if c.Name() != name {
+			t.Fatalf("unexpected container name: got %q, want %q", c.Name(), name)
+		}
+
+		return c
+	}
+
+	setup := func(t *testing.T) Container {
+		return open(t, xtesting.SequentialName("blob"))
+	}
+
+	put := func(t *testing.T, c Container, content []byte) Ref {
+		t.Helper()
+
+		ref, err := c.Put(t.Context(), bytes.NewReader(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := HashOf(content); ref.Hash != want {
+			t.Fatalf("unexpected hash: got %s, want %s", ref.Hash, want)
+		}
+
+		if want := int64(len(content)); ref.Size != want {
+			t.Fatalf("unexpected size: got %d, want %d", ref.Size, want)
+		}
+
+		return ref
+	}
+
+	release := func(t *testing.T, c Container, h Hash) {
+		t.Helper()
+
+		if err := c.Release(t.Context(), h); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expectContent := func(t *testing.T, c Container, h Hash, want []byte) {
+		t.Helper()
+
+		r, ok, err := c.Get(t.Context(), h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatalf("expected blob %s to exist", h)
+		}
+		defer r.Close()
+
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(got, want) {
+			t.Fatalf("unexpected content: got %d byte(s), want %d byte(s)", len(got), len(want))
+		}
+
+		ok, err = c.Has(t.Context(), h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatalf("expected Has() to report that blob %s exists", h)
+		}
+	}
+
+	expectNotFound := func(t *testing.T, c Container, h Hash) {
+		t.Helper()
+
+		r, ok, err := c.Get(t.Context(), h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			r.Close()
+			t.Fatalf("expected blob %s to be absent", h)
+		}
+
+		ok, err = c.Has(t.Context(), h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Fatalf("expected Has() to report that blob %s is absent", h)
+		}
+	}
+
+	random := func(size int) []byte {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(rand.Uint32())
+		}
+		return data
+	}
+
+	t.Run("type Container", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("func Put()", func(t *testing.T) {
+			t.Parallel()
+
+			cases := []struct {
+				Desc    string
+				Content []byte
+			}{
+				{"empty content", []byte{}},
+				{"small content", []byte("<content>")},
+				// The large content is big enough to span multiple chunks,
+				// items or parts in each of the drivers.
+				{"large content", random(9<<20 + 123)},
+			}
+
+			for _, c := range cases {
+				t.Run(c.Desc, func(t *testing.T) {
+					t.Parallel()
+
+					cont := setup(t)
+					ref := put(t, cont, c.Content)
+					expectContent(t, cont, ref.Hash, c.Content)
+				})
+			}
+
+			t.Run("it does not store a blob if the reader fails", func(t *testing.T) {
+				t.Parallel()
+
+				c := setup(t)
+				content := []byte("<content>")
+				want := errors.New("<error>")
+
+				_, err := c.Put(
+					t.Context(),
+					io.MultiReader(
+						bytes.NewReader(content),
+						iotest.ErrReader(want),
+					),
+				)
+				if !errors.Is(err, want) {
+					t.Fatalf("unexpected error: got %v, want %v", err, want)
+				}
+
+				expectNotFound(t, c, HashOf(content))
+			})
+
+			t.Run("it deduplicates identical content", func(t *testing.T) {
+				t.Parallel()
+
+				c := setup(t)
+				content := []byte("<content>")
+
+				a := put(t, c, content)
+				b := put(t, c, content)
+
+				if a != b {
+					t.Fatalf("expected identical references, got %v and %v", a, b)
+				}
+
+				expectContent(t, c, a.Hash, content)
+			})
+
+			t.Run("it does not share blobs between containers", func(t *testing.T) {
+				t.Parallel()
+
+				a := setup(t)
+				b := setup(t)
+				content := []byte("<content>")
+
+				ref := put(t, a, content)
+				expectNotFound(t, b, ref.Hash)
+			})
+
+			t.Run("it allows concurrent storage of identical content", func(t *testing.T) {
+				t.Parallel()
+
+				const n = 5
+
+				name := xtesting.SequentialName("blob")
+				content := random(1 << 10)
+
+				var g sync.WaitGroup
+				for range n {
+					g.Go(func() {
+						// Each producer uses its own handle, as it would if
+						// each were a separate process.
+						c, err := store.Open(t.Context(), name)
+						if err != nil {
+							t.Error(err)
+							return
+						}
+						defer c.Close()
+
+						if _, err := c.Put(t.Context(), bytes.NewReader(content)); err != nil {
+							t.Error(err)
+						}
+					})
+				}
+				g.Wait()
+
+				if t.Failed() {
+					return
+				}
+
+				c := open(t, name)
+				h := HashOf(content)
+
+				for range n - 1 {
+					release(t, c, h)
+					expectContent(t, c, h, content)
+				}
+
+				release(t, c, h)
+				expectNotFound(t, c, h)
+			})
+		})
+
+		t.Run("func Get()", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it returns false if the blob does not exist", func(t *testing.T) {
+				t.Parallel()
+
+				c := setup(t)
+				expectNotFound(t, c, HashOf([]byte("<content>")))
+			})
+		})
+
+		t.Run("func Release()", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it removes the blob when the last reference is released", func(t *testing.T) {
+				t.Parallel()
+
+				c := setup(t)
+				content := []byte("<content>")
+
+				ref := put(t, c, content)
+				put(t, c, content)
+
+				release(t, c, ref.Hash)
+				expectContent(t, c, ref.Hash, content)
+
+				release(t, c, ref.Hash)
+				expectNotFound(t, c, ref.Hash)
+			})
+
+			t.Run("it allows the content to be stored again after removal", func(t *testing.T) {
+				t.Parallel()
+
+				c := setup(t)
+				content := []byte("<content>")
+
+				ref := put(t, c, content)
+				release(t, c, ref.Hash)
+				expectNotFound(t, c, ref.Hash)
+
+				put(t, c, content)
+				expectContent(t, c, ref.Hash, content)
+			})
+
+			t.Run("it does not return an error if the blob does not exist", func(t *testing.T) {
+				t.Parallel()
+
+				c := setup(t)
+				release(t, c, HashOf([]byte("<content>")))
+			})
+		})
+	})
+
+	t.Run("type Writer", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("it stores the written content when closed", func(t *testing.T) {
+			t.Parallel()
+
+			c := setup(t)
+			content := random(1 << 20)
+
+			w := NewWriter(t.Context(), c)
+
+			for chunk := range slices.Chunk(content, 1000) {
+				if _, err := w.Write(chunk); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			ref := w.Ref()
+
+			if want := HashOf(content); ref.Hash != want {
+				t.Fatalf("unexpected hash: got %s, want %s", ref.Hash, want)
+			}
+
+			expectContent(t, c, ref.Hash, content)
+		})
+
+		t.Run("it does not store the content when aborted", func(t *testing.T) {
+			t.Parallel()
+
+			c := setup(t)
+			content := []byte("<content>")
+
+			w := NewWriter(t.Context(), c)
+
+			if _, err := w.Write(content); err != nil {
+				t.Fatal(err)
+			}
+
+			w.Abort()
+
+			expectNotFound(t, c, HashOf(content))
+		})
+	})
+}
@@ -0,0 +1,86 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// Writer is an [io.Writer] that stores the content written to it as a blob.
+//
+// The blob is stored when the writer is closed, at which point a reference to
+// the blob is available via [Writer.Ref].
+type Writer struct {
+	pipe *io.PipeWriter
+	done chan struct{}
+	ref  Ref
+	err  error
+}
+
+// NewWriter returns a [Writer] that stores the content written to it as a blob
+// in c.
+//
+// The writer must be closed by a call to [Writer.Close] or [Writer.Abort].
+func NewWriter(ctx context.Context, c Container) *Writer {
+	r, w := io.Pipe()
+
+	wr := &Writer{
+		pipe: w,
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(wr.done)
+		wr.ref, wr.err = c.Put(ctx, r)
+		r.CloseWithError(wr.err)
+	}()
+
+	return wr
+}
+
+// Write writes p to the blob.
+func (w *Writer) Write(p []byte) (int, error) {
+	n, err := w.pipe.Write(p)
+	if errors.Is(err, io.ErrClosedPipe) {
+		<-w.done
+		if w.err != nil {
+			return n, w.err
+		}
+	}
+	return n, err
+}
+
+// Close stores the content that has been written to w and waits for the blob
+// to be stored.
+func (w *Writer) Close() error {
+	w.pipe.Close()
+	<-w.done
+	return w.err
+}
+
+// Abort discards the content that has been written to w without storing the
+// blob.
+func (w *Writer) Abort() {
+	w.pipe.CloseWithError(errAborted)
+	<-w.done
+}
+
+// Ref returns a reference to the stored blob. It panics if the blob has not
+// been stored successfully.
+func (w *Writer) Ref() Ref {
+	select {
+	case <-w.done:
+	default:
+		panic("blob writer has not been closed")
+	}
+
+	if w.err != nil {
+		panic("blob was not stored successfully")
+	}
+
+	return w.ref
+}
+
+// errAborted is the error that [Container.Put] observes when reading from an
+// aborted [Writer].
+var errAborted = errors.New("blob writer aborted")
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// format converts between the binary representation of keys, values and set
+// members and their representation on the command-line.
+type format interface {
+	// Encode returns the binary representation of s.
+	Encode(s string) ([]byte, error)
+
+	// Text returns the human-readable representation of data.
+	Text(data []byte) (string, error)
+
+	// JSON returns the representation of data used in JSON output.
+	JSON(data []byte) (json.RawMessage, error)
+}
+
+// formatOptions holds the flags that select a [format].
+type formatOptions struct {
+	Name          string
+	DescriptorSet string
+	Message       string
+}
+
+// newFormat returns the format described by opts.
+func newFormat(opts formatOptions) (format, error) {
+	switch opts.Name {
+	case "raw":
+		return rawFormat{}, nil
+	case "hex":
+		return hexFormat{}, nil
+	case "json":
+		return jsonFormat{}, nil
+	case "proto":
+		return newProtoFormat(opts.DescriptorSet, opts.Message)
+	default:
+		return nil, fmt.Errorf("unknown format %q, expected raw, hex, json or proto", opts.Name)
+	}
+}
+
+// rawFormat represents data as a string containing the data verbatim.
+type rawFormat struct{}
+
+func (rawFormat) Encode(s string) ([]byte, error) {
+	return []byte(s), nil
+}
+
+func (rawFormat) Text(data []byte) (string, error) {
+	return string(data), nil
+}
+
+func (rawFormat) JSON(data []byte) (json.RawMessage, error) {
+	if !utf8.Valid(data) {
+		return nil, errors.New("data is not valid UTF-8, use the hex format instead")
+	}
+	return jsonString(string(data))
+}
+
+// hexFormat represents data as a hexadecimal string.
+type hexFormat struct{}
+
+func (hexFormat) Encode(s string) ([]byte, error) {
+	return hex.DecodeString(s)
+}
+
+func (hexFormat) Text(data []byte) (string, error) {
+	return hex.EncodeToString(data), nil
+}
+
+func (hexFormat) JSON(data []byte) (json.RawMessage, error) {
+	return jsonString(hex.EncodeToString(data))
+}
+
+// jsonFormat represents data that is itself JSON.
+type jsonFormat struct{}
+
+func (jsonFormat) Encode(s string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(s)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (f jsonFormat) Text(data []byte) (string, error) {
+	v, err := f.JSON(data)
+	return string(v), err
+}
+
+func (jsonFormat) JSON(data []byte) (json.RawMessage, error) {
+	if !json.Valid(data) {
+		return nil, errors.New("data is not valid JSON")
+	}
+	return data, nil
+}
+
+// protoFormat represents data that is a binary-encoded protocol buffers
+// message, using the protocol buffers JSON mapping.
+type protoFormat struct {
+	desc protoreflect.MessageDescriptor
+}
+
+// newProtoFormat returns a format for the message with the given full name,
+// as described by a serialized FileDescriptorSet, such as the output of
+// "protoc --include_imports --descriptor_set_out".
+func newProtoFormat(descriptorSet, message string) (*protoFormat, error) {
+	if descriptorSet == "" || message == "" {
+		return nil, errors.New("the proto format requires -descriptor-set and -message")
+	}
+
+	data, err := os.ReadFile(descriptorSet)
+	if err != nil {
+		return nil, err
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("unable to parse descriptor set: %w", err)
+	}
+
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse descriptor set: %w", err)
+	}
+
+	d, err := files.FindDescriptorByName(protoreflect.FullName(message))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find message %q: %w", message, err)
+	}
+
+	desc, ok := d.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a message", message)
+	}
+
+	return &protoFormat{desc}, nil
+}
+
+func (f *protoFormat) Encode(s string) ([]byte, error) {
+	m := dynamicpb.NewMessage(f.desc)
+	if err := protojson.Unmarshal([]byte(s), m); err != nil {
+		return nil, err
+	}
+	return proto.Marshal(m)
+}
+
+func (f *protoFormat) Text(data []byte) (string, error) {
+	v, err := f.JSON(data)
+	return string(v), err
+}
+
+func (f *protoFormat) JSON(data []byte) (json.RawMessage, error) {
+	m := dynamicpb.NewMessage(f.desc)
+	if err := proto.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+
+	v, err := protojson.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	// protojson deliberately produces unstable whitespace.
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// jsonString returns the JSON representation of s, without the HTML escaping
+// performed by [json.Marshal].
+func jsonString(s string) (json.RawMessage, error) {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(s); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/health"
+)
+
+// storeOutput is the JSON representation of a store's health and
+// capabilities.
+type storeOutput struct {
+	Store        health.Store   `json:"store"`
+	Healthy      bool           `json:"healthy"`
+	Error        string         `json:"error,omitempty"`
+	Capabilities map[string]any `json:"capabilities,omitempty"`
+}
+
+func (a *app) health(ctx context.Context, args []string) error {
+	if _, err := a.command("health").Parse(args); err != nil {
+		return err
+	}
+
+	return a.withDriver(
+		ctx,
+		func(d driver.Driver) error {
+			caps := capabilities(d.Capabilities())
+			report := health.Check(ctx, d)
+
+			for _, s := range health.Stores() {
+				out := storeOutput{
+					Store:        s,
+					Healthy:      true,
+					Capabilities: caps[s],
+				}
+
+				status := "healthy"
+				if res, ok := report.Result(s); ok && res.Err != nil {
+					out.Healthy = false
+					out.Error = res.Err.Error()
+					status = "unhealthy: " + out.Error
+				}
+
+				var text strings.Builder
+				fmt.Fprintf(&text, "%s\t%s", s, status)
+				for _, k := range slices.Sorted(maps.Keys(out.Capabilities)) {
+					fmt.Fprintf(&text, "\t%s=%v", k, out.Capabilities[k])
+				}
+
+				if err := a.print(out, text.String()); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	)
+}
+
+// capabilities returns the capabilities in c, keyed by store.
+func capabilities(c driver.Capabilities) map[health.Store]map[string]any {
+	return map[health.Store]map[string]any{
+		health.JournalStore: {
+			"max_record_size":  c.Journal.MaxRecordSize,
+			"metered_requests": c.Journal.MeteredRequests,
+		},
+		health.KVStore: {
+			"max_key_size":     c.KV.MaxKeySize,
+			"max_value_size":   c.KV.MaxValueSize,
+			"ordered_range":    c.KV.OrderedRange,
+			"change_feed":      c.KV.ChangeFeed,
+			"conflict_detail":  c.KV.ConflictDetail,
+			"metered_requests": c.KV.MeteredRequests,
+		},
+		health.SetStore: {
+			"max_value_size":   c.Set.MaxValueSize,
+			"ordered_range":    c.Set.OrderedRange,
+			"metered_requests": c.Set.MeteredRequests,
+		},
+	}
+}
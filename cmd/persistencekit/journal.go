@@ -0,0 +1,325 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/journal"
+)
+
+// recordOutput is the JSON representation of a journal record.
+type recordOutput struct {
+	Position journal.Position `json:"position"`
+	Record   json.RawMessage  `json:"record"`
+}
+
+// boundsOutput is the JSON representation of a journal's bounds.
+type boundsOutput struct {
+	Begin journal.Position `json:"begin"`
+	End   journal.Position `json:"end"`
+}
+
+// withJournal calls fn with the journal with the given name.
+func (a *app) withJournal(
+	ctx context.Context,
+	name string,
+	fn func(journal.BinaryJournal) error,
+) error {
+	return a.withDriver(
+		ctx,
+		func(d driver.Driver) error {
+			j, err := d.JournalStore().Open(ctx, name)
+			if err != nil {
+				return err
+			}
+
+			return errors.Join(
+				fn(j),
+				j.Close(),
+			)
+		},
+	)
+}
+
+// printRecord writes a journal record to stdout.
+func (a *app) printRecord(pos journal.Position, rec []byte) error {
+	text, v, err := a.value(a.Values, rec)
+	if err != nil {
+		return fmt.Errorf("unable to format record at position %d: %w", pos, err)
+	}
+
+	return a.print(
+		recordOutput{pos, v},
+		fmt.Sprintf("%d\t%s", pos, text),
+	)
+}
+
+func (a *app) journalList(ctx context.Context, args []string) error {
+	if _, err := a.command("journal list").Parse(args); err != nil {
+		return err
+	}
+
+	return a.withDriver(
+		ctx,
+		func(d driver.Driver) error {
+			l, ok := d.JournalStore().(journal.NameLister)
+			if !ok {
+				return errors.New("the driver does not record the names of its journals")
+			}
+
+			names, err := l.Names(ctx)
+			if err != nil {
+				return err
+			}
+
+			return a.printNames(names)
+		},
+	)
+}
+
+func (a *app) journalBounds(ctx context.Context, args []string) error {
+	args, err := a.command("journal bounds", "journal").Parse(args)
+	if err != nil {
+		return err
+	}
+
+	return a.withJournal(
+		ctx,
+		args[0],
+		func(j journal.BinaryJournal) error {
+			bounds, err := j.Bounds(ctx)
+			if err != nil {
+				return err
+			}
+
+			return a.print(
+				boundsOutput{bounds.Begin, bounds.End},
+				bounds.String(),
+			)
+		},
+	)
+}
+
+func (a *app) journalGet(ctx context.Context, args []string) error {
+	args, err := a.command("journal get", "journal", "position").Parse(args)
+	if err != nil {
+		return err
+	}
+
+	pos, err := parseUint("position", args[1])
+	if err != nil {
+		return err
+	}
+
+	return a.withJournal(
+		ctx,
+		args[0],
+		func(j journal.BinaryJournal) error {
+			rec, err := j.Get(ctx, journal.Position(pos))
+			if err != nil {
+				return err
+			}
+			return a.printRecord(journal.Position(pos), rec)
+		},
+	)
+}
+
+func (a *app) journalRange(ctx context.Context, args []string) error {
+	cmd := a.command("journal range", "journal")
+	from := cmd.Flags.Int64("from", -1, "the position of the first record to show (default the beginning of the journal)")
+	limit := cmd.Flags.Uint64("limit", 0, "the maximum number of records to show (default no limit)")
+
+	args, err := cmd.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	return a.withJournal(
+		ctx,
+		args[0],
+		func(j journal.BinaryJournal) error {
+			bounds, err := j.Bounds(ctx)
+			if err != nil {
+				return err
+			}
+
+			begin := bounds.Begin
+			if *from >= 0 {
+				begin = journal.Position(*from)
+			}
+
+			end := bounds.End
+			if *limit != 0 {
+				end = min(end, begin+journal.Position(*limit))
+			}
+
+			return a.printRecords(ctx, j, begin, end)
+		},
+	)
+}
+
+// printRecords writes the records in the half-open interval [begin, end) to
+// stdout.
+func (a *app) printRecords(
+	ctx context.Context,
+	j journal.BinaryJournal,
+	begin, end journal.Position,
+) error {
+	if begin >= end {
+		return nil
+	}
+
+	return j.Range(
+		ctx,
+		begin,
+		func(_ context.Context, pos journal.Position, rec []byte) (bool, error) {
+			if err := a.printRecord(pos, rec); err != nil {
+				return false, err
+			}
+			return pos+1 < end, nil
+		},
+	)
+}
+
+func (a *app) journalTail(ctx context.Context, args []string) error {
+	cmd := a.command("journal tail", "journal")
+	n := cmd.Flags.Uint64("n", 10, "the number of records to show")
+	follow := cmd.Flags.Bool("follow", false, "continue to show records as they are appended, until interrupted")
+	interval := cmd.Flags.Duration("interval", time.Second, "the interval at which to poll for new records when using -follow")
+
+	args, err := cmd.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	return a.withJournal(
+		ctx,
+		args[0],
+		func(j journal.BinaryJournal) error {
+			bounds, err := j.Bounds(ctx)
+			if err != nil {
+				return err
+			}
+
+			begin := bounds.Begin
+			if bounds.Len() > int(*n) {
+				begin = bounds.End - journal.Position(*n)
+			}
+
+			if err := a.printRecords(ctx, j, begin, bounds.End); err != nil {
+				return err
+			}
+
+			if !*follow {
+				return nil
+			}
+
+			next := bounds.End
+
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(*interval):
+				}
+
+				bounds, err := j.Bounds(ctx)
+				if err != nil {
+					if ctx.Err() != nil {
+						return nil
+					}
+					return err
+				}
+
+				// Skip any records that were truncated since the last poll.
+				next = max(next, bounds.Begin)
+
+				if err := a.printRecords(ctx, j, next, bounds.End); err != nil {
+					if ctx.Err() != nil {
+						return nil
+					}
+					return err
+				}
+
+				next = max(next, bounds.End)
+			}
+		},
+	)
+}
+
+func (a *app) journalTruncate(ctx context.Context, args []string) error {
+	cmd := a.command("journal truncate", "journal", "position")
+	yes := cmd.Flags.Bool("yes", false, "truncate the journal without asking for confirmation")
+
+	args, err := cmd.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	pos, err := parseUint("position", args[1])
+	if err != nil {
+		return err
+	}
+
+	return a.withJournal(
+		ctx,
+		args[0],
+		func(j journal.BinaryJournal) error {
+			bounds, err := j.Bounds(ctx)
+			if err != nil {
+				return err
+			}
+
+			end := journal.Position(pos)
+
+			if end > bounds.End {
+				return fmt.Errorf("position %d is beyond the end of the journal %s", end, bounds)
+			}
+
+			if end <= bounds.Begin {
+				return nil
+			}
+
+			if !*yes {
+				if !a.confirm(
+					"truncate the %q journal to position %d, permanently removing %d record(s)?",
+					j.Name(),
+					end,
+					end-bounds.Begin,
+				) {
+					return errors.New("truncation aborted")
+				}
+			}
+
+			if err := j.Truncate(ctx, end); err != nil {
+				return err
+			}
+
+			return a.print(
+				boundsOutput{end, bounds.End},
+				journal.Interval{Begin: end, End: bounds.End}.String(),
+			)
+		},
+	)
+}
+
+// confirm asks the user to confirm an operation, and returns true if they
+// answer "y" or "yes".
+func (a *app) confirm(format string, args ...any) bool {
+	fmt.Fprintf(a.Stderr, format+" [y/N] ", args...)
+
+	line, _ := bufio.NewReader(a.Stdin).ReadString('\n')
+
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
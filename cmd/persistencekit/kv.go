@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/kv"
+)
+
+// pairOutput is the JSON representation of a key/value pair.
+type pairOutput struct {
+	Key      json.RawMessage `json:"key"`
+	Value    json.RawMessage `json:"value,omitempty"`
+	Revision kv.Revision     `json:"revision"`
+}
+
+// withKeyspace calls fn with the keyspace with the given name.
+func (a *app) withKeyspace(
+	ctx context.Context,
+	name string,
+	fn func(kv.BinaryKeyspace) error,
+) error {
+	return a.withDriver(
+		ctx,
+		func(d driver.Driver) error {
+			ks, err := d.KVStore().Open(ctx, name)
+			if err != nil {
+				return err
+			}
+
+			return errors.Join(
+				fn(ks),
+				ks.Close(),
+			)
+		},
+	)
+}
+
+// printPair writes a key/value pair to stdout. If keysOnly is true, the value
+// is omitted.
+func (a *app) printPair(k, v []byte, r kv.Revision, keysOnly bool) error {
+	keyText, key, err := a.value(a.Keys, k)
+	if err != nil {
+		return fmt.Errorf("unable to format key: %w", err)
+	}
+
+	if keysOnly {
+		return a.print(
+			pairOutput{Key: key, Revision: r},
+			keyText,
+		)
+	}
+
+	valueText, value, err := a.value(a.Values, v)
+	if err != nil {
+		return fmt.Errorf("unable to format value of key %q: %w", keyText, err)
+	}
+
+	return a.print(
+		pairOutput{key, value, r},
+		fmt.Sprintf("%s\t%s", keyText, valueText),
+	)
+}
+
+func (a *app) kvList(ctx context.Context, args []string) error {
+	if _, err := a.command("kv list").Parse(args); err != nil {
+		return err
+	}
+
+	return a.withDriver(
+		ctx,
+		func(d driver.Driver) error {
+			l, ok := d.KVStore().(kv.NameLister)
+			if !ok {
+				return errors.New("the driver does not record the names of its keyspaces")
+			}
+
+			names, err := l.Names(ctx)
+			if err != nil {
+				return err
+			}
+
+			return a.printNames(names)
+		},
+	)
+}
+
+func (a *app) kvGet(ctx context.Context, args []string) error {
+	args, err := a.command("kv get", "keyspace", "key").Parse(args)
+	if err != nil {
+		return err
+	}
+
+	k, err := a.Keys.Encode(args[1])
+	if err != nil {
+		return usagef("invalid key: %s", err)
+	}
+
+	return a.withKeyspace(
+		ctx,
+		args[0],
+		func(ks kv.BinaryKeyspace) error {
+			v, r, err := ks.Get(ctx, k)
+			if err != nil {
+				return err
+			}
+
+			if r == "" {
+				return fmt.Errorf("key %q is not present in the %q keyspace", args[1], ks.Name())
+			}
+
+			_, key, err := a.value(a.Keys, k)
+			if err != nil {
+				return fmt.Errorf("unable to format key: %w", err)
+			}
+
+			text, value, err := a.value(a.Values, v)
+			if err != nil {
+				return fmt.Errorf("unable to format value: %w", err)
+			}
+
+			return a.print(pairOutput{key, value, r}, text)
+		},
+	)
+}
+
+func (a *app) kvSet(ctx context.Context, args []string) error {
+	cmd := a.command("kv set", "keyspace", "key", "value")
+	rev := cmd.Flags.String("revision", "", "the expected current revision of the key, which makes the change conditional (default unconditional)")
+
+	args, err := cmd.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	k, err := a.Keys.Encode(args[1])
+	if err != nil {
+		return usagef("invalid key: %s", err)
+	}
+
+	v, err := a.Values.Encode(args[2])
+	if err != nil {
+		return usagef("invalid value: %s", err)
+	}
+
+	if len(v) == 0 {
+		return usagef("the value must not be empty, use \"kv delete\" to delete a key")
+	}
+
+	return a.withKeyspace(
+		ctx,
+		args[0],
+		func(ks kv.BinaryKeyspace) error {
+			return a.setKey(ctx, ks, k, v, *rev)
+		},
+	)
+}
+
+func (a *app) kvDelete(ctx context.Context, args []string) error {
+	cmd := a.command("kv delete", "keyspace", "key")
+	rev := cmd.Flags.String("revision", "", "the expected current revision of the key, which makes the change conditional (default unconditional)")
+
+	args, err := cmd.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	k, err := a.Keys.Encode(args[1])
+	if err != nil {
+		return usagef("invalid key: %s", err)
+	}
+
+	return a.withKeyspace(
+		ctx,
+		args[0],
+		func(ks kv.BinaryKeyspace) error {
+			return a.setKey(ctx, ks, k, nil, *rev)
+		},
+	)
+}
+
+// setKey associates v with k, and writes the new revision to stdout. If rev
+// is non-empty, the change is conditional on the key's current revision.
+func (a *app) setKey(
+	ctx context.Context,
+	ks kv.BinaryKeyspace,
+	k, v []byte,
+	rev string,
+) error {
+	var (
+		r   kv.Revision
+		err error
+	)
+
+	if rev != "" {
+		r, err = ks.Set(ctx, k, v, kv.Revision(rev))
+	} else {
+		err = ks.SetUnconditional(ctx, k, v)
+		if err == nil {
+			_, r, err = ks.Get(ctx, k)
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, key, err := a.value(a.Keys, k)
+	if err != nil {
+		return fmt.Errorf("unable to format key: %w", err)
+	}
+
+	return a.print(
+		pairOutput{Key: key, Revision: r},
+		string(r),
+	)
+}
+
+func (a *app) kvRange(ctx context.Context, args []string) error {
+	cmd := a.command("kv range", "keyspace")
+	keysOnly := cmd.Flags.Bool("keys-only", false, "show only the keys")
+	limit := cmd.Flags.Uint64("limit", 0, "the maximum number of pairs to show (default no limit)")
+
+	args, err := cmd.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	return a.withKeyspace(
+		ctx,
+		args[0],
+		func(ks kv.BinaryKeyspace) error {
+			var count uint64
+
+			if *keysOnly {
+				return kv.RangeKeys(
+					ctx,
+					ks,
+					func(_ context.Context, k []byte, r kv.Revision) (bool, error) {
+						count++
+						return *limit == 0 || count < *limit, a.printPair(k, nil, r, true)
+					},
+				)
+			}
+
+			return ks.Range(
+				ctx,
+				func(_ context.Context, k, v []byte, r kv.Revision) (bool, error) {
+					count++
+					return *limit == 0 || count < *limit, a.printPair(k, v, r, false)
+				},
+			)
+		},
+	)
+}
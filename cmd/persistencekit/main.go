@@ -0,0 +1,359 @@
+// Command persistencekit inspects and operates on the stores of a persistence
+// driver.
+//
+// The driver is identified by a URL, as accepted by [persistencekit.ParseURL],
+// which is read from the -url flag or the PERSISTENCEKIT_URL environment
+// variable. Run "persistencekit help" for a list of commands.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/dogmatiq/persistencekit"
+	"github.com/dogmatiq/persistencekit/driver"
+)
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	err := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+	case errors.As(err, &usageError{}):
+		fmt.Fprintf(os.Stderr, "persistencekit: %s\n", err)
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "persistencekit: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+const usage = `usage: persistencekit [flags] <command> [arguments]
+
+Commands:
+  health                           show the health and capabilities of each store
+  provision                        create the infrastructure used by each store
+  deprovision                      delete the infrastructure used by each store
+
+  journal list                     show the names of the journals
+  journal bounds <journal>         show the bounds of a journal
+  journal get <journal> <pos>      show the record at a position
+  journal range <journal>          show the records in a journal
+  journal tail <journal>           show the most recent records in a journal
+  journal truncate <journal> <pos> remove the records before a position
+
+  kv list                          show the names of the keyspaces
+  kv get <keyspace> <key>          show the value associated with a key
+  kv set <keyspace> <key> <value>  associate a value with a key
+  kv delete <keyspace> <key>       delete a key
+  kv range <keyspace>              show the key/value pairs in a keyspace
+
+  set list                         show the names of the sets
+  set has <set> <member>           show whether a value is a member of a set
+  set add <set> <member>           add a member to a set
+  set remove <set> <member>        remove a member from a set
+  set range <set>                  show the members of a set
+
+Only some drivers, such as the postgres and memory drivers, record the names of
+their journals, keyspaces and sets. The list commands fail for other drivers,
+in which case the names must be known in advance. Run "persistencekit <command> -h" for the flags
+accepted by each command.
+
+Flags:
+`
+
+// usageError is an error caused by invalid command-line arguments.
+type usageError struct {
+	Message string
+}
+
+func (e usageError) Error() string {
+	return e.Message
+}
+
+func usagef(format string, args ...any) error {
+	return usageError{fmt.Sprintf(format, args...)}
+}
+
+// app is the state shared by each command.
+type app struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// URL is the URL of the driver.
+	URL string
+
+	// JSON is true if output is written as JSON, one value per line.
+	JSON bool
+
+	// Keys is the format of keyspace keys.
+	Keys format
+
+	// Values is the format of journal records, keyspace values and set
+	// members.
+	Values format
+}
+
+// run executes the command described by args.
+func run(
+	ctx context.Context,
+	args []string,
+	stdin io.Reader,
+	stdout, stderr io.Writer,
+) error {
+	a := &app{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
+	}
+
+	var keys, values formatOptions
+
+	flags := flag.NewFlagSet("persistencekit", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(&a.URL, "url", os.Getenv("PERSISTENCEKIT_URL"), "the driver URL (default $PERSISTENCEKIT_URL)")
+	flags.BoolVar(&a.JSON, "json", false, "write output as JSON, one value per line")
+	flags.StringVar(&values.Name, "format", "raw", "the format of records, values and members: raw, hex, json or proto")
+	flags.StringVar(&keys.Name, "key-format", "raw", "the format of keys: raw, hex or json")
+	flags.StringVar(&values.DescriptorSet, "descriptor-set", "", "a protocol buffers FileDescriptorSet file, used by the proto format")
+	flags.StringVar(&values.Message, "message", "", "the full name of the protocol buffers message type, used by the proto format")
+
+	if err := flags.Parse(args); err != nil {
+		return flagError(err)
+	}
+
+	if keys.Name == "proto" {
+		return usagef("the proto format is not supported for keys")
+	}
+
+	var err error
+
+	if a.Keys, err = newFormat(keys); err != nil {
+		return usageError{err.Error()}
+	}
+
+	if a.Values, err = newFormat(values); err != nil {
+		return usageError{err.Error()}
+	}
+
+	args = flags.Args()
+	if len(args) == 0 {
+		flags.Usage()
+		return usagef("no command specified")
+	}
+
+	commands := map[string]map[string]func(context.Context, []string) error{
+		"health": {
+			"": a.health,
+		},
+		"provision": {
+			"": a.provision,
+		},
+		"deprovision": {
+			"": a.deprovision,
+		},
+		"journal": {
+			"list":     a.journalList,
+			"bounds":   a.journalBounds,
+			"get":      a.journalGet,
+			"range":    a.journalRange,
+			"tail":     a.journalTail,
+			"truncate": a.journalTruncate,
+		},
+		"kv": {
+			"list":   a.kvList,
+			"get":    a.kvGet,
+			"set":    a.kvSet,
+			"delete": a.kvDelete,
+			"range":  a.kvRange,
+		},
+		"set": {
+			"list":   a.setList,
+			"has":    a.setHas,
+			"add":    a.setAdd,
+			"remove": a.setRemove,
+			"range":  a.setRange,
+		},
+	}
+
+	if args[0] == "help" {
+		flags.Usage()
+		return nil
+	}
+
+	group, ok := commands[args[0]]
+	if !ok {
+		return usagef("unknown command %q", args[0])
+	}
+
+	if fn, ok := group[""]; ok {
+		return fn(ctx, args[1:])
+	}
+
+	if len(args) < 2 {
+		return usagef("no %s command specified", args[0])
+	}
+
+	fn, ok := group[args[1]]
+	if !ok {
+		return usagef("unknown %s command %q", args[0], args[1])
+	}
+
+	return fn(ctx, args[2:])
+}
+
+// command parses the flags and positional arguments of a command.
+type command struct {
+	Name  string
+	Args  []string
+	Flags *flag.FlagSet
+}
+
+func (a *app) command(name string, args ...string) *command {
+	c := &command{
+		Name:  name,
+		Args:  args,
+		Flags: flag.NewFlagSet(name, flag.ContinueOnError),
+	}
+
+	c.Flags.SetOutput(a.Stderr)
+	c.Flags.Usage = func() {
+		fmt.Fprintf(a.Stderr, "usage: persistencekit %s", name)
+		if hasFlags(c.Flags) {
+			fmt.Fprint(a.Stderr, " [flags]")
+		}
+		for _, arg := range args {
+			fmt.Fprintf(a.Stderr, " <%s>", arg)
+		}
+		fmt.Fprintln(a.Stderr)
+		c.Flags.PrintDefaults()
+	}
+
+	return c
+}
+
+// Parse parses args, and returns the positional arguments.
+func (c *command) Parse(args []string) ([]string, error) {
+	if err := c.Flags.Parse(args); err != nil {
+		return nil, flagError(err)
+	}
+
+	if c.Flags.NArg() != len(c.Args) {
+		c.Flags.Usage()
+		return nil, usagef("%s: expected %d argument(s), got %d", c.Name, len(c.Args), c.Flags.NArg())
+	}
+
+	return c.Flags.Args(), nil
+}
+
+// flagError returns the error to report when flags cannot be parsed. The
+// flag package has already written the details to stderr.
+func flagError(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	return usageError{err.Error()}
+}
+
+func hasFlags(f *flag.FlagSet) bool {
+	has := false
+	f.VisitAll(func(*flag.Flag) { has = true })
+	return has
+}
+
+// driver returns a driver connected to the backend identified by the -url
+// flag. The caller must close the driver.
+func (a *app) driver(ctx context.Context) (driver.Driver, error) {
+	if a.URL == "" {
+		return nil, usagef("no driver URL specified, use -url or set $PERSISTENCEKIT_URL")
+	}
+
+	cfg, err := persistencekit.ParseURL(ctx, a.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.NewDriver(ctx)
+}
+
+// withDriver calls fn with a driver connected to the backend identified by
+// the -url flag.
+func (a *app) withDriver(ctx context.Context, fn func(driver.Driver) error) error {
+	d, err := a.driver(ctx)
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(
+		fn(d),
+		d.Close(),
+	)
+}
+
+// print writes v to stdout as a line of JSON if the -json flag is set,
+// otherwise it writes text as a line of human-readable output.
+func (a *app) print(v any, text string) error {
+	if a.JSON {
+		enc := json.NewEncoder(a.Stdout)
+		enc.SetEscapeHTML(false)
+		return enc.Encode(v)
+	}
+
+	_, err := fmt.Fprintln(a.Stdout, text)
+	return err
+}
+
+// nameOutput is the JSON representation of the name of a journal, keyspace or
+// set.
+type nameOutput struct {
+	Name string `json:"name"`
+}
+
+// printNames writes each of the given names to stdout.
+func (a *app) printNames(names []string) error {
+	for _, n := range names {
+		if err := a.print(nameOutput{n}, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// value returns the human-readable and JSON representations of data using
+// the format f.
+func (a *app) value(f format, data []byte) (string, json.RawMessage, error) {
+	if a.JSON {
+		v, err := f.JSON(data)
+		return "", v, err
+	}
+
+	v, err := f.Text(data)
+	return v, nil, err
+}
+
+// parseUint parses a non-negative integer argument.
+func parseUint(name, s string) (uint64, error) {
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, usagef("invalid %s %q, expected a non-negative integer", name, s)
+	}
+	return n, nil
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/driver/memory"
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+	"github.com/dogmatiq/persistencekit/journal"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestRun(t *testing.T) {
+	setup := func(t *testing.T) (string, func(stdin string, args ...string) (string, error)) {
+		t.Helper()
+
+		silo := xtesting.UniqueName("cli-test")
+		url := "memory:///" + silo
+
+		return silo, func(stdin string, args ...string) (string, error) {
+			t.Helper()
+
+			var stdout, stderr bytes.Buffer
+			err := run(
+				t.Context(),
+				append([]string{"-url", url}, args...),
+				strings.NewReader(stdin),
+				&stdout,
+				&stderr,
+			)
+			return stdout.String(), err
+		}
+	}
+
+	mustRun := func(t *testing.T, exec func(string, ...string) (string, error), args ...string) string {
+		t.Helper()
+
+		out, err := exec("", args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	expect := func(t *testing.T, got, want string) {
+		t.Helper()
+
+		if got != want {
+			t.Fatalf("unexpected output:\ngot:\n%s\nwant:\n%s", got, want)
+		}
+	}
+
+	t.Run("journal", func(t *testing.T) {
+		silo, exec := setup(t)
+
+		j, err := memory.New(&memory.Config{Silo: silo}).JournalStore().Open(t.Context(), "j")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer j.Close()
+
+		for i, rec := range []string{"zero", "one", "two", "three"} {
+			if err := j.Append(t.Context(), journal.Position(i), []byte(rec)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		t.Run("list", func(t *testing.T) {
+			expect(t, mustRun(t, exec, "journal", "list"), "j\n")
+			expect(t, mustRun(t, exec, "-json", "journal", "list"), `{"name":"j"}`+"\n")
+		})
+
+		t.Run("bounds", func(t *testing.T) {
+			expect(t, mustRun(t, exec, "journal", "bounds", "j"), "[0, 4)\n")
+			expect(t, mustRun(t, exec, "-json", "journal", "bounds", "j"), `{"begin":0,"end":4}`+"\n")
+		})
+
+		t.Run("get", func(t *testing.T) {
+			expect(t, mustRun(t, exec, "journal", "get", "j", "2"), "2\ttwo\n")
+			expect(t, mustRun(t, exec, "-format", "hex", "journal", "get", "j", "1"), "1\t6f6e65\n")
+		})
+
+		t.Run("range", func(t *testing.T) {
+			expect(t, mustRun(t, exec, "journal", "range", "-from", "1", "-limit", "2", "j"), "1\tone\n2\ttwo\n")
+			expect(t, mustRun(t, exec, "-json", "journal", "range", "-from", "3", "j"), `{"position":3,"record":"three"}`+"\n")
+		})
+
+		t.Run("tail", func(t *testing.T) {
+			expect(t, mustRun(t, exec, "journal", "tail", "-n", "2", "j"), "2\ttwo\n3\tthree\n")
+		})
+
+		t.Run("truncate", func(t *testing.T) {
+			if _, err := exec("n\n", "journal", "truncate", "j", "1"); err == nil || err.Error() != "truncation aborted" {
+				t.Fatalf("unexpected error: got %v, want truncation aborted", err)
+			}
+
+			if _, err := exec("yes\n", "journal", "truncate", "j", "1"); err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, mustRun(t, exec, "journal", "truncate", "-yes", "j", "2"), "[2, 4)\n")
+			expect(t, mustRun(t, exec, "journal", "bounds", "j"), "[2, 4)\n")
+		})
+	})
+
+	t.Run("kv", func(t *testing.T) {
+		_, exec := setup(t)
+
+		mustRun(t, exec, "-format", "json", "kv", "set", "ks", "<key>", `{ "a": 1 }`)
+		expect(t, mustRun(t, exec, "-format", "json", "kv", "get", "ks", "<key>"), `{"a":1}`+"\n")
+
+		rev := strings.TrimSpace(mustRun(t, exec, "-json", "kv", "get", "ks", "<key>"))
+		if !strings.Contains(rev, `"key":"<key>"`) {
+			t.Fatalf("unexpected JSON output: %s", rev)
+		}
+
+		if _, err := exec("", "kv", "set", "-revision", "<stale>", "ks", "<key>", "<value>"); err == nil {
+			t.Fatal("expected a conflict error")
+		}
+
+		mustRun(t, exec, "-key-format", "hex", "kv", "set", "ks", "00ff", "<binary>")
+		keys := strings.Fields(mustRun(t, exec, "-key-format", "hex", "kv", "range", "-keys-only", "ks"))
+		slices.Sort(keys)
+		expect(t, strings.Join(keys, " "), "00ff 3c6b65793e")
+		expect(t, mustRun(t, exec, "kv", "list"), "ks\n")
+
+		mustRun(t, exec, "kv", "delete", "ks", "<key>")
+		if _, err := exec("", "kv", "get", "ks", "<key>"); err == nil {
+			t.Fatal("expected an error for a missing key")
+		}
+	})
+
+	t.Run("proto", func(t *testing.T) {
+		_, exec := setup(t)
+
+		data, err := proto.Marshal(&descriptorpb.FileDescriptorSet{
+			File: []*descriptorpb.FileDescriptorProto{
+				protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto),
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		file := filepath.Join(t.TempDir(), "descriptors.pb")
+		if err := os.WriteFile(file, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		flags := []string{
+			"-format", "proto",
+			"-descriptor-set", file,
+			"-message", "google.protobuf.FileDescriptorProto",
+		}
+
+		mustRun(t, exec, append(flags, "kv", "set", "ks", "<key>", `{"name": "<name>"}`)...)
+		expect(t, mustRun(t, exec, append(flags, "kv", "get", "ks", "<key>")...), `{"name":"<name>"}`+"\n")
+		expect(t, mustRun(t, exec, "-format", "hex", "kv", "get", "ks", "<key>"), "0a063c6e616d653e\n")
+	})
+
+	t.Run("set", func(t *testing.T) {
+		_, exec := setup(t)
+
+		expect(t, mustRun(t, exec, "set", "add", "s", "<member>"), "true\n")
+		expect(t, mustRun(t, exec, "set", "add", "s", "<member>"), "false\n")
+		expect(t, mustRun(t, exec, "set", "has", "s", "<member>"), "true\n")
+		expect(t, mustRun(t, exec, "set", "list"), "s\n")
+		expect(t, mustRun(t, exec, "-json", "set", "range", "s"), `{"member":"<member>"}`+"\n")
+		expect(t, mustRun(t, exec, "-json", "set", "remove", "s", "<member>"), `{"changed":true}`+"\n")
+		expect(t, mustRun(t, exec, "set", "has", "s", "<member>"), "false\n")
+	})
+
+	t.Run("health", func(t *testing.T) {
+		_, exec := setup(t)
+
+		out := mustRun(t, exec, "health")
+		if !strings.HasPrefix(out, "journal\thealthy\t") {
+			t.Fatalf("unexpected output: %s", out)
+		}
+	})
+
+	t.Run("provision", func(t *testing.T) {
+		_, exec := setup(t)
+
+		expect(t, mustRun(t, exec, "provision", "-dry-run"), "")
+		expect(t, mustRun(t, exec, "provision"), "")
+		expect(t, mustRun(t, exec, "deprovision", "-dry-run"), "")
+		expect(t, mustRun(t, exec, "deprovision"), "")
+	})
+
+	t.Run("it returns a usage error for invalid arguments", func(t *testing.T) {
+		_, exec := setup(t)
+
+		cases := [][]string{
+			{},
+			{"unknown"},
+			{"journal"},
+			{"journal", "unknown"},
+			{"journal", "get", "name"},
+			{"journal", "get", "name", "-1"},
+			{"-format", "unknown", "health"},
+			{"-key-format", "proto", "health"},
+			{"-format", "proto", "health"},
+			{"-key-format", "hex", "kv", "get", "ks", "zz"},
+			{"kv", "range", "-unknown", "ks"},
+			{"provision", "unexpected"},
+		}
+
+		for _, args := range cases {
+			_, err := exec("", args...)
+			if !errors.As(err, &usageError{}) {
+				t.Fatalf("%q: unexpected error: got %v, want a usage error", args, err)
+			}
+		}
+	})
+}
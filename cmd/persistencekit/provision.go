@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/provision"
+)
+
+// planOutput is the JSON representation of a planned change to a backend
+// resource.
+type planOutput struct {
+	Action   provision.Action `json:"action"`
+	Resource string           `json:"resource"`
+	Detail   string           `json:"detail,omitempty"`
+}
+
+// printPlan writes the changes in a plan to stdout.
+func (a *app) printPlan(plan provision.Plan) error {
+	if plan.Empty() && !a.JSON {
+		_, err := fmt.Fprintln(a.Stderr, "no changes")
+		return err
+	}
+
+	for _, c := range plan.Changes {
+		text := c.String()
+		if c.Detail != "" {
+			text += "\n" + indent(strings.TrimSuffix(c.Detail, "\n"))
+		}
+
+		if err := a.print(
+			planOutput{c.Action, c.Resource, c.Detail},
+			text,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *app) provision(ctx context.Context, args []string) error {
+	cmd := a.command("provision")
+	dryRun := cmd.Flags.Bool("dry-run", false, "show the changes that would be made without applying them")
+
+	if _, err := cmd.Parse(args); err != nil {
+		return err
+	}
+
+	return a.withDriver(
+		ctx,
+		func(d driver.Driver) error {
+			plan, err := provision.PlanProvision(ctx, d)
+			if err != nil {
+				// Drivers that cannot plan their changes can still be
+				// provisioned.
+				if *dryRun || !errors.Is(err, provision.ErrUnsupported) {
+					return err
+				}
+			} else if err := a.printPlan(plan); err != nil {
+				return err
+			}
+
+			if *dryRun {
+				return nil
+			}
+
+			return provision.Provision(ctx, d)
+		},
+	)
+}
+
+func (a *app) deprovision(ctx context.Context, args []string) error {
+	cmd := a.command("deprovision")
+	dryRun := cmd.Flags.Bool("dry-run", false, "show the changes that would be made without applying them")
+	yes := cmd.Flags.Bool("yes", false, "deprovision the driver without asking for confirmation")
+
+	if _, err := cmd.Parse(args); err != nil {
+		return err
+	}
+
+	return a.withDriver(
+		ctx,
+		func(d driver.Driver) error {
+			plan, err := provision.PlanDeprovision(ctx, d)
+			if err != nil {
+				return err
+			}
+
+			if err := a.printPlan(plan); err != nil {
+				return err
+			}
+
+			if *dryRun || plan.Empty() {
+				return nil
+			}
+
+			if !*yes {
+				if !a.confirm(
+					"permanently delete the %d resource(s) listed above, and all of the data they contain?",
+					len(plan.Changes),
+				) {
+					return errors.New("deprovisioning aborted")
+				}
+			}
+
+			return provision.Deprovision(ctx, d)
+		},
+	)
+}
+
+// indent indents each line of s.
+func indent(s string) string {
+	return "    " + strings.ReplaceAll(s, "\n", "\n    ")
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/set"
+)
+
+// memberOutput is the JSON representation of a set member.
+type memberOutput struct {
+	Member json.RawMessage `json:"member"`
+}
+
+// changeOutput is the JSON representation of the result of an operation that
+// may or may not modify a store.
+type changeOutput struct {
+	Changed bool `json:"changed"`
+}
+
+// presenceOutput is the JSON representation of the result of a membership
+// test.
+type presenceOutput struct {
+	Present bool `json:"present"`
+}
+
+// withSet calls fn with the set with the given name.
+func (a *app) withSet(
+	ctx context.Context,
+	name string,
+	fn func(set.BinarySet) error,
+) error {
+	return a.withDriver(
+		ctx,
+		func(d driver.Driver) error {
+			s, err := d.SetStore().Open(ctx, name)
+			if err != nil {
+				return err
+			}
+
+			return errors.Join(
+				fn(s),
+				s.Close(),
+			)
+		},
+	)
+}
+
+// member parses the set name and member from the positional arguments of a
+// set command.
+func (a *app) member(name string, args []string) (string, []byte, error) {
+	args, err := a.command(name, "set", "member").Parse(args)
+	if err != nil {
+		return "", nil, err
+	}
+
+	v, err := a.Values.Encode(args[1])
+	if err != nil {
+		return "", nil, usagef("invalid member: %s", err)
+	}
+
+	return args[0], v, nil
+}
+
+func (a *app) setList(ctx context.Context, args []string) error {
+	if _, err := a.command("set list").Parse(args); err != nil {
+		return err
+	}
+
+	return a.withDriver(
+		ctx,
+		func(d driver.Driver) error {
+			l, ok := d.SetStore().(set.NameLister)
+			if !ok {
+				return errors.New("the driver does not record the names of its sets")
+			}
+
+			names, err := l.Names(ctx)
+			if err != nil {
+				return err
+			}
+
+			return a.printNames(names)
+		},
+	)
+}
+
+func (a *app) setHas(ctx context.Context, args []string) error {
+	name, v, err := a.member("set has", args)
+	if err != nil {
+		return err
+	}
+
+	return a.withSet(
+		ctx,
+		name,
+		func(s set.BinarySet) error {
+			ok, err := s.Has(ctx, v)
+			if err != nil {
+				return err
+			}
+			return a.print(presenceOutput{ok}, strconv.FormatBool(ok))
+		},
+	)
+}
+
+func (a *app) setAdd(ctx context.Context, args []string) error {
+	name, v, err := a.member("set add", args)
+	if err != nil {
+		return err
+	}
+
+	return a.withSet(
+		ctx,
+		name,
+		func(s set.BinarySet) error {
+			ok, err := s.TryAdd(ctx, v)
+			if err != nil {
+				return err
+			}
+			return a.print(changeOutput{ok}, strconv.FormatBool(ok))
+		},
+	)
+}
+
+func (a *app) setRemove(ctx context.Context, args []string) error {
+	name, v, err := a.member("set remove", args)
+	if err != nil {
+		return err
+	}
+
+	return a.withSet(
+		ctx,
+		name,
+		func(s set.BinarySet) error {
+			ok, err := s.TryRemove(ctx, v)
+			if err != nil {
+				return err
+			}
+			return a.print(changeOutput{ok}, strconv.FormatBool(ok))
+		},
+	)
+}
+
+func (a *app) setRange(ctx context.Context, args []string) error {
+	cmd := a.command("set range", "set")
+	limit := cmd.Flags.Uint64("limit", 0, "the maximum number of members to show (default no limit)")
+
+	args, err := cmd.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	return a.withSet(
+		ctx,
+		args[0],
+		func(s set.BinarySet) error {
+			var count uint64
+
+			return s.Range(
+				ctx,
+				func(_ context.Context, v []byte) (bool, error) {
+					text, member, err := a.value(a.Values, v)
+					if err != nil {
+						return false, fmt.Errorf("unable to format member: %w", err)
+					}
+
+					count++
+					return *limit == 0 || count < *limit, a.print(memberOutput{member}, text)
+				},
+			)
+		},
+	)
+}
@@ -9,13 +9,21 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/dogmatiq/persistencekit/blob"
 	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamoblob"
 	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamojournal"
 	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamokv"
+	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamolease"
+	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamoqueue"
+	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamoschedule"
 	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamoset"
 	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xaws"
 	"github.com/dogmatiq/persistencekit/journal"
 	"github.com/dogmatiq/persistencekit/kv"
+	"github.com/dogmatiq/persistencekit/lease"
+	"github.com/dogmatiq/persistencekit/queue"
+	"github.com/dogmatiq/persistencekit/schedule"
 	"github.com/dogmatiq/persistencekit/set"
 )
 
@@ -51,7 +59,8 @@ type Config struct {
 	ClientOptions []func(*awsdynamodb.Options)
 
 	// TablePrefix is the prefix for DynamoDB table names. Each primitive uses a
-	// separate table ("<prefix>-journal", "<prefix>-kv", "<prefix>-set").
+	// separate table ("<prefix>-journal", "<prefix>-kv", "<prefix>-set",
+	// "<prefix>-lease", "<prefix>-queue", "<prefix>-schedule", "<prefix>-blob").
 	TablePrefix string
 }
 
@@ -69,7 +78,8 @@ func (c *Config) NewDriver(context.Context) (driver.Driver, error) {
 //
 // The table prefix is prepended to the names of each DynamoDB table. Each
 // primitive uses a separate table ("<prefix>-journal", "<prefix>-kv",
-// "<prefix>-set"). If a host is specified, it is used as a custom endpoint.
+// "<prefix>-set", "<prefix>-lease", "<prefix>-queue", "<prefix>-schedule",
+// "<prefix>-blob"). If a host is specified, it is used as a custom endpoint.
 //
 // Supported query parameters:
 //   - region: AWS region (e.g. "us-east-1"); if omitted, resolved from the environment
@@ -122,6 +132,32 @@ func (d *Driver) SetStore() set.BinaryStore {
 	return dynamoset.NewBinaryStore(d.client, d.tablePrefix+"-set")
 }
 
+// LeaseStore returns a lease store backed by DynamoDB.
+func (d *Driver) LeaseStore() lease.Store {
+	return dynamolease.NewStore(d.client, d.tablePrefix+"-lease")
+}
+
+// QueueStore returns a queue store backed by DynamoDB.
+func (d *Driver) QueueStore() queue.BinaryStore {
+	return dynamoqueue.NewBinaryStore(d.client, d.tablePrefix+"-queue")
+}
+
+// ScheduleStore returns a schedule store backed by DynamoDB.
+func (d *Driver) ScheduleStore() schedule.BinaryStore {
+	return dynamoschedule.NewBinaryStore(d.client, d.tablePrefix+"-schedule")
+}
+
+// BlobStore returns a blob store backed by DynamoDB.
+func (d *Driver) BlobStore() blob.Store {
+	return dynamoblob.NewStore(d.client, d.tablePrefix+"-blob")
+}
+
+// Capabilities returns the limits and optional features of the driver's
+// stores.
+func (d *Driver) Capabilities() driver.Capabilities {
+	return driver.StoreCapabilities(d)
+}
+
 // Close is a no-op. The DynamoDB client does not require explicit cleanup.
 func (d *Driver) Close() error {
 	return nil
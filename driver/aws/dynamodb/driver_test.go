@@ -5,8 +5,12 @@ import (
 	"testing"
 
 	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb"
+	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamoblob"
 	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamojournal"
 	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamokv"
+	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamolease"
+	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamoqueue"
+	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamoschedule"
 	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamoset"
 	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xdynamodb"
 	"github.com/dogmatiq/persistencekit/internal/drivertest"
@@ -15,14 +19,18 @@ import (
 
 func TestNew(t *testing.T) {
 	var (
-		tablePrefix  = xtesting.UniqueName("new")
-		journalTable = tablePrefix + "-journal"
-		kvTable      = tablePrefix + "-kv"
-		setTable     = tablePrefix + "-set"
+		tablePrefix   = xtesting.UniqueName("new")
+		journalTable  = tablePrefix + "-journal"
+		kvTable       = tablePrefix + "-kv"
+		setTable      = tablePrefix + "-set"
+		leaseTable    = tablePrefix + "-lease"
+		queueTable    = tablePrefix + "-queue"
+		scheduleTable = tablePrefix + "-schedule"
+		blobTable     = tablePrefix + "-blob"
 	)
 
 	client, _ := xdynamodb.NewTestClient(t)
-	xdynamodb.CleanupTable(t, client, journalTable, kvTable, setTable)
+	xdynamodb.CleanupTable(t, client, journalTable, kvTable, setTable, leaseTable, queueTable, scheduleTable, blobTable)
 
 	d := dynamodb.NewFromClient(client, tablePrefix)
 	t.Cleanup(func() {
@@ -35,19 +43,121 @@ func TestNew(t *testing.T) {
 		dynamojournal.NewBinaryStore(client, journalTable),
 		dynamokv.NewBinaryStore(client, kvTable),
 		dynamoset.NewBinaryStore(client, setTable),
+		dynamolease.NewStore(client, leaseTable),
+		dynamoqueue.NewBinaryStore(client, queueTable),
+		dynamoschedule.NewBinaryStore(client, scheduleTable),
+		dynamoblob.NewStore(client, blobTable),
 	)
 }
 
+func TestCheckHealth(t *testing.T) {
+	tablePrefix := xtesting.UniqueName("health")
+
+	client, _ := xdynamodb.NewTestClient(t)
+	xdynamodb.CleanupTable(
+		t,
+		client,
+		tablePrefix+"-journal",
+		tablePrefix+"-kv",
+		tablePrefix+"-set",
+		tablePrefix+"-lease",
+		tablePrefix+"-queue",
+		tablePrefix+"-schedule",
+		tablePrefix+"-blob",
+	)
+
+	d := dynamodb.NewFromClient(client, tablePrefix)
+	t.Cleanup(func() {
+		d.Close()
+	})
+
+	drivertest.RunHealthTests(t, d)
+}
+
+func TestProvision(t *testing.T) {
+	tablePrefix := xtesting.UniqueName("provision")
+
+	client, _ := xdynamodb.NewTestClient(t)
+	xdynamodb.CleanupTable(
+		t,
+		client,
+		tablePrefix+"-journal",
+		tablePrefix+"-kv",
+		tablePrefix+"-set",
+		tablePrefix+"-lease",
+		tablePrefix+"-queue",
+		tablePrefix+"-schedule",
+		tablePrefix+"-blob",
+	)
+
+	d := dynamodb.NewFromClient(client, tablePrefix)
+	t.Cleanup(func() {
+		d.Close()
+	})
+
+	drivertest.RunProvisionTests(t, d)
+}
+
+func TestBackup(t *testing.T) {
+	client, _ := xdynamodb.NewTestClient(t)
+
+	var drivers []*dynamodb.Driver
+	for _, tablePrefix := range []string{
+		xtesting.UniqueName("backup-source"),
+		xtesting.UniqueName("backup-target"),
+	} {
+		xdynamodb.CleanupTable(
+			t,
+			client,
+			tablePrefix+"-journal",
+			tablePrefix+"-kv",
+			tablePrefix+"-set",
+		)
+
+		d := dynamodb.NewFromClient(client, tablePrefix)
+		t.Cleanup(func() {
+			d.Close()
+		})
+
+		drivers = append(drivers, d)
+	}
+
+	drivertest.RunBackupTests(t, drivers[0], drivers[1])
+}
+
+func TestChangeFeed(t *testing.T) {
+	tablePrefix := xtesting.UniqueName("change-feed")
+
+	client, _ := xdynamodb.NewTestClient(t)
+	xdynamodb.CleanupTable(
+		t,
+		client,
+		tablePrefix+"-journal",
+		tablePrefix+"-kv",
+	)
+
+	d := dynamodb.NewFromClient(client, tablePrefix)
+	t.Cleanup(func() {
+		d.Close()
+	})
+
+	drivertest.RunChangeFeedTests(t, d)
+}
+
 func TestParseURL(t *testing.T) {
 	var (
-		tablePrefix  = xtesting.UniqueName("url")
-		journalTable = tablePrefix + "-journal"
-		kvTable      = tablePrefix + "-kv"
-		setTable     = tablePrefix + "-set"
+		tablePrefix   = xtesting.UniqueName("url")
+		journalTable  = tablePrefix + "-journal"
+		kvTable       = tablePrefix + "-kv"
+		setTable      = tablePrefix + "-set"
+		leaseTable    = tablePrefix + "-lease"
+		queueTable    = tablePrefix + "-queue"
+		scheduleTable = tablePrefix + "-schedule"
+		blobTable     = tablePrefix + "-blob"
 	)
 
 	client, endpoint := xdynamodb.NewTestClient(t)
-	xdynamodb.CleanupTable(t, client, journalTable, kvTable, setTable)
+	xdynamodb.CleanupTable(t, client, journalTable, kvTable, setTable, leaseTable, queueTable, scheduleTable, blobTable)
 
 	t.Setenv("AWS_ACCESS_KEY_ID", "id")
 	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
@@ -71,20 +181,28 @@ func TestParseURL(t *testing.T) {
 		dynamojournal.NewBinaryStore(client, journalTable),
 		dynamokv.NewBinaryStore(client, kvTable),
 		dynamoset.NewBinaryStore(client, setTable),
+		dynamolease.NewStore(client, leaseTable),
+		dynamoqueue.NewBinaryStore(client, queueTable),
+		dynamoschedule.NewBinaryStore(client, scheduleTable),
+		dynamoblob.NewStore(client, blobTable),
 	)
 }
 
 func TestFromURL(t *testing.T) {
 	t.Run("it returns a working driver", func(t *testing.T) {
 		var (
-			tablePrefix  = xtesting.UniqueName("fromurl")
-			journalTable = tablePrefix + "-journal"
-			kvTable      = tablePrefix + "-kv"
-			setTable     = tablePrefix + "-set"
+			tablePrefix   = xtesting.UniqueName("fromurl")
+			journalTable  = tablePrefix + "-journal"
+			kvTable       = tablePrefix + "-kv"
+			setTable      = tablePrefix + "-set"
+			leaseTable    = tablePrefix + "-lease"
+			queueTable    = tablePrefix + "-queue"
+			scheduleTable = tablePrefix + "-schedule"
+			blobTable     = tablePrefix + "-blob"
 		)
 
 		client, endpoint := xdynamodb.NewTestClient(t)
-		xdynamodb.CleanupTable(t, client, journalTable, kvTable, setTable)
+		xdynamodb.CleanupTable(t, client, journalTable, kvTable, setTable, leaseTable, queueTable, scheduleTable, blobTable)
 
 		t.Setenv("AWS_ACCESS_KEY_ID", "id")
 		t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
@@ -109,6 +227,10 @@ func TestFromURL(t *testing.T) {
 			dynamojournal.NewBinaryStore(client, journalTable),
 			dynamokv.NewBinaryStore(client, kvTable),
 			dynamoset.NewBinaryStore(client, setTable),
+			dynamolease.NewStore(client, leaseTable),
+			dynamoqueue.NewBinaryStore(client, queueTable),
+			dynamoschedule.NewBinaryStore(client, scheduleTable),
+			dynamoblob.NewStore(client, blobTable),
 		)
 	})
 
@@ -0,0 +1,355 @@
+package dynamoblob
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/dogmatiq/persistencekit/blob"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xaws"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xdynamodb"
+)
+
+// chunkSize is the maximum size of each chunk of blob content, in bytes. It is
+// chosen to keep each chunk item comfortably within DynamoDB's 400 KB item
+// size limit.
+const chunkSize = 350 << 10
+
+type container struct {
+	Client    *dynamodb.Client
+	Table     string
+	OnRequest func(any) []func(*dynamodb.Options)
+
+	name string
+
+	attr struct {
+		BlobKey   types.AttributeValueMemberS
+		UploadKey types.AttributeValueMemberS
+		Upload    types.AttributeValueMemberS
+		Seq       types.AttributeValueMemberN
+		Size      types.AttributeValueMemberN
+		Chunks    types.AttributeValueMemberN
+		Data      types.AttributeValueMemberB
+	}
+
+	request struct {
+		PutChunk    dynamodb.PutItemInput
+		DeleteChunk dynamodb.DeleteItemInput
+		Get         dynamodb.GetItemInput
+		Reference   dynamodb.UpdateItemInput
+		Release     dynamodb.UpdateItemInput
+		Delete      dynamodb.DeleteItemInput
+	}
+}
+
+func (c *container) Name() string {
+	return c.name
+}
+
+func (c *container) Put(ctx context.Context, r io.Reader) (_ blob.Ref, err error) {
+	uploadID := newUploadID()
+	c.attr.UploadKey.Value = uploadKey(uploadID)
+
+	var (
+		ref    blob.Ref
+		chunks uint64
+		data   = make([]byte, chunkSize)
+		hash   = sha256.New()
+	)
+
+	// Remove the uploaded chunks if the blob is not stored successfully, or
+	// if identical content is already stored.
+	discard := true
+	defer func() {
+		if discard {
+			if e := c.deleteChunks(context.WithoutCancel(ctx), uploadID, chunks); e != nil && err == nil {
+				err = e
+			}
+		}
+	}()
+
+	r = io.TeeReader(r, hash)
+
+	for {
+		n, err := io.ReadFull(r, data)
+		if n != 0 {
+			c.attr.Seq.Value = strconv.FormatUint(chunks, 10)
+			c.attr.Data.Value = data[:n]
+
+			if _, err := xaws.Do(
+				ctx,
+				c.Client.PutItem,
+				c.OnRequest,
+				&c.request.PutChunk,
+			); err != nil {
+				return blob.Ref{}, fmt.Errorf("unable to write blob chunk: %w", err)
+			}
+
+			chunks++
+			ref.Size += int64(n)
+		}
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return blob.Ref{}, err
+		}
+	}
+
+	hash.Sum(ref.Hash[:0])
+
+	c.attr.BlobKey.Value = blobKey(c.name, ref.Hash)
+	c.attr.Upload.Value = uploadID
+	c.attr.Size.Value = strconv.FormatInt(ref.Size, 10)
+	c.attr.Chunks.Value = strconv.FormatUint(chunks, 10)
+
+	out, err := xaws.Do(
+		ctx,
+		c.Client.UpdateItem,
+		c.OnRequest,
+		&c.request.Reference,
+	)
+	if err != nil {
+		return blob.Ref{}, fmt.Errorf("unable to reference blob: %w", err)
+	}
+
+	existing, err := xdynamodb.AsString(out.Attributes, uploadAttr)
+	if err != nil {
+		return blob.Ref{}, err
+	}
+
+	discard = existing != uploadID
+
+	return ref, nil
+}
+
+func (c *container) Get(ctx context.Context, h blob.Hash) (io.ReadCloser, bool, error) {
+	item, ok, err := c.get(ctx, h)
+	if !ok || err != nil {
+		return nil, false, err
+	}
+
+	uploadID, err := xdynamodb.AsString(item, uploadAttr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	chunks, err := xdynamodb.AsUint[uint64](item, chunksAttr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rd := &reader{
+		ctx:       ctx,
+		client:    c.Client,
+		onRequest: c.OnRequest,
+		chunks:    chunks,
+	}
+
+	rd.attr.UploadKey.Value = uploadKey(uploadID)
+	rd.request = dynamodb.GetItemInput{
+		TableName:      aws.String(c.Table),
+		ConsistentRead: aws.Bool(true),
+		Key: map[string]types.AttributeValue{
+			keyAttr: &rd.attr.UploadKey,
+			seqAttr: &rd.attr.Seq,
+		},
+	}
+
+	return rd, true, nil
+}
+
+func (c *container) Has(ctx context.Context, h blob.Hash) (bool, error) {
+	_, ok, err := c.get(ctx, h)
+	return ok, err
+}
+
+func (c *container) Release(ctx context.Context, h blob.Hash) error {
+	c.attr.BlobKey.Value = blobKey(c.name, h)
+
+	out, err := xaws.Do(
+		ctx,
+		c.Client.UpdateItem,
+		c.OnRequest,
+		&c.request.Release,
+	)
+	if errors.As(err, new(*types.ConditionalCheckFailedException)) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to release blob: %w", err)
+	}
+
+	refs, err := xdynamodb.AsUint[uint64](out.Attributes, refsAttr)
+	if err != nil {
+		return err
+	}
+
+	if refs != 0 {
+		return nil
+	}
+
+	uploadID, err := xdynamodb.AsString(out.Attributes, uploadAttr)
+	if err != nil {
+		return err
+	}
+
+	chunks, err := xdynamodb.AsUint[uint64](out.Attributes, chunksAttr)
+	if err != nil {
+		return err
+	}
+
+	// The blob is only removed if it has not been referenced again by a
+	// concurrent call to Put() since the reference count reached zero.
+	if _, err := xaws.Do(
+		ctx,
+		c.Client.DeleteItem,
+		c.OnRequest,
+		&c.request.Delete,
+	); err != nil {
+		if errors.As(err, new(*types.ConditionalCheckFailedException)) {
+			return nil
+		}
+		return fmt.Errorf("unable to delete blob: %w", err)
+	}
+
+	return c.deleteChunks(ctx, uploadID, chunks)
+}
+
+func (c *container) Close() error {
+	return nil
+}
+
+// get loads the item that represents the blob with the given hash. It returns
+// false if the blob does not exist, or has no remaining references.
+func (c *container) get(ctx context.Context, h blob.Hash) (map[string]types.AttributeValue, bool, error) {
+	c.attr.BlobKey.Value = blobKey(c.name, h)
+
+	out, err := xaws.Do(
+		ctx,
+		c.Client.GetItem,
+		c.OnRequest,
+		&c.request.Get,
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to load blob: %w", err)
+	}
+
+	if out.Item == nil {
+		return nil, false, nil
+	}
+
+	refs, err := xdynamodb.AsUint[uint64](out.Item, refsAttr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return out.Item, refs != 0, nil
+}
+
+// deleteChunks deletes the chunks that were written by the upload with the
+// given ID.
+func (c *container) deleteChunks(ctx context.Context, uploadID string, chunks uint64) error {
+	c.attr.UploadKey.Value = uploadKey(uploadID)
+
+	for seq := range chunks {
+		c.attr.Seq.Value = strconv.FormatUint(seq, 10)
+
+		if _, err := xaws.Do(
+			ctx,
+			c.Client.DeleteItem,
+			c.OnRequest,
+			&c.request.DeleteChunk,
+		); err != nil {
+			return fmt.Errorf("unable to delete blob chunk: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// reader is an [io.ReadCloser] that reads blob content one chunk at a time.
+type reader struct {
+	ctx       context.Context
+	client    *dynamodb.Client
+	onRequest func(any) []func(*dynamodb.Options)
+
+	attr struct {
+		UploadKey types.AttributeValueMemberS
+		Seq       types.AttributeValueMemberN
+	}
+	request dynamodb.GetItemInput
+
+	seq    uint64
+	chunks uint64
+	chunk  []byte
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if len(r.chunk) == 0 {
+		if r.seq == r.chunks {
+			return 0, io.EOF
+		}
+
+		r.attr.Seq.Value = strconv.FormatUint(r.seq, 10)
+
+		out, err := xaws.Do(
+			r.ctx,
+			r.client.GetItem,
+			r.onRequest,
+			&r.request,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("unable to read blob chunk: %w", err)
+		}
+
+		if out.Item == nil {
+			return 0, errors.New("unable to read blob chunk: blob was removed while it was being read")
+		}
+
+		r.chunk, err = xdynamodb.AsBytes(out.Item, dataAttr)
+		if err != nil {
+			return 0, err
+		}
+
+		r.seq++
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+
+	return n, nil
+}
+
+func (r *reader) Close() error {
+	return nil
+}
+
+// blobKey returns the partition key of the item that represents the blob with
+// the given hash. The hash precedes the container name so that the key is
+// unambiguous regardless of the characters in the name.
+func blobKey(container string, h blob.Hash) string {
+	return "blob:" + h.String() + ":" + container
+}
+
+// uploadKey returns the partition key of the chunks written by the upload with
+// the given ID.
+func uploadKey(uploadID string) string {
+	return "upload:" + uploadID
+}
+
+// newUploadID returns a new random upload ID.
+func newUploadID() string {
+	var data [16]byte
+	_, _ = rand.Read(data[:])
+	return hex.EncodeToString(data[:])
+}
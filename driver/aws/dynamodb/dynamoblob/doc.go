@@ -0,0 +1,28 @@
+// Package dynamoblob provides a [blob.Store] implementation that persists to a
+// DynamoDB table.
+//
+// Blob content is stored as a sequence of chunks, each in a separate item, such
+// that blobs are not subject to DynamoDB's item size limit. Each blob is
+// represented by an additional item that records its size, its reference count
+// and the location of its content.
+//
+// Content is written to the table before the hash of the content is known. If a
+// write is interrupted, for example by a process crash, the chunks that were
+// written remain in the table but are never read.
+//
+// # IAM Permissions
+//
+// The following IAM actions are required on the DynamoDB table:
+//   - dynamodb:DescribeTable
+//   - dynamodb:GetItem
+//   - dynamodb:PutItem
+//   - dynamodb:UpdateItem
+//   - dynamodb:DeleteItem
+//
+// If the table does not already exist, the store attempts to create it
+// automatically, which requires the following additional action:
+//   - dynamodb:CreateTable
+//
+// The store's Provision method can be called to trigger provisioning ahead of
+// time.
+package dynamoblob
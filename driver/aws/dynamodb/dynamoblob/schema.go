@@ -0,0 +1,173 @@
+package dynamoblob
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xdynamodb"
+)
+
+var (
+	// keyAttr is the name of the attribute that identifies the partition of
+	// each item. Together with [seqAttr], it forms the primary key of the
+	// table.
+	//
+	// For blob items, it is derived from the container name and the hash of
+	// the blob's content. For chunk items, it is derived from the ID of the
+	// upload that wrote the chunk.
+	keyAttr = "K"
+
+	// seqAttr is the name of the attribute that stores the sequence number of
+	// each chunk within its upload. It is always zero for blob items.
+	seqAttr = "N"
+
+	// sizeAttr is the name of the attribute that stores the size of the blob's
+	// content, in bytes.
+	sizeAttr = "Z"
+
+	// refsAttr is the name of the attribute that stores the number of
+	// references to the blob.
+	refsAttr = "R"
+
+	// uploadAttr is the name of the attribute that stores the ID of the upload
+	// that wrote the blob's content.
+	uploadAttr = "U"
+
+	// chunksAttr is the name of the attribute that stores the number of chunks
+	// in the blob's content.
+	chunksAttr = "C"
+
+	// dataAttr is the name of the attribute that stores the content of each
+	// chunk.
+	dataAttr = "D"
+)
+
+// keySchema is the primary key of the store's table.
+var keySchema = []xdynamodb.KeyAttr{
+	{
+		Name:    &keyAttr,
+		Type:    types.ScalarAttributeTypeS,
+		KeyType: types.KeyTypeHash,
+	},
+	{
+		Name:    &seqAttr,
+		Type:    types.ScalarAttributeTypeN,
+		KeyType: types.KeyTypeRange,
+	},
+}
+
+// Provision creates the DynamoDB table used by the store if it does not already
+// exist.
+//
+// The store also creates the table on first use if it does not exist. Provision
+// allows infrastructure to be created ahead of time, for example as part of a
+// deployment pipeline, so that the application itself does not need broad IAM
+// permissions.
+func (s *store) Provision(ctx context.Context) error {
+	return s.provisionOnce.Do(ctx, func(ctx context.Context) error {
+		_, err := xdynamodb.CreateTableIfNotExists(
+			ctx,
+			s.Client,
+			s.Table,
+			s.OnRequest,
+			keySchema...,
+		)
+		return err
+	})
+}
+
+// CreateTableInput returns the parameters of the CreateTable request used to
+// create the store's table, if it does not already exist.
+func CreateTableInput(table string) *dynamodb.CreateTableInput {
+	return xdynamodb.NewCreateTableInput(table, keySchema, nil)
+}
+
+func (c *container) prepareRequests(table string) {
+	blobKey := map[string]types.AttributeValue{
+		keyAttr: &c.attr.BlobKey,
+		seqAttr: &types.AttributeValueMemberN{Value: "0"},
+	}
+
+	// PutChunk writes the chunk of content at c.attr.UploadKey and c.attr.Seq.
+	c.request.PutChunk = dynamodb.PutItemInput{
+		TableName: &table,
+		Item: map[string]types.AttributeValue{
+			keyAttr:  &c.attr.UploadKey,
+			seqAttr:  &c.attr.Seq,
+			dataAttr: &c.attr.Data,
+		},
+	}
+
+	// DeleteChunk removes the chunk of content at c.attr.UploadKey and
+	// c.attr.Seq.
+	c.request.DeleteChunk = dynamodb.DeleteItemInput{
+		TableName: &table,
+		Key: map[string]types.AttributeValue{
+			keyAttr: &c.attr.UploadKey,
+			seqAttr: &c.attr.Seq,
+		},
+	}
+
+	// Get loads the blob at c.attr.BlobKey.
+	c.request.Get = dynamodb.GetItemInput{
+		TableName:      &table,
+		ConsistentRead: aws.Bool(true),
+		Key:            blobKey,
+	}
+
+	// Reference adds a reference to the blob at c.attr.BlobKey, creating it
+	// with the content written by the upload at c.attr.Upload if it does not
+	// already exist.
+	c.request.Reference = dynamodb.UpdateItemInput{
+		TableName: &table,
+		Key:       blobKey,
+		ExpressionAttributeNames: map[string]string{
+			"#Z": sizeAttr,
+			"#U": uploadAttr,
+			"#C": chunksAttr,
+			"#R": refsAttr,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":Z":   &c.attr.Size,
+			":U":   &c.attr.Upload,
+			":C":   &c.attr.Chunks,
+			":one": &types.AttributeValueMemberN{Value: "1"},
+		},
+		UpdateExpression: aws.String(
+			`SET #Z = if_not_exists(#Z, :Z), #U = if_not_exists(#U, :U), #C = if_not_exists(#C, :C) ADD #R :one`,
+		),
+		ReturnValues: types.ReturnValueAllNew,
+	}
+
+	// Release removes a reference to the blob at c.attr.BlobKey, if it has
+	// any references remaining.
+	c.request.Release = dynamodb.UpdateItemInput{
+		TableName: &table,
+		Key:       blobKey,
+		ExpressionAttributeNames: map[string]string{
+			"#R": refsAttr,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":minusone": &types.AttributeValueMemberN{Value: "-1"},
+			":zero":     &types.AttributeValueMemberN{Value: "0"},
+		},
+		UpdateExpression:    aws.String(`ADD #R :minusone`),
+		ConditionExpression: aws.String(`#R > :zero`),
+		ReturnValues:        types.ReturnValueAllNew,
+	}
+
+	// Delete removes the blob at c.attr.BlobKey, if it has no references.
+	c.request.Delete = dynamodb.DeleteItemInput{
+		TableName: &table,
+		Key:       blobKey,
+		ExpressionAttributeNames: map[string]string{
+			"#R": refsAttr,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":zero": &types.AttributeValueMemberN{Value: "0"},
+		},
+		ConditionExpression: aws.String(`#R = :zero`),
+	}
+}
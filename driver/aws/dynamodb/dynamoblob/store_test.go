@@ -0,0 +1,21 @@
+package dynamoblob_test
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/blob"
+	. "github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamoblob"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xdynamodb"
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+)
+
+func TestStore(t *testing.T) {
+	client, _ := xdynamodb.NewTestClient(t)
+	table := xtesting.UniqueName("table")
+	xdynamodb.CleanupTable(t, client, table)
+
+	blob.RunTests(
+		t,
+		NewStore(client, table),
+	)
+}
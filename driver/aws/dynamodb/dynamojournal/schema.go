@@ -54,6 +54,20 @@ var (
 	}
 )
 
+// keySchema is the primary key of the store's table.
+var keySchema = []xdynamodb.KeyAttr{
+	{
+		Name:    &journalAttr,
+		Type:    types.ScalarAttributeTypeS,
+		KeyType: types.KeyTypeHash,
+	},
+	{
+		Name:    &positionAttr,
+		Type:    types.ScalarAttributeTypeN,
+		KeyType: types.KeyTypeRange,
+	},
+}
+
 // Provision creates the DynamoDB table used by the store if it does not already
 // exist.
 //
@@ -68,21 +82,18 @@ func (s *store) Provision(ctx context.Context) error {
 			s.Client,
 			s.Table,
 			s.OnRequest,
-			xdynamodb.KeyAttr{
-				Name:    &journalAttr,
-				Type:    types.ScalarAttributeTypeS,
-				KeyType: types.KeyTypeHash,
-			},
-			xdynamodb.KeyAttr{
-				Name:    &positionAttr,
-				Type:    types.ScalarAttributeTypeN,
-				KeyType: types.KeyTypeRange,
-			},
+			keySchema...,
 		)
 		return err
 	})
 }
 
+// CreateTableInput returns the parameters of the CreateTable request used to
+// create the store's table, if it does not already exist.
+func CreateTableInput(table string) *dynamodb.CreateTableInput {
+	return xdynamodb.NewCreateTableInput(table, keySchema, nil)
+}
+
 // prepareRequests prepares the DynamoDB API requests used by the journal.
 //
 // The requests are built once and reused for the lifetime of the journal to
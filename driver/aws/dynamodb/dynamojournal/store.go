@@ -76,3 +76,13 @@ func (s *store) Open(ctx context.Context, name string) (journal.BinaryJournal, e
 
 	return j, nil
 }
+
+// Capabilities returns the limits and optional features of the store.
+func (s *store) Capabilities() journal.Capabilities {
+	return journal.Capabilities{
+		// DynamoDB limits items to 400 KB, including the attribute names and
+		// the other attributes on the item.
+		MaxRecordSize:   350 * 1024,
+		MeteredRequests: true,
+	}
+}
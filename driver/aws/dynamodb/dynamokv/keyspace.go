@@ -28,6 +28,7 @@ type keyspace struct {
 		Get                 dynamodb.GetItemInput
 		Has                 dynamodb.GetItemInput
 		Range               dynamodb.QueryInput
+		RangeKeys           dynamodb.QueryInput
 		Update              dynamodb.UpdateItemInput
 		UpdateUnconditional dynamodb.UpdateItemInput
 		Delete              dynamodb.DeleteItemInput
@@ -89,7 +90,7 @@ func (ks *keyspace) Set(ctx context.Context, k, v []byte, r kv.Revision) (kv.Rev
 	ks.attr.Value.Value = v
 	gen, ok := kvrevision.TryUnmarshalGeneration(r)
 	if !ok {
-		return "", kv.ConflictError[[]byte]{
+		return "", kv.ConflictError[[]byte, []byte]{
 			Keyspace: ks.attr.Keyspace.Value,
 			Key:      k,
 			Revision: r,
@@ -100,11 +101,7 @@ func (ks *keyspace) Set(ctx context.Context, k, v []byte, r kv.Revision) (kv.Rev
 	convertConflictError := func(message string, err error) error {
 		var conflict *types.ConditionalCheckFailedException
 		if errors.As(err, &conflict) {
-			return kv.ConflictError[[]byte]{
-				Keyspace: ks.attr.Keyspace.Value,
-				Key:      k,
-				Revision: r,
-			}
+			return ks.conflictError(k, r, conflict.Item)
 		}
 
 		return fmt.Errorf("%s: %w", message, err)
@@ -135,6 +132,39 @@ func (ks *keyspace) Set(ctx context.Context, k, v []byte, r kv.Revision) (kv.Rev
 	return kvrevision.IncrementGeneration(r), nil
 }
 
+// conflictError returns a [kv.ConflictError] for a failed conditional write to
+// k at revision r.
+//
+// item is the item as it existed when the condition was evaluated, as returned
+// by DynamoDB, or nil if the item did not exist.
+func (ks *keyspace) conflictError(k []byte, r kv.Revision, item map[string]types.AttributeValue) error {
+	conflict := kv.ConflictError[[]byte, []byte]{
+		Keyspace:     ks.attr.Keyspace.Value,
+		Key:          k,
+		Revision:     r,
+		CurrentKnown: true,
+	}
+
+	if item == nil {
+		return conflict
+	}
+
+	v, err := xdynamodb.AsBytes(item, valueAttr)
+	if err != nil {
+		return err
+	}
+
+	gen, err := xdynamodb.AsUint[uint64](item, generationAttr)
+	if err != nil {
+		return err
+	}
+
+	conflict.CurrentValue = v
+	conflict.CurrentRevision = kvrevision.MarshalGeneration(gen)
+
+	return conflict
+}
+
 func (ks *keyspace) SetUnconditional(ctx context.Context, k, v []byte) error {
 	ks.attr.Key.Value = k
 	ks.attr.Value.Value = v
@@ -195,6 +225,32 @@ func (ks *keyspace) Range(ctx context.Context, fn kv.BinaryRangeFunc) error {
 	return nil
 }
 
+func (ks *keyspace) RangeKeys(ctx context.Context, fn kv.BinaryKeyRangeFunc) error {
+	if err := xdynamodb.QueryRange(
+		ctx,
+		ks.Client,
+		ks.OnRequest,
+		&ks.request.RangeKeys,
+		func(ctx context.Context, item map[string]types.AttributeValue) (bool, error) {
+			k, err := xdynamodb.AsBytes(item, keyAttr)
+			if err != nil {
+				return false, err
+			}
+
+			gen, err := xdynamodb.AsUint[uint64](item, generationAttr)
+			if err != nil {
+				return false, err
+			}
+
+			return fn(ctx, k, kvrevision.MarshalGeneration(gen))
+		},
+	); err != nil {
+		return fmt.Errorf("unable to range over keyspace keys: %w", err)
+	}
+
+	return nil
+}
+
 func (ks *keyspace) Close() error {
 	return nil
 }
@@ -33,6 +33,20 @@ var (
 	nonExistentAttr = "X"
 )
 
+// keySchema is the primary key of the store's table.
+var keySchema = []xdynamodb.KeyAttr{
+	{
+		Name:    &keyspaceAttr,
+		Type:    types.ScalarAttributeTypeS,
+		KeyType: types.KeyTypeHash,
+	},
+	{
+		Name:    &keyAttr,
+		Type:    types.ScalarAttributeTypeB,
+		KeyType: types.KeyTypeRange,
+	},
+}
+
 // Provision creates the DynamoDB table used by the store if it does not already
 // exist.
 //
@@ -47,21 +61,18 @@ func (s *store) Provision(ctx context.Context) error {
 			s.Client,
 			s.Table,
 			s.OnRequest,
-			xdynamodb.KeyAttr{
-				Name:    &keyspaceAttr,
-				Type:    types.ScalarAttributeTypeS,
-				KeyType: types.KeyTypeHash,
-			},
-			xdynamodb.KeyAttr{
-				Name:    &keyAttr,
-				Type:    types.ScalarAttributeTypeB,
-				KeyType: types.KeyTypeRange,
-			},
+			keySchema...,
 		)
 		return err
 	})
 }
 
+// CreateTableInput returns the parameters of the CreateTable request used to
+// create the store's table, if it does not already exist.
+func CreateTableInput(table string) *dynamodb.CreateTableInput {
+	return xdynamodb.NewCreateTableInput(table, keySchema, nil)
+}
+
 func (ks *keyspace) prepareRequests(table string) {
 	key := map[string]types.AttributeValue{
 		keyspaceAttr: &ks.attr.Keyspace,
@@ -106,6 +117,21 @@ func (ks *keyspace) prepareRequests(table string) {
 		},
 	}
 
+	// RangeKeys fetches all keys in the keyspace, without their values.
+	ks.request.RangeKeys = dynamodb.QueryInput{
+		TableName:              &table,
+		KeyConditionExpression: aws.String(`#S = :S`),
+		ProjectionExpression:   aws.String(`#K, #G`),
+		ExpressionAttributeNames: map[string]string{
+			"#S": keyspaceAttr,
+			"#K": keyAttr,
+			"#G": generationAttr,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":S": &ks.attr.Keyspace,
+		},
+	}
+
 	// Update sets the value associated with ks.attr.Key to ks.attr.Value at
 	// revision ks.attr.CurrentRevision.
 	ks.request.Update = dynamodb.UpdateItemInput{
@@ -126,6 +152,10 @@ func (ks *keyspace) prepareRequests(table string) {
 		// Fail if the revision does not match so we can return
 		// [kv.ConflictError].
 		ConditionExpression: aws.String(`(attribute_not_exists(#G) AND :G = :0) OR #G = :G`),
+
+		// Return the existing item on failure so that the current value and
+		// revision can be included in the [kv.ConflictError].
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
 	}
 
 	// SetUnconditional sets the value associated with ks.attr.Key to
@@ -159,6 +189,10 @@ func (ks *keyspace) prepareRequests(table string) {
 		// Fail if the revision does not match so we can return
 		// [kv.ConflictError].
 		ConditionExpression: aws.String(`(attribute_not_exists(#G) AND :G = :0) OR #G = :G`),
+
+		// Return the existing item on failure so that the current value and
+		// revision can be included in the [kv.ConflictError].
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
 	}
 
 	// DeleteUnconditional removes the ks.attr.Key key unconditionally.
@@ -75,3 +75,22 @@ func (s *store) Open(ctx context.Context, name string) (kv.BinaryKeyspace, error
 
 	return ks, nil
 }
+
+// Capabilities returns the limits and optional features of the store.
+func (s *store) Capabilities() kv.Capabilities {
+	return kv.Capabilities{
+		// Keys are stored in the table's sort key, which DynamoDB limits to
+		// 1024 bytes.
+		MaxKeySize: 1024,
+
+		// DynamoDB limits items to 400 KB, including the attribute names and
+		// the other attributes on the item.
+		MaxValueSize: 350 * 1024,
+
+		// Query results are sorted by the sort key.
+		OrderedRange: true,
+
+		ConflictDetail:  true,
+		MeteredRequests: true,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamojournal"
 	. "github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamokv"
 	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xdynamodb"
 	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
@@ -18,6 +19,21 @@ func TestStore(t *testing.T) {
 	)
 }
 
+func TestChangeJournal(t *testing.T) {
+	client, table := setup(t)
+
+	journalTable := xtesting.UniqueName("table")
+	xdynamodb.CleanupTable(t, client, journalTable)
+
+	journals := dynamojournal.NewBinaryStore(client, journalTable)
+
+	kv.RunChangeFeedTests(
+		t,
+		kv.WithChangeJournal(NewBinaryStore(client, table), journals),
+		kv.NewJournalChangeFeedStore(journals),
+	)
+}
+
 func BenchmarkStore(b *testing.B) {
 	client, table := setup(b)
 	kv.RunBenchmarks(
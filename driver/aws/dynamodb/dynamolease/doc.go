@@ -0,0 +1,20 @@
+// Package dynamolease provides a [lease.Store] implementation that persists to
+// a DynamoDB table.
+//
+// Lease expiry is determined using the clock of the client that performs each
+// operation, so the clocks of all clients that share a table should be
+// synchronized to well within the duration of the leases they acquire.
+//
+// # IAM Permissions
+//
+// The following IAM actions are required on the DynamoDB table:
+//   - dynamodb:DescribeTable
+//   - dynamodb:UpdateItem
+//
+// If the table does not already exist, the store attempts to create it
+// automatically, which requires the following additional action:
+//   - dynamodb:CreateTable
+//
+// The store's Provision method can be called to trigger provisioning ahead of
+// time.
+package dynamolease
@@ -0,0 +1,156 @@
+package dynamolease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xaws"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xdynamodb"
+	"github.com/dogmatiq/persistencekit/lease"
+)
+
+type leaseimpl struct {
+	Client    *dynamodb.Client
+	OnRequest func(any) []func(*dynamodb.Options)
+
+	attr struct {
+		Name      types.AttributeValueMemberS
+		Holder    types.AttributeValueMemberS
+		Token     types.AttributeValueMemberN
+		ExpiresAt types.AttributeValueMemberN
+		Now       types.AttributeValueMemberN
+	}
+
+	request struct {
+		Extend   dynamodb.UpdateItemInput
+		Takeover dynamodb.UpdateItemInput
+		Renew    dynamodb.UpdateItemInput
+		Release  dynamodb.UpdateItemInput
+	}
+}
+
+func (l *leaseimpl) Name() string {
+	return l.attr.Name.Value
+}
+
+func (l *leaseimpl) Acquire(
+	ctx context.Context,
+	holder string,
+	ttl time.Duration,
+) (lease.Grant, bool, error) {
+	expiresAt := l.setTimes(ttl)
+	l.attr.Holder.Value = holder
+
+	// First attempt to extend the lease in case it is already held by the same
+	// holder, then attempt to take it over in case it is not held at all.
+	for _, req := range []*dynamodb.UpdateItemInput{
+		&l.request.Extend,
+		&l.request.Takeover,
+	} {
+		out, err := xaws.Do(
+			ctx,
+			l.Client.UpdateItem,
+			l.OnRequest,
+			req,
+		)
+
+		var conflict *types.ConditionalCheckFailedException
+		if errors.As(err, &conflict) {
+			continue
+		}
+		if err != nil {
+			return lease.Grant{}, false, fmt.Errorf("unable to acquire lease: %w", err)
+		}
+
+		token, err := xdynamodb.AsUint[lease.Token](out.Attributes, tokenAttr)
+		if err != nil {
+			return lease.Grant{}, false, fmt.Errorf("unable to acquire lease: %w", err)
+		}
+
+		return lease.Grant{
+			Holder:    holder,
+			Token:     token,
+			ExpiresAt: expiresAt,
+		}, true, nil
+	}
+
+	return lease.Grant{}, false, nil
+}
+
+func (l *leaseimpl) Renew(
+	ctx context.Context,
+	g lease.Grant,
+	ttl time.Duration,
+) (lease.Grant, error) {
+	expiresAt := l.setTimes(ttl)
+	l.attr.Token.Value = formatToken(g.Token)
+
+	_, err := xaws.Do(
+		ctx,
+		l.Client.UpdateItem,
+		l.OnRequest,
+		&l.request.Renew,
+	)
+
+	var conflict *types.ConditionalCheckFailedException
+	if errors.As(err, &conflict) {
+		return lease.Grant{}, lease.NotHeldError{
+			Lease: l.attr.Name.Value,
+			Token: g.Token,
+		}
+	}
+	if err != nil {
+		return lease.Grant{}, fmt.Errorf("unable to renew lease: %w", err)
+	}
+
+	g.ExpiresAt = expiresAt
+	return g, nil
+}
+
+func (l *leaseimpl) Release(ctx context.Context, g lease.Grant) error {
+	l.setTimes(0)
+	l.attr.Token.Value = formatToken(g.Token)
+
+	_, err := xaws.Do(
+		ctx,
+		l.Client.UpdateItem,
+		l.OnRequest,
+		&l.request.Release,
+	)
+
+	var conflict *types.ConditionalCheckFailedException
+	if errors.As(err, &conflict) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to release lease: %w", err)
+	}
+
+	return nil
+}
+
+func (l *leaseimpl) Close() error {
+	return nil
+}
+
+// setTimes sets the attributes that represent the current time and the time at
+// which a lease acquired now with the given TTL expires. It returns the expiry
+// time, truncated to the precision at which it is stored.
+func (l *leaseimpl) setTimes(ttl time.Duration) time.Time {
+	now := time.Now()
+	expiresAt := time.UnixMilli(now.Add(ttl).UnixMilli())
+
+	l.attr.Now.Value = strconv.FormatInt(now.UnixMilli(), 10)
+	l.attr.ExpiresAt.Value = strconv.FormatInt(expiresAt.UnixMilli(), 10)
+
+	return expiresAt
+}
+
+func formatToken(t lease.Token) string {
+	return strconv.FormatUint(uint64(t), 10)
+}
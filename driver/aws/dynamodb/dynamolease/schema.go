@@ -0,0 +1,145 @@
+package dynamolease
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xdynamodb"
+)
+
+var (
+	// nameAttr is the name of the attribute that stores the lease name on each
+	// item. It is the primary key of the table.
+	nameAttr = "N"
+
+	// holderAttr is the name of the attribute that stores the identifier of
+	// the lease's current (or most recent) holder.
+	holderAttr = "H"
+
+	// tokenAttr is the name of the attribute that stores the fencing token of
+	// the lease's current (or most recent) acquisition.
+	tokenAttr = "T"
+
+	// expiresAtAttr is the name of the attribute that stores the time at which
+	// the lease expires, as a Unix timestamp in milliseconds.
+	expiresAtAttr = "E"
+)
+
+// keySchema is the primary key of the store's table.
+var keySchema = []xdynamodb.KeyAttr{
+	{
+		Name:    &nameAttr,
+		Type:    types.ScalarAttributeTypeS,
+		KeyType: types.KeyTypeHash,
+	},
+}
+
+// Provision creates the DynamoDB table used by the store if it does not already
+// exist.
+//
+// The store also creates the table on first use if it does not exist. Provision
+// allows infrastructure to be created ahead of time, for example as part of a
+// deployment pipeline, so that the application itself does not need broad IAM
+// permissions.
+func (s *store) Provision(ctx context.Context) error {
+	return s.provisionOnce.Do(ctx, func(ctx context.Context) error {
+		_, err := xdynamodb.CreateTableIfNotExists(
+			ctx,
+			s.Client,
+			s.Table,
+			s.OnRequest,
+			keySchema...,
+		)
+		return err
+	})
+}
+
+// CreateTableInput returns the parameters of the CreateTable request used to
+// create the store's table, if it does not already exist.
+func CreateTableInput(table string) *dynamodb.CreateTableInput {
+	return xdynamodb.NewCreateTableInput(table, keySchema, nil)
+}
+
+func (l *leaseimpl) prepareRequests(table string) {
+	key := map[string]types.AttributeValue{
+		nameAttr: &l.attr.Name,
+	}
+
+	// Extend updates the expiry time of the lease to l.attr.ExpiresAt, if it
+	// is currently held by l.attr.Holder.
+	l.request.Extend = dynamodb.UpdateItemInput{
+		TableName: &table,
+		Key:       key,
+		ExpressionAttributeNames: map[string]string{
+			"#H": holderAttr,
+			"#E": expiresAtAttr,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":H":   &l.attr.Holder,
+			":E":   &l.attr.ExpiresAt,
+			":now": &l.attr.Now,
+		},
+		UpdateExpression:    aws.String(`SET #E = :E`),
+		ConditionExpression: aws.String(`#H = :H AND #E > :now`),
+		ReturnValues:        types.ReturnValueAllNew,
+	}
+
+	// Takeover assigns the lease to l.attr.Holder and increments its fencing
+	// token, if the lease does not exist or has expired.
+	l.request.Takeover = dynamodb.UpdateItemInput{
+		TableName: &table,
+		Key:       key,
+		ExpressionAttributeNames: map[string]string{
+			"#N": nameAttr,
+			"#H": holderAttr,
+			"#T": tokenAttr,
+			"#E": expiresAtAttr,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":H":   &l.attr.Holder,
+			":E":   &l.attr.ExpiresAt,
+			":now": &l.attr.Now,
+			":one": &types.AttributeValueMemberN{Value: "1"},
+		},
+		UpdateExpression:    aws.String(`SET #H = :H, #E = :E ADD #T :one`),
+		ConditionExpression: aws.String(`attribute_not_exists(#N) OR #E <= :now`),
+		ReturnValues:        types.ReturnValueAllNew,
+	}
+
+	// Renew updates the expiry time of the lease to l.attr.ExpiresAt, if it is
+	// currently held under the fencing token l.attr.Token.
+	l.request.Renew = dynamodb.UpdateItemInput{
+		TableName: &table,
+		Key:       key,
+		ExpressionAttributeNames: map[string]string{
+			"#T": tokenAttr,
+			"#E": expiresAtAttr,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":T":   &l.attr.Token,
+			":E":   &l.attr.ExpiresAt,
+			":now": &l.attr.Now,
+		},
+		UpdateExpression:    aws.String(`SET #E = :E`),
+		ConditionExpression: aws.String(`#T = :T AND #E > :now`),
+	}
+
+	// Release expires the lease immediately, if it is currently held under the
+	// fencing token l.attr.Token.
+	l.request.Release = dynamodb.UpdateItemInput{
+		TableName: &table,
+		Key:       key,
+		ExpressionAttributeNames: map[string]string{
+			"#T": tokenAttr,
+			"#E": expiresAtAttr,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":T":   &l.attr.Token,
+			":now": &l.attr.Now,
+		},
+		UpdateExpression:    aws.String(`SET #E = :now`),
+		ConditionExpression: aws.String(`#T = :T AND #E > :now`),
+	}
+}
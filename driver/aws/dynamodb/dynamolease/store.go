@@ -0,0 +1,77 @@
+package dynamolease
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/dogmatiq/enginekit/x/xsync"
+	"github.com/dogmatiq/persistencekit/lease"
+)
+
+// store is an implementation of [lease.Store] that persists to a DynamoDB
+// table.
+type store struct {
+	Client    *dynamodb.Client
+	Table     string
+	OnRequest func(any) []func(*dynamodb.Options)
+
+	provisionOnce xsync.SucceedOnce
+}
+
+// NewStore returns a new [lease.Store] that uses the given DynamoDB client to
+// store leases in the given table.
+func NewStore(
+	client *dynamodb.Client,
+	table string,
+	options ...Option,
+) lease.Store {
+	if table == "" {
+		panic("table name must not be empty")
+	}
+
+	s := &store{
+		Client: client,
+		Table:  table,
+	}
+
+	for _, opt := range options {
+		opt(s)
+	}
+
+	return s
+}
+
+// Option is a functional option that changes the behavior of [NewStore].
+type Option func(*store)
+
+// WithRequestHook is an [Option] that configures fn as a pre-request hook.
+//
+// Before each DynamoDB API request, fn is passed a pointer to the input struct,
+// e.g. [dynamodb.GetItemInput], which it may modify in-place. It may be called
+// with any DynamoDB request type. The types of requests used may change in any
+// version without notice.
+//
+// Any functions returned by fn will be applied to the request's options before
+// the request is sent.
+func WithRequestHook(fn func(any) []func(*dynamodb.Options)) Option {
+	return func(s *store) {
+		s.OnRequest = fn
+	}
+}
+
+// Open returns the lease with the given name.
+func (s *store) Open(ctx context.Context, name string) (lease.Lease, error) {
+	if err := s.Provision(ctx); err != nil {
+		return nil, err
+	}
+
+	l := &leaseimpl{
+		Client:    s.Client,
+		OnRequest: s.OnRequest,
+	}
+
+	l.attr.Name.Value = name
+	l.prepareRequests(s.Table)
+
+	return l, nil
+}
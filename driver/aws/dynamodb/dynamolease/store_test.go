@@ -0,0 +1,26 @@
+package dynamolease_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	. "github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamolease"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xdynamodb"
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+	"github.com/dogmatiq/persistencekit/lease"
+)
+
+func TestStore(t *testing.T) {
+	client, table := setup(t)
+	lease.RunTests(
+		t,
+		NewStore(client, table),
+	)
+}
+
+func setup(t testing.TB) (*dynamodb.Client, string) {
+	client, _ := xdynamodb.NewTestClient(t)
+	table := xtesting.UniqueName("table")
+	xdynamodb.CleanupTable(t, client, table)
+	return client, table
+}
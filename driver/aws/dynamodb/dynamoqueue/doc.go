@@ -0,0 +1,32 @@
+// Package dynamoqueue provides a [queue.BinaryStore] implementation that
+// persists to a DynamoDB table.
+//
+// Visible messages are found using a local secondary index ordered by the time
+// at which each message becomes visible, which allows them to be read with
+// strong consistency without reading hidden messages. As with any table that
+// has a local secondary index, the messages within a single queue are limited
+// to 10 GB in total.
+//
+// Message visibility is determined using the clock of the client that performs
+// each operation, so the clocks of all clients that share a table should be
+// synchronized to well within the visibility timeouts that they use.
+//
+// # IAM Permissions
+//
+// The following IAM actions are required on the DynamoDB table and its
+// indexes:
+//   - dynamodb:DescribeTable
+//   - dynamodb:GetItem
+//   - dynamodb:PutItem
+//   - dynamodb:UpdateItem
+//   - dynamodb:DeleteItem
+//   - dynamodb:Query
+//   - dynamodb:TransactWriteItems
+//
+// If the table does not already exist, the store attempts to create it
+// automatically, which requires the following additional action:
+//   - dynamodb:CreateTable
+//
+// The store's Provision method can be called to trigger provisioning ahead of
+// time.
+package dynamoqueue
@@ -0,0 +1,281 @@
+package dynamoqueue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xaws"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xdynamodb"
+	"github.com/dogmatiq/persistencekit/queue"
+)
+
+type queueimpl struct {
+	Client    *dynamodb.Client
+	OnRequest func(any) []func(*dynamodb.Options)
+
+	attr struct {
+		Queue           types.AttributeValueMemberS
+		DeadLetterQueue types.AttributeValueMemberS
+		ID              types.AttributeValueMemberS
+		Value           types.AttributeValueMemberB
+		Attempt         types.AttributeValueMemberN
+		VisibleAt       types.AttributeValueMemberN
+		Visibility      types.AttributeValueMemberS
+		Now             types.AttributeValueMemberN
+		Until           types.AttributeValueMemberS
+	}
+
+	request struct {
+		Enqueue    dynamodb.PutItemInput
+		Visible    dynamodb.QueryInput
+		Claim      dynamodb.UpdateItemInput
+		Get        dynamodb.GetItemInput
+		Ack        dynamodb.DeleteItemInput
+		Nack       dynamodb.UpdateItemInput
+		DeadLetter dynamodb.TransactWriteItemsInput
+	}
+}
+
+func (q *queueimpl) Name() string {
+	return q.attr.Queue.Value
+}
+
+func (q *queueimpl) Enqueue(ctx context.Context, v []byte) error {
+	now, _ := q.setTimes(0)
+	q.attr.ID.Value = newID(now)
+	q.attr.Value.Value = v
+	q.setVisibility(now)
+
+	if _, err := xaws.Do(
+		ctx,
+		q.Client.PutItem,
+		q.OnRequest,
+		&q.request.Enqueue,
+	); err != nil {
+		return fmt.Errorf("unable to enqueue message: %w", err)
+	}
+
+	return nil
+}
+
+func (q *queueimpl) Dequeue(ctx context.Context, timeout time.Duration) (queue.BinaryMessage, bool, error) {
+	_, visibleAt := q.setTimes(timeout)
+
+	var (
+		m  queue.BinaryMessage
+		ok bool
+	)
+
+	if err := xdynamodb.QueryRange(
+		ctx,
+		q.Client,
+		q.OnRequest,
+		&q.request.Visible,
+		func(ctx context.Context, item map[string]types.AttributeValue) (bool, error) {
+			id, err := xdynamodb.AsString(item, idAttr)
+			if err != nil {
+				return false, err
+			}
+
+			attempt, err := xdynamodb.AsUint[uint](item, attemptAttr)
+			if err != nil {
+				return false, err
+			}
+
+			value, err := xdynamodb.AsBytes(item, valueAttr)
+			if err != nil {
+				return false, err
+			}
+
+			q.attr.ID.Value = id
+			q.attr.Attempt.Value = strconv.FormatUint(uint64(attempt), 10)
+			q.setVisibility(visibleAt)
+
+			_, err = xaws.Do(
+				ctx,
+				q.Client.UpdateItem,
+				q.OnRequest,
+				&q.request.Claim,
+			)
+
+			if errors.As(err, new(*types.ConditionalCheckFailedException)) {
+				// Another consumer claimed the message first, try the next
+				// one.
+				return true, nil
+			}
+			if err != nil {
+				return false, err
+			}
+
+			m = queue.BinaryMessage{
+				ID:      id,
+				Value:   value,
+				Attempt: int(attempt) + 1,
+			}
+			ok = true
+
+			return false, nil
+		},
+	); err != nil {
+		return queue.BinaryMessage{}, false, fmt.Errorf("unable to dequeue message: %w", err)
+	}
+
+	return m, ok, nil
+}
+
+func (q *queueimpl) Ack(ctx context.Context, m queue.BinaryMessage) error {
+	q.setMessage(m)
+
+	_, err := xaws.Do(
+		ctx,
+		q.Client.DeleteItem,
+		q.OnRequest,
+		&q.request.Ack,
+	)
+
+	if errors.As(err, new(*types.ConditionalCheckFailedException)) {
+		return q.stale(m)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to acknowledge message: %w", err)
+	}
+
+	return nil
+}
+
+func (q *queueimpl) Nack(ctx context.Context, m queue.BinaryMessage, delay time.Duration) error {
+	_, visibleAt := q.setTimes(delay)
+	q.setMessage(m)
+	q.setVisibility(visibleAt)
+
+	_, err := xaws.Do(
+		ctx,
+		q.Client.UpdateItem,
+		q.OnRequest,
+		&q.request.Nack,
+	)
+
+	if errors.As(err, new(*types.ConditionalCheckFailedException)) {
+		return q.stale(m)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to return message to queue: %w", err)
+	}
+
+	return nil
+}
+
+func (q *queueimpl) DeadLetter(ctx context.Context, m queue.BinaryMessage) error {
+	now, _ := q.setTimes(0)
+	q.setMessage(m)
+	q.setVisibility(now)
+
+	// The value is read from the stored message rather than taken from m, as
+	// the caller is not required to supply it.
+	out, err := xaws.Do(
+		ctx,
+		q.Client.GetItem,
+		q.OnRequest,
+		&q.request.Get,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to move message to dead-letter queue: %w", err)
+	}
+	if out.Item == nil {
+		return q.stale(m)
+	}
+
+	value, err := xdynamodb.AsBytes(out.Item, valueAttr)
+	if err != nil {
+		return fmt.Errorf("unable to move message to dead-letter queue: %w", err)
+	}
+	q.attr.Value.Value = value
+
+	_, err = xaws.Do(
+		ctx,
+		q.Client.TransactWriteItems,
+		q.OnRequest,
+		&q.request.DeadLetter,
+	)
+
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		for _, reason := range canceled.CancellationReasons {
+			if reason.Code != nil && *reason.Code == "ConditionalCheckFailed" {
+				return q.stale(m)
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("unable to move message to dead-letter queue: %w", err)
+	}
+
+	return nil
+}
+
+func (q *queueimpl) Close() error {
+	return nil
+}
+
+// setTimes sets the attributes that represent the current time and the time at
+// which a message hidden now for duration d becomes visible, and returns those
+// times.
+func (q *queueimpl) setTimes(d time.Duration) (now, visibleAt time.Time) {
+	now = time.Now()
+	visibleAt = now.Add(d)
+
+	q.attr.Now.Value = strconv.FormatInt(now.UnixMilli(), 10)
+	q.attr.VisibleAt.Value = strconv.FormatInt(visibleAt.UnixMilli(), 10)
+
+	// Every message that is visible at or before now has a visibility that
+	// sorts before that of a message becoming visible in the next millisecond.
+	q.attr.Until.Value = formatVisibility(now.Add(time.Millisecond), "")
+
+	return now, visibleAt
+}
+
+// setVisibility sets the attribute that orders the message at q.attr.ID within
+// the visible index, such that it becomes visible at t.
+func (q *queueimpl) setVisibility(t time.Time) {
+	q.attr.Visibility.Value = formatVisibility(t, q.attr.ID.Value)
+}
+
+// setMessage sets the attributes that identify m.
+func (q *queueimpl) setMessage(m queue.BinaryMessage) {
+	q.attr.ID.Value = m.ID
+	q.attr.Attempt.Value = strconv.Itoa(m.Attempt)
+}
+
+func (q *queueimpl) stale(m queue.BinaryMessage) error {
+	return queue.StaleMessageError{
+		Queue:   q.attr.Queue.Value,
+		ID:      m.ID,
+		Attempt: m.Attempt,
+	}
+}
+
+// formatVisibility returns the value of the visibility attribute of the message
+// with the given ID that becomes visible at t. The time is encoded as
+// fixed-width hexadecimal milliseconds so that the values sort by time, and
+// then by ID.
+func formatVisibility(t time.Time, id string) string {
+	return fmt.Sprintf("%016x%s", uint64(t.UnixMilli()), id)
+}
+
+// newID returns a new message ID. IDs sort in the order in which messages were
+// enqueued by the same client, and approximately by enqueue time across
+// clients.
+func newID(now time.Time) string {
+	var data [16]byte
+	binary.BigEndian.PutUint64(data[:8], uint64(now.UnixNano()))
+	_, _ = rand.Read(data[8:])
+	return hex.EncodeToString(data[:])
+}
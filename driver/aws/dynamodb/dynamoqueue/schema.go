@@ -0,0 +1,228 @@
+package dynamoqueue
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xdynamodb"
+)
+
+var (
+	// queueAttr is the name of the attribute that stores the queue name on
+	// each item. Together with [idAttr], it forms the primary key of the table.
+	queueAttr = "Q"
+
+	// idAttr is the name of the attribute that stores the message ID on each
+	// item. IDs are ordered by the time at which the message was enqueued.
+	idAttr = "I"
+
+	// valueAttr is the name of the attribute that stores the message value on
+	// each item.
+	valueAttr = "V"
+
+	// attemptAttr is the name of the attribute that stores the number of times
+	// the message has been dequeued.
+	attemptAttr = "A"
+
+	// visibleAtAttr is the name of the attribute that stores the time at which
+	// the message next becomes visible, in milliseconds since the Unix epoch.
+	visibleAtAttr = "T"
+
+	// visibilityAttr is the name of the attribute that orders the messages in
+	// each queue by the time at which they become visible, and then by their
+	// ID. Together with [queueAttr], it forms the key of [visibleIndex].
+	visibilityAttr = "K"
+
+	// visibleIndex is the name of the local secondary index that orders the
+	// messages in each queue by their visibility.
+	visibleIndex = "visible"
+)
+
+// keySchema is the primary key of the store's table.
+var keySchema = []xdynamodb.KeyAttr{
+	{
+		Name:    &queueAttr,
+		Type:    types.ScalarAttributeTypeS,
+		KeyType: types.KeyTypeHash,
+	},
+	{
+		Name:    &idAttr,
+		Type:    types.ScalarAttributeTypeS,
+		KeyType: types.KeyTypeRange,
+	},
+}
+
+// indexes are the local secondary indexes of the store's table.
+var indexes = []xdynamodb.LocalIndex{
+	{
+		Name: &visibleIndex,
+		RangeKey: xdynamodb.KeyAttr{
+			Name: &visibilityAttr,
+			Type: types.ScalarAttributeTypeS,
+		},
+	},
+}
+
+// Provision creates the DynamoDB table used by the store if it does not already
+// exist.
+//
+// The store also creates the table on first use if it does not exist. Provision
+// allows infrastructure to be created ahead of time, for example as part of a
+// deployment pipeline, so that the application itself does not need broad IAM
+// permissions.
+func (s *store) Provision(ctx context.Context) error {
+	return s.provisionOnce.Do(ctx, func(ctx context.Context) error {
+		_, err := xdynamodb.CreateIndexedTableIfNotExists(
+			ctx,
+			s.Client,
+			s.Table,
+			s.OnRequest,
+			keySchema,
+			indexes,
+		)
+		return err
+	})
+}
+
+// CreateTableInput returns the parameters of the CreateTable request used to
+// create the store's table, if it does not already exist.
+func CreateTableInput(table string) *dynamodb.CreateTableInput {
+	return xdynamodb.NewCreateTableInput(table, keySchema, indexes)
+}
+
+func (q *queueimpl) prepareRequests(table string) {
+	key := map[string]types.AttributeValue{
+		queueAttr: &q.attr.Queue,
+		idAttr:    &q.attr.ID,
+	}
+
+	// Enqueue adds a new message to the queue that is visible immediately.
+	q.request.Enqueue = dynamodb.PutItemInput{
+		TableName: &table,
+		Item: map[string]types.AttributeValue{
+			queueAttr:      &q.attr.Queue,
+			idAttr:         &q.attr.ID,
+			valueAttr:      &q.attr.Value,
+			attemptAttr:    &types.AttributeValueMemberN{Value: "0"},
+			visibleAtAttr:  &q.attr.Now,
+			visibilityAttr: &q.attr.Visibility,
+		},
+	}
+
+	// Visible finds the messages in the queue that are currently visible, in
+	// the order they became visible, and then in the order they were enqueued.
+	// Hidden messages sort after q.attr.Until, so they are never read.
+	q.request.Visible = dynamodb.QueryInput{
+		TableName:              &table,
+		IndexName:              &visibleIndex,
+		ConsistentRead:         aws.Bool(true),
+		KeyConditionExpression: aws.String(`#Q = :Q AND #K < :until`),
+		ExpressionAttributeNames: map[string]string{
+			"#Q": queueAttr,
+			"#K": visibilityAttr,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":Q":     &q.attr.Queue,
+			":until": &q.attr.Until,
+		},
+	}
+
+	// Claim hides the message at q.attr.ID until q.attr.VisibleAt and
+	// increments its attempt number, if it has not been claimed by another
+	// consumer since it was found by the Visible query.
+	q.request.Claim = dynamodb.UpdateItemInput{
+		TableName: &table,
+		Key:       key,
+		ExpressionAttributeNames: map[string]string{
+			"#A": attemptAttr,
+			"#T": visibleAtAttr,
+			"#K": visibilityAttr,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":A":   &q.attr.Attempt,
+			":T":   &q.attr.VisibleAt,
+			":K":   &q.attr.Visibility,
+			":now": &q.attr.Now,
+			":one": &types.AttributeValueMemberN{Value: "1"},
+		},
+		UpdateExpression:    aws.String(`SET #T = :T, #K = :K ADD #A :one`),
+		ConditionExpression: aws.String(`#A = :A AND #T <= :now`),
+	}
+
+	// Get fetches the message at q.attr.ID.
+	q.request.Get = dynamodb.GetItemInput{
+		TableName:      &table,
+		Key:            key,
+		ConsistentRead: aws.Bool(true),
+	}
+
+	// Ack removes the message at q.attr.ID, if it has not been redelivered
+	// since it was dequeued with the attempt number q.attr.Attempt.
+	q.request.Ack = dynamodb.DeleteItemInput{
+		TableName: &table,
+		Key:       key,
+		ExpressionAttributeNames: map[string]string{
+			"#A": attemptAttr,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":A": &q.attr.Attempt,
+		},
+		ConditionExpression: aws.String(`#A = :A`),
+	}
+
+	// Nack makes the message at q.attr.ID visible at q.attr.VisibleAt, if it
+	// has not been redelivered since it was dequeued with the attempt number
+	// q.attr.Attempt.
+	q.request.Nack = dynamodb.UpdateItemInput{
+		TableName: &table,
+		Key:       key,
+		ExpressionAttributeNames: map[string]string{
+			"#A": attemptAttr,
+			"#T": visibleAtAttr,
+			"#K": visibilityAttr,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":A": &q.attr.Attempt,
+			":T": &q.attr.VisibleAt,
+			":K": &q.attr.Visibility,
+		},
+		UpdateExpression:    aws.String(`SET #T = :T, #K = :K`),
+		ConditionExpression: aws.String(`#A = :A`),
+	}
+
+	// DeadLetter atomically removes the message at q.attr.ID, if it has not
+	// been redelivered since it was dequeued with the attempt number
+	// q.attr.Attempt, and adds it to the dead-letter queue with the same ID.
+	q.request.DeadLetter = dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Delete: &types.Delete{
+					TableName: &table,
+					Key:       key,
+					ExpressionAttributeNames: map[string]string{
+						"#A": attemptAttr,
+					},
+					ExpressionAttributeValues: map[string]types.AttributeValue{
+						":A": &q.attr.Attempt,
+					},
+					ConditionExpression: aws.String(`#A = :A`),
+				},
+			},
+			{
+				Put: &types.Put{
+					TableName: &table,
+					Item: map[string]types.AttributeValue{
+						queueAttr:      &q.attr.DeadLetterQueue,
+						idAttr:         &q.attr.ID,
+						valueAttr:      &q.attr.Value,
+						attemptAttr:    &types.AttributeValueMemberN{Value: "0"},
+						visibleAtAttr:  &q.attr.Now,
+						visibilityAttr: &q.attr.Visibility,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,21 @@
+package dynamoqueue_test
+
+import (
+	"testing"
+
+	. "github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamoqueue"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xdynamodb"
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+	"github.com/dogmatiq/persistencekit/queue"
+)
+
+func TestStore(t *testing.T) {
+	client, _ := xdynamodb.NewTestClient(t)
+	table := xtesting.UniqueName("table")
+	xdynamodb.CleanupTable(t, client, table)
+
+	queue.RunTests(
+		t,
+		NewBinaryStore(client, table),
+	)
+}
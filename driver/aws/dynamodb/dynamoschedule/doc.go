@@ -0,0 +1,29 @@
+// Package dynamoschedule provides a [schedule.BinaryStore] implementation that
+// persists to a DynamoDB table.
+//
+// Due items are found using a local secondary index ordered by due time, which
+// allows them to be read with strong consistency. As with any table that has a
+// local secondary index, the items within a single schedule are limited to
+// 10 GB in total.
+//
+// Claims are determined using the clock of the client that performs each
+// operation, so the clocks of all clients that share a table should be
+// synchronized to well within the claim timeouts that they use.
+//
+// # IAM Permissions
+//
+// The following IAM actions are required on the DynamoDB table and its
+// indexes:
+//   - dynamodb:DescribeTable
+//   - dynamodb:PutItem
+//   - dynamodb:UpdateItem
+//   - dynamodb:DeleteItem
+//   - dynamodb:Query
+//
+// If the table does not already exist, the store attempts to create it
+// automatically, which requires the following additional action:
+//   - dynamodb:CreateTable
+//
+// The store's Provision method can be called to trigger provisioning ahead of
+// time.
+package dynamoschedule
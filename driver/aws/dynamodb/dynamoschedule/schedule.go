@@ -0,0 +1,179 @@
+package dynamoschedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xaws"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xdynamodb"
+	"github.com/dogmatiq/persistencekit/schedule"
+)
+
+type scheduleimpl struct {
+	Client    *dynamodb.Client
+	OnRequest func(any) []func(*dynamodb.Options)
+
+	attr struct {
+		Schedule  types.AttributeValueMemberS
+		ID        types.AttributeValueMemberS
+		DueAt     types.AttributeValueMemberN
+		PrevDueAt types.AttributeValueMemberN
+		Value     types.AttributeValueMemberB
+		Until     types.AttributeValueMemberN
+		Now       types.AttributeValueMemberN
+	}
+
+	request struct {
+		Schedule dynamodb.PutItemInput
+		Cancel   dynamodb.DeleteItemInput
+		Due      dynamodb.QueryInput
+		Claim    dynamodb.UpdateItemInput
+		Complete dynamodb.DeleteItemInput
+	}
+}
+
+func (s *scheduleimpl) Name() string {
+	return s.attr.Schedule.Value
+}
+
+func (s *scheduleimpl) Schedule(ctx context.Context, id string, dueAt time.Time, v []byte) error {
+	s.attr.ID.Value = id
+	s.attr.DueAt.Value = formatTime(dueAt)
+	s.attr.Value.Value = v
+
+	if _, err := xaws.Do(
+		ctx,
+		s.Client.PutItem,
+		s.OnRequest,
+		&s.request.Schedule,
+	); err != nil {
+		return fmt.Errorf("unable to schedule item: %w", err)
+	}
+
+	return nil
+}
+
+func (s *scheduleimpl) Cancel(ctx context.Context, id string) error {
+	s.attr.ID.Value = id
+
+	if _, err := xaws.Do(
+		ctx,
+		s.Client.DeleteItem,
+		s.OnRequest,
+		&s.request.Cancel,
+	); err != nil {
+		return fmt.Errorf("unable to cancel item: %w", err)
+	}
+
+	return nil
+}
+
+func (s *scheduleimpl) RangeDue(ctx context.Context, t time.Time, fn schedule.BinaryRangeFunc) error {
+	// The query uses its own attribute for the upper bound, so that fn can
+	// manipulate the schedule without affecting subsequent pages of results.
+	s.attr.Until.Value = formatTime(t)
+
+	if err := xdynamodb.QueryRange(
+		ctx,
+		s.Client,
+		s.OnRequest,
+		&s.request.Due,
+		func(ctx context.Context, item map[string]types.AttributeValue) (bool, error) {
+			id, err := xdynamodb.AsString(item, idAttr)
+			if err != nil {
+				return false, err
+			}
+
+			dueAt, err := xdynamodb.AsNumericString(item, dueAtAttr)
+			if err != nil {
+				return false, err
+			}
+
+			ms, err := strconv.ParseInt(dueAt, 10, 64)
+			if err != nil {
+				return false, fmt.Errorf("integrity error: %q attribute is malformed: %w", dueAtAttr, err)
+			}
+
+			value, err := xdynamodb.AsBytes(item, valueAttr)
+			if err != nil {
+				return false, err
+			}
+
+			return fn(ctx, schedule.BinaryItem{
+				ID:    id,
+				DueAt: time.UnixMilli(ms),
+				Value: value,
+			})
+		},
+	); err != nil {
+		return fmt.Errorf("unable to range over due items: %w", err)
+	}
+
+	return nil
+}
+
+func (s *scheduleimpl) Claim(
+	ctx context.Context,
+	item schedule.BinaryItem,
+	timeout time.Duration,
+) (schedule.BinaryItem, bool, error) {
+	now := time.Now()
+	dueAt := time.UnixMilli(now.Add(timeout).UnixMilli())
+
+	s.attr.ID.Value = item.ID
+	s.attr.PrevDueAt.Value = formatTime(item.DueAt)
+	s.attr.DueAt.Value = formatTime(dueAt)
+	s.attr.Now.Value = formatTime(now)
+
+	_, err := xaws.Do(
+		ctx,
+		s.Client.UpdateItem,
+		s.OnRequest,
+		&s.request.Claim,
+	)
+
+	if errors.As(err, new(*types.ConditionalCheckFailedException)) {
+		return schedule.BinaryItem{}, false, nil
+	}
+	if err != nil {
+		return schedule.BinaryItem{}, false, fmt.Errorf("unable to claim item: %w", err)
+	}
+
+	item.DueAt = dueAt
+	return item, true, nil
+}
+
+func (s *scheduleimpl) Complete(ctx context.Context, item schedule.BinaryItem) (bool, error) {
+	s.attr.ID.Value = item.ID
+	s.attr.PrevDueAt.Value = formatTime(item.DueAt)
+
+	_, err := xaws.Do(
+		ctx,
+		s.Client.DeleteItem,
+		s.OnRequest,
+		&s.request.Complete,
+	)
+
+	if errors.As(err, new(*types.ConditionalCheckFailedException)) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("unable to complete item: %w", err)
+	}
+
+	return true, nil
+}
+
+func (s *scheduleimpl) Close() error {
+	return nil
+}
+
+// formatTime returns the representation of t as stored in a numeric attribute.
+func formatTime(t time.Time) string {
+	return strconv.FormatInt(t.UnixMilli(), 10)
+}
@@ -0,0 +1,158 @@
+package dynamoschedule
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xdynamodb"
+)
+
+var (
+	// scheduleAttr is the name of the attribute that stores the schedule name
+	// on each item. Together with [idAttr], it forms the primary key of the
+	// table.
+	scheduleAttr = "S"
+
+	// idAttr is the name of the attribute that stores the item ID on each
+	// item.
+	idAttr = "I"
+
+	// dueAtAttr is the name of the attribute that stores the time at which the
+	// item becomes due, in milliseconds since the Unix epoch. Together with
+	// [scheduleAttr], it forms the key of [dueIndex].
+	dueAtAttr = "D"
+
+	// valueAttr is the name of the attribute that stores the item's value.
+	valueAttr = "V"
+
+	// dueIndex is the name of the local secondary index that orders the items
+	// in each schedule by their due time.
+	dueIndex = "due"
+)
+
+// keySchema is the primary key of the store's table.
+var keySchema = []xdynamodb.KeyAttr{
+	{
+		Name:    &scheduleAttr,
+		Type:    types.ScalarAttributeTypeS,
+		KeyType: types.KeyTypeHash,
+	},
+	{
+		Name:    &idAttr,
+		Type:    types.ScalarAttributeTypeS,
+		KeyType: types.KeyTypeRange,
+	},
+}
+
+// indexes are the local secondary indexes of the store's table.
+var indexes = []xdynamodb.LocalIndex{
+	{
+		Name: &dueIndex,
+		RangeKey: xdynamodb.KeyAttr{
+			Name: &dueAtAttr,
+			Type: types.ScalarAttributeTypeN,
+		},
+	},
+}
+
+// Provision creates the DynamoDB table used by the store if it does not already
+// exist.
+//
+// The store also creates the table on first use if it does not exist. Provision
+// allows infrastructure to be created ahead of time, for example as part of a
+// deployment pipeline, so that the application itself does not need broad IAM
+// permissions.
+func (s *store) Provision(ctx context.Context) error {
+	return s.provisionOnce.Do(ctx, func(ctx context.Context) error {
+		_, err := xdynamodb.CreateIndexedTableIfNotExists(
+			ctx,
+			s.Client,
+			s.Table,
+			s.OnRequest,
+			keySchema,
+			indexes,
+		)
+		return err
+	})
+}
+
+// CreateTableInput returns the parameters of the CreateTable request used to
+// create the store's table, if it does not already exist.
+func CreateTableInput(table string) *dynamodb.CreateTableInput {
+	return xdynamodb.NewCreateTableInput(table, keySchema, indexes)
+}
+
+func (s *scheduleimpl) prepareRequests(table string) {
+	key := map[string]types.AttributeValue{
+		scheduleAttr: &s.attr.Schedule,
+		idAttr:       &s.attr.ID,
+	}
+
+	// Schedule adds or replaces the item at s.attr.ID.
+	s.request.Schedule = dynamodb.PutItemInput{
+		TableName: &table,
+		Item: map[string]types.AttributeValue{
+			scheduleAttr: &s.attr.Schedule,
+			idAttr:       &s.attr.ID,
+			dueAtAttr:    &s.attr.DueAt,
+			valueAttr:    &s.attr.Value,
+		},
+	}
+
+	// Cancel removes the item at s.attr.ID.
+	s.request.Cancel = dynamodb.DeleteItemInput{
+		TableName: &table,
+		Key:       key,
+	}
+
+	// Due finds the items that are due at or before s.attr.Until, in order of
+	// their due time.
+	s.request.Due = dynamodb.QueryInput{
+		TableName:              &table,
+		IndexName:              &dueIndex,
+		ConsistentRead:         aws.Bool(true),
+		KeyConditionExpression: aws.String(`#S = :S AND #D <= :until`),
+		ExpressionAttributeNames: map[string]string{
+			"#S": scheduleAttr,
+			"#D": dueAtAttr,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":S":     &s.attr.Schedule,
+			":until": &s.attr.Until,
+		},
+	}
+
+	// Claim changes the due time of the item at s.attr.ID to s.attr.DueAt, if
+	// it is currently due at s.attr.PrevDueAt, which is no later than
+	// s.attr.Now.
+	s.request.Claim = dynamodb.UpdateItemInput{
+		TableName: &table,
+		Key:       key,
+		ExpressionAttributeNames: map[string]string{
+			"#D": dueAtAttr,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":D":    &s.attr.DueAt,
+			":prev": &s.attr.PrevDueAt,
+			":now":  &s.attr.Now,
+		},
+		UpdateExpression:    aws.String(`SET #D = :D`),
+		ConditionExpression: aws.String(`#D = :prev AND #D <= :now`),
+	}
+
+	// Complete removes the item at s.attr.ID, if it is currently due at
+	// s.attr.PrevDueAt.
+	s.request.Complete = dynamodb.DeleteItemInput{
+		TableName: &table,
+		Key:       key,
+		ExpressionAttributeNames: map[string]string{
+			"#D": dueAtAttr,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":prev": &s.attr.PrevDueAt,
+		},
+		ConditionExpression: aws.String(`#D = :prev`),
+	}
+}
@@ -0,0 +1,21 @@
+package dynamoschedule_test
+
+import (
+	"testing"
+
+	. "github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamoschedule"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xdynamodb"
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+	"github.com/dogmatiq/persistencekit/schedule"
+)
+
+func TestStore(t *testing.T) {
+	client, _ := xdynamodb.NewTestClient(t)
+	table := xtesting.UniqueName("table")
+	xdynamodb.CleanupTable(t, client, table)
+
+	schedule.RunTests(
+		t,
+		NewBinaryStore(client, table),
+	)
+}
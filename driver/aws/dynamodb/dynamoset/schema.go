@@ -25,6 +25,20 @@ var (
 	nonExistentAttr = "X"
 )
 
+// keySchema is the primary key of the store's table.
+var keySchema = []xdynamodb.KeyAttr{
+	{
+		Name:    &setAttr,
+		Type:    types.ScalarAttributeTypeS,
+		KeyType: types.KeyTypeHash,
+	},
+	{
+		Name:    &memberAttr,
+		Type:    types.ScalarAttributeTypeB,
+		KeyType: types.KeyTypeRange,
+	},
+}
+
 // Provision creates the DynamoDB table used by the store if it does not already
 // exist.
 //
@@ -39,21 +53,18 @@ func (s *store) Provision(ctx context.Context) error {
 			s.Client,
 			s.Table,
 			s.OnRequest,
-			xdynamodb.KeyAttr{
-				Name:    &setAttr,
-				Type:    types.ScalarAttributeTypeS,
-				KeyType: types.KeyTypeHash,
-			},
-			xdynamodb.KeyAttr{
-				Name:    &memberAttr,
-				Type:    types.ScalarAttributeTypeB,
-				KeyType: types.KeyTypeRange,
-			},
+			keySchema...,
 		)
 		return err
 	})
 }
 
+// CreateTableInput returns the parameters of the CreateTable request used to
+// create the store's table, if it does not already exist.
+func CreateTableInput(table string) *dynamodb.CreateTableInput {
+	return xdynamodb.NewCreateTableInput(table, keySchema, nil)
+}
+
 func (s *setimpl) prepareRequests(table string) {
 	key := map[string]types.AttributeValue{
 		setAttr:    &s.attr.Set,
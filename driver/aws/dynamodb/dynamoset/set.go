@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
@@ -50,6 +52,94 @@ func (s *setimpl) Has(ctx context.Context, v []byte) (bool, error) {
 	return out.Item != nil, nil
 }
 
+// batchGetLimit is the maximum number of keys that DynamoDB accepts in a single
+// BatchGetItem request.
+const batchGetLimit = 100
+
+// batchGetBackoff is the initial delay before retrying the keys that were not
+// processed by a BatchGetItem request.
+const batchGetBackoff = 50 * time.Millisecond
+
+// maxBatchGetBackoff is the maximum delay between retries of a BatchGetItem
+// request.
+const maxBatchGetBackoff = 5 * time.Second
+
+func (s *setimpl) HasBatch(ctx context.Context, vs [][]byte) ([]bool, error) {
+	table := *s.request.Has.TableName
+	members := map[string]struct{}{}
+
+	// BatchGetItem rejects requests that contain the same key more than once.
+	var unique [][]byte
+	seen := map[string]struct{}{}
+	for _, v := range vs {
+		if _, ok := seen[string(v)]; !ok {
+			seen[string(v)] = struct{}{}
+			unique = append(unique, v)
+		}
+	}
+
+	for batch := range slices.Chunk(unique, batchGetLimit) {
+		keys := make([]map[string]types.AttributeValue, len(batch))
+		for i, v := range batch {
+			keys[i] = map[string]types.AttributeValue{
+				setAttr:    &s.attr.Set,
+				memberAttr: &types.AttributeValueMemberB{Value: v},
+			}
+		}
+
+		req := &dynamodb.BatchGetItemInput{
+			RequestItems: map[string]types.KeysAndAttributes{
+				table: {
+					Keys:                     keys,
+					ProjectionExpression:     aws.String("#M"),
+					ExpressionAttributeNames: map[string]string{"#M": memberAttr},
+				},
+			},
+		}
+
+		// Keep requesting any keys that DynamoDB did not process, for example
+		// because the table's provisioned throughput was exceeded, backing off
+		// between attempts as recommended by AWS.
+		for delay := batchGetBackoff; ; delay = min(delay*2, maxBatchGetBackoff) {
+			out, err := xaws.Do(
+				ctx,
+				s.Client.BatchGetItem,
+				s.OnRequest,
+				req,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("unable to get set members: %w", err)
+			}
+
+			for _, item := range out.Responses[table] {
+				v, err := xdynamodb.AsBytes(item, memberAttr)
+				if err != nil {
+					return nil, err
+				}
+				members[string(v)] = struct{}{}
+			}
+
+			req.RequestItems = out.UnprocessedKeys
+			if len(req.RequestItems) == 0 {
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+	}
+
+	result := make([]bool, len(vs))
+	for i, v := range vs {
+		_, result[i] = members[string(v)]
+	}
+
+	return result, nil
+}
+
 func (s *setimpl) Add(ctx context.Context, v []byte) error {
 	s.request.Put.ConditionExpression = nil
 	return s.add(ctx, v)
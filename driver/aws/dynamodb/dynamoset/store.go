@@ -75,3 +75,16 @@ func (s *store) Open(ctx context.Context, name string) (set.BinarySet, error) {
 
 	return set, nil
 }
+
+// Capabilities returns the limits and optional features of the store.
+func (s *store) Capabilities() set.Capabilities {
+	return set.Capabilities{
+		// Members are stored in the table's sort key, which DynamoDB limits to
+		// 1024 bytes.
+		MaxValueSize: 1024,
+
+		// Query results are sorted by the sort key.
+		OrderedRange:    true,
+		MeteredRequests: true,
+	}
+}
@@ -0,0 +1,43 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xaws"
+	"github.com/dogmatiq/persistencekit/health"
+)
+
+// CheckHealth checks that the DynamoDB table used by each store exists and is
+// available.
+func (d *Driver) CheckHealth(ctx context.Context) health.Report {
+	return health.NewReport(
+		func(s health.Store) error {
+			table := d.table(s)
+
+			res, err := xaws.Do(
+				ctx,
+				d.client.DescribeTable,
+				nil,
+				&awsdynamodb.DescribeTableInput{
+					TableName: &table,
+				},
+			)
+			if errors.As(err, new(*types.ResourceNotFoundException)) {
+				return fmt.Errorf("the %q DynamoDB table does not exist, the %s store has not been provisioned", table, s)
+			} else if err != nil {
+				return fmt.Errorf("unable to describe the %q DynamoDB table: %w", table, err)
+			}
+
+			switch res.Table.TableStatus {
+			case types.TableStatusActive, types.TableStatusUpdating:
+				return nil
+			default:
+				return fmt.Errorf("the %q DynamoDB table is not available (status: %s)", table, res.Table.TableStatus)
+			}
+		},
+	)
+}
@@ -0,0 +1,142 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamoblob"
+	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamojournal"
+	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamokv"
+	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamolease"
+	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamoqueue"
+	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamoschedule"
+	"github.com/dogmatiq/persistencekit/driver/aws/dynamodb/dynamoset"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xaws"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xdynamodb"
+	"github.com/dogmatiq/persistencekit/health"
+	"github.com/dogmatiq/persistencekit/provision"
+)
+
+// createTableInput returns a function that returns the CreateTable request
+// used to create each store's table.
+var createTableInput = map[health.Store]func(table string) *awsdynamodb.CreateTableInput{
+	health.JournalStore:  dynamojournal.CreateTableInput,
+	health.KVStore:       dynamokv.CreateTableInput,
+	health.SetStore:      dynamoset.CreateTableInput,
+	health.LeaseStore:    dynamolease.CreateTableInput,
+	health.QueueStore:    dynamoqueue.CreateTableInput,
+	health.ScheduleStore: dynamoschedule.CreateTableInput,
+	health.BlobStore:     dynamoblob.CreateTableInput,
+}
+
+// PlanProvision returns the CreateTable requests that would be made to create
+// the table of each store that has not been provisioned.
+func (d *Driver) PlanProvision(ctx context.Context) (provision.Plan, error) {
+	var plan provision.Plan
+
+	for _, s := range health.Stores() {
+		table := d.table(s)
+
+		exists, err := d.tableExists(ctx, table)
+		if err != nil {
+			return provision.Plan{}, err
+		}
+
+		if !exists {
+			plan.Changes = append(
+				plan.Changes,
+				provision.Change{
+					Action:   provision.Create,
+					Resource: fmt.Sprintf("DynamoDB table %q", table),
+					Detail:   xaws.Describe(createTableInput[s](table)),
+				},
+			)
+		}
+	}
+
+	return plan, nil
+}
+
+// PlanDeprovision returns the tables that [Driver.Deprovision] would delete.
+func (d *Driver) PlanDeprovision(ctx context.Context) (provision.Plan, error) {
+	var plan provision.Plan
+
+	for _, s := range health.Stores() {
+		table := d.table(s)
+
+		exists, err := d.tableExists(ctx, table)
+		if err != nil {
+			return provision.Plan{}, err
+		}
+
+		if exists {
+			plan.Changes = append(
+				plan.Changes,
+				provision.Change{
+					Action:   provision.Delete,
+					Resource: fmt.Sprintf("DynamoDB table %q", table),
+				},
+			)
+		}
+	}
+
+	return plan, nil
+}
+
+// Deprovision deletes the table used by each store, and waits for the
+// deletions to complete.
+func (d *Driver) Deprovision(ctx context.Context) error {
+	for _, s := range health.Stores() {
+		if err := xdynamodb.DeleteTableIfExists(ctx, d.client, d.table(s), nil); err != nil {
+			return err
+		}
+	}
+
+	w := awsdynamodb.NewTableNotExistsWaiter(d.client)
+
+	for _, s := range health.Stores() {
+		table := d.table(s)
+
+		// We set the maximum wait time quite high, as the deadline from ctx, if
+		// shorter, will take precedence.
+		if err := w.Wait(
+			ctx,
+			&awsdynamodb.DescribeTableInput{
+				TableName: &table,
+			},
+			5*time.Minute,
+		); err != nil {
+			return fmt.Errorf("unable to wait for the %q DynamoDB table to be deleted: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+// table returns the name of the table used by the given store.
+func (d *Driver) table(s health.Store) string {
+	return d.tablePrefix + "-" + string(s)
+}
+
+// tableExists returns true if the given table exists and is not being deleted.
+func (d *Driver) tableExists(ctx context.Context, table string) (bool, error) {
+	res, err := xaws.Do(
+		ctx,
+		d.client.DescribeTable,
+		nil,
+		&awsdynamodb.DescribeTableInput{
+			TableName: &table,
+		},
+	)
+	if errors.As(err, new(*types.ResourceNotFoundException)) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("unable to describe the %q DynamoDB table: %w", table, err)
+	}
+
+	return res.Table.TableStatus != types.TableStatusDeleting, nil
+}
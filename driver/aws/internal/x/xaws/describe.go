@@ -0,0 +1,66 @@
+package xaws
+
+import (
+	"encoding/json"
+)
+
+// Describe returns a human-readable JSON representation of an AWS API request
+// or value, omitting any fields that are not set.
+func Describe(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+
+	var m any
+	if err := json.Unmarshal(data, &m); err != nil {
+		panic(err)
+	}
+
+	data, err = json.MarshalIndent(prune(m), "", "  ")
+	if err != nil {
+		panic(err)
+	}
+
+	return string(data)
+}
+
+// prune removes null values, empty strings, and empty objects and arrays, from
+// the JSON value v. It returns nil if v itself is empty.
+func prune(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, x := range v {
+			if x = prune(x); x == nil {
+				delete(v, k)
+			} else {
+				v[k] = x
+			}
+		}
+		if len(v) == 0 {
+			return nil
+		}
+		return v
+
+	case []any:
+		var pruned []any
+		for _, x := range v {
+			if x = prune(x); x != nil {
+				pruned = append(pruned, x)
+			}
+		}
+		if len(pruned) == 0 {
+			return nil
+		}
+		return pruned
+
+	case string:
+		if v == "" {
+			return nil
+		}
+		return v
+
+	default:
+		return v
+	}
+}
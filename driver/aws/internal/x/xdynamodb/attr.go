@@ -62,6 +62,18 @@ func AsNumericString(
 	return attr.Value, nil
 }
 
+// AsString fetches a string attribute from an item.
+func AsString(
+	item map[string]types.AttributeValue,
+	name string,
+) (string, error) {
+	attr, err := attrAs[*types.AttributeValueMemberS](item, name)
+	if err != nil {
+		return "", err
+	}
+	return attr.Value, nil
+}
+
 // AsBytes fetches a binary attribute from an item.
 func AsBytes(
 	item map[string]types.AttributeValue,
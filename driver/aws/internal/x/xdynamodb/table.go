@@ -18,6 +18,13 @@ type KeyAttr struct {
 	KeyType types.KeyType
 }
 
+// LocalIndex describes a local secondary index of a DynamoDB table. The index
+// shares the table's hash key and projects all attributes.
+type LocalIndex struct {
+	Name     *string
+	RangeKey KeyAttr
+}
+
 // CreateTableIfNotExists creates a DynamoDB table if it does not exist. It
 // returns true if the table was created, or false if it already existed.
 func CreateTableIfNotExists(
@@ -26,6 +33,20 @@ func CreateTableIfNotExists(
 	table string,
 	onRequest func(any) []func(*dynamodb.Options),
 	key ...KeyAttr,
+) (bool, error) {
+	return CreateIndexedTableIfNotExists(ctx, client, table, onRequest, key, nil)
+}
+
+// CreateIndexedTableIfNotExists creates a DynamoDB table with local secondary
+// indexes if it does not exist. It returns true if the table was created, or
+// false if it already existed.
+func CreateIndexedTableIfNotExists(
+	ctx context.Context,
+	client *dynamodb.Client,
+	table string,
+	onRequest func(any) []func(*dynamodb.Options),
+	key []KeyAttr,
+	indexes []LocalIndex,
 ) (bool, error) {
 	var created bool
 
@@ -39,7 +60,7 @@ func CreateTableIfNotExists(
 	)
 	if errors.As(err, new(*types.ResourceNotFoundException)) {
 		var err error
-		created, err = createTable(ctx, client, table, onRequest, key)
+		created, err = createTable(ctx, client, table, onRequest, key, indexes)
 		if err != nil {
 			return false, err
 		}
@@ -64,7 +85,32 @@ func createTable(
 	table string,
 	onRequest func(any) []func(*dynamodb.Options),
 	key []KeyAttr,
+	indexes []LocalIndex,
 ) (bool, error) {
+	req := NewCreateTableInput(table, key, indexes)
+
+	if _, err := xaws.Do(
+		ctx,
+		client.CreateTable,
+		onRequest,
+		req,
+	); err != nil {
+		if errors.As(err, new(*types.ResourceInUseException)) {
+			return false, nil
+		}
+		return false, fmt.Errorf("unable to create DynamoDB table: %w", err)
+	}
+
+	return true, nil
+}
+
+// NewCreateTableInput returns the parameters of the CreateTable request used
+// to create a table with the given key and local secondary indexes.
+func NewCreateTableInput(
+	table string,
+	key []KeyAttr,
+	indexes []LocalIndex,
+) *dynamodb.CreateTableInput {
 	req := &dynamodb.CreateTableInput{
 		TableName:   &table,
 		BillingMode: types.BillingModePayPerRequest,
@@ -88,19 +134,46 @@ func createTable(
 		)
 	}
 
-	if _, err := xaws.Do(
-		ctx,
-		client.CreateTable,
-		onRequest,
-		req,
-	); err != nil {
-		if errors.As(err, new(*types.ResourceInUseException)) {
-			return false, nil
+	for _, idx := range indexes {
+		req.AttributeDefinitions = append(
+			req.AttributeDefinitions,
+			types.AttributeDefinition{
+				AttributeName: idx.RangeKey.Name,
+				AttributeType: idx.RangeKey.Type,
+			},
+		)
+
+		index := types.LocalSecondaryIndex{
+			IndexName: idx.Name,
+			Projection: &types.Projection{
+				ProjectionType: types.ProjectionTypeAll,
+			},
 		}
-		return false, fmt.Errorf("unable to create DynamoDB table: %w", err)
+
+		for _, k := range key {
+			if k.KeyType == types.KeyTypeHash {
+				index.KeySchema = append(
+					index.KeySchema,
+					types.KeySchemaElement{
+						AttributeName: k.Name,
+						KeyType:       types.KeyTypeHash,
+					},
+				)
+			}
+		}
+
+		index.KeySchema = append(
+			index.KeySchema,
+			types.KeySchemaElement{
+				AttributeName: idx.RangeKey.Name,
+				KeyType:       types.KeyTypeRange,
+			},
+		)
+
+		req.LocalSecondaryIndexes = append(req.LocalSecondaryIndexes, index)
 	}
 
-	return true, nil
+	return req
 }
 
 // waitForTable blocks until the given DynamoDB table is created.
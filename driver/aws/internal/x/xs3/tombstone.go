@@ -27,6 +27,24 @@ const (
 // TombstoneTagging is the URL-encoded tag string applied to tombstone objects.
 var TombstoneTagging = aws.String(url.Values{TombstoneTagKey: []string{TombstoneTagValue}}.Encode())
 
+// TombstoneLifecycleRule returns the S3 lifecycle rule that expires tombstone
+// objects.
+func TombstoneLifecycleRule() types.LifecycleRule {
+	return types.LifecycleRule{
+		ID:     aws.String(TombstoneLifecycleRuleID),
+		Status: types.ExpirationStatusEnabled,
+		Filter: &types.LifecycleRuleFilter{
+			Tag: &types.Tag{
+				Key:   aws.String(TombstoneTagKey),
+				Value: aws.String(TombstoneTagValue),
+			},
+		},
+		Expiration: &types.LifecycleExpiration{
+			Days: aws.Int32(1),
+		},
+	}
+}
+
 // EnsureTombstoneLifecycleRule adds an S3 lifecycle rule to expire tombstone
 // objects if one is not already present. It reads the existing rules before
 // writing to avoid clobbering any user-managed rules.
@@ -36,6 +54,30 @@ func EnsureTombstoneLifecycleRule(
 	bucket string,
 	onRequest func(any) []func(*s3.Options),
 ) error {
+	in, err := PlanTombstoneLifecycleRule(ctx, client, bucket, onRequest)
+	if err != nil || in == nil {
+		return err
+	}
+
+	_, err = xaws.Do(
+		ctx,
+		client.PutBucketLifecycleConfiguration,
+		onRequest,
+		in,
+	)
+
+	return err
+}
+
+// PlanTombstoneLifecycleRule returns the request that
+// [EnsureTombstoneLifecycleRule] would make to add the lifecycle rule that
+// expires tombstone objects, or nil if the rule is already present.
+func PlanTombstoneLifecycleRule(
+	ctx context.Context,
+	client *s3.Client,
+	bucket string,
+	onRequest func(any) []func(*s3.Options),
+) (*s3.PutBucketLifecycleConfigurationInput, error) {
 	res, err := xaws.Do(
 		ctx,
 		client.GetBucketLifecycleConfiguration,
@@ -49,42 +91,21 @@ func EnsureTombstoneLifecycleRule(
 	if err != nil {
 		var apiErr smithy.APIError
 		if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "NoSuchLifecycleConfiguration" {
-			return err
+			return nil, err
 		}
 	} else {
 		for _, r := range res.Rules {
 			if aws.ToString(r.ID) == TombstoneLifecycleRuleID {
-				return nil // Rule already present.
+				return nil, nil // Rule already present.
 			}
 		}
 		rules = res.Rules
 	}
 
-	rules = append(rules, types.LifecycleRule{
-		ID:     aws.String(TombstoneLifecycleRuleID),
-		Status: types.ExpirationStatusEnabled,
-		Filter: &types.LifecycleRuleFilter{
-			Tag: &types.Tag{
-				Key:   aws.String(TombstoneTagKey),
-				Value: aws.String(TombstoneTagValue),
-			},
+	return &s3.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucket),
+		LifecycleConfiguration: &types.BucketLifecycleConfiguration{
+			Rules: append(rules, TombstoneLifecycleRule()),
 		},
-		Expiration: &types.LifecycleExpiration{
-			Days: aws.Int32(1),
-		},
-	})
-
-	_, err = xaws.Do(
-		ctx,
-		client.PutBucketLifecycleConfiguration,
-		onRequest,
-		&s3.PutBucketLifecycleConfigurationInput{
-			Bucket: aws.String(bucket),
-			LifecycleConfiguration: &types.BucketLifecycleConfiguration{
-				Rules: rules,
-			},
-		},
-	)
-
-	return err
+	}, nil
 }
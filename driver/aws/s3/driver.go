@@ -9,13 +9,21 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/dogmatiq/persistencekit/blob"
 	"github.com/dogmatiq/persistencekit/driver"
 	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xaws"
+	"github.com/dogmatiq/persistencekit/driver/aws/s3/s3blob"
 	"github.com/dogmatiq/persistencekit/driver/aws/s3/s3journal"
 	"github.com/dogmatiq/persistencekit/driver/aws/s3/s3kv"
+	"github.com/dogmatiq/persistencekit/driver/aws/s3/s3lease"
+	"github.com/dogmatiq/persistencekit/driver/aws/s3/s3queue"
+	"github.com/dogmatiq/persistencekit/driver/aws/s3/s3schedule"
 	"github.com/dogmatiq/persistencekit/driver/aws/s3/s3set"
 	"github.com/dogmatiq/persistencekit/journal"
 	"github.com/dogmatiq/persistencekit/kv"
+	"github.com/dogmatiq/persistencekit/lease"
+	"github.com/dogmatiq/persistencekit/queue"
+	"github.com/dogmatiq/persistencekit/schedule"
 	"github.com/dogmatiq/persistencekit/set"
 )
 
@@ -136,6 +144,32 @@ func (d *Driver) SetStore() set.BinaryStore {
 	return s3set.NewBinaryStore(d.client, d.bucket)
 }
 
+// LeaseStore returns a lease store backed by S3.
+func (d *Driver) LeaseStore() lease.Store {
+	return s3lease.NewStore(d.client, d.bucket)
+}
+
+// QueueStore returns a queue store backed by S3.
+func (d *Driver) QueueStore() queue.BinaryStore {
+	return s3queue.NewBinaryStore(d.client, d.bucket)
+}
+
+// ScheduleStore returns a schedule store backed by S3.
+func (d *Driver) ScheduleStore() schedule.BinaryStore {
+	return s3schedule.NewBinaryStore(d.client, d.bucket)
+}
+
+// BlobStore returns a blob store backed by S3.
+func (d *Driver) BlobStore() blob.Store {
+	return s3blob.NewStore(d.client, d.bucket)
+}
+
+// Capabilities returns the limits and optional features of the driver's
+// stores.
+func (d *Driver) Capabilities() driver.Capabilities {
+	return driver.StoreCapabilities(d)
+}
+
 // Close is a no-op. The S3 client does not require explicit cleanup.
 func (d *Driver) Close() error {
 	return nil
@@ -6,8 +6,12 @@ import (
 
 	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xs3"
 	"github.com/dogmatiq/persistencekit/driver/aws/s3"
+	"github.com/dogmatiq/persistencekit/driver/aws/s3/s3blob"
 	"github.com/dogmatiq/persistencekit/driver/aws/s3/s3journal"
 	"github.com/dogmatiq/persistencekit/driver/aws/s3/s3kv"
+	"github.com/dogmatiq/persistencekit/driver/aws/s3/s3lease"
+	"github.com/dogmatiq/persistencekit/driver/aws/s3/s3queue"
+	"github.com/dogmatiq/persistencekit/driver/aws/s3/s3schedule"
 	"github.com/dogmatiq/persistencekit/driver/aws/s3/s3set"
 	"github.com/dogmatiq/persistencekit/internal/drivertest"
 	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
@@ -29,9 +33,73 @@ func TestNew(t *testing.T) {
 		s3journal.NewBinaryStore(client, bucket),
 		s3kv.NewBinaryStore(client, bucket),
 		s3set.NewBinaryStore(client, bucket),
+		s3lease.NewStore(client, bucket),
+		s3queue.NewBinaryStore(client, bucket),
+		s3schedule.NewBinaryStore(client, bucket),
+		s3blob.NewStore(client, bucket),
 	)
 }
 
+func TestCheckHealth(t *testing.T) {
+	client, _ := xs3.NewTestClient(t)
+	bucket := xtesting.UniqueName("health")
+	xs3.CleanupBucket(t, client, bucket)
+
+	d := s3.NewFromClient(client, bucket)
+	t.Cleanup(func() {
+		d.Close()
+	})
+
+	drivertest.RunHealthTests(t, d)
+}
+
+func TestProvision(t *testing.T) {
+	client, _ := xs3.NewTestClient(t)
+	bucket := xtesting.UniqueName("provision")
+	xs3.CleanupBucket(t, client, bucket)
+
+	d := s3.NewFromClient(client, bucket)
+	t.Cleanup(func() {
+		d.Close()
+	})
+
+	drivertest.RunProvisionTests(t, d)
+}
+
+func TestBackup(t *testing.T) {
+	client, _ := xs3.NewTestClient(t)
+
+	var drivers []*s3.Driver
+	for _, bucket := range []string{
+		xtesting.UniqueName("backup-source"),
+		xtesting.UniqueName("backup-target"),
+	} {
+		xs3.CleanupBucket(t, client, bucket)
+
+		d := s3.NewFromClient(client, bucket)
+		t.Cleanup(func() {
+			d.Close()
+		})
+
+		drivers = append(drivers, d)
+	}
+
+	drivertest.RunBackupTests(t, drivers[0], drivers[1])
+}
+
+func TestChangeFeed(t *testing.T) {
+	client, _ := xs3.NewTestClient(t)
+	bucket := xtesting.UniqueName("change-feed")
+	xs3.CleanupBucket(t, client, bucket)
+
+	d := s3.NewFromClient(client, bucket)
+	t.Cleanup(func() {
+		d.Close()
+	})
+
+	drivertest.RunChangeFeedTests(t, d)
+}
+
 func TestParseURL(t *testing.T) {
 	client, endpoint := xs3.NewTestClient(t)
 	bucket := xtesting.UniqueName("url")
@@ -59,6 +127,10 @@ func TestParseURL(t *testing.T) {
 		s3journal.NewBinaryStore(client, bucket),
 		s3kv.NewBinaryStore(client, bucket),
 		s3set.NewBinaryStore(client, bucket),
+		s3lease.NewStore(client, bucket),
+		s3queue.NewBinaryStore(client, bucket),
+		s3schedule.NewBinaryStore(client, bucket),
+		s3blob.NewStore(client, bucket),
 	)
 }
 
@@ -91,6 +163,10 @@ func TestFromURL(t *testing.T) {
 			s3journal.NewBinaryStore(client, bucket),
 			s3kv.NewBinaryStore(client, bucket),
 			s3set.NewBinaryStore(client, bucket),
+			s3lease.NewStore(client, bucket),
+			s3queue.NewBinaryStore(client, bucket),
+			s3schedule.NewBinaryStore(client, bucket),
+			s3blob.NewStore(client, bucket),
 		)
 	})
 
@@ -0,0 +1,82 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xaws"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xs3"
+	"github.com/dogmatiq/persistencekit/health"
+)
+
+// CheckHealth checks that the S3 bucket exists and is accessible, and that the
+// lifecycle rule that expires tombstone objects is present for the stores that
+// rely on it.
+func (d *Driver) CheckHealth(ctx context.Context) health.Report {
+	bucketErr := d.checkBucket(ctx)
+	ruleErr := bucketErr
+	if ruleErr == nil {
+		ruleErr = d.checkTombstoneLifecycleRule(ctx)
+	}
+
+	return health.NewReport(
+		func(s health.Store) error {
+			switch s {
+			case health.JournalStore, health.LeaseStore:
+				return bucketErr
+			default:
+				if ruleErr != nil {
+					return fmt.Errorf("%w, the %s store has not been provisioned", ruleErr, s)
+				}
+				return nil
+			}
+		},
+	)
+}
+
+func (d *Driver) checkBucket(ctx context.Context) error {
+	exists, err := d.bucketExists(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("the %q S3 bucket does not exist", d.bucket)
+	}
+
+	return nil
+}
+
+func (d *Driver) checkTombstoneLifecycleRule(ctx context.Context) error {
+	res, err := xaws.Do(
+		ctx,
+		d.client.GetBucketLifecycleConfiguration,
+		nil,
+		&awss3.GetBucketLifecycleConfigurationInput{
+			Bucket: aws.String(d.bucket),
+		},
+	)
+	if err != nil {
+		var apiErr smithy.APIError
+		if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "NoSuchLifecycleConfiguration" {
+			return fmt.Errorf("unable to read the lifecycle configuration of the %q S3 bucket: %w", d.bucket, err)
+		}
+	} else {
+		for _, r := range res.Rules {
+			if aws.ToString(r.ID) != xs3.TombstoneLifecycleRuleID {
+				continue
+			}
+			if r.Status != types.ExpirationStatusEnabled {
+				return fmt.Errorf("the %q lifecycle rule of the %q S3 bucket is disabled", xs3.TombstoneLifecycleRuleID, d.bucket)
+			}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("the %q S3 bucket does not have the %q lifecycle rule", d.bucket, xs3.TombstoneLifecycleRuleID)
+}
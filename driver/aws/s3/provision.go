@@ -0,0 +1,108 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xaws"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xs3"
+	"github.com/dogmatiq/persistencekit/provision"
+)
+
+// PlanProvision returns the changes that would be made to create the S3
+// bucket, and to add the lifecycle rule that expires tombstone objects.
+func (d *Driver) PlanProvision(ctx context.Context) (provision.Plan, error) {
+	exists, err := d.bucketExists(ctx)
+	if err != nil {
+		return provision.Plan{}, err
+	}
+
+	var plan provision.Plan
+
+	if !exists {
+		plan.Changes = append(
+			plan.Changes,
+			provision.Change{
+				Action:   provision.Create,
+				Resource: fmt.Sprintf("S3 bucket %q", d.bucket),
+				Detail: xaws.Describe(
+					&awss3.CreateBucketInput{
+						Bucket: aws.String(d.bucket),
+					},
+				),
+			},
+		)
+	} else {
+		in, err := xs3.PlanTombstoneLifecycleRule(ctx, d.client, d.bucket, nil)
+		if err != nil {
+			return provision.Plan{}, fmt.Errorf("unable to read the lifecycle configuration of the %q S3 bucket: %w", d.bucket, err)
+		}
+
+		if in == nil {
+			return plan, nil
+		}
+	}
+
+	plan.Changes = append(
+		plan.Changes,
+		provision.Change{
+			Action:   provision.Create,
+			Resource: fmt.Sprintf("S3 lifecycle rule %q on bucket %q", xs3.TombstoneLifecycleRuleID, d.bucket),
+			Detail:   xaws.Describe(xs3.TombstoneLifecycleRule()),
+		},
+	)
+
+	return plan, nil
+}
+
+// PlanDeprovision returns the changes that [Driver.Deprovision] would make.
+func (d *Driver) PlanDeprovision(ctx context.Context) (provision.Plan, error) {
+	exists, err := d.bucketExists(ctx)
+	if err != nil {
+		return provision.Plan{}, err
+	}
+
+	var plan provision.Plan
+
+	if exists {
+		plan.Changes = append(
+			plan.Changes,
+			provision.Change{
+				Action:   provision.Delete,
+				Resource: fmt.Sprintf("S3 bucket %q", d.bucket),
+				Detail:   "All objects in the bucket are deleted, including any that were not written by persistencekit.",
+			},
+		)
+	}
+
+	return plan, nil
+}
+
+// Deprovision deletes the S3 bucket and all of the objects in it.
+//
+// The bucket is shared by all of the driver's stores, so it must not be used
+// for any other purpose.
+func (d *Driver) Deprovision(ctx context.Context) error {
+	return xs3.DeleteBucketIfExists(ctx, d.client, d.bucket, nil)
+}
+
+// bucketExists returns true if the S3 bucket exists.
+func (d *Driver) bucketExists(ctx context.Context) (bool, error) {
+	if _, err := xaws.Do(
+		ctx,
+		d.client.HeadBucket,
+		nil,
+		&awss3.HeadBucketInput{
+			Bucket: aws.String(d.bucket),
+		},
+	); err != nil {
+		if xs3.IsNotExists(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("unable to access the %q S3 bucket: %w", d.bucket, err)
+	}
+
+	return true, nil
+}
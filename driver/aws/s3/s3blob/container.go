@@ -0,0 +1,452 @@
+package s3blob
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/dogmatiq/persistencekit/blob"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xaws"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xs3"
+	"github.com/dogmatiq/persistencekit/internal/x/xerrors"
+)
+
+const (
+	// refsMetaData is the name of the reference object meta-data that contains
+	// the number of references to the blob.
+	refsMetaData = "refs"
+
+	// sizeMetaData is the name of the reference object meta-data that contains
+	// the size of the blob's content, in bytes.
+	sizeMetaData = "size"
+
+	// uploadMetaData is the name of the reference object meta-data that
+	// contains the ID of the upload that wrote the blob's content. It is empty
+	// if the blob has no content.
+	uploadMetaData = "upload"
+)
+
+// refBody is the body of each live reference object.
+//
+// Tombstones are size-zero objects (tagged for lifecycle expiry); the body
+// ensures that a reference object can be distinguished from a tombstone.
+var refBody = []byte{'R'}
+
+// partSize is the size of each part of a multipart upload, in bytes. Content
+// that fits within a single part is written using a single request.
+const partSize = 8 << 20
+
+// container is an implementation of [blob.Container] that persists to an S3
+// bucket.
+//
+// Each blob is represented by a "reference object", keyed by the hash of the
+// blob's content, that records the number of references to the blob and the
+// key of the "data object" that contains the content itself.
+type container struct {
+	client    *s3.Client
+	onRequest func(any) []func(*s3.Options)
+
+	// name is the container name.
+	name string
+
+	// bucket is the name of the S3 bucket in which the container's blobs are
+	// stored.
+	bucket string
+
+	// refKeyPrefix and dataKeyPrefix are the prefixes of the keys of the
+	// reference and data objects, respectively.
+	refKeyPrefix, dataKeyPrefix string
+}
+
+// ref is the state of a blob as stored in a reference object.
+type ref struct {
+	ETag   string
+	Exists bool
+	Refs   uint64
+	Size   int64
+	Upload string
+}
+
+func (c *container) Name() string {
+	return c.name
+}
+
+func (c *container) Put(ctx context.Context, r io.Reader) (_ blob.Ref, err error) {
+	defer xerrors.Wrap(&err, "unable to store blob in the %q container", c.name)
+
+	hash := sha256.New()
+
+	uploadID, size, err := c.upload(ctx, io.TeeReader(r, hash))
+	if err != nil {
+		return blob.Ref{}, err
+	}
+
+	result := blob.Ref{Size: size}
+	hash.Sum(result.Hash[:0])
+
+	key := c.refKey(result.Hash)
+
+	// Remove the uploaded content if the blob is not stored successfully, or
+	// if identical content is already stored. Errors are ignored, as the
+	// reference may already have been recorded, in which case the blob is
+	// stored successfully and the caller must not retry. Any content that is
+	// not removed is unreferenced, and does not affect the blob.
+	discard := uploadID != ""
+	defer func() {
+		if discard {
+			_ = c.remove(context.WithoutCancel(ctx), c.dataKey(uploadID), "")
+		}
+	}()
+
+	for {
+		current, err := c.head(ctx, key)
+		if err != nil {
+			return blob.Ref{}, err
+		}
+
+		next := ref{
+			Refs:   1,
+			Size:   size,
+			Upload: uploadID,
+		}
+
+		if current.Exists {
+			next = current
+			next.Refs++
+		}
+
+		if err := c.putRef(ctx, key, current.ETag, next); err == nil {
+			discard = current.Exists
+			return result, nil
+		} else if !xs3.IsConflict(err) && !xs3.IsNotExists(err) && !xs3.IsAlreadyExists(err) {
+			return blob.Ref{}, err
+		}
+		// The reference object was modified concurrently; retry.
+	}
+}
+
+func (c *container) Get(ctx context.Context, h blob.Hash) (_ io.ReadCloser, _ bool, err error) {
+	defer xerrors.Wrap(&err, "unable to read blob from the %q container", c.name)
+
+	current, err := c.head(ctx, c.refKey(h))
+	if !current.Exists || err != nil {
+		return nil, false, err
+	}
+
+	if current.Upload == "" {
+		return io.NopCloser(xs3.NewReadSeeker(nil)), true, nil
+	}
+
+	res, err := xaws.Do(
+		ctx,
+		c.client.GetObject,
+		c.onRequest,
+		&s3.GetObjectInput{
+			Bucket: &c.bucket,
+			Key:    aws.String(c.dataKey(current.Upload)),
+		},
+	)
+	if err != nil && !xs3.IsNotExists(err) {
+		return nil, false, err
+	}
+
+	// The data object is replaced with a tombstone when the blob is removed.
+	if err != nil || aws.ToInt64(res.ContentLength) != current.Size {
+		if res != nil {
+			res.Body.Close()
+		}
+		return nil, false, errors.New("blob was removed while it was being read")
+	}
+
+	return res.Body, true, nil
+}
+
+func (c *container) Has(ctx context.Context, h blob.Hash) (_ bool, err error) {
+	defer xerrors.Wrap(&err, "unable to determine if blob exists in the %q container", c.name)
+
+	current, err := c.head(ctx, c.refKey(h))
+	return current.Exists, err
+}
+
+func (c *container) Release(ctx context.Context, h blob.Hash) (err error) {
+	defer xerrors.Wrap(&err, "unable to release blob in the %q container", c.name)
+
+	key := c.refKey(h)
+
+	for {
+		current, err := c.head(ctx, key)
+		if !current.Exists || err != nil {
+			return err
+		}
+
+		if current.Refs > 1 {
+			next := current
+			next.Refs--
+			err = c.putRef(ctx, key, current.ETag, next)
+		} else {
+			err = c.remove(ctx, key, current.ETag)
+		}
+
+		if err == nil {
+			if current.Refs > 1 || current.Upload == "" {
+				return nil
+			}
+			return c.remove(ctx, c.dataKey(current.Upload), "")
+		}
+
+		if !xs3.IsConflict(err) && !xs3.IsNotExists(err) {
+			return err
+		}
+		// The reference object was modified concurrently; retry.
+	}
+}
+
+func (c *container) Close() error {
+	return nil
+}
+
+// upload writes the content read from r to a new data object. It returns the
+// ID of the upload, which is empty if r produces no content.
+func (c *container) upload(ctx context.Context, r io.Reader) (string, int64, error) {
+	data := make([]byte, partSize)
+
+	n, err := io.ReadFull(r, data)
+	if err == io.EOF {
+		return "", 0, nil
+	}
+
+	uploadID := newUploadID()
+	key := c.dataKey(uploadID)
+
+	if err == io.ErrUnexpectedEOF {
+		_, err := xaws.Do(
+			ctx,
+			c.client.PutObject,
+			c.onRequest,
+			&s3.PutObjectInput{
+				Bucket:        &c.bucket,
+				Key:           &key,
+				Body:          xs3.NewReadSeeker(data[:n]),
+				ContentLength: aws.Int64(int64(n)),
+			},
+		)
+		return uploadID, int64(n), err
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	size, err := c.uploadParts(ctx, key, r, data)
+	return uploadID, size, err
+}
+
+// uploadParts writes the content read from r to the object at key using a
+// multipart upload. data is a buffer that already contains the first part.
+func (c *container) uploadParts(ctx context.Context, key string, r io.Reader, data []byte) (size int64, err error) {
+	res, err := xaws.Do(
+		ctx,
+		c.client.CreateMultipartUpload,
+		c.onRequest,
+		&s3.CreateMultipartUploadInput{
+			Bucket: &c.bucket,
+			Key:    &key,
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		if err != nil {
+			_, _ = xaws.Do(
+				context.WithoutCancel(ctx),
+				c.client.AbortMultipartUpload,
+				c.onRequest,
+				&s3.AbortMultipartUploadInput{
+					Bucket:   &c.bucket,
+					Key:      &key,
+					UploadId: res.UploadId,
+				},
+			)
+		}
+	}()
+
+	var parts []types.CompletedPart
+	n := len(data)
+
+	for {
+		part, err := xaws.Do(
+			ctx,
+			c.client.UploadPart,
+			c.onRequest,
+			&s3.UploadPartInput{
+				Bucket:        &c.bucket,
+				Key:           &key,
+				UploadId:      res.UploadId,
+				PartNumber:    aws.Int32(int32(len(parts) + 1)),
+				Body:          xs3.NewReadSeeker(data[:n]),
+				ContentLength: aws.Int64(int64(n)),
+			},
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		parts = append(parts, types.CompletedPart{
+			ETag:       part.ETag,
+			PartNumber: aws.Int32(int32(len(parts) + 1)),
+		})
+		size += int64(n)
+
+		n, err = io.ReadFull(r, data)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return 0, err
+		}
+	}
+
+	if _, err := xaws.Do(
+		ctx,
+		c.client.CompleteMultipartUpload,
+		c.onRequest,
+		&s3.CompleteMultipartUploadInput{
+			Bucket:   &c.bucket,
+			Key:      &key,
+			UploadId: res.UploadId,
+			MultipartUpload: &types.CompletedMultipartUpload{
+				Parts: parts,
+			},
+		},
+	); err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
+
+// head returns the state of the blob stored in the reference object at key. If
+// the object does not exist, ETag is empty.
+func (c *container) head(ctx context.Context, key string) (ref, error) {
+	res, err := xaws.Do(
+		ctx,
+		c.client.HeadObject,
+		c.onRequest,
+		&s3.HeadObjectInput{
+			Bucket: &c.bucket,
+			Key:    &key,
+		},
+	)
+	if xs3.IsNotExists(err) {
+		return ref{}, nil
+	}
+	if err != nil {
+		return ref{}, err
+	}
+
+	r := ref{
+		ETag: aws.ToString(res.ETag),
+	}
+
+	if aws.ToInt64(res.ContentLength) == 0 {
+		return r, nil // tombstone
+	}
+
+	r.Exists = true
+	r.Upload = res.Metadata[uploadMetaData]
+
+	r.Refs, err = strconv.ParseUint(res.Metadata[refsMetaData], 10, 64)
+	if err != nil {
+		return ref{}, fmt.Errorf("integrity error: %q meta-data is malformed: %w", refsMetaData, err)
+	}
+
+	r.Size, err = strconv.ParseInt(res.Metadata[sizeMetaData], 10, 64)
+	if err != nil {
+		return ref{}, fmt.Errorf("integrity error: %q meta-data is malformed: %w", sizeMetaData, err)
+	}
+
+	return r, nil
+}
+
+// putRef writes r to the reference object at key. If etag is non-empty, the
+// write only succeeds if the object's ETag still matches etag; otherwise, it
+// only succeeds if the object does not exist.
+func (c *container) putRef(ctx context.Context, key, etag string, r ref) error {
+	req := &s3.PutObjectInput{
+		Bucket:        &c.bucket,
+		Key:           &key,
+		Body:          xs3.NewReadSeeker(refBody),
+		ContentLength: aws.Int64(int64(len(refBody))),
+		Metadata: map[string]string{
+			refsMetaData:   strconv.FormatUint(r.Refs, 10),
+			sizeMetaData:   strconv.FormatInt(r.Size, 10),
+			uploadMetaData: r.Upload,
+		},
+	}
+
+	if etag == "" {
+		req.IfNoneMatch = aws.String("*")
+	} else {
+		req.IfMatch = aws.String(etag)
+	}
+
+	_, err := xaws.Do(
+		ctx,
+		c.client.PutObject,
+		c.onRequest,
+		req,
+	)
+	return err
+}
+
+// remove replaces the object at key with a tombstone. If etag is non-empty,
+// the write only succeeds if the object's ETag still matches etag.
+func (c *container) remove(ctx context.Context, key, etag string) error {
+	req := &s3.PutObjectInput{
+		Bucket:        &c.bucket,
+		Key:           &key,
+		Body:          xs3.NewReadSeeker(nil),
+		ContentLength: aws.Int64(0),
+		Tagging:       xs3.TombstoneTagging,
+	}
+
+	if etag != "" {
+		req.IfMatch = aws.String(etag)
+	}
+
+	_, err := xaws.Do(
+		ctx,
+		c.client.PutObject,
+		c.onRequest,
+		req,
+	)
+	return err
+}
+
+// refKey returns the key of the reference object for the blob with the given
+// hash.
+func (c *container) refKey(h blob.Hash) string {
+	return c.refKeyPrefix + h.String()
+}
+
+// dataKey returns the key of the data object written by the upload with the
+// given ID.
+func (c *container) dataKey(uploadID string) string {
+	return c.dataKeyPrefix + uploadID
+}
+
+// newUploadID returns a new random upload ID.
+func newUploadID() string {
+	var data [16]byte
+	_, _ = rand.Read(data[:])
+	return hex.EncodeToString(data[:])
+}
@@ -0,0 +1,35 @@
+// Package s3blob provides a [blob.Store] implementation that persists to an S3
+// bucket.
+//
+// The content of each blob is stored in a single "data object", which is
+// written using a multipart upload when the content is too large to be written
+// in a single request. Each blob is also represented by a small "reference
+// object", keyed by the hash of its content, that records the number of
+// references to the blob. Changes to reference objects are made using
+// conditional writes, such that concurrent changes are detected.
+//
+// Content is written before the hash of the content is known. If a write is
+// interrupted, for example by a process crash, the data object that was written
+// remains in the bucket but is never read.
+//
+// # IAM Permissions
+//
+// The following IAM actions are required on the S3 bucket:
+//   - s3:GetObject
+//   - s3:PutObject
+//   - s3:AbortMultipartUpload
+//   - s3:ListBucket
+//
+// Removed blobs are replaced with placeholder objects that are removed
+// automatically by an S3 lifecycle rule. The store ensures this rule is
+// present, which requires the following additional actions:
+//   - s3:GetLifecycleConfiguration
+//   - s3:PutLifecycleConfiguration
+//
+// If the bucket does not already exist, the store attempts to create it
+// automatically, which requires the following additional action:
+//   - s3:CreateBucket
+//
+// The store's Provision method can be called to trigger provisioning ahead of
+// time.
+package s3blob
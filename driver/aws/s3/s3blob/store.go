@@ -0,0 +1,95 @@
+package s3blob
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/dogmatiq/enginekit/x/xsync"
+	"github.com/dogmatiq/persistencekit/blob"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xs3"
+)
+
+// store is an implementation of [blob.Store] that persists to an S3 bucket.
+type store struct {
+	Client    *s3.Client
+	Bucket    string
+	OnRequest func(any) []func(*s3.Options)
+
+	provisionOnce xsync.SucceedOnce
+}
+
+// NewStore returns a new [blob.Store] that uses the given S3 client to store
+// blobs in the given bucket.
+func NewStore(
+	client *s3.Client,
+	bucket string,
+	options ...Option,
+) blob.Store {
+	if bucket == "" {
+		panic("bucket name must not be empty")
+	}
+
+	s := &store{
+		Client: client,
+		Bucket: bucket,
+	}
+
+	for _, opt := range options {
+		opt(s)
+	}
+
+	return s
+}
+
+// Option is a functional option that changes the behavior of [NewStore].
+type Option func(*store)
+
+// WithRequestHook is an [Option] that configures fn as a pre-request hook.
+//
+// Before each S3 API request, fn is passed a pointer to the input struct, e.g.
+// [s3.HeadObjectInput], which it may modify in-place. It may be called with any
+// S3 request type. The types of requests used may change in any version without
+// notice.
+//
+// Any functions returned by fn will be applied to the request's options before
+// the request is sent.
+func WithRequestHook(fn func(any) []func(*s3.Options)) Option {
+	return func(s *store) {
+		s.OnRequest = fn
+	}
+}
+
+// Provision creates the S3 bucket and lifecycle rules used by the store if they
+// do not already exist.
+//
+// The store also creates the bucket on first use if it does not exist. Provision
+// allows infrastructure to be created ahead of time, for example as part of a
+// deployment pipeline, so that the application itself does not need broad IAM
+// permissions.
+func (s *store) Provision(ctx context.Context) error {
+	return s.provisionOnce.Do(ctx, func(ctx context.Context) error {
+		if _, err := xs3.CreateBucketIfNotExists(ctx, s.Client, s.Bucket, s.OnRequest); err != nil {
+			return err
+		}
+		return xs3.EnsureTombstoneLifecycleRule(ctx, s.Client, s.Bucket, s.OnRequest)
+	})
+}
+
+// Open returns the container with the given name.
+func (s *store) Open(ctx context.Context, name string) (blob.Container, error) {
+	if err := s.Provision(ctx); err != nil {
+		return nil, err
+	}
+
+	prefix := "blob/" + url.PathEscape(name) + "/"
+
+	return &container{
+		client:        s.Client,
+		onRequest:     s.OnRequest,
+		name:          name,
+		bucket:        s.Bucket,
+		refKeyPrefix:  prefix + "ref/",
+		dataKeyPrefix: prefix + "data/",
+	}, nil
+}
@@ -0,0 +1,21 @@
+package s3blob_test
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/blob"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xs3"
+	. "github.com/dogmatiq/persistencekit/driver/aws/s3/s3blob"
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+)
+
+func TestStore(t *testing.T) {
+	client, _ := xs3.NewTestClient(t)
+	bucket := xtesting.UniqueName("bucket")
+	xs3.CleanupBucket(t, client, bucket)
+
+	blob.RunTests(
+		t,
+		NewStore(client, bucket),
+	)
+}
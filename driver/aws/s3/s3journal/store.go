@@ -99,3 +99,13 @@ func (s *store) Open(ctx context.Context, name string) (journal.BinaryJournal, e
 
 	return j, nil
 }
+
+// Capabilities returns the limits and optional features of the store.
+func (s *store) Capabilities() journal.Capabilities {
+	return journal.Capabilities{
+		// Each record is written in a single PUT request, which S3 limits to
+		// 5 GiB.
+		MaxRecordSize:   5 << 30,
+		MeteredRequests: true,
+	}
+}
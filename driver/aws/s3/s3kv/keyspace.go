@@ -127,7 +127,7 @@ func (ks *keyspace) setWrite(ctx context.Context, k, v []byte, r kv.Revision) (k
 			},
 		)
 		if xs3.IsConflict(err) || xs3.IsNotExists(err) {
-			return "", kv.ConflictError[[]byte]{Keyspace: ks.name, Key: k, Revision: r}
+			return "", kv.ConflictError[[]byte, []byte]{Keyspace: ks.name, Key: k, Revision: r}
 		}
 		if err != nil {
 			return "", err
@@ -166,7 +166,7 @@ func (ks *keyspace) setWrite(ctx context.Context, k, v []byte, r kv.Revision) (k
 			continue
 		}
 		if size > 0 {
-			return "", kv.ConflictError[[]byte]{Keyspace: ks.name, Key: k, Revision: r}
+			return "", kv.ConflictError[[]byte, []byte]{Keyspace: ks.name, Key: k, Revision: r}
 		}
 
 		// Replace the tombstone with the real value.
@@ -212,7 +212,7 @@ func (ks *keyspace) setDelete(ctx context.Context, k []byte, r kv.Revision) (kv.
 			},
 		)
 		if xs3.IsConflict(err) || xs3.IsNotExists(err) {
-			return "", kv.ConflictError[[]byte]{Keyspace: ks.name, Key: k, Revision: r}
+			return "", kv.ConflictError[[]byte, []byte]{Keyspace: ks.name, Key: k, Revision: r}
 		}
 		if err != nil {
 			return "", err
@@ -250,7 +250,7 @@ func (ks *keyspace) setDelete(ctx context.Context, k []byte, r kv.Revision) (kv.
 		// Key is absent or already a tombstone — intent satisfied.
 		return "", nil
 	}
-	return "", kv.ConflictError[[]byte]{Keyspace: ks.name, Key: k, Revision: r}
+	return "", kv.ConflictError[[]byte, []byte]{Keyspace: ks.name, Key: k, Revision: r}
 }
 
 // headObject returns the ETag and content length of the object at key.
@@ -388,6 +388,48 @@ func (ks *keyspace) Range(ctx context.Context, fn kv.BinaryRangeFunc) (err error
 	}
 }
 
+func (ks *keyspace) RangeKeys(ctx context.Context, fn kv.BinaryKeyRangeFunc) (err error) {
+	defer xerrors.Wrap(&err, "unable to range over the keys in the %q keyspace", ks.name)
+
+	req := &s3.ListObjectsV2Input{
+		Bucket: &ks.bucket,
+		Prefix: aws.String(ks.objectKeyPrefix),
+	}
+
+	for {
+		list, err := xaws.Do(
+			ctx,
+			ks.client.ListObjectsV2,
+			ks.onRequest,
+			req,
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, obj := range list.Contents {
+			if aws.ToInt64(obj.Size) == 0 {
+				continue // tombstone
+			}
+
+			k, err := ks.decodeObjectKey(aws.ToString(obj.Key))
+			if err != nil {
+				return err
+			}
+
+			ok, err := fn(ctx, k, kv.Revision(aws.ToString(obj.ETag)))
+			if !ok || err != nil {
+				return err
+			}
+		}
+
+		if list.IsTruncated == nil || !*list.IsTruncated {
+			return nil
+		}
+		req.ContinuationToken = list.NextContinuationToken
+	}
+}
+
 func (ks *keyspace) Close() error {
 	return nil
 }
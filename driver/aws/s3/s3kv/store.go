@@ -92,3 +92,22 @@ func (s *store) Open(ctx context.Context, name string) (kv.BinaryKeyspace, error
 
 	return ks, nil
 }
+
+// Capabilities returns the limits and optional features of the store.
+func (s *store) Capabilities() kv.Capabilities {
+	return kv.Capabilities{
+		// S3 limits object keys to 1024 bytes. Each key is hex-encoded after
+		// the keyspace's prefix, so this limit leaves room for keyspace names
+		// of up to several hundred bytes.
+		MaxKeySize: 256,
+
+		// Each value is written in a single PUT request, which S3 limits to
+		// 5 GiB.
+		MaxValueSize: 5 << 30,
+
+		// S3 lists objects in lexicographical order of their keys, which the
+		// hex encoding preserves.
+		OrderedRange:    true,
+		MeteredRequests: true,
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xs3"
+	"github.com/dogmatiq/persistencekit/driver/aws/s3/s3journal"
 	. "github.com/dogmatiq/persistencekit/driver/aws/s3/s3kv"
 	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
 	"github.com/dogmatiq/persistencekit/kv"
@@ -18,6 +19,17 @@ func TestStore(t *testing.T) {
 	)
 }
 
+func TestChangeJournal(t *testing.T) {
+	client, bucket := setup(t)
+	journals := s3journal.NewBinaryStore(client, bucket)
+
+	kv.RunChangeFeedTests(
+		t,
+		kv.WithChangeJournal(NewBinaryStore(client, bucket), journals),
+		kv.NewJournalChangeFeedStore(journals),
+	)
+}
+
 func BenchmarkStore(b *testing.B) {
 	client, bucket := setup(b)
 	kv.RunBenchmarks(
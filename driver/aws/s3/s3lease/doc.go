@@ -0,0 +1,23 @@
+// Package s3lease provides a [lease.Store] implementation that persists to an
+// S3 bucket.
+//
+// Each lease is stored as a single object. Changes to the lease are made using
+// conditional writes, such that concurrent changes are detected and retried.
+//
+// Lease expiry is determined using the clock of the client that performs each
+// operation, so the clocks of all clients that share a bucket should be
+// synchronized to well within the duration of the leases they acquire.
+//
+// # IAM Permissions
+//
+// The following IAM actions are required on the S3 bucket:
+//   - s3:GetObject
+//   - s3:PutObject
+//
+// If the bucket does not already exist, the store attempts to create it
+// automatically, which requires the following additional action:
+//   - s3:CreateBucket
+//
+// The store's Provision method can be called to trigger provisioning ahead of
+// time.
+package s3lease
@@ -0,0 +1,258 @@
+package s3lease
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xaws"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xs3"
+	"github.com/dogmatiq/persistencekit/internal/x/xerrors"
+	"github.com/dogmatiq/persistencekit/lease"
+)
+
+const (
+	// holderMetaData is the name of the object meta-data that contains the
+	// identifier of the lease's current (or most recent) holder.
+	holderMetaData = "holder"
+
+	// tokenMetaData is the name of the object meta-data that contains the
+	// fencing token of the lease's current (or most recent) acquisition.
+	tokenMetaData = "token"
+
+	// expiresAtMetaData is the name of the object meta-data that contains the
+	// time at which the lease expires, as a Unix timestamp in milliseconds.
+	expiresAtMetaData = "expires-at"
+)
+
+// leaseimpl is an implementation of [lease.Lease] that persists to an S3
+// bucket.
+type leaseimpl struct {
+	client    *s3.Client
+	onRequest func(any) []func(*s3.Options)
+
+	// name is the lease name.
+	name string
+
+	// bucket is the name of the S3 bucket in which the lease is stored.
+	bucket string
+
+	// objectKey is the key of the S3 object that stores the lease.
+	objectKey string
+}
+
+func (l *leaseimpl) Name() string {
+	return l.name
+}
+
+func (l *leaseimpl) Acquire(
+	ctx context.Context,
+	holder string,
+	ttl time.Duration,
+) (_ lease.Grant, _ bool, err error) {
+	defer xerrors.Wrap(&err, "unable to acquire the %q lease", l.name)
+
+	for {
+		current, etag, err := l.load(ctx)
+		if err != nil {
+			return lease.Grant{}, false, err
+		}
+
+		now := time.Now()
+		g := lease.Grant{
+			Holder:    holder,
+			Token:     current.Token,
+			ExpiresAt: expiry(now, ttl),
+		}
+
+		if isHeld(current, now) {
+			if current.Holder != holder {
+				return lease.Grant{}, false, nil
+			}
+		} else {
+			g.Token++
+		}
+
+		ok, err := l.save(ctx, g, etag)
+		if err != nil {
+			return lease.Grant{}, false, err
+		}
+		if ok {
+			return g, true, nil
+		}
+		// The lease was modified concurrently; retry.
+	}
+}
+
+func (l *leaseimpl) Renew(
+	ctx context.Context,
+	g lease.Grant,
+	ttl time.Duration,
+) (_ lease.Grant, err error) {
+	defer xerrors.Wrap(&err, "unable to renew the %q lease", l.name)
+
+	for {
+		current, etag, err := l.load(ctx)
+		if err != nil {
+			return lease.Grant{}, err
+		}
+
+		now := time.Now()
+
+		if current.Token != g.Token || !isHeld(current, now) {
+			return lease.Grant{}, lease.NotHeldError{
+				Lease: l.name,
+				Token: g.Token,
+			}
+		}
+
+		current.ExpiresAt = expiry(now, ttl)
+
+		ok, err := l.save(ctx, current, etag)
+		if err != nil {
+			return lease.Grant{}, err
+		}
+		if ok {
+			return current, nil
+		}
+		// The lease was modified concurrently; retry.
+	}
+}
+
+func (l *leaseimpl) Release(ctx context.Context, g lease.Grant) (err error) {
+	defer xerrors.Wrap(&err, "unable to release the %q lease", l.name)
+
+	for {
+		current, etag, err := l.load(ctx)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		if current.Token != g.Token || !isHeld(current, now) {
+			return nil
+		}
+
+		current.ExpiresAt = expiry(now, 0)
+
+		ok, err := l.save(ctx, current, etag)
+		if ok || err != nil {
+			return err
+		}
+		// The lease was modified concurrently; retry.
+	}
+}
+
+func (l *leaseimpl) Close() error {
+	return nil
+}
+
+// load returns the current state of the lease and the ETag of the object that
+// stores it. If the object does not exist, etag is "".
+func (l *leaseimpl) load(ctx context.Context) (g lease.Grant, etag string, err error) {
+	res, err := xaws.Do(
+		ctx,
+		l.client.HeadObject,
+		l.onRequest,
+		&s3.HeadObjectInput{
+			Bucket: &l.bucket,
+			Key:    &l.objectKey,
+		},
+	)
+	if xs3.IsNotExists(err) {
+		return lease.Grant{}, "", nil
+	}
+	if err != nil {
+		return lease.Grant{}, "", err
+	}
+
+	g, err = unmarshalGrant(res.Metadata)
+	if err != nil {
+		return lease.Grant{}, "", err
+	}
+
+	return g, aws.ToString(res.ETag), nil
+}
+
+// save writes g to the object that stores the lease, if the object's ETag still
+// matches etag. It returns false if the object has been modified since it was
+// loaded.
+func (l *leaseimpl) save(ctx context.Context, g lease.Grant, etag string) (bool, error) {
+	req := &s3.PutObjectInput{
+		Bucket:        &l.bucket,
+		Key:           &l.objectKey,
+		Body:          xs3.NewReadSeeker(nil),
+		ContentLength: aws.Int64(0),
+		Metadata:      marshalGrant(g),
+	}
+
+	if etag == "" {
+		req.IfNoneMatch = aws.String("*")
+	} else {
+		req.IfMatch = aws.String(etag)
+	}
+
+	_, err := xaws.Do(
+		ctx,
+		l.client.PutObject,
+		l.onRequest,
+		req,
+	)
+	if xs3.IsConflict(err) || xs3.IsNotExists(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// isHeld returns true if the lease described by g is held at time t.
+func isHeld(g lease.Grant, t time.Time) bool {
+	return g.ExpiresAt.After(t)
+}
+
+// expiry returns the time at which a lease acquired at t with the given TTL
+// expires, truncated to the precision at which it is stored.
+func expiry(t time.Time, ttl time.Duration) time.Time {
+	return time.UnixMilli(t.Add(ttl).UnixMilli())
+}
+
+func marshalGrant(g lease.Grant) map[string]string {
+	return map[string]string{
+		// The holder is escaped because S3 meta-data values are restricted
+		// to US-ASCII.
+		holderMetaData:    url.QueryEscape(g.Holder),
+		tokenMetaData:     strconv.FormatUint(uint64(g.Token), 10),
+		expiresAtMetaData: strconv.FormatInt(g.ExpiresAt.UnixMilli(), 10),
+	}
+}
+
+func unmarshalGrant(meta map[string]string) (g lease.Grant, err error) {
+	holder, ok := meta[holderMetaData]
+	if !ok {
+		return lease.Grant{}, fmt.Errorf("integrity error: %q meta-data is missing", holderMetaData)
+	}
+
+	g.Holder, err = url.QueryUnescape(holder)
+	if err != nil {
+		return lease.Grant{}, fmt.Errorf("integrity error: %q meta-data is malformed: %w", holderMetaData, err)
+	}
+
+	token, err := strconv.ParseUint(meta[tokenMetaData], 10, 64)
+	if err != nil {
+		return lease.Grant{}, fmt.Errorf("integrity error: %q meta-data is malformed: %w", tokenMetaData, err)
+	}
+	g.Token = lease.Token(token)
+
+	expiresAt, err := strconv.ParseInt(meta[expiresAtMetaData], 10, 64)
+	if err != nil {
+		return lease.Grant{}, fmt.Errorf("integrity error: %q meta-data is malformed: %w", expiresAtMetaData, err)
+	}
+	g.ExpiresAt = time.UnixMilli(expiresAt)
+
+	return g, nil
+}
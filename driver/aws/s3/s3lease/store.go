@@ -0,0 +1,90 @@
+package s3lease
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/dogmatiq/enginekit/x/xsync"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xs3"
+	"github.com/dogmatiq/persistencekit/lease"
+)
+
+// store is an implementation of [lease.Store] that persists to an S3 bucket.
+type store struct {
+	Client    *s3.Client
+	Bucket    string
+	OnRequest func(any) []func(*s3.Options)
+
+	provisionOnce xsync.SucceedOnce
+}
+
+// NewStore returns a new [lease.Store] that uses the given S3 client to store
+// leases in the given bucket.
+func NewStore(
+	client *s3.Client,
+	bucket string,
+	options ...Option,
+) lease.Store {
+	if bucket == "" {
+		panic("bucket name must not be empty")
+	}
+
+	s := &store{
+		Client: client,
+		Bucket: bucket,
+	}
+
+	for _, opt := range options {
+		opt(s)
+	}
+
+	return s
+}
+
+// Option is a functional option that changes the behavior of [NewStore].
+type Option func(*store)
+
+// WithRequestHook is an [Option] that configures fn as a pre-request hook.
+//
+// Before each S3 API request, fn is passed a pointer to the input struct, e.g.
+// [s3.HeadObjectInput], which it may modify in-place. It may be called with any
+// S3 request type. The types of requests used may change in any version without
+// notice.
+//
+// Any functions returned by fn will be applied to the request's options before
+// the request is sent.
+func WithRequestHook(fn func(any) []func(*s3.Options)) Option {
+	return func(s *store) {
+		s.OnRequest = fn
+	}
+}
+
+// Provision creates the S3 bucket used by the store if it does not already
+// exist.
+//
+// The store also creates the bucket on first use if it does not exist. Provision
+// allows infrastructure to be created ahead of time, for example as part of a
+// deployment pipeline, so that the application itself does not need broad IAM
+// permissions.
+func (s *store) Provision(ctx context.Context) error {
+	return s.provisionOnce.Do(ctx, func(ctx context.Context) error {
+		_, err := xs3.CreateBucketIfNotExists(ctx, s.Client, s.Bucket, s.OnRequest)
+		return err
+	})
+}
+
+// Open returns the lease with the given name.
+func (s *store) Open(ctx context.Context, name string) (lease.Lease, error) {
+	if err := s.Provision(ctx); err != nil {
+		return nil, err
+	}
+
+	return &leaseimpl{
+		client:    s.Client,
+		onRequest: s.OnRequest,
+		name:      name,
+		bucket:    s.Bucket,
+		objectKey: "lease/" + url.PathEscape(name),
+	}, nil
+}
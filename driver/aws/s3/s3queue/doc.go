@@ -0,0 +1,35 @@
+// Package s3queue provides a [queue.BinaryStore] implementation that persists
+// to an S3 bucket.
+//
+// Each message is stored as a single object. Changes to a message are made
+// using conditional writes, such that concurrent consumers never claim the
+// same delivery attempt.
+//
+// Each dequeue operation lists the queue's objects and reads each one until a
+// visible message is found, so it is best suited to low-volume queues that do
+// not accumulate large numbers of in-flight or delayed messages.
+//
+// Message visibility is determined using the clock of the client that performs
+// each operation, so the clocks of all clients that share a bucket should be
+// synchronized to well within the visibility timeouts that they use.
+//
+// # IAM Permissions
+//
+// The following IAM actions are required on the S3 bucket:
+//   - s3:GetObject
+//   - s3:PutObject
+//   - s3:ListBucket
+//
+// Removed messages are marked with placeholder objects that are removed
+// automatically by an S3 lifecycle rule. The store ensures this rule is
+// present, which requires the following additional actions:
+//   - s3:GetLifecycleConfiguration
+//   - s3:PutLifecycleConfiguration
+//
+// If the bucket does not already exist, the store attempts to create it
+// automatically, which requires the following additional action:
+//   - s3:CreateBucket
+//
+// The store's Provision method can be called to trigger provisioning ahead of
+// time.
+package s3queue
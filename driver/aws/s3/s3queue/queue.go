@@ -0,0 +1,372 @@
+package s3queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xaws"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xs3"
+	"github.com/dogmatiq/persistencekit/internal/x/xerrors"
+	"github.com/dogmatiq/persistencekit/queue"
+)
+
+const (
+	// attemptMetaData is the name of the object meta-data that contains the
+	// number of times the message has been dequeued.
+	attemptMetaData = "attempt"
+
+	// visibleAtMetaData is the name of the object meta-data that contains the
+	// time at which the message next becomes visible, as a Unix timestamp in
+	// milliseconds.
+	visibleAtMetaData = "visible-at"
+)
+
+// bodyPrefix is the byte that precedes the message value in the body of each
+// live message object.
+//
+// Tombstones are size-zero objects (tagged for lifecycle expiry); the prefix
+// ensures that a message with an empty value can be distinguished from a
+// tombstone.
+const bodyPrefix byte = 'M'
+
+// queueimpl is an implementation of [queue.BinaryQueue] that persists to an S3
+// bucket.
+type queueimpl struct {
+	client    *s3.Client
+	onRequest func(any) []func(*s3.Options)
+
+	// name is the queue name.
+	name string
+
+	// bucket is the name of the S3 bucket in which the queue's messages are
+	// stored.
+	bucket string
+}
+
+// object is a message as stored in an S3 object.
+type object struct {
+	ETag      string
+	Value     []byte
+	Attempt   int
+	VisibleAt time.Time
+}
+
+func (q *queueimpl) Name() string {
+	return q.name
+}
+
+func (q *queueimpl) Enqueue(ctx context.Context, v []byte) (err error) {
+	defer xerrors.Wrap(&err, "unable to enqueue message on the %q queue", q.name)
+
+	now := time.Now()
+
+	return q.put(
+		ctx,
+		objectKeyPrefix(q.name)+newID(now),
+		object{Value: v, VisibleAt: now},
+		nil,
+	)
+}
+
+func (q *queueimpl) Dequeue(ctx context.Context, timeout time.Duration) (_ queue.BinaryMessage, _ bool, err error) {
+	defer xerrors.Wrap(&err, "unable to dequeue message from the %q queue", q.name)
+
+	prefix := objectKeyPrefix(q.name)
+	req := &s3.ListObjectsV2Input{
+		Bucket: &q.bucket,
+		Prefix: &prefix,
+	}
+
+	for {
+		list, err := xaws.Do(
+			ctx,
+			q.client.ListObjectsV2,
+			q.onRequest,
+			req,
+		)
+		if err != nil {
+			return queue.BinaryMessage{}, false, err
+		}
+
+		for _, obj := range list.Contents {
+			if aws.ToInt64(obj.Size) == 0 {
+				continue // tombstone
+			}
+
+			key := aws.ToString(obj.Key)
+
+			m, ok, err := q.claim(ctx, key, timeout)
+			if err != nil {
+				return queue.BinaryMessage{}, false, err
+			}
+			if ok {
+				m.ID = key[len(prefix):]
+				return m, true, nil
+			}
+		}
+
+		if list.IsTruncated == nil || !*list.IsTruncated {
+			return queue.BinaryMessage{}, false, nil
+		}
+		req.ContinuationToken = list.NextContinuationToken
+	}
+}
+
+// claim hides the message at key for the given visibility timeout and
+// increments its attempt number, if it is currently visible. It returns false
+// if the message is not visible, or was claimed by another consumer first.
+func (q *queueimpl) claim(ctx context.Context, key string, timeout time.Duration) (queue.BinaryMessage, bool, error) {
+	obj, ok, err := q.get(ctx, key)
+	if !ok || err != nil {
+		return queue.BinaryMessage{}, false, err
+	}
+
+	now := time.Now()
+	if obj.VisibleAt.After(now) {
+		return queue.BinaryMessage{}, false, nil
+	}
+
+	etag := obj.ETag
+	obj.Attempt++
+	obj.VisibleAt = now.Add(timeout)
+
+	if err := q.put(ctx, key, obj, &etag); err != nil {
+		if xs3.IsConflict(err) || xs3.IsNotExists(err) {
+			return queue.BinaryMessage{}, false, nil
+		}
+		return queue.BinaryMessage{}, false, err
+	}
+
+	return queue.BinaryMessage{
+		Value:   obj.Value,
+		Attempt: obj.Attempt,
+	}, true, nil
+}
+
+func (q *queueimpl) Ack(ctx context.Context, m queue.BinaryMessage) (err error) {
+	defer xerrors.Wrap(&err, "unable to acknowledge message on the %q queue", q.name)
+
+	key := objectKeyPrefix(q.name) + m.ID
+
+	for {
+		obj, err := q.current(ctx, key, m)
+		if err != nil {
+			return err
+		}
+
+		if err := q.remove(ctx, key, obj.ETag); err == nil {
+			return nil
+		} else if !xs3.IsConflict(err) && !xs3.IsNotExists(err) {
+			return err
+		}
+		// The message was modified concurrently; retry.
+	}
+}
+
+func (q *queueimpl) Nack(ctx context.Context, m queue.BinaryMessage, delay time.Duration) (err error) {
+	defer xerrors.Wrap(&err, "unable to return message to the %q queue", q.name)
+
+	key := objectKeyPrefix(q.name) + m.ID
+
+	for {
+		obj, err := q.current(ctx, key, m)
+		if err != nil {
+			return err
+		}
+
+		etag := obj.ETag
+		obj.VisibleAt = time.Now().Add(delay)
+
+		if err := q.put(ctx, key, obj, &etag); err == nil {
+			return nil
+		} else if !xs3.IsConflict(err) && !xs3.IsNotExists(err) {
+			return err
+		}
+		// The message was modified concurrently; retry.
+	}
+}
+
+func (q *queueimpl) DeadLetter(ctx context.Context, m queue.BinaryMessage) (err error) {
+	defer xerrors.Wrap(&err, "unable to move message to the dead-letter queue of the %q queue", q.name)
+
+	key := objectKeyPrefix(q.name) + m.ID
+	dlqKey := objectKeyPrefix(queue.DeadLetterName(q.name)) + m.ID
+
+	for {
+		obj, err := q.current(ctx, key, m)
+		if err != nil {
+			return err
+		}
+
+		// The message is added to the dead-letter queue before it is removed
+		// from this queue, so that it is never lost if the removal fails. It
+		// retains its ID, which is unique across all queues.
+		if err := q.put(
+			ctx,
+			dlqKey,
+			object{Value: obj.Value, VisibleAt: time.Now()},
+			nil,
+		); err != nil {
+			return err
+		}
+
+		err = q.remove(ctx, key, obj.ETag)
+		if err == nil {
+			return nil
+		}
+		if !xs3.IsConflict(err) && !xs3.IsNotExists(err) {
+			return err
+		}
+
+		// The message was modified concurrently, so it may no longer be
+		// current. Withdraw it from the dead-letter queue before checking
+		// again.
+		if err := q.remove(ctx, dlqKey, ""); err != nil {
+			return err
+		}
+	}
+}
+
+func (q *queueimpl) Close() error {
+	return nil
+}
+
+// current returns the stored state of the message identified by m, or a
+// [queue.StaleMessageError] if it has been redelivered or removed since m was
+// dequeued.
+func (q *queueimpl) current(ctx context.Context, key string, m queue.BinaryMessage) (object, error) {
+	obj, ok, err := q.get(ctx, key)
+	if err != nil {
+		return object{}, err
+	}
+
+	if !ok || obj.Attempt != m.Attempt {
+		return object{}, queue.StaleMessageError{
+			Queue:   q.name,
+			ID:      m.ID,
+			Attempt: m.Attempt,
+		}
+	}
+
+	return obj, nil
+}
+
+// get returns the message stored at key. It returns false if the object does
+// not exist or is a tombstone.
+func (q *queueimpl) get(ctx context.Context, key string) (object, bool, error) {
+	res, err := xaws.Do(
+		ctx,
+		q.client.GetObject,
+		q.onRequest,
+		&s3.GetObjectInput{
+			Bucket: &q.bucket,
+			Key:    &key,
+		},
+	)
+	if xs3.IsNotExists(err) {
+		return object{}, false, nil
+	}
+	if err != nil {
+		return object{}, false, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return object{}, false, err
+	}
+
+	if len(body) == 0 {
+		return object{}, false, nil // tombstone
+	}
+
+	if body[0] != bodyPrefix {
+		return object{}, false, fmt.Errorf("integrity error: object %q has an unrecognized body", key)
+	}
+
+	obj := object{
+		ETag:  aws.ToString(res.ETag),
+		Value: body[1:],
+	}
+
+	obj.Attempt, err = strconv.Atoi(res.Metadata[attemptMetaData])
+	if err != nil {
+		return object{}, false, fmt.Errorf("integrity error: %q meta-data is malformed: %w", attemptMetaData, err)
+	}
+
+	visibleAt, err := strconv.ParseInt(res.Metadata[visibleAtMetaData], 10, 64)
+	if err != nil {
+		return object{}, false, fmt.Errorf("integrity error: %q meta-data is malformed: %w", visibleAtMetaData, err)
+	}
+	obj.VisibleAt = time.UnixMilli(visibleAt)
+
+	return obj, true, nil
+}
+
+// put writes obj to key. If etag is non-nil, the write only succeeds if the
+// object's ETag still matches *etag.
+func (q *queueimpl) put(ctx context.Context, key string, obj object, etag *string) error {
+	body := make([]byte, 0, len(obj.Value)+1)
+	body = append(body, bodyPrefix)
+	body = append(body, obj.Value...)
+
+	_, err := xaws.Do(
+		ctx,
+		q.client.PutObject,
+		q.onRequest,
+		&s3.PutObjectInput{
+			Bucket:        &q.bucket,
+			Key:           &key,
+			IfMatch:       etag,
+			Body:          xs3.NewReadSeeker(body),
+			ContentLength: aws.Int64(int64(len(body))),
+			Metadata: map[string]string{
+				attemptMetaData:   strconv.Itoa(obj.Attempt),
+				visibleAtMetaData: strconv.FormatInt(obj.VisibleAt.UnixMilli(), 10),
+			},
+		},
+	)
+	return err
+}
+
+// remove replaces the object at key with a tombstone. If etag is non-empty,
+// the write only succeeds if the object's ETag still matches etag.
+func (q *queueimpl) remove(ctx context.Context, key, etag string) error {
+	req := &s3.PutObjectInput{
+		Bucket:        &q.bucket,
+		Key:           &key,
+		Body:          xs3.NewReadSeeker(nil),
+		ContentLength: aws.Int64(0),
+		Tagging:       xs3.TombstoneTagging,
+	}
+
+	if etag != "" {
+		req.IfMatch = aws.String(etag)
+	}
+
+	_, err := xaws.Do(
+		ctx,
+		q.client.PutObject,
+		q.onRequest,
+		req,
+	)
+	return err
+}
+
+// newID returns a new message ID. IDs sort in the order in which messages were
+// enqueued by the same client, and approximately by enqueue time across
+// clients.
+func newID(now time.Time) string {
+	var data [16]byte
+	binary.BigEndian.PutUint64(data[:8], uint64(now.UnixNano()))
+	_, _ = rand.Read(data[8:])
+	return hex.EncodeToString(data[:])
+}
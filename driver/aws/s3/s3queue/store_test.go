@@ -0,0 +1,21 @@
+package s3queue_test
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xs3"
+	. "github.com/dogmatiq/persistencekit/driver/aws/s3/s3queue"
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+	"github.com/dogmatiq/persistencekit/queue"
+)
+
+func TestStore(t *testing.T) {
+	client, _ := xs3.NewTestClient(t)
+	bucket := xtesting.UniqueName("bucket")
+	xs3.CleanupBucket(t, client, bucket)
+
+	queue.RunTests(
+		t,
+		NewBinaryStore(client, bucket),
+	)
+}
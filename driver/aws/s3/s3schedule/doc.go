@@ -0,0 +1,36 @@
+// Package s3schedule provides a [schedule.BinaryStore] implementation that
+// persists to an S3 bucket.
+//
+// Each item is stored as a single object, accompanied by a marker object whose
+// key begins with the item's due time, such that due items can be found by
+// listing the markers in key order. Changes to an item are made using
+// conditional writes, such that concurrent changes are detected.
+//
+// A marker is written before the item that refers to it. If an operation fails
+// part way through, the marker may remain after the item has changed. Such
+// markers are ignored, but are still listed when ranging over due items.
+//
+// Claims are determined using the clock of the client that performs each
+// operation, so the clocks of all clients that share a bucket should be
+// synchronized to well within the claim timeouts that they use.
+//
+// # IAM Permissions
+//
+// The following IAM actions are required on the S3 bucket:
+//   - s3:GetObject
+//   - s3:PutObject
+//   - s3:ListBucket
+//
+// Removed items and markers are replaced with placeholder objects that are
+// removed automatically by an S3 lifecycle rule. The store ensures this rule is
+// present, which requires the following additional actions:
+//   - s3:GetLifecycleConfiguration
+//   - s3:PutLifecycleConfiguration
+//
+// If the bucket does not already exist, the store attempts to create it
+// automatically, which requires the following additional action:
+//   - s3:CreateBucket
+//
+// The store's Provision method can be called to trigger provisioning ahead of
+// time.
+package s3schedule
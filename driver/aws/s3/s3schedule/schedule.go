@@ -0,0 +1,530 @@
+package s3schedule
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xaws"
+	"github.com/dogmatiq/persistencekit/driver/aws/internal/x/xs3"
+	"github.com/dogmatiq/persistencekit/internal/x/xerrors"
+	"github.com/dogmatiq/persistencekit/schedule"
+)
+
+const (
+	// dueAtMetaData is the name of the item object meta-data that contains the
+	// time at which the item becomes due, as a Unix timestamp in milliseconds.
+	dueAtMetaData = "due-at"
+
+	// markerMetaData is the name of the item object meta-data that contains
+	// the token of the marker object that refers to the item.
+	markerMetaData = "marker"
+)
+
+// bodyPrefix is the byte that precedes the value in the body of each live item
+// object.
+//
+// Tombstones are size-zero objects (tagged for lifecycle expiry); the prefix
+// ensures that an item with an empty value can be distinguished from a
+// tombstone.
+const bodyPrefix byte = 'I'
+
+// markerBody is the content written to live marker objects.
+var markerBody = []byte("M")
+
+// scheduleimpl is an implementation of [schedule.BinarySchedule] that persists
+// to an S3 bucket.
+type scheduleimpl struct {
+	client    *s3.Client
+	onRequest func(any) []func(*s3.Options)
+
+	// name is the schedule name.
+	name string
+
+	// bucket is the name of the S3 bucket in which the schedule's items are
+	// stored.
+	bucket string
+
+	// itemKeyPrefix is the string prepended to the key of each item object.
+	itemKeyPrefix string
+
+	// markerKeyPrefix is the string prepended to the key of each marker
+	// object.
+	markerKeyPrefix string
+}
+
+// object is the state of an item as stored in an S3 object.
+type object struct {
+	ETag   string
+	Live   bool
+	DueAt  time.Time
+	Marker string
+	Value  []byte
+}
+
+// marker identifies a marker object.
+type marker struct {
+	DueAt time.Time
+	ID    string
+	Token string
+}
+
+func (s *scheduleimpl) Name() string {
+	return s.name
+}
+
+func (s *scheduleimpl) Schedule(ctx context.Context, id string, dueAt time.Time, v []byte) (err error) {
+	defer xerrors.Wrap(&err, "unable to schedule item in the %q schedule", s.name)
+
+	dueAt = truncate(dueAt)
+
+	for {
+		current, err := s.head(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		ok, err := s.replace(ctx, id, current, dueAt, v)
+		if ok || err != nil {
+			return err
+		}
+		// The item was modified concurrently; retry.
+	}
+}
+
+func (s *scheduleimpl) Cancel(ctx context.Context, id string) (err error) {
+	defer xerrors.Wrap(&err, "unable to cancel item in the %q schedule", s.name)
+
+	for {
+		current, err := s.head(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !current.Live {
+			return nil
+		}
+
+		ok, err := s.remove(ctx, id, current)
+		if ok || err != nil {
+			return err
+		}
+		// The item was modified concurrently; retry.
+	}
+}
+
+func (s *scheduleimpl) RangeDue(ctx context.Context, t time.Time, fn schedule.BinaryRangeFunc) (err error) {
+	defer xerrors.Wrap(&err, "unable to range over due items in the %q schedule", s.name)
+
+	req := &s3.ListObjectsV2Input{
+		Bucket: &s.bucket,
+		Prefix: aws.String(s.markerKeyPrefix),
+	}
+
+	for {
+		list, err := xaws.Do(
+			ctx,
+			s.client.ListObjectsV2,
+			s.onRequest,
+			req,
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, obj := range list.Contents {
+			if aws.ToInt64(obj.Size) == 0 {
+				continue // tombstone
+			}
+
+			m, err := s.parseMarkerKey(aws.ToString(obj.Key))
+			if err != nil {
+				return err
+			}
+
+			if m.DueAt.After(t) {
+				return nil
+			}
+
+			current, err := s.get(ctx, m.ID)
+			if err != nil {
+				return err
+			}
+
+			if !current.Live || current.Marker != m.Token {
+				continue // the marker no longer refers to the item
+			}
+
+			ok, err := fn(ctx, schedule.BinaryItem{
+				ID:    m.ID,
+				DueAt: current.DueAt,
+				Value: current.Value,
+			})
+			if !ok || err != nil {
+				return err
+			}
+		}
+
+		if list.IsTruncated == nil || !*list.IsTruncated {
+			return nil
+		}
+		req.ContinuationToken = list.NextContinuationToken
+	}
+}
+
+func (s *scheduleimpl) Claim(
+	ctx context.Context,
+	item schedule.BinaryItem,
+	timeout time.Duration,
+) (_ schedule.BinaryItem, _ bool, err error) {
+	defer xerrors.Wrap(&err, "unable to claim item in the %q schedule", s.name)
+
+	for {
+		// The item is read in full, as its value must be re-written along
+		// with its new due time.
+		current, err := s.get(ctx, item.ID)
+		if err != nil {
+			return schedule.BinaryItem{}, false, err
+		}
+
+		now := time.Now()
+
+		if !current.Live || !current.DueAt.Equal(item.DueAt) || current.DueAt.After(now) {
+			return schedule.BinaryItem{}, false, nil
+		}
+
+		dueAt := truncate(now.Add(timeout))
+
+		ok, err := s.replace(ctx, item.ID, current, dueAt, current.Value)
+		if err != nil {
+			return schedule.BinaryItem{}, false, err
+		}
+		if ok {
+			item.DueAt = dueAt
+			return item, true, nil
+		}
+		// The item was modified concurrently; retry.
+	}
+}
+
+func (s *scheduleimpl) Complete(ctx context.Context, item schedule.BinaryItem) (_ bool, err error) {
+	defer xerrors.Wrap(&err, "unable to complete item in the %q schedule", s.name)
+
+	for {
+		current, err := s.head(ctx, item.ID)
+		if err != nil {
+			return false, err
+		}
+
+		if !current.Live || !current.DueAt.Equal(item.DueAt) {
+			return false, nil
+		}
+
+		ok, err := s.remove(ctx, item.ID, current)
+		if ok || err != nil {
+			return ok, err
+		}
+		// The item was modified concurrently; retry.
+	}
+}
+
+func (s *scheduleimpl) Close() error {
+	return nil
+}
+
+// replace writes an item with the given ID, due time and value, if the item
+// object has not been modified since current was read. It returns false if the
+// item has been modified.
+func (s *scheduleimpl) replace(
+	ctx context.Context,
+	id string,
+	current object,
+	dueAt time.Time,
+	v []byte,
+) (bool, error) {
+	m := marker{
+		DueAt: dueAt,
+		ID:    id,
+		Token: newToken(),
+	}
+
+	// The marker is written first so that the item is never without a marker
+	// that refers to it.
+	if err := s.putMarker(ctx, m); err != nil {
+		return false, err
+	}
+
+	body := make([]byte, 0, len(v)+1)
+	body = append(body, bodyPrefix)
+	body = append(body, v...)
+
+	req := &s3.PutObjectInput{
+		Bucket:        &s.bucket,
+		Key:           aws.String(s.itemKey(id)),
+		Body:          xs3.NewReadSeeker(body),
+		ContentLength: aws.Int64(int64(len(body))),
+		Metadata: map[string]string{
+			dueAtMetaData:  strconv.FormatInt(dueAt.UnixMilli(), 10),
+			markerMetaData: m.Token,
+		},
+	}
+
+	if current.ETag == "" {
+		req.IfNoneMatch = aws.String("*")
+	} else {
+		req.IfMatch = aws.String(current.ETag)
+	}
+
+	_, err := xaws.Do(
+		ctx,
+		s.client.PutObject,
+		s.onRequest,
+		req,
+	)
+	if xs3.IsConflict(err) || xs3.IsNotExists(err) {
+		// The new marker's token is unique, so it is safe to remove it.
+		return false, s.removeMarker(ctx, m)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if current.Live {
+		return true, s.removeMarker(ctx, s.markerOf(id, current))
+	}
+
+	return true, nil
+}
+
+// remove replaces the item object with a tombstone, if it has not been
+// modified since current was read. It returns false if the item has been
+// modified.
+func (s *scheduleimpl) remove(ctx context.Context, id string, current object) (bool, error) {
+	_, err := xaws.Do(
+		ctx,
+		s.client.PutObject,
+		s.onRequest,
+		&s3.PutObjectInput{
+			Bucket:        &s.bucket,
+			Key:           aws.String(s.itemKey(id)),
+			IfMatch:       aws.String(current.ETag),
+			Body:          xs3.NewReadSeeker(nil),
+			ContentLength: aws.Int64(0),
+			Tagging:       xs3.TombstoneTagging,
+		},
+	)
+	if xs3.IsConflict(err) || xs3.IsNotExists(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, s.removeMarker(ctx, s.markerOf(id, current))
+}
+
+// head returns the state of the item with the given ID, without its value. If
+// the object does not exist, ETag is empty.
+func (s *scheduleimpl) head(ctx context.Context, id string) (object, error) {
+	res, err := xaws.Do(
+		ctx,
+		s.client.HeadObject,
+		s.onRequest,
+		&s3.HeadObjectInput{
+			Bucket: &s.bucket,
+			Key:    aws.String(s.itemKey(id)),
+		},
+	)
+	if xs3.IsNotExists(err) {
+		return object{}, nil
+	}
+	if err != nil {
+		return object{}, err
+	}
+
+	return unmarshalObject(
+		aws.ToString(res.ETag),
+		aws.ToInt64(res.ContentLength) > 0,
+		res.Metadata,
+	)
+}
+
+// get returns the state of the item with the given ID, including its value. If
+// the object does not exist, ETag is empty.
+func (s *scheduleimpl) get(ctx context.Context, id string) (object, error) {
+	key := s.itemKey(id)
+
+	res, err := xaws.Do(
+		ctx,
+		s.client.GetObject,
+		s.onRequest,
+		&s3.GetObjectInput{
+			Bucket: &s.bucket,
+			Key:    &key,
+		},
+	)
+	if xs3.IsNotExists(err) {
+		return object{}, nil
+	}
+	if err != nil {
+		return object{}, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return object{}, err
+	}
+
+	obj, err := unmarshalObject(
+		aws.ToString(res.ETag),
+		len(body) > 0,
+		res.Metadata,
+	)
+	if err != nil || !obj.Live {
+		return obj, err
+	}
+
+	if body[0] != bodyPrefix {
+		return object{}, fmt.Errorf("integrity error: object %q has an unrecognized body", key)
+	}
+	obj.Value = body[1:]
+
+	return obj, nil
+}
+
+// putMarker writes the marker object for m.
+func (s *scheduleimpl) putMarker(ctx context.Context, m marker) error {
+	_, err := xaws.Do(
+		ctx,
+		s.client.PutObject,
+		s.onRequest,
+		&s3.PutObjectInput{
+			Bucket:        &s.bucket,
+			Key:           aws.String(s.markerKey(m)),
+			Body:          xs3.NewReadSeeker(markerBody),
+			ContentLength: aws.Int64(int64(len(markerBody))),
+		},
+	)
+	return err
+}
+
+// removeMarker replaces the marker object for m with a tombstone.
+func (s *scheduleimpl) removeMarker(ctx context.Context, m marker) error {
+	_, err := xaws.Do(
+		ctx,
+		s.client.PutObject,
+		s.onRequest,
+		&s3.PutObjectInput{
+			Bucket:        &s.bucket,
+			Key:           aws.String(s.markerKey(m)),
+			Body:          xs3.NewReadSeeker(nil),
+			ContentLength: aws.Int64(0),
+			Tagging:       xs3.TombstoneTagging,
+		},
+	)
+	return err
+}
+
+// itemKey returns the S3 object key for the item with the given ID.
+func (s *scheduleimpl) itemKey(id string) string {
+	return s.itemKeyPrefix + url.PathEscape(id)
+}
+
+// markerOf returns the marker that refers to the item with the given ID and
+// state.
+func (s *scheduleimpl) markerOf(id string, obj object) marker {
+	return marker{
+		DueAt: obj.DueAt,
+		ID:    id,
+		Token: obj.Marker,
+	}
+}
+
+// markerKey returns the S3 object key for m.
+//
+// The due time is encoded such that the lexical order of the keys matches the
+// chronological order of the due times, including those before the Unix epoch.
+func (s *scheduleimpl) markerKey(m marker) string {
+	return fmt.Sprintf(
+		"%s%016x/%s/%s",
+		s.markerKeyPrefix,
+		uint64(m.DueAt.UnixMilli())^(1<<63),
+		url.PathEscape(m.ID),
+		m.Token,
+	)
+}
+
+// parseMarkerKey parses a full S3 object key produced by [markerKey].
+func (s *scheduleimpl) parseMarkerKey(key string) (marker, error) {
+	suffix, ok := strings.CutPrefix(key, s.markerKeyPrefix)
+	if !ok {
+		return marker{}, fmt.Errorf("malformed object key %q: expected prefix %q", key, s.markerKeyPrefix)
+	}
+
+	parts := strings.Split(suffix, "/")
+	if len(parts) != 3 {
+		return marker{}, fmt.Errorf("malformed object key %q: expected due time, ID and token", key)
+	}
+
+	due, err := strconv.ParseUint(parts[0], 16, 64)
+	if err != nil {
+		return marker{}, fmt.Errorf("malformed object key %q: %w", key, err)
+	}
+
+	id, err := url.PathUnescape(parts[1])
+	if err != nil {
+		return marker{}, fmt.Errorf("malformed object key %q: %w", key, err)
+	}
+
+	return marker{
+		DueAt: time.UnixMilli(int64(due ^ (1 << 63))),
+		ID:    id,
+		Token: parts[2],
+	}, nil
+}
+
+func unmarshalObject(etag string, live bool, meta map[string]string) (object, error) {
+	obj := object{
+		ETag: etag,
+		Live: live,
+	}
+
+	if !live {
+		return obj, nil
+	}
+
+	dueAt, err := strconv.ParseInt(meta[dueAtMetaData], 10, 64)
+	if err != nil {
+		return object{}, fmt.Errorf("integrity error: %q meta-data is malformed: %w", dueAtMetaData, err)
+	}
+	obj.DueAt = time.UnixMilli(dueAt)
+
+	token, ok := meta[markerMetaData]
+	if !ok {
+		return object{}, fmt.Errorf("integrity error: %q meta-data is missing", markerMetaData)
+	}
+	obj.Marker = token
+
+	return obj, nil
+}
+
+// truncate returns t truncated to the precision at which due times are
+// stored.
+func truncate(t time.Time) time.Time {
+	return time.UnixMilli(t.UnixMilli())
+}
+
+// newToken returns a new random marker token.
+func newToken() string {
+	var data [8]byte
+	_, _ = rand.Read(data[:])
+	return hex.EncodeToString(data[:])
+}
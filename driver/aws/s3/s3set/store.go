@@ -90,3 +90,18 @@ func (s *store) Open(ctx context.Context, name string) (set.BinarySet, error) {
 		objectKeyPrefix: "set/" + url.PathEscape(name) + "/",
 	}, nil
 }
+
+// Capabilities returns the limits and optional features of the store.
+func (s *store) Capabilities() set.Capabilities {
+	return set.Capabilities{
+		// S3 limits object keys to 1024 bytes. Each member is hex-encoded after
+		// the set's prefix, so this limit leaves room for set names of up to
+		// several hundred bytes.
+		MaxValueSize: 256,
+
+		// S3 lists objects in lexicographical order of their keys, which the
+		// hex encoding preserves.
+		OrderedRange:    true,
+		MeteredRequests: true,
+	}
+}
@@ -0,0 +1,32 @@
+package driver
+
+import (
+	"github.com/dogmatiq/persistencekit/journal"
+	"github.com/dogmatiq/persistencekit/kv"
+	"github.com/dogmatiq/persistencekit/set"
+)
+
+// Capabilities describes the limits and optional features of a [Driver]'s
+// stores.
+type Capabilities struct {
+	// Journal describes the driver's journal store.
+	Journal journal.Capabilities
+
+	// KV describes the driver's key/value store.
+	KV kv.Capabilities
+
+	// Set describes the driver's set store.
+	Set set.Capabilities
+}
+
+// StoreCapabilities returns the [Capabilities] reported by the stores of d.
+//
+// It is a convenience for [Driver] implementations whose capabilities are
+// exactly those of their stores.
+func StoreCapabilities(d Driver) Capabilities {
+	return Capabilities{
+		Journal: journal.CapabilitiesOf(d.JournalStore()),
+		KV:      kv.CapabilitiesOf(d.KVStore()),
+		Set:     set.CapabilitiesOf(d.SetStore()),
+	}
+}
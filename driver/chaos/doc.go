@@ -0,0 +1,11 @@
+// Package chaos provides a persistence [Driver] that wraps another driver and
+// injects faults into its operations.
+//
+// It is intended for testing retry and idempotency logic against failure modes
+// that real backends exhibit but that are difficult to reproduce on demand,
+// such as transient errors, spurious optimistic concurrency conflicts,
+// ambiguous writes and stale reads.
+//
+// Faults are injected into the journal, key/value and set stores. All other
+// stores are passed through to the wrapped driver unchanged.
+package chaos
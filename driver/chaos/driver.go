@@ -0,0 +1,224 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/blob"
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/journal"
+	"github.com/dogmatiq/persistencekit/kv"
+	"github.com/dogmatiq/persistencekit/lease"
+	"github.com/dogmatiq/persistencekit/queue"
+	"github.com/dogmatiq/persistencekit/schedule"
+	"github.com/dogmatiq/persistencekit/set"
+)
+
+// Driver is a persistence driver that injects faults into the operations of
+// another driver.
+type Driver struct {
+	next     driver.Driver
+	injector *injector
+}
+
+// New returns a [Driver] that injects the given faults into the operations of
+// next.
+//
+// The seed initializes the random source used to decide which faults are
+// injected. A given seed produces the same faults for the same sequence of
+// operations. If seed is zero, a random seed is used.
+func New(next driver.Driver, f Faults, seed uint64) *Driver {
+	return &Driver{
+		next:     next,
+		injector: newInjector(f, seed),
+	}
+}
+
+// Config holds the configuration for a chaos persistence driver.
+type Config struct {
+	// Driver is the configuration of the wrapped driver.
+	Driver driver.Config
+
+	// Faults describes the faults to inject.
+	Faults Faults
+
+	// Seed initializes the random source used to decide which faults are
+	// injected. If it is zero, a random seed is used.
+	Seed uint64
+}
+
+// NewDriver returns a [Driver] described by the given configuration.
+func (c *Config) NewDriver(ctx context.Context) (driver.Driver, error) {
+	next, err := c.Driver.NewDriver(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return New(next, c.Faults, c.Seed), nil
+}
+
+// Resolver returns the [driver.Config] for a URL.
+type Resolver func(context.Context, *url.URL) (driver.Config, error)
+
+// ParseURL returns a [Config] for the given URL string.
+//
+// URL format:
+//
+//	chaos+<scheme>://<rest>
+//
+// The URL of the wrapped driver is obtained by removing the "chaos+" prefix
+// from the scheme and removing the query parameters listed below. It is then
+// passed to resolve to obtain the wrapped driver's configuration.
+//
+// Supported query parameters:
+//   - error_rate: probability that any operation fails
+//   - error_rate.<operation>: probability that a specific operation fails (e.g. error_rate.kv.set)
+//   - ambiguous_rate: probability that a write succeeds but reports failure
+//   - conflict_rate: probability that a conditional write fails with a spurious conflict
+//   - stale_read_rate: probability that a read returns a previously observed result
+//   - latency: maximum injected latency (e.g. "10ms")
+//   - seed: seed for the random source (e.g. "42")
+func ParseURL(ctx context.Context, u string, resolve Resolver) (*Config, error) {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chaos URL: %w", err)
+	}
+	return FromURL(ctx, parsed, resolve)
+}
+
+// FromURL returns a [Config] for the given URL.
+//
+// See [ParseURL] for the URL format.
+func FromURL(ctx context.Context, u *url.URL, resolve Resolver) (*Config, error) {
+	scheme, ok := strings.CutPrefix(u.Scheme, "chaos+")
+	if !ok {
+		return nil, fmt.Errorf("invalid chaos URL: unexpected scheme %q", u.Scheme)
+	}
+	if scheme == "" {
+		return nil, errors.New("invalid chaos URL: the scheme of the wrapped driver is required (e.g. chaos+memory:///<silo>)")
+	}
+
+	cfg := &Config{}
+	query := u.Query()
+
+	for key, values := range query {
+		if len(values) != 1 {
+			return nil, fmt.Errorf("invalid chaos URL: %q parameter must be specified at most once", key)
+		}
+		value := values[0]
+
+		var err error
+		switch key {
+		case "error_rate":
+			cfg.Faults.ErrorRate, err = parseRate(value)
+		case "ambiguous_rate":
+			cfg.Faults.AmbiguousRate, err = parseRate(value)
+		case "conflict_rate":
+			cfg.Faults.ConflictRate, err = parseRate(value)
+		case "stale_read_rate":
+			cfg.Faults.StaleReadRate, err = parseRate(value)
+		case "latency":
+			cfg.Faults.Latency, err = time.ParseDuration(value)
+			if err == nil && cfg.Faults.Latency < 0 {
+				err = errors.New("must not be negative")
+			}
+		case "seed":
+			cfg.Seed, err = strconv.ParseUint(value, 10, 64)
+		default:
+			op, ok := strings.CutPrefix(key, "error_rate.")
+			if !ok {
+				continue // leave the parameter for the wrapped driver
+			}
+			if _, ok := operations[Operation(op)]; !ok {
+				return nil, fmt.Errorf("invalid chaos URL: unrecognized operation %q", op)
+			}
+
+			var rate float64
+			rate, err = parseRate(value)
+			if cfg.Faults.OperationErrorRates == nil {
+				cfg.Faults.OperationErrorRates = map[Operation]float64{}
+			}
+			cfg.Faults.OperationErrorRates[Operation(op)] = rate
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid chaos URL: invalid %q parameter: %w", key, err)
+		}
+
+		query.Del(key)
+	}
+
+	inner := *u
+	inner.Scheme = scheme
+	inner.RawQuery = query.Encode()
+
+	next, err := resolve(ctx, &inner)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Driver = next
+
+	return cfg, nil
+}
+
+func parseRate(s string) (float64, error) {
+	r, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if r < 0 || r > 1 {
+		return 0, errors.New("must be between 0 and 1")
+	}
+	return r, nil
+}
+
+// JournalStore returns the wrapped driver's journal store, with fault
+// injection.
+func (d *Driver) JournalStore() journal.BinaryStore {
+	return &journalStore{d.next.JournalStore(), d.injector}
+}
+
+// KVStore returns the wrapped driver's key/value store, with fault injection.
+func (d *Driver) KVStore() kv.BinaryStore {
+	return &kvStore{d.next.KVStore(), d.injector}
+}
+
+// SetStore returns the wrapped driver's set store, with fault injection.
+func (d *Driver) SetStore() set.BinaryStore {
+	return &setStore{d.next.SetStore(), d.injector}
+}
+
+// LeaseStore returns the wrapped driver's lease store.
+func (d *Driver) LeaseStore() lease.Store {
+	return d.next.LeaseStore()
+}
+
+// QueueStore returns the wrapped driver's queue store.
+func (d *Driver) QueueStore() queue.BinaryStore {
+	return d.next.QueueStore()
+}
+
+// ScheduleStore returns the wrapped driver's schedule store.
+func (d *Driver) ScheduleStore() schedule.BinaryStore {
+	return d.next.ScheduleStore()
+}
+
+// BlobStore returns the wrapped driver's blob store.
+func (d *Driver) BlobStore() blob.Store {
+	return d.next.BlobStore()
+}
+
+// Capabilities returns the limits and optional features of the driver's
+// stores.
+func (d *Driver) Capabilities() driver.Capabilities {
+	return driver.StoreCapabilities(d)
+}
+
+// Close closes the wrapped driver.
+func (d *Driver) Close() error {
+	return d.next.Close()
+}
@@ -0,0 +1,417 @@
+package chaos_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/driver"
+	. "github.com/dogmatiq/persistencekit/driver/chaos"
+	"github.com/dogmatiq/persistencekit/driver/memory"
+	"github.com/dogmatiq/persistencekit/internal/drivertest"
+	"github.com/dogmatiq/persistencekit/journal"
+	"github.com/dogmatiq/persistencekit/kv"
+	"github.com/dogmatiq/persistencekit/persistencekittest"
+	"github.com/dogmatiq/persistencekit/provision"
+	"github.com/dogmatiq/persistencekit/set"
+)
+
+func TestNew(t *testing.T) {
+	ref := memory.New(&memory.Config{Silo: "chaos-test-new"})
+	t.Cleanup(func() {
+		ref.Close()
+	})
+
+	d := New(memory.New(&memory.Config{Silo: "chaos-test-new"}), Faults{}, 0)
+	t.Cleanup(func() {
+		d.Close()
+	})
+
+	drivertest.RunTests(
+		t,
+		d,
+		ref.JournalStore(),
+		ref.KVStore(),
+		ref.SetStore(),
+		ref.LeaseStore(),
+		ref.QueueStore(),
+		ref.ScheduleStore(),
+		ref.BlobStore(),
+	)
+}
+
+func TestConfig(t *testing.T) {
+	persistencekittest.Run(
+		t,
+		&Config{
+			Driver: &memory.Config{Silo: "chaos-test-config"},
+		},
+	)
+}
+
+func TestDriver_Capabilities(t *testing.T) {
+	d := New(
+		memory.New(&memory.Config{Silo: "chaos-test-capabilities"}),
+		Faults{ConflictRate: 0.5},
+		0,
+	)
+
+	caps := d.Capabilities()
+
+	if caps.KV.ChangeFeed {
+		t.Fatal("did not expect the key/value store to report a change feed")
+	}
+
+	if caps.KV.ConflictDetail {
+		t.Fatal("did not expect the key/value store to report conflict details")
+	}
+}
+
+func TestDriver_provisioning(t *testing.T) {
+	d := New(memory.New(&memory.Config{Silo: "chaos-test-provision"}), Faults{ErrorRate: 1}, 0)
+	defer d.Close()
+
+	if _, err := provision.PlanProvision(t.Context(), d); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := provision.PlanDeprovision(t.Context(), d); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := provision.Deprovision(t.Context(), d); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	t.Run("it parses the fault configuration", func(t *testing.T) {
+		cfg, err := ParseURL(
+			t.Context(),
+			"chaos+memory:///chaos-test-parse-url?error_rate=0.05&error_rate.kv.set=1&ambiguous_rate=0.1&conflict_rate=0.2&stale_read_rate=0.3&latency=5ms&seed=42",
+			resolve,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := cfg.Faults.ErrorRate, 0.05; got != want {
+			t.Fatalf("unexpected error rate: got %v, want %v", got, want)
+		}
+
+		if got, want := cfg.Faults.OperationErrorRates[KVSet], 1.0; got != want {
+			t.Fatalf("unexpected kv.set error rate: got %v, want %v", got, want)
+		}
+
+		if got, want := cfg.Faults.AmbiguousRate, 0.1; got != want {
+			t.Fatalf("unexpected ambiguous rate: got %v, want %v", got, want)
+		}
+
+		if got, want := cfg.Faults.ConflictRate, 0.2; got != want {
+			t.Fatalf("unexpected conflict rate: got %v, want %v", got, want)
+		}
+
+		if got, want := cfg.Faults.StaleReadRate, 0.3; got != want {
+			t.Fatalf("unexpected stale read rate: got %v, want %v", got, want)
+		}
+
+		if got, want := cfg.Faults.Latency, 5*time.Millisecond; got != want {
+			t.Fatalf("unexpected latency: got %v, want %v", got, want)
+		}
+
+		if got, want := cfg.Seed, uint64(42); got != want {
+			t.Fatalf("unexpected seed: got %v, want %v", got, want)
+		}
+
+		inner, ok := cfg.Driver.(*memory.Config)
+		if !ok {
+			t.Fatalf("unexpected wrapped config type: %T", cfg.Driver)
+		}
+
+		if got, want := inner.Silo, "chaos-test-parse-url"; got != want {
+			t.Fatalf("unexpected silo: got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("it passes unrecognized parameters to the wrapped driver", func(t *testing.T) {
+		_, err := ParseURL(t.Context(), "chaos+memory:///silo?error_rate=0.05&other=1", resolve)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+
+		if !strings.Contains(err.Error(), "query parameters are not supported") {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	})
+
+	t.Run("when the URL is invalid", func(t *testing.T) {
+		cases := []struct {
+			Name    string
+			URL     string
+			WantErr string
+		}{
+			{"wrong scheme", "memory:///silo", `unexpected scheme "memory"`},
+			{"no wrapped scheme", "chaos+:///silo", "the scheme of the wrapped driver is required"},
+			{"rate out of range", "chaos+memory:///silo?error_rate=1.5", `invalid "error_rate" parameter: must be between 0 and 1`},
+			{"malformed rate", "chaos+memory:///silo?conflict_rate=x", `invalid "conflict_rate" parameter`},
+			{"unknown operation", "chaos+memory:///silo?error_rate.kv.frobnicate=1", `unrecognized operation "kv.frobnicate"`},
+			{"negative latency", "chaos+memory:///silo?latency=-1s", `invalid "latency" parameter: must not be negative`},
+			{"malformed seed", "chaos+memory:///silo?seed=-1", `invalid "seed" parameter`},
+			{"repeated parameter", "chaos+memory:///silo?seed=1&seed=2", `"seed" parameter must be specified at most once`},
+		}
+		for _, tc := range cases {
+			t.Run(tc.Name, func(t *testing.T) {
+				_, err := ParseURL(t.Context(), tc.URL, resolve)
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				if !strings.Contains(err.Error(), tc.WantErr) {
+					t.Fatalf("unexpected error: got %q, want substring %q", err.Error(), tc.WantErr)
+				}
+			})
+		}
+	})
+}
+
+func TestDriver(t *testing.T) {
+	t.Run("it fails operations at the configured error rate", func(t *testing.T) {
+		d := New(
+			memory.New(&memory.Config{Silo: "chaos-test-error-rate"}),
+			Faults{
+				OperationErrorRates: map[Operation]float64{
+					KVSetUnconditional: 1,
+				},
+			},
+			1,
+		)
+
+		ks, err := d.KVStore().Open(t.Context(), "<keyspace>")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ks.Close()
+
+		err = ks.SetUnconditional(t.Context(), []byte("<key>"), []byte("<value>"))
+
+		var fault FaultError
+		if !errors.As(err, &fault) {
+			t.Fatalf("expected a fault error, got %v", err)
+		}
+
+		if fault.Operation != KVSetUnconditional || fault.Ambiguous {
+			t.Fatalf("unexpected fault: %+v", fault)
+		}
+
+		ok, err := ks.Has(t.Context(), []byte("<key>"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Fatal("expected the failed write not to be performed")
+		}
+	})
+
+	t.Run("it performs ambiguous writes", func(t *testing.T) {
+		d := New(
+			memory.New(&memory.Config{Silo: "chaos-test-ambiguous"}),
+			Faults{AmbiguousRate: 1},
+			1,
+		)
+
+		s, err := d.SetStore().Open(t.Context(), "<set>")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+
+		err = s.Add(t.Context(), []byte("<value>"))
+
+		var fault FaultError
+		if !errors.As(err, &fault) {
+			t.Fatalf("expected a fault error, got %v", err)
+		}
+
+		if !fault.Ambiguous {
+			t.Fatal("expected the fault to be ambiguous")
+		}
+
+		ok, err := s.Has(t.Context(), []byte("<value>"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("expected the ambiguous write to be performed")
+		}
+	})
+
+	t.Run("it injects spurious conflicts", func(t *testing.T) {
+		d := New(
+			memory.New(&memory.Config{Silo: "chaos-test-conflict"}),
+			Faults{ConflictRate: 1},
+			1,
+		)
+
+		j, err := d.JournalStore().Open(t.Context(), "<journal>")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer j.Close()
+
+		if err := j.Append(t.Context(), 0, []byte("<record>")); !journal.IsConflict(err) {
+			t.Fatalf("expected a journal conflict, got %v", err)
+		}
+
+		ks, err := d.KVStore().Open(t.Context(), "<keyspace>")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ks.Close()
+
+		if _, err := ks.Set(t.Context(), []byte("<key>"), []byte("<value>"), ""); !kv.IsConflict(err) {
+			t.Fatalf("expected a kv conflict, got %v", err)
+		}
+
+		bounds, err := j.Bounds(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bounds.IsEmpty() {
+			t.Fatal("expected the conflicting append not to be performed")
+		}
+	})
+
+	t.Run("it returns stale reads", func(t *testing.T) {
+		next := memory.New(&memory.Config{Silo: "chaos-test-stale-read"})
+		d := New(next, Faults{StaleReadRate: 1}, 1)
+
+		ks, err := d.KVStore().Open(t.Context(), "<keyspace>")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ks.Close()
+
+		k := []byte("<key>")
+
+		if _, _, err := ks.Get(t.Context(), k); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ks.SetUnconditional(t.Context(), k, []byte("<value>")); err != nil {
+			t.Fatal(err)
+		}
+
+		v, r, err := ks.Get(t.Context(), k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(v) != 0 || r != "" {
+			t.Fatalf("expected a stale read, got %q at revision %q", v, r)
+		}
+	})
+
+	t.Run("it forwards optional operations to the wrapped driver", func(t *testing.T) {
+		d := New(
+			memory.New(&memory.Config{Silo: "chaos-test-forward"}),
+			Faults{
+				// Fail every range operation, so that the optional operations
+				// can only succeed if they are forwarded to the wrapped driver,
+				// rather than falling back to ranging over the wrapper.
+				OperationErrorRates: map[Operation]float64{
+					KVRange:  1,
+					SetRange: 1,
+				},
+			},
+			1,
+		)
+
+		ks, err := d.KVStore().Open(t.Context(), "<keyspace>")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ks.Close()
+
+		if err := ks.SetUnconditional(t.Context(), []byte("<key>"), []byte("<value>")); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := kv.RangeKeys(
+			t.Context(),
+			ks,
+			func(context.Context, []byte, kv.Revision) (bool, error) {
+				return true, nil
+			},
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		if stats, err := kv.StatsOf(t.Context(), ks); err != nil {
+			t.Fatal(err)
+		} else if stats.Keys != 1 {
+			t.Fatalf("unexpected key count: got %d, want 1", stats.Keys)
+		}
+
+		s, err := d.SetStore().Open(t.Context(), "<set>")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+
+		if err := s.Add(t.Context(), []byte("<value>")); err != nil {
+			t.Fatal(err)
+		}
+
+		if stats, err := set.StatsOf(t.Context(), s); err != nil {
+			t.Fatal(err)
+		} else if stats.Members != 1 {
+			t.Fatalf("unexpected member count: got %d, want 1", stats.Members)
+		}
+	})
+
+	t.Run("it injects the same faults given the same seed", func(t *testing.T) {
+		run := func(silo string) []bool {
+			d := New(
+				memory.New(&memory.Config{Silo: silo}),
+				Faults{
+					OperationErrorRates: map[Operation]float64{
+						SetAdd: 0.5,
+					},
+				},
+				42,
+			)
+
+			s, err := d.SetStore().Open(t.Context(), "<set>")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+
+			var outcomes []bool
+			for range 100 {
+				err := s.Add(t.Context(), []byte("<value>"))
+				outcomes = append(outcomes, err == nil)
+			}
+			return outcomes
+		}
+
+		a := run("chaos-test-seed-a")
+		b := run("chaos-test-seed-b")
+
+		for i := range a {
+			if a[i] != b[i] {
+				t.Fatalf("outcomes diverge at operation %d", i)
+			}
+		}
+	})
+}
+
+func resolve(ctx context.Context, u *url.URL) (driver.Config, error) {
+	cfg, err := memory.FromURL(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
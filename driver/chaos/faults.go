@@ -0,0 +1,268 @@
+package chaos
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Operation identifies a store operation into which faults may be injected.
+type Operation string
+
+// Operations into which faults may be injected.
+const (
+	JournalOpen     Operation = "journal.open"
+	JournalBounds   Operation = "journal.bounds"
+	JournalGet      Operation = "journal.get"
+	JournalRange    Operation = "journal.range"
+	JournalAppend   Operation = "journal.append"
+	JournalTruncate Operation = "journal.truncate"
+	JournalStats    Operation = "journal.stats"
+
+	KVOpen             Operation = "kv.open"
+	KVGet              Operation = "kv.get"
+	KVHas              Operation = "kv.has"
+	KVSet              Operation = "kv.set"
+	KVSetUnconditional Operation = "kv.set_unconditional"
+	KVRange            Operation = "kv.range"
+	KVRangeKeys        Operation = "kv.range_keys"
+	KVStats            Operation = "kv.stats"
+
+	SetOpen         Operation = "set.open"
+	SetHas          Operation = "set.has"
+	SetHasBatch     Operation = "set.has_batch"
+	SetAdd          Operation = "set.add"
+	SetTryAdd       Operation = "set.try_add"
+	SetRemove       Operation = "set.remove"
+	SetTryRemove    Operation = "set.try_remove"
+	SetRange        Operation = "set.range"
+	SetRangeAlgebra Operation = "set.range_algebra"
+	SetStats        Operation = "set.stats"
+)
+
+// operations is the set of all known operations.
+var operations = map[Operation]struct{}{
+	JournalOpen:        {},
+	JournalBounds:      {},
+	JournalGet:         {},
+	JournalRange:       {},
+	JournalAppend:      {},
+	JournalTruncate:    {},
+	JournalStats:       {},
+	KVOpen:             {},
+	KVGet:              {},
+	KVHas:              {},
+	KVSet:              {},
+	KVSetUnconditional: {},
+	KVRange:            {},
+	KVRangeKeys:        {},
+	KVStats:            {},
+	SetOpen:            {},
+	SetHas:             {},
+	SetHasBatch:        {},
+	SetAdd:             {},
+	SetTryAdd:          {},
+	SetRemove:          {},
+	SetTryRemove:       {},
+	SetRange:           {},
+	SetRangeAlgebra:    {},
+	SetStats:           {},
+}
+
+// Faults describes the faults injected by a [Driver].
+//
+// Each rate is a probability between 0 and 1. The zero value injects no
+// faults.
+type Faults struct {
+	// ErrorRate is the probability that an operation fails with a [FaultError]
+	// without being performed.
+	ErrorRate float64
+
+	// OperationErrorRates overrides ErrorRate for specific operations.
+	OperationErrorRates map[Operation]float64
+
+	// AmbiguousRate is the probability that a write operation is performed
+	// successfully but a [FaultError] is returned anyway, such that the caller
+	// cannot tell whether the write took effect.
+	AmbiguousRate float64
+
+	// ConflictRate is the probability that a conditional write fails with a
+	// spurious conflict error without being performed.
+	//
+	// It applies to [JournalAppend], which fails with a
+	// [journal.ConflictError], and [KVSet], which fails with a
+	// [kv.ConflictError].
+	ConflictRate float64
+
+	// StaleReadRate is the probability that a read returns a result that was
+	// observed by an earlier read of the same data, instead of the current one.
+	//
+	// It applies to [JournalBounds], [KVGet], [KVHas], [SetHas] and
+	// [SetHasBatch].
+	StaleReadRate float64
+
+	// Latency is the maximum delay injected before each operation. The actual
+	// delay is chosen uniformly between zero and Latency.
+	Latency time.Duration
+}
+
+// FaultError is the error returned by an operation that has failed due to an
+// injected fault.
+type FaultError struct {
+	// Operation is the operation that failed.
+	Operation Operation
+
+	// Ambiguous is true if the operation was performed successfully despite the
+	// error.
+	Ambiguous bool
+}
+
+func (e FaultError) Error() string {
+	if e.Ambiguous {
+		return fmt.Sprintf("chaos: injected ambiguous failure of %s operation", e.Operation)
+	}
+	return fmt.Sprintf("chaos: injected failure of %s operation", e.Operation)
+}
+
+// maxObservations is the maximum number of read results that are retained for
+// replay as stale reads. Once the limit is reached, the oldest observation is
+// discarded to make room for each new one.
+const maxObservations = 1024
+
+// injector makes the random decisions that determine which faults are
+// injected, and records the results of reads so that they can be replayed as
+// stale reads.
+type injector struct {
+	faults Faults
+
+	m        sync.Mutex
+	rand     *rand.Rand
+	observed map[string]any
+
+	// evict is a ring buffer containing the keys of the entries in observed,
+	// and next is the index of the oldest key once the buffer is full.
+	evict []string
+	next  int
+}
+
+func newInjector(f Faults, seed uint64) *injector {
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+
+	return &injector{
+		faults:   f,
+		rand:     rand.New(rand.NewPCG(seed, seed)),
+		observed: map[string]any{},
+	}
+}
+
+// chance returns true with probability p.
+func (in *injector) chance(p float64) bool {
+	if p <= 0 {
+		return false
+	}
+
+	in.m.Lock()
+	defer in.m.Unlock()
+
+	return in.rand.Float64() < p
+}
+
+// Before is called before op is performed. It injects latency, then returns a
+// non-nil error if op should fail without being performed.
+func (in *injector) Before(ctx context.Context, op Operation) error {
+	if in.faults.Latency > 0 {
+		in.m.Lock()
+		d := time.Duration(in.rand.Int64N(int64(in.faults.Latency) + 1))
+		in.m.Unlock()
+
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	rate := in.faults.ErrorRate
+	if r, ok := in.faults.OperationErrorRates[op]; ok {
+		rate = r
+	}
+
+	if in.chance(rate) {
+		return FaultError{Operation: op}
+	}
+
+	return nil
+}
+
+// After is called after the write operation op has been performed. It returns
+// the error to report to the caller.
+func (in *injector) After(op Operation, err error) error {
+	if err == nil && in.chance(in.faults.AmbiguousRate) {
+		return FaultError{Operation: op, Ambiguous: true}
+	}
+	return err
+}
+
+// Conflict returns true if a conditional write should fail with a spurious
+// conflict.
+func (in *injector) Conflict() bool {
+	return in.chance(in.faults.ConflictRate)
+}
+
+// staleRead returns the result of a read operation to report to the caller.
+//
+// It either records current as the most recently observed result for key and
+// returns it, or returns the result that was observed previously.
+func staleRead[T any](in *injector, key string, current T) T {
+	stale := in.chance(in.faults.StaleReadRate)
+
+	in.m.Lock()
+	defer in.m.Unlock()
+
+	if stale {
+		if prev, ok := in.observed[key]; ok {
+			return prev.(T)
+		}
+	}
+
+	in.observe(key, current)
+	return current
+}
+
+// observe records v as the most recently observed result for key, discarding
+// the oldest observation if the limit has been reached.
+//
+// It assumes in.m is held.
+func (in *injector) observe(key string, v any) {
+	if _, ok := in.observed[key]; !ok {
+		if len(in.evict) < maxObservations {
+			in.evict = append(in.evict, key)
+		} else {
+			delete(in.observed, in.evict[in.next])
+			in.evict[in.next] = key
+			in.next = (in.next + 1) % maxObservations
+		}
+	}
+
+	in.observed[key] = v
+}
+
+// observationKey returns the key under which the result of a read of k from
+// the named primitive is recorded.
+func observationKey(op Operation, name string, k []byte) string {
+	var buf bytes.Buffer
+	buf.WriteString(string(op))
+	buf.WriteByte(0)
+	buf.WriteString(name)
+	buf.WriteByte(0)
+	buf.Write(k)
+	return buf.String()
+}
@@ -0,0 +1,13 @@
+package chaos
+
+import (
+	"context"
+
+	"github.com/dogmatiq/persistencekit/health"
+)
+
+// CheckHealth checks the health of the wrapped driver. Faults are not injected
+// into health checks.
+func (d *Driver) CheckHealth(ctx context.Context) health.Report {
+	return health.Check(ctx, d.next)
+}
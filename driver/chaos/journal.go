@@ -0,0 +1,109 @@
+package chaos
+
+import (
+	"context"
+
+	"github.com/dogmatiq/persistencekit/journal"
+)
+
+type journalStore struct {
+	next     journal.BinaryStore
+	injector *injector
+}
+
+func (s *journalStore) Provision(ctx context.Context) error {
+	return s.next.Provision(ctx)
+}
+
+func (s *journalStore) Capabilities() journal.Capabilities {
+	return journal.CapabilitiesOf(s.next)
+}
+
+func (s *journalStore) Open(ctx context.Context, name string) (journal.BinaryJournal, error) {
+	if err := s.injector.Before(ctx, JournalOpen); err != nil {
+		return nil, err
+	}
+
+	next, err := s.next.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &journ{next, s.injector}, nil
+}
+
+type journ struct {
+	next     journal.BinaryJournal
+	injector *injector
+}
+
+func (j *journ) Name() string {
+	return j.next.Name()
+}
+
+func (j *journ) Bounds(ctx context.Context) (journal.Interval, error) {
+	if err := j.injector.Before(ctx, JournalBounds); err != nil {
+		return journal.Interval{}, err
+	}
+
+	bounds, err := j.next.Bounds(ctx)
+	if err != nil {
+		return journal.Interval{}, err
+	}
+
+	return staleRead(
+		j.injector,
+		observationKey(JournalBounds, j.next.Name(), nil),
+		bounds,
+	), nil
+}
+
+func (j *journ) Get(ctx context.Context, pos journal.Position) ([]byte, error) {
+	if err := j.injector.Before(ctx, JournalGet); err != nil {
+		return nil, err
+	}
+	return j.next.Get(ctx, pos)
+}
+
+func (j *journ) Range(ctx context.Context, pos journal.Position, fn journal.BinaryRangeFunc) error {
+	if err := j.injector.Before(ctx, JournalRange); err != nil {
+		return err
+	}
+	return j.next.Range(ctx, pos, fn)
+}
+
+func (j *journ) Stats(ctx context.Context) (journal.Stats, error) {
+	if err := j.injector.Before(ctx, JournalStats); err != nil {
+		return journal.Stats{}, err
+	}
+	return journal.StatsOf(ctx, j.next)
+}
+
+func (j *journ) Append(ctx context.Context, pos journal.Position, rec []byte) error {
+	if err := j.injector.Before(ctx, JournalAppend); err != nil {
+		return err
+	}
+
+	if j.injector.Conflict() {
+		return journal.ConflictError{
+			Journal:  j.next.Name(),
+			Position: pos,
+		}
+	}
+
+	err := j.next.Append(ctx, pos, rec)
+	return j.injector.After(JournalAppend, err)
+}
+
+func (j *journ) Truncate(ctx context.Context, pos journal.Position) error {
+	if err := j.injector.Before(ctx, JournalTruncate); err != nil {
+		return err
+	}
+
+	err := j.next.Truncate(ctx, pos)
+	return j.injector.After(JournalTruncate, err)
+}
+
+func (j *journ) Close() error {
+	return j.next.Close()
+}
@@ -0,0 +1,149 @@
+package chaos
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/dogmatiq/persistencekit/kv"
+)
+
+type kvStore struct {
+	next     kv.BinaryStore
+	injector *injector
+}
+
+func (s *kvStore) Provision(ctx context.Context) error {
+	return s.next.Provision(ctx)
+}
+
+func (s *kvStore) Capabilities() kv.Capabilities {
+	c := kv.CapabilitiesOf(s.next)
+
+	// The store does not provide a change feed, and spurious conflicts do not
+	// include the current value of the key.
+	c.ChangeFeed = false
+	if s.injector.faults.ConflictRate > 0 {
+		c.ConflictDetail = false
+	}
+
+	return c
+}
+
+func (s *kvStore) Open(ctx context.Context, name string) (kv.BinaryKeyspace, error) {
+	if err := s.injector.Before(ctx, KVOpen); err != nil {
+		return nil, err
+	}
+
+	next, err := s.next.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyspace{next, s.injector}, nil
+}
+
+type keyspace struct {
+	next     kv.BinaryKeyspace
+	injector *injector
+}
+
+// kvObservation is the result of a [kv.BinaryKeyspace.Get] operation.
+type kvObservation struct {
+	Value    []byte
+	Revision kv.Revision
+}
+
+func (ks *keyspace) Name() string {
+	return ks.next.Name()
+}
+
+func (ks *keyspace) Get(ctx context.Context, k []byte) ([]byte, kv.Revision, error) {
+	if err := ks.injector.Before(ctx, KVGet); err != nil {
+		return nil, "", err
+	}
+
+	v, r, err := ks.next.Get(ctx, k)
+	if err != nil {
+		return nil, "", err
+	}
+
+	obs := staleRead(
+		ks.injector,
+		observationKey(KVGet, ks.next.Name(), k),
+		kvObservation{bytes.Clone(v), r},
+	)
+
+	return bytes.Clone(obs.Value), obs.Revision, nil
+}
+
+func (ks *keyspace) Has(ctx context.Context, k []byte) (bool, error) {
+	if err := ks.injector.Before(ctx, KVHas); err != nil {
+		return false, err
+	}
+
+	ok, err := ks.next.Has(ctx, k)
+	if err != nil {
+		return false, err
+	}
+
+	return staleRead(
+		ks.injector,
+		observationKey(KVHas, ks.next.Name(), k),
+		ok,
+	), nil
+}
+
+func (ks *keyspace) Set(ctx context.Context, k, v []byte, r kv.Revision) (kv.Revision, error) {
+	if err := ks.injector.Before(ctx, KVSet); err != nil {
+		return "", err
+	}
+
+	if ks.injector.Conflict() {
+		return "", kv.ConflictError[[]byte, []byte]{
+			Keyspace: ks.next.Name(),
+			Key:      k,
+			Revision: r,
+		}
+	}
+
+	r, err := ks.next.Set(ctx, k, v, r)
+	if err := ks.injector.After(KVSet, err); err != nil {
+		return "", err
+	}
+
+	return r, nil
+}
+
+func (ks *keyspace) SetUnconditional(ctx context.Context, k, v []byte) error {
+	if err := ks.injector.Before(ctx, KVSetUnconditional); err != nil {
+		return err
+	}
+
+	err := ks.next.SetUnconditional(ctx, k, v)
+	return ks.injector.After(KVSetUnconditional, err)
+}
+
+func (ks *keyspace) Range(ctx context.Context, fn kv.BinaryRangeFunc) error {
+	if err := ks.injector.Before(ctx, KVRange); err != nil {
+		return err
+	}
+	return ks.next.Range(ctx, fn)
+}
+
+func (ks *keyspace) RangeKeys(ctx context.Context, fn kv.BinaryKeyRangeFunc) error {
+	if err := ks.injector.Before(ctx, KVRangeKeys); err != nil {
+		return err
+	}
+	return kv.RangeKeys(ctx, ks.next, fn)
+}
+
+func (ks *keyspace) Stats(ctx context.Context) (kv.Stats, error) {
+	if err := ks.injector.Before(ctx, KVStats); err != nil {
+		return kv.Stats{}, err
+	}
+	return kv.StatsOf(ctx, ks.next)
+}
+
+func (ks *keyspace) Close() error {
+	return ks.next.Close()
+}
@@ -0,0 +1,33 @@
+package chaos
+
+import (
+	"context"
+
+	"github.com/dogmatiq/persistencekit/provision"
+)
+
+// PlanProvision returns the changes that provisioning the wrapped driver would
+// make. Faults are not injected into provisioning.
+//
+// It returns [provision.ErrUnsupported] if the wrapped driver does not
+// implement [provision.Planner].
+func (d *Driver) PlanProvision(ctx context.Context) (provision.Plan, error) {
+	return provision.PlanProvision(ctx, d.next)
+}
+
+// PlanDeprovision returns the changes that deprovisioning the wrapped driver
+// would make.
+//
+// It returns [provision.ErrUnsupported] if the wrapped driver does not
+// implement [provision.Planner].
+func (d *Driver) PlanDeprovision(ctx context.Context) (provision.Plan, error) {
+	return provision.PlanDeprovision(ctx, d.next)
+}
+
+// Deprovision removes the infrastructure used by the wrapped driver.
+//
+// It returns [provision.ErrUnsupported] if the wrapped driver does not
+// implement [provision.Deprovisioner].
+func (d *Driver) Deprovision(ctx context.Context) error {
+	return provision.Deprovision(ctx, d.next)
+}
@@ -0,0 +1,169 @@
+package chaos
+
+import (
+	"context"
+
+	"github.com/dogmatiq/persistencekit/set"
+)
+
+type setStore struct {
+	next     set.BinaryStore
+	injector *injector
+}
+
+func (s *setStore) Provision(ctx context.Context) error {
+	return s.next.Provision(ctx)
+}
+
+func (s *setStore) Capabilities() set.Capabilities {
+	return set.CapabilitiesOf(s.next)
+}
+
+func (s *setStore) Open(ctx context.Context, name string) (set.BinarySet, error) {
+	if err := s.injector.Before(ctx, SetOpen); err != nil {
+		return nil, err
+	}
+
+	next, err := s.next.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chaosSet{next, s.injector}, nil
+}
+
+type chaosSet struct {
+	next     set.BinarySet
+	injector *injector
+}
+
+func (s *chaosSet) Name() string {
+	return s.next.Name()
+}
+
+func (s *chaosSet) Has(ctx context.Context, v []byte) (bool, error) {
+	if err := s.injector.Before(ctx, SetHas); err != nil {
+		return false, err
+	}
+
+	ok, err := s.next.Has(ctx, v)
+	if err != nil {
+		return false, err
+	}
+
+	return staleRead(
+		s.injector,
+		observationKey(SetHas, s.next.Name(), v),
+		ok,
+	), nil
+}
+
+func (s *chaosSet) HasBatch(ctx context.Context, vs [][]byte) ([]bool, error) {
+	if err := s.injector.Before(ctx, SetHasBatch); err != nil {
+		return nil, err
+	}
+
+	result, err := set.HasBatch(ctx, s.next, vs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, v := range vs {
+		result[i] = staleRead(
+			s.injector,
+			observationKey(SetHas, s.next.Name(), v),
+			result[i],
+		)
+	}
+
+	return result, nil
+}
+
+func (s *chaosSet) Add(ctx context.Context, v []byte) error {
+	if err := s.injector.Before(ctx, SetAdd); err != nil {
+		return err
+	}
+
+	err := s.next.Add(ctx, v)
+	return s.injector.After(SetAdd, err)
+}
+
+func (s *chaosSet) TryAdd(ctx context.Context, v []byte) (bool, error) {
+	if err := s.injector.Before(ctx, SetTryAdd); err != nil {
+		return false, err
+	}
+
+	ok, err := s.next.TryAdd(ctx, v)
+	if err := s.injector.After(SetTryAdd, err); err != nil {
+		return false, err
+	}
+
+	return ok, nil
+}
+
+func (s *chaosSet) Remove(ctx context.Context, v []byte) error {
+	if err := s.injector.Before(ctx, SetRemove); err != nil {
+		return err
+	}
+
+	err := s.next.Remove(ctx, v)
+	return s.injector.After(SetRemove, err)
+}
+
+func (s *chaosSet) TryRemove(ctx context.Context, v []byte) (bool, error) {
+	if err := s.injector.Before(ctx, SetTryRemove); err != nil {
+		return false, err
+	}
+
+	ok, err := s.next.TryRemove(ctx, v)
+	if err := s.injector.After(SetTryRemove, err); err != nil {
+		return false, err
+	}
+
+	return ok, nil
+}
+
+func (s *chaosSet) Range(ctx context.Context, fn set.BinaryRangeFunc) error {
+	if err := s.injector.Before(ctx, SetRange); err != nil {
+		return err
+	}
+	return s.next.Range(ctx, fn)
+}
+
+func (s *chaosSet) RangeAlgebra(
+	ctx context.Context,
+	op set.Operation,
+	others []set.BinarySet,
+	fn set.BinaryRangeFunc,
+) (bool, error) {
+	r, ok := s.next.(set.AlgebraRanger[[]byte])
+	if !ok {
+		return false, nil
+	}
+
+	next := make([]set.BinarySet, len(others))
+	for i, o := range others {
+		x, ok := o.(*chaosSet)
+		if !ok {
+			return false, nil
+		}
+		next[i] = x.next
+	}
+
+	if err := s.injector.Before(ctx, SetRangeAlgebra); err != nil {
+		return false, err
+	}
+
+	return r.RangeAlgebra(ctx, op, next, fn)
+}
+
+func (s *chaosSet) Stats(ctx context.Context) (set.Stats, error) {
+	if err := s.injector.Before(ctx, SetStats); err != nil {
+		return set.Stats{}, err
+	}
+	return set.StatsOf(ctx, s.next)
+}
+
+func (s *chaosSet) Close() error {
+	return s.next.Close()
+}
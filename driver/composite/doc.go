@@ -0,0 +1,6 @@
+// Package composite provides a persistence [Driver] that assembles its stores
+// from several other drivers.
+//
+// It allows each primitive to be stored in the backend best suited to it, such
+// as journals in PostgreSQL, keyspaces in DynamoDB and sets in memory.
+package composite
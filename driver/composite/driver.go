@@ -0,0 +1,342 @@
+package composite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+
+	"github.com/dogmatiq/persistencekit/blob"
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/journal"
+	"github.com/dogmatiq/persistencekit/kv"
+	"github.com/dogmatiq/persistencekit/lease"
+	"github.com/dogmatiq/persistencekit/queue"
+	"github.com/dogmatiq/persistencekit/schedule"
+	"github.com/dogmatiq/persistencekit/set"
+)
+
+// Driver is a persistence driver that provides each store from a separately
+// configured underlying driver.
+type Driver struct {
+	drivers Drivers
+}
+
+// Drivers is the set of underlying drivers used by a [Driver].
+//
+// Each field is the driver that provides the corresponding store. A nil field
+// selects the Default driver.
+type Drivers struct {
+	Default  driver.Driver
+	Journal  driver.Driver
+	KV       driver.Driver
+	Set      driver.Driver
+	Lease    driver.Driver
+	Queue    driver.Driver
+	Schedule driver.Driver
+	Blob     driver.Driver
+}
+
+// stores returns the per-store fields of d.
+func (d *Drivers) stores() []store[driver.Driver] {
+	return []store[driver.Driver]{
+		{"journal", &d.Journal},
+		{"kv", &d.KV},
+		{"set", &d.Set},
+		{"lease", &d.Lease},
+		{"queue", &d.Queue},
+		{"schedule", &d.Schedule},
+		{"blob", &d.Blob},
+	}
+}
+
+// store is a per-store field of [Drivers] or [Config].
+type store[T any] struct {
+	Name  string
+	Value *T
+}
+
+// New returns a [Driver] that provides each store from the given drivers.
+//
+// It returns an error if any store has neither its own driver nor a default.
+// Closing the returned driver closes each of the underlying drivers.
+func New(d Drivers) (*Driver, error) {
+	for _, s := range d.stores() {
+		if *s.Value != nil {
+			continue
+		}
+
+		if d.Default == nil {
+			return nil, fmt.Errorf("no driver is configured for the %s store", s.Name)
+		}
+
+		*s.Value = d.Default
+	}
+
+	return &Driver{d}, nil
+}
+
+// Config holds the configuration for a composite persistence driver.
+//
+// Each field is the configuration of the driver that provides the
+// corresponding store. A nil field selects the Default configuration.
+type Config struct {
+	Default  driver.Config
+	Journal  driver.Config
+	KV       driver.Config
+	Set      driver.Config
+	Lease    driver.Config
+	Queue    driver.Config
+	Schedule driver.Config
+	Blob     driver.Config
+}
+
+// stores returns the per-store fields of c.
+func (c *Config) stores() []store[driver.Config] {
+	return []store[driver.Config]{
+		{"journal", &c.Journal},
+		{"kv", &c.KV},
+		{"set", &c.Set},
+		{"lease", &c.Lease},
+		{"queue", &c.Queue},
+		{"schedule", &c.Schedule},
+		{"blob", &c.Blob},
+	}
+}
+
+// NewDriver returns a [Driver] described by the given configuration.
+//
+// Stores that use equal configuration values share a single underlying
+// driver.
+func (c *Config) NewDriver(ctx context.Context) (_ driver.Driver, err error) {
+	var (
+		drivers Drivers
+		opened  []driver.Driver
+		shared  = map[driver.Config]driver.Driver{}
+	)
+
+	defer func() {
+		if err != nil {
+			for _, d := range opened {
+				d.Close()
+			}
+		}
+	}()
+
+	open := func(cfg driver.Config) (driver.Driver, error) {
+		comparable := reflect.TypeOf(cfg).Comparable()
+
+		if comparable {
+			if d, ok := shared[cfg]; ok {
+				return d, nil
+			}
+		}
+
+		d, err := cfg.NewDriver(ctx)
+		if err != nil {
+			return nil, err
+		}
+		opened = append(opened, d)
+
+		if comparable {
+			shared[cfg] = d
+		}
+
+		return d, nil
+	}
+
+	cfgs := c.stores()
+	for i, s := range drivers.stores() {
+		cfg := *cfgs[i].Value
+		if cfg == nil {
+			cfg = c.Default
+		}
+
+		if cfg == nil {
+			return nil, fmt.Errorf("no driver is configured for the %s store", s.Name)
+		}
+
+		d, err := open(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create the driver for the %s store: %w", s.Name, err)
+		}
+
+		*s.Value = d
+	}
+
+	return &Driver{drivers}, nil
+}
+
+// Resolver returns the [driver.Config] for a URL.
+type Resolver func(context.Context, *url.URL) (driver.Config, error)
+
+// ParseURL returns a [Config] for the given URL string.
+//
+// URL format:
+//
+//	composite:?default=<url>&journal=<url>&kv=<url>&...
+//
+// Each query parameter is the URL of the driver that provides the
+// corresponding store, and is passed to resolve to obtain that driver's
+// configuration. Nested URLs that contain '&' or '#' characters must be
+// percent-encoded.
+//
+// Supported query parameters:
+//   - default: driver for any store without its own parameter
+//   - journal: driver for the journal store
+//   - kv: driver for the key/value store
+//   - set: driver for the set store
+//   - lease: driver for the lease store
+//   - queue: driver for the queue store
+//   - schedule: driver for the schedule store
+//   - blob: driver for the blob store
+//
+// Stores with identical URLs share a single underlying driver.
+func ParseURL(ctx context.Context, u string, resolve Resolver) (*Config, error) {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return nil, fmt.Errorf("invalid composite URL: %w", err)
+	}
+	return FromURL(ctx, parsed, resolve)
+}
+
+// FromURL returns a [Config] for the given URL.
+//
+// See [ParseURL] for the URL format.
+func FromURL(ctx context.Context, u *url.URL, resolve Resolver) (*Config, error) {
+	if u.Scheme != "composite" {
+		return nil, fmt.Errorf("invalid composite URL: unexpected scheme %q", u.Scheme)
+	}
+
+	if u.Opaque != "" || u.Host != "" || u.Path != "" {
+		return nil, errors.New("invalid composite URL: drivers must be specified as query parameters (e.g. composite:?default=<url>)")
+	}
+
+	cfg := &Config{}
+	params := map[string]*driver.Config{
+		"default": &cfg.Default,
+	}
+	for _, s := range cfg.stores() {
+		params[s.Name] = s.Value
+	}
+
+	resolved := map[string]driver.Config{}
+
+	for key, values := range u.Query() {
+		target, ok := params[key]
+		if !ok {
+			return nil, fmt.Errorf("invalid composite URL: unrecognized %q parameter", key)
+		}
+
+		if len(values) != 1 {
+			return nil, fmt.Errorf("invalid composite URL: %q parameter must be specified at most once", key)
+		}
+		value := values[0]
+
+		if c, ok := resolved[value]; ok {
+			*target = c
+			continue
+		}
+
+		parsed, err := url.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid composite URL: invalid %q parameter: %w", key, err)
+		}
+
+		c, err := resolve(ctx, parsed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid composite URL: invalid %q parameter: %w", key, err)
+		}
+
+		resolved[value] = c
+		*target = c
+	}
+
+	if cfg.Default == nil {
+		for _, s := range cfg.stores() {
+			if *s.Value == nil {
+				return nil, fmt.Errorf("invalid composite URL: no driver is configured for the %s store, specify the %q or %q parameter", s.Name, s.Name, "default")
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+// JournalStore returns the journal store of the journal driver.
+func (d *Driver) JournalStore() journal.BinaryStore {
+	return d.drivers.Journal.JournalStore()
+}
+
+// KVStore returns the key/value store of the key/value driver.
+func (d *Driver) KVStore() kv.BinaryStore {
+	return d.drivers.KV.KVStore()
+}
+
+// SetStore returns the set store of the set driver.
+func (d *Driver) SetStore() set.BinaryStore {
+	return d.drivers.Set.SetStore()
+}
+
+// LeaseStore returns the lease store of the lease driver.
+func (d *Driver) LeaseStore() lease.Store {
+	return d.drivers.Lease.LeaseStore()
+}
+
+// QueueStore returns the queue store of the queue driver.
+func (d *Driver) QueueStore() queue.BinaryStore {
+	return d.drivers.Queue.QueueStore()
+}
+
+// ScheduleStore returns the schedule store of the schedule driver.
+func (d *Driver) ScheduleStore() schedule.BinaryStore {
+	return d.drivers.Schedule.ScheduleStore()
+}
+
+// BlobStore returns the blob store of the blob driver.
+func (d *Driver) BlobStore() blob.Store {
+	return d.drivers.Blob.BlobStore()
+}
+
+// Capabilities returns the limits and optional features of the driver's
+// stores.
+func (d *Driver) Capabilities() driver.Capabilities {
+	return driver.StoreCapabilities(d)
+}
+
+// Close closes each of the underlying drivers, including the default driver.
+//
+// A driver that provides more than one store is closed once.
+func (d *Driver) Close() error {
+	var (
+		errs   []error
+		closed []driver.Driver
+	)
+
+	stores := append(
+		[]store[driver.Driver]{{"default", &d.drivers.Default}},
+		d.drivers.stores()...,
+	)
+
+	for _, s := range stores {
+		v := *s.Value
+		if v == nil {
+			continue
+		}
+
+		if reflect.TypeOf(v).Comparable() {
+			if slices.Contains(closed, v) {
+				continue
+			}
+			closed = append(closed, v)
+		}
+
+		if err := v.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("unable to close the driver for the %s store: %w", s.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
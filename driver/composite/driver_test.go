@@ -0,0 +1,329 @@
+package composite_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/driver"
+	. "github.com/dogmatiq/persistencekit/driver/composite"
+	"github.com/dogmatiq/persistencekit/driver/memory"
+	"github.com/dogmatiq/persistencekit/health"
+	"github.com/dogmatiq/persistencekit/internal/drivertest"
+	"github.com/dogmatiq/persistencekit/persistencekittest"
+	"github.com/dogmatiq/persistencekit/provision"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("it provides each store from the configured driver", func(t *testing.T) {
+		journals := memory.New(&memory.Config{Silo: "composite-test-new-journal"})
+		keyspaces := memory.New(&memory.Config{Silo: "composite-test-new-kv"})
+		others := memory.New(&memory.Config{Silo: "composite-test-new-default"})
+
+		d, err := New(Drivers{
+			Default: memory.New(&memory.Config{Silo: "composite-test-new-default"}),
+			Journal: memory.New(&memory.Config{Silo: "composite-test-new-journal"}),
+			KV:      memory.New(&memory.Config{Silo: "composite-test-new-kv"}),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			d.Close()
+		})
+
+		drivertest.RunTests(
+			t,
+			d,
+			journals.JournalStore(),
+			keyspaces.KVStore(),
+			others.SetStore(),
+			others.LeaseStore(),
+			others.QueueStore(),
+			others.ScheduleStore(),
+			others.BlobStore(),
+		)
+	})
+
+	t.Run("it returns an error if a store has no driver", func(t *testing.T) {
+		_, err := New(Drivers{
+			Journal: memory.New(&memory.Config{Silo: "composite-test-new-missing"}),
+		})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+
+		want := "no driver is configured for the kv store"
+		if err.Error() != want {
+			t.Fatalf("unexpected error: got %q, want %q", err.Error(), want)
+		}
+	})
+}
+
+func TestConfig(t *testing.T) {
+	persistencekittest.Run(
+		t,
+		&Config{
+			Default: &memory.Config{Silo: "composite-test-config-default"},
+			Journal: &memory.Config{Silo: "composite-test-config-journal"},
+			Set:     &memory.Config{Silo: "composite-test-config-set"},
+		},
+	)
+}
+
+func TestDriver_Close(t *testing.T) {
+	shared := &closeCounter{Driver: memory.New(&memory.Config{Silo: "composite-test-close"})}
+	failing := &closeCounter{
+		Driver: memory.New(&memory.Config{Silo: "composite-test-close"}),
+		Err:    errors.New("<error>"),
+	}
+
+	d, err := New(Drivers{
+		Default: shared,
+		Blob:    failing,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.Close()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	want := "unable to close the driver for the blob store: <error>"
+	if err.Error() != want {
+		t.Fatalf("unexpected error: got %q, want %q", err.Error(), want)
+	}
+
+	if shared.Calls != 1 {
+		t.Fatalf("expected the shared driver to be closed once, got %d", shared.Calls)
+	}
+
+	if failing.Calls != 1 {
+		t.Fatalf("expected the blob driver to be closed once, got %d", failing.Calls)
+	}
+}
+
+func TestDriver_provisioning(t *testing.T) {
+	journals := &planner{
+		Driver: memory.New(&memory.Config{Silo: "composite-test-plan-journal"}),
+		Name:   "<journal>",
+	}
+	others := &planner{
+		Driver: memory.New(&memory.Config{Silo: "composite-test-plan-default"}),
+		Name:   "<default>",
+	}
+
+	d, err := New(Drivers{
+		Default: others,
+		Journal: journals,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	t.Run("it combines the plans of the underlying drivers", func(t *testing.T) {
+		for _, fn := range []func(context.Context, driver.Driver) (provision.Plan, error){
+			provision.PlanProvision,
+			provision.PlanDeprovision,
+		} {
+			plan, err := fn(t.Context(), d)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, c := range plan.Changes {
+				got = append(got, c.Resource)
+			}
+
+			want := []string{"<journal>", "<default>"}
+			if !slices.Equal(got, want) {
+				t.Fatalf("unexpected resources: got %q, want %q", got, want)
+			}
+		}
+	})
+
+	t.Run("it deprovisions each underlying driver once", func(t *testing.T) {
+		if err := provision.Deprovision(t.Context(), d); err != nil {
+			t.Fatal(err)
+		}
+
+		if journals.Deprovisioned != 1 || others.Deprovisioned != 1 {
+			t.Fatalf(
+				"unexpected deprovision calls: journal=%d, default=%d",
+				journals.Deprovisioned,
+				others.Deprovisioned,
+			)
+		}
+	})
+
+	t.Run("it returns an error if an underlying driver does not support planning", func(t *testing.T) {
+		d, err := New(Drivers{
+			Default: others,
+			Blob:    &closeCounter{Driver: memory.New(&memory.Config{Silo: "composite-test-plan-blob"})},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := provision.PlanProvision(t.Context(), d); !errors.Is(err, provision.ErrUnsupported) {
+			t.Fatalf("unexpected error: got %v, want %v", err, provision.ErrUnsupported)
+		}
+	})
+}
+
+func TestParseURL(t *testing.T) {
+	t.Run("it resolves the driver for each store", func(t *testing.T) {
+		cfg, err := ParseURL(
+			t.Context(),
+			"composite:?default=memory:///composite-test-default&journal=memory:///composite-test-journal&set=memory:///composite-test-journal",
+			resolve,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expectSilo(t, cfg.Default, "composite-test-default")
+		expectSilo(t, cfg.Journal, "composite-test-journal")
+		expectSilo(t, cfg.Set, "composite-test-journal")
+
+		if cfg.Journal != cfg.Set {
+			t.Fatal("expected stores with identical URLs to share a configuration")
+		}
+
+		if cfg.KV != nil {
+			t.Fatal("expected the kv store to use the default driver")
+		}
+
+		d, err := cfg.NewDriver(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer d.Close()
+	})
+
+	t.Run("when the URL is invalid", func(t *testing.T) {
+		cases := []struct {
+			Name    string
+			URL     string
+			WantErr string
+		}{
+			{"wrong scheme", "memory:///silo", `unexpected scheme "memory"`},
+			{"path", "composite:///path?default=memory:///silo", "drivers must be specified as query parameters"},
+			{"unrecognized parameter", "composite:?default=memory:///silo&other=memory:///silo", `unrecognized "other" parameter`},
+			{"repeated parameter", "composite:?kv=memory:///a&kv=memory:///b", `"kv" parameter must be specified at most once`},
+			{"invalid nested URL", "composite:?default=redis://localhost", `invalid "default" parameter: unsupported scheme "redis"`},
+			{"missing store", "composite:?journal=memory:///silo", "no driver is configured for the kv store"},
+		}
+		for _, tc := range cases {
+			t.Run(tc.Name, func(t *testing.T) {
+				_, err := ParseURL(t.Context(), tc.URL, resolve)
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				if !strings.Contains(err.Error(), tc.WantErr) {
+					t.Fatalf("unexpected error: got %q, want substring %q", err.Error(), tc.WantErr)
+				}
+			})
+		}
+	})
+}
+
+// closeCounter is a [driver.Driver] that counts calls to Close.
+type closeCounter struct {
+	driver.Driver
+	Err   error
+	Calls int
+}
+
+func (d *closeCounter) Close() error {
+	d.Calls++
+	return d.Err
+}
+
+// planner is a [driver.Driver] that implements [provision.Planner] and
+// [provision.Deprovisioner], producing plans that contain a single change to a
+// resource with the given name.
+type planner struct {
+	driver.Driver
+	Name          string
+	Deprovisioned int
+}
+
+func (d *planner) PlanProvision(context.Context) (provision.Plan, error) {
+	return provision.Plan{
+		Changes: []provision.Change{{Action: provision.Create, Resource: d.Name}},
+	}, nil
+}
+
+func (d *planner) PlanDeprovision(context.Context) (provision.Plan, error) {
+	return provision.Plan{
+		Changes: []provision.Change{{Action: provision.Delete, Resource: d.Name}},
+	}, nil
+}
+
+func (d *planner) Deprovision(context.Context) error {
+	d.Deprovisioned++
+	return nil
+}
+
+func expectSilo(t *testing.T, cfg driver.Config, want string) {
+	t.Helper()
+
+	mem, ok := cfg.(*memory.Config)
+	if !ok {
+		t.Fatalf("unexpected config type: %T", cfg)
+	}
+
+	if mem.Silo != want {
+		t.Fatalf("unexpected silo: got %q, want %q", mem.Silo, want)
+	}
+}
+
+func resolve(ctx context.Context, u *url.URL) (driver.Config, error) {
+	if u.Scheme != "memory" {
+		return nil, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+
+	cfg, err := memory.FromURL(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func TestDriver_CheckHealth(t *testing.T) {
+	d, err := New(Drivers{
+		Default: memory.New(&memory.Config{Silo: "composite-test-health"}),
+		KV:      uncheckedDriver{memory.New(&memory.Config{Silo: "composite-test-health"})},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := d.CheckHealth(t.Context())
+
+	for _, res := range report.Results {
+		if res.Store == health.KVStore {
+			if !errors.Is(res.Err, health.ErrUnsupported) {
+				t.Fatalf("unexpected error for the kv store: %v", res.Err)
+			}
+		} else if res.Err != nil {
+			t.Fatalf("unexpected error for the %s store: %s", res.Store, res.Err)
+		}
+	}
+}
+
+// uncheckedDriver is a [driver.Driver] that does not implement
+// [health.Checker].
+type uncheckedDriver struct {
+	driver.Driver
+}
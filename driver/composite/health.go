@@ -0,0 +1,50 @@
+package composite
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/health"
+)
+
+// CheckHealth checks the health of each store using the underlying driver that
+// provides it.
+func (d *Driver) CheckHealth(ctx context.Context) health.Report {
+	drivers := map[health.Store]driver.Driver{}
+	for _, s := range d.drivers.stores() {
+		drivers[health.Store(s.Name)] = *s.Value
+	}
+
+	reports := map[driver.Driver]health.Report{}
+
+	return health.NewReport(
+		func(s health.Store) error {
+			v := drivers[s]
+			comparable := reflect.TypeOf(v).Comparable()
+
+			var (
+				report health.Report
+				ok     bool
+			)
+
+			if comparable {
+				report, ok = reports[v]
+			}
+
+			if !ok {
+				report = health.Check(ctx, v)
+				if comparable {
+					reports[v] = report
+				}
+			}
+
+			res, ok := report.Result(s)
+			if !ok {
+				return fmt.Errorf("the driver for the %s store did not report its health", s)
+			}
+			return res.Err
+		},
+	)
+}
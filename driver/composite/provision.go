@@ -0,0 +1,89 @@
+package composite
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/provision"
+)
+
+// PlanProvision returns the changes that provisioning each of the underlying
+// drivers would make, in the order of the stores they provide.
+//
+// It returns an error that wraps [provision.ErrUnsupported] if any of the
+// underlying drivers does not implement [provision.Planner].
+func (d *Driver) PlanProvision(ctx context.Context) (provision.Plan, error) {
+	return d.plan(ctx, provision.PlanProvision)
+}
+
+// PlanDeprovision returns the changes that [Driver.Deprovision] would make.
+//
+// It returns an error that wraps [provision.ErrUnsupported] if any of the
+// underlying drivers does not implement [provision.Planner].
+func (d *Driver) PlanDeprovision(ctx context.Context) (provision.Plan, error) {
+	return d.plan(ctx, provision.PlanDeprovision)
+}
+
+// Deprovision removes the infrastructure used by each of the underlying
+// drivers that provides at least one store.
+//
+// Each underlying driver removes all of its infrastructure, including that of
+// any stores that it provides to other composite drivers. The default driver
+// is not deprovisioned unless it provides one of the stores.
+//
+// It returns an error that wraps [provision.ErrUnsupported] if any of the
+// underlying drivers does not implement [provision.Deprovisioner].
+func (d *Driver) Deprovision(ctx context.Context) error {
+	for _, s := range d.underlying() {
+		if err := provision.Deprovision(ctx, *s.Value); err != nil {
+			return fmt.Errorf("unable to deprovision the driver for the %s store: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+// plan returns the combined plans produced by calling fn with each of the
+// underlying drivers.
+func (d *Driver) plan(
+	ctx context.Context,
+	fn func(context.Context, driver.Driver) (provision.Plan, error),
+) (provision.Plan, error) {
+	var plan provision.Plan
+
+	for _, s := range d.underlying() {
+		p, err := fn(ctx, *s.Value)
+		if err != nil {
+			return provision.Plan{}, fmt.Errorf("unable to plan the driver for the %s store: %w", s.Name, err)
+		}
+		plan.Changes = append(plan.Changes, p.Changes...)
+	}
+
+	return plan, nil
+}
+
+// underlying returns the distinct drivers that provide the stores, each
+// labelled with the first store that it provides.
+func (d *Driver) underlying() []store[driver.Driver] {
+	var (
+		result []store[driver.Driver]
+		seen   []driver.Driver
+	)
+
+	for _, s := range d.drivers.stores() {
+		v := *s.Value
+
+		if reflect.TypeOf(v).Comparable() {
+			if slices.Contains(seen, v) {
+				continue
+			}
+			seen = append(seen, v)
+		}
+
+		result = append(result, s)
+	}
+
+	return result
+}
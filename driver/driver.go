@@ -4,8 +4,12 @@ package driver
 import (
 	"context"
 
+	"github.com/dogmatiq/persistencekit/blob"
 	"github.com/dogmatiq/persistencekit/journal"
 	"github.com/dogmatiq/persistencekit/kv"
+	"github.com/dogmatiq/persistencekit/lease"
+	"github.com/dogmatiq/persistencekit/queue"
+	"github.com/dogmatiq/persistencekit/schedule"
 	"github.com/dogmatiq/persistencekit/set"
 )
 
@@ -20,6 +24,22 @@ type Driver interface {
 	// SetStore returns the set store provided by this driver.
 	SetStore() set.BinaryStore
 
+	// LeaseStore returns the lease store provided by this driver.
+	LeaseStore() lease.Store
+
+	// QueueStore returns the queue store provided by this driver.
+	QueueStore() queue.BinaryStore
+
+	// ScheduleStore returns the schedule store provided by this driver.
+	ScheduleStore() schedule.BinaryStore
+
+	// BlobStore returns the blob store provided by this driver.
+	BlobStore() blob.Store
+
+	// Capabilities returns the limits and optional features of the driver's
+	// stores.
+	Capabilities() Capabilities
+
 	// Close closes the driver, releasing any resources.
 	Close() error
 }
@@ -7,21 +7,33 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/dogmatiq/persistencekit/blob"
 	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/driver/memory/memoryblob"
 	"github.com/dogmatiq/persistencekit/driver/memory/memoryjournal"
 	"github.com/dogmatiq/persistencekit/driver/memory/memorykv"
+	"github.com/dogmatiq/persistencekit/driver/memory/memorylease"
+	"github.com/dogmatiq/persistencekit/driver/memory/memoryqueue"
+	"github.com/dogmatiq/persistencekit/driver/memory/memoryschedule"
 	"github.com/dogmatiq/persistencekit/driver/memory/memoryset"
 	"github.com/dogmatiq/persistencekit/journal"
 	"github.com/dogmatiq/persistencekit/kv"
+	"github.com/dogmatiq/persistencekit/lease"
+	"github.com/dogmatiq/persistencekit/queue"
+	"github.com/dogmatiq/persistencekit/schedule"
 	"github.com/dogmatiq/persistencekit/set"
 )
 
 var silos sync.Map
 
 type silo struct {
-	kv      memorykv.BinaryStore
-	journal memoryjournal.BinaryStore
-	set     memoryset.BinaryStore
+	kv       memorykv.BinaryStore
+	journal  memoryjournal.BinaryStore
+	set      memoryset.BinaryStore
+	lease    memorylease.Store
+	queue    memoryqueue.BinaryStore
+	schedule memoryschedule.BinaryStore
+	blob     memoryblob.Store
 }
 
 // Driver is a persistence driver backed by a named in-memory silo.
@@ -102,6 +114,32 @@ func (d *Driver) SetStore() set.BinaryStore {
 	return &d.silo.set
 }
 
+// LeaseStore returns the silo's in-memory lease store.
+func (d *Driver) LeaseStore() lease.Store {
+	return &d.silo.lease
+}
+
+// QueueStore returns the silo's in-memory queue store.
+func (d *Driver) QueueStore() queue.BinaryStore {
+	return &d.silo.queue
+}
+
+// ScheduleStore returns the silo's in-memory schedule store.
+func (d *Driver) ScheduleStore() schedule.BinaryStore {
+	return &d.silo.schedule
+}
+
+// BlobStore returns the silo's in-memory blob store.
+func (d *Driver) BlobStore() blob.Store {
+	return &d.silo.blob
+}
+
+// Capabilities returns the limits and optional features of the driver's
+// stores.
+func (d *Driver) Capabilities() driver.Capabilities {
+	return driver.StoreCapabilities(d)
+}
+
 // Close is a no-op. The silo's state persists for the lifetime of the process.
 func (d *Driver) Close() error {
 	return nil
@@ -25,9 +25,61 @@ func TestNew(t *testing.T) {
 		ref.JournalStore(),
 		ref.KVStore(),
 		ref.SetStore(),
+		ref.LeaseStore(),
+		ref.QueueStore(),
+		ref.ScheduleStore(),
+		ref.BlobStore(),
 	)
 }
 
+func TestCheckHealth(t *testing.T) {
+	d := New(&Config{Silo: "test-check-health"})
+	t.Cleanup(func() {
+		d.Close()
+	})
+
+	if err := d.CheckHealth(t.Context()).Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBackup(t *testing.T) {
+	source := New(&Config{Silo: "test-backup-source"})
+	target := New(&Config{Silo: "test-backup-target"})
+	t.Cleanup(func() {
+		source.Close()
+		target.Close()
+	})
+
+	drivertest.RunBackupTests(t, source, target)
+}
+
+func TestChangeFeed(t *testing.T) {
+	d := New(&Config{Silo: "test-change-feed"})
+	t.Cleanup(func() {
+		d.Close()
+	})
+
+	drivertest.RunChangeFeedTests(t, d)
+}
+
+func TestCapabilities(t *testing.T) {
+	d := New(&Config{Silo: "test-capabilities"})
+	t.Cleanup(func() {
+		d.Close()
+	})
+
+	caps := d.Capabilities()
+
+	if !caps.KV.ChangeFeed {
+		t.Fatal("expected the key/value store to report a change feed")
+	}
+
+	if !caps.KV.ConflictDetail {
+		t.Fatal("expected the key/value store to report conflict details")
+	}
+}
+
 func TestParseURL(t *testing.T) {
 	ref := New(&Config{Silo: "test-parse-url"})
 	t.Cleanup(func() {
@@ -53,6 +105,10 @@ func TestParseURL(t *testing.T) {
 		ref.JournalStore(),
 		ref.KVStore(),
 		ref.SetStore(),
+		ref.LeaseStore(),
+		ref.QueueStore(),
+		ref.ScheduleStore(),
+		ref.BlobStore(),
 	)
 }
 
@@ -83,6 +139,10 @@ func TestFromURL(t *testing.T) {
 			ref.JournalStore(),
 			ref.KVStore(),
 			ref.SetStore(),
+			ref.LeaseStore(),
+			ref.QueueStore(),
+			ref.ScheduleStore(),
+			ref.BlobStore(),
 		)
 	})
 
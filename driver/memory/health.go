@@ -0,0 +1,17 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/dogmatiq/persistencekit/health"
+)
+
+// CheckHealth reports each store as healthy. In-memory stores are always
+// reachable and do not require provisioning.
+func (d *Driver) CheckHealth(context.Context) health.Report {
+	return health.NewReport(
+		func(health.Store) error {
+			return nil
+		},
+	)
+}
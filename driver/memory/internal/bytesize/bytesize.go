@@ -0,0 +1,13 @@
+package bytesize
+
+// Of returns the size of v's binary representation.
+//
+// Only []byte values have a binary representation in memory, so Of returns
+// zero for values of any other type. This matches the statistics reported for
+// non-binary stores that are computed by ranging over their contents.
+func Of[T any](v T) uint64 {
+	if b, ok := any(v).([]byte); ok {
+		return uint64(len(b))
+	}
+	return 0
+}
@@ -0,0 +1,3 @@
+// Package bytesize measures the values held by the in-memory stores for the
+// purposes of reporting their statistics.
+package bytesize
@@ -0,0 +1,129 @@
+package memoryblob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/dogmatiq/persistencekit/blob"
+)
+
+// state is the in-memory state of a container.
+type state struct {
+	sync.RWMutex
+	Blobs map[blob.Hash]*entry
+}
+
+// entry is a blob stored within a container.
+type entry struct {
+	Content []byte
+	Refs    int
+}
+
+// container is an implementation of [blob.Container] that manipulates a
+// container's in-memory [state].
+type container struct {
+	name  string
+	state *state
+}
+
+func (c *container) Name() string {
+	return c.name
+}
+
+func (c *container) Put(ctx context.Context, r io.Reader) (blob.Ref, error) {
+	if c.state == nil {
+		panic("container is closed")
+	}
+
+	// The content is read before acquiring the lock, as r may block
+	// indefinitely.
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return blob.Ref{}, err
+	}
+
+	ref := blob.Ref{
+		Hash: blob.HashOf(content),
+		Size: int64(len(content)),
+	}
+
+	c.state.Lock()
+	defer c.state.Unlock()
+
+	if e, ok := c.state.Blobs[ref.Hash]; ok {
+		e.Refs++
+		return ref, ctx.Err()
+	}
+
+	if c.state.Blobs == nil {
+		c.state.Blobs = map[blob.Hash]*entry{}
+	}
+
+	c.state.Blobs[ref.Hash] = &entry{
+		Content: content,
+		Refs:    1,
+	}
+
+	return ref, ctx.Err()
+}
+
+func (c *container) Get(ctx context.Context, h blob.Hash) (io.ReadCloser, bool, error) {
+	if c.state == nil {
+		panic("container is closed")
+	}
+
+	c.state.RLock()
+	defer c.state.RUnlock()
+
+	e, ok := c.state.Blobs[h]
+	if !ok {
+		return nil, false, ctx.Err()
+	}
+
+	// The content is never modified once stored, so it's safe to read from
+	// it without copying.
+	return io.NopCloser(bytes.NewReader(e.Content)), true, ctx.Err()
+}
+
+func (c *container) Has(ctx context.Context, h blob.Hash) (bool, error) {
+	if c.state == nil {
+		panic("container is closed")
+	}
+
+	c.state.RLock()
+	defer c.state.RUnlock()
+
+	_, ok := c.state.Blobs[h]
+	return ok, ctx.Err()
+}
+
+func (c *container) Release(ctx context.Context, h blob.Hash) error {
+	if c.state == nil {
+		panic("container is closed")
+	}
+
+	c.state.Lock()
+	defer c.state.Unlock()
+
+	if e, ok := c.state.Blobs[h]; ok {
+		e.Refs--
+		if e.Refs == 0 {
+			delete(c.state.Blobs, h)
+		}
+	}
+
+	return ctx.Err()
+}
+
+func (c *container) Close() error {
+	if c.state == nil {
+		return errors.New("container is already closed")
+	}
+
+	c.state = nil
+
+	return nil
+}
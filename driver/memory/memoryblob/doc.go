@@ -0,0 +1,2 @@
+// Package memoryblob provides an in-memory implementation of [blob.Store].
+package memoryblob
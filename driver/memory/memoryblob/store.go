@@ -0,0 +1,35 @@
+package memoryblob
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dogmatiq/persistencekit/blob"
+)
+
+// Store is an implementation of [blob.Store] that stores blobs in memory.
+type Store struct {
+	containers sync.Map // map[string]*state
+}
+
+// Provision is a no-op; memory stores do not require provisioning.
+func (s *Store) Provision(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Open returns the container with the given name.
+func (s *Store) Open(ctx context.Context, name string) (blob.Container, error) {
+	st, ok := s.containers.Load(name)
+
+	if !ok {
+		st, _ = s.containers.LoadOrStore(
+			name,
+			&state{},
+		)
+	}
+
+	return &container{
+		name:  name,
+		state: st.(*state),
+	}, ctx.Err()
+}
@@ -0,0 +1,15 @@
+package memoryblob_test
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/blob"
+	. "github.com/dogmatiq/persistencekit/driver/memory/memoryblob"
+)
+
+func TestStore(t *testing.T) {
+	blob.RunTests(
+		t,
+		&Store{},
+	)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"sync"
 
+	"github.com/dogmatiq/persistencekit/driver/memory/internal/bytesize"
 	"github.com/dogmatiq/persistencekit/driver/memory/internal/clone"
 	"github.com/dogmatiq/persistencekit/journal"
 )
@@ -14,6 +15,7 @@ type state[T any] struct {
 	sync.RWMutex
 	Bounds  journal.Interval
 	Records []T
+	Bytes   uint64
 }
 
 // journ is an implementation of [journal.Journal] that manipulates a journal's
@@ -113,6 +115,7 @@ func (j *journ[T]) Append(ctx context.Context, pos journal.Position, rec T) erro
 	case pos == j.state.Bounds.End:
 		j.state.Records = append(j.state.Records, rec)
 		j.state.Bounds.End++
+		j.state.Bytes += bytesize.Of(rec)
 	default:
 		panic("position out of range, this causes undefined behavior in a 'real' journal implementation")
 	}
@@ -133,13 +136,31 @@ func (j *journ[T]) Truncate(ctx context.Context, pos journal.Position) error {
 	}
 
 	if pos > j.state.Bounds.Begin {
-		j.state.Records = j.state.Records[pos-j.state.Bounds.Begin:]
+		n := pos - j.state.Bounds.Begin
+		for _, rec := range j.state.Records[:n] {
+			j.state.Bytes -= bytesize.Of(rec)
+		}
+		j.state.Records = j.state.Records[n:]
 		j.state.Bounds.Begin = pos
 	}
 
 	return ctx.Err()
 }
 
+func (j *journ[T]) Stats(ctx context.Context) (journal.Stats, error) {
+	if j.state == nil {
+		panic("journal is closed")
+	}
+
+	j.state.RLock()
+	defer j.state.RUnlock()
+
+	return journal.Stats{
+		Records: uint64(j.state.Bounds.Len()),
+		Bytes:   j.state.Bytes,
+	}, ctx.Err()
+}
+
 func (j *journ[T]) Close() error {
 	if j.state == nil {
 		return errors.New("journal is already closed")
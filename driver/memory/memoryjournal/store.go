@@ -2,6 +2,7 @@ package memoryjournal
 
 import (
 	"context"
+	"slices"
 	"sync"
 
 	"github.com/dogmatiq/persistencekit/journal"
@@ -22,6 +23,17 @@ func (s *Store[T]) Provision(ctx context.Context) error {
 	return ctx.Err()
 }
 
+// Capabilities returns the limits and optional features of the store.
+func (s *Store[T]) Capabilities() journal.Capabilities {
+	return journal.Capabilities{}
+}
+
+// Names returns the names of the journals that have been opened, in lexical
+// order.
+func (s *Store[T]) Names(ctx context.Context) ([]string, error) {
+	return names(&s.journals), ctx.Err()
+}
+
 // Open returns the journal with the given name.
 func (s *Store[T]) Open(ctx context.Context, name string) (journal.Journal[T], error) {
 	st, ok := s.journals.Load(name)
@@ -38,3 +50,19 @@ func (s *Store[T]) Open(ctx context.Context, name string) (journal.Journal[T], e
 		state: st.(*state[T]),
 	}, ctx.Err()
 }
+
+// names returns the keys of m, in lexical order.
+func names(m *sync.Map) []string {
+	var result []string
+
+	m.Range(
+		func(k, _ any) bool {
+			result = append(result, k.(string))
+			return true
+		},
+	)
+
+	slices.Sort(result)
+
+	return result
+}
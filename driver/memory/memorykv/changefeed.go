@@ -0,0 +1,80 @@
+package memorykv
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dogmatiq/persistencekit/driver/memory/internal/clone"
+	"github.com/dogmatiq/persistencekit/kv"
+)
+
+// changeFeed is an implementation of [kv.ChangeFeed] that reads the changes
+// recorded in a keyspace's in-memory [state].
+type changeFeed[K, V any, C comparable] struct {
+	name         string
+	state        *state[C, V]
+	unmarshalKey func(C) K
+}
+
+func (f *changeFeed[K, V, C]) Name() string {
+	return f.name
+}
+
+func (f *changeFeed[K, V, C]) End(ctx context.Context) (kv.Cursor, error) {
+	if f.state == nil {
+		panic("change feed is closed")
+	}
+
+	f.state.RLock()
+	defer f.state.RUnlock()
+
+	return f.state.ChangesBegin + kv.Cursor(len(f.state.Changes)), ctx.Err()
+}
+
+func (f *changeFeed[K, V, C]) Range(ctx context.Context, c kv.Cursor, fn kv.ChangeFunc[K, V]) error {
+	if f.state == nil {
+		panic("change feed is closed")
+	}
+
+	f.state.RLock()
+	changes := f.state.Changes
+	begin := f.state.ChangesBegin
+	f.state.RUnlock()
+
+	if c < begin {
+		return kv.ChangeNotFoundError{
+			Keyspace: f.name,
+			Cursor:   c,
+		}
+	}
+
+	for index := c; index < begin+kv.Cursor(len(changes)); index++ {
+		ch := changes[index-begin]
+
+		ok, err := fn(
+			ctx,
+			index,
+			kv.Change[K, V]{
+				Key:      f.unmarshalKey(ch.Key),
+				Value:    clone.Clone(ch.Item.Value),
+				Revision: ch.Item.Revision,
+				Deleted:  ch.Item.Revision == "",
+			},
+		)
+		if !ok || err != nil {
+			return err
+		}
+	}
+
+	return ctx.Err()
+}
+
+func (f *changeFeed[K, V, C]) Close() error {
+	if f.state == nil {
+		return errors.New("change feed is already closed")
+	}
+
+	f.state = nil
+
+	return nil
+}
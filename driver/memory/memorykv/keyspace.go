@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"sync"
 
+	"github.com/dogmatiq/persistencekit/driver/memory/internal/bytesize"
 	"github.com/dogmatiq/persistencekit/driver/memory/internal/clone"
 	"github.com/dogmatiq/persistencekit/internal/kvrevision"
 	"github.com/dogmatiq/persistencekit/kv"
@@ -16,6 +17,13 @@ import (
 type state[C comparable, V any] struct {
 	sync.RWMutex
 	Items map[C]item[V]
+	Bytes uint64
+
+	// Changes contains the most recent changes to the keyspace, beginning with
+	// the change at ChangesBegin. It contains at most MaxChanges entries.
+	Changes      []change[C, V]
+	ChangesBegin kv.Cursor
+	MaxChanges   int
 }
 
 type item[V any] struct {
@@ -23,6 +31,28 @@ type item[V any] struct {
 	Revision kv.Revision
 }
 
+// record appends ch to the keyspace's changes, discarding the oldest change if
+// there are more than MaxChanges. It must be called with the lock held.
+//
+// Existing elements are never modified, as [changeFeed.Range] reads from a
+// slice of Changes without holding the lock. Discarded changes are released
+// once append allocates a new backing array.
+func (st *state[C, V]) record(ch change[C, V]) {
+	st.Changes = append(st.Changes, ch)
+
+	if n := len(st.Changes) - st.MaxChanges; n > 0 {
+		st.Changes = st.Changes[n:]
+		st.ChangesBegin += kv.Cursor(n)
+	}
+}
+
+// change is a record of a modification to a key/value pair, as exposed by the
+// keyspace's [changeFeed].
+type change[C comparable, V any] struct {
+	Key  C
+	Item item[V]
+}
+
 // keyspace is an implementation of [kv.BinaryKeyspace] that manipulates a
 // keyspace's in-memory [state].
 type keyspace[K, V any, C comparable] struct {
@@ -87,18 +117,28 @@ func (ks *keyspace[K, V, C]) set(ctx context.Context, k K, v V, r *kv.Revision)
 	defer ks.state.Unlock()
 
 	c := ks.marshalKey(k)
-	i := ks.state.Items[c]
+	i, exists := ks.state.Items[c]
 
 	if r != nil && *r != i.Revision {
-		return "", kv.ConflictError[K]{
-			Keyspace: ks.name,
-			Key:      k,
-			Revision: *r,
+		return "", kv.ConflictError[K, V]{
+			Keyspace:        ks.name,
+			Key:             k,
+			Revision:        *r,
+			CurrentValue:    clone.Clone(i.Value),
+			CurrentRevision: i.Revision,
+			CurrentKnown:    true,
 		}
 	}
 
+	if exists {
+		ks.state.Bytes -= bytesize.Of(k) + bytesize.Of(i.Value)
+	}
+
 	if reflect.ValueOf(v).IsZero() {
-		delete(ks.state.Items, c)
+		if exists {
+			delete(ks.state.Items, c)
+			ks.state.record(change[C, V]{Key: c})
+		}
 		return "", ctx.Err()
 	}
 
@@ -106,13 +146,16 @@ func (ks *keyspace[K, V, C]) set(ctx context.Context, k K, v V, r *kv.Revision)
 		ks.state.Items = map[C]item[V]{}
 	}
 
-	next := kvrevision.IncrementGeneration(i.Revision)
-	ks.state.Items[c] = item[V]{
+	next := item[V]{
 		Value:    v,
-		Revision: next,
+		Revision: kvrevision.IncrementGeneration(i.Revision),
 	}
 
-	return next, ctx.Err()
+	ks.state.Items[c] = next
+	ks.state.Bytes += bytesize.Of(k) + bytesize.Of(v)
+	ks.state.record(change[C, V]{c, next})
+
+	return next.Revision, ctx.Err()
 }
 
 func (ks *keyspace[K, V, C]) Range(ctx context.Context, fn kv.RangeFunc[K, V]) error {
@@ -139,6 +182,39 @@ func (ks *keyspace[K, V, C]) Range(ctx context.Context, fn kv.RangeFunc[K, V]) e
 	return nil
 }
 
+func (ks *keyspace[K, V, C]) RangeKeys(ctx context.Context, fn kv.KeyRangeFunc[K]) error {
+	if ks.state == nil {
+		panic("keyspace is closed")
+	}
+
+	ks.state.RLock()
+	items := maps.Clone(ks.state.Items)
+	ks.state.RUnlock()
+
+	for c, i := range items {
+		ok, err := fn(ctx, ks.unmarshalKey(c), i.Revision)
+		if !ok || err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ks *keyspace[K, V, C]) Stats(ctx context.Context) (kv.Stats, error) {
+	if ks.state == nil {
+		panic("keyspace is closed")
+	}
+
+	ks.state.RLock()
+	defer ks.state.RUnlock()
+
+	return kv.Stats{
+		Keys:  uint64(len(ks.state.Items)),
+		Bytes: ks.state.Bytes,
+	}, ctx.Err()
+}
+
 func (ks *keyspace[K, V, C]) Close() error {
 	if ks.state == nil {
 		return errors.New("keyspace is already closed")
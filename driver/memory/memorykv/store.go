@@ -2,13 +2,33 @@ package memorykv
 
 import (
 	"context"
+	"slices"
 	"sync"
 
 	"github.com/dogmatiq/persistencekit/kv"
 )
 
+// capabilities is the set of limits and optional features of the in-memory
+// key/value stores.
+var capabilities = kv.Capabilities{
+	ChangeFeed:     true,
+	ConflictDetail: true,
+}
+
+// DefaultMaxChanges is the default number of changes retained in the change
+// feed of each keyspace.
+const DefaultMaxChanges = 10000
+
 // Store is an in-memory implementation of [kv.Store].
+//
+// It also implements [kv.ChangeFeedStore], recording the most recent changes
+// made to each keyspace.
 type Store[K comparable, V any] struct {
+	// MaxChanges is the maximum number of changes retained in the change feed
+	// of each keyspace. Older changes are discarded. If it is zero,
+	// [DefaultMaxChanges] is used.
+	MaxChanges int
+
 	keyspaces sync.Map // map[string]*state[K, V]
 }
 
@@ -17,23 +37,54 @@ func (s *Store[K, V]) Provision(ctx context.Context) error {
 	return ctx.Err()
 }
 
+// Capabilities returns the limits and optional features of the store.
+func (s *Store[K, V]) Capabilities() kv.Capabilities {
+	return capabilities
+}
+
+// Names returns the names of the keyspaces that have been opened, in lexical
+// order.
+func (s *Store[K, V]) Names(ctx context.Context) ([]string, error) {
+	return names(&s.keyspaces), ctx.Err()
+}
+
 // Open returns the keyspace with the given name.
 func (s *Store[K, V]) Open(ctx context.Context, name string) (kv.Keyspace[K, V], error) {
+	return &keyspace[K, V, K]{
+		name:         name,
+		state:        s.state(name),
+		marshalKey:   identity[K],
+		unmarshalKey: identity[K],
+	}, ctx.Err()
+}
+
+// OpenChangeFeed returns the change feed of the keyspace with the given name.
+func (s *Store[K, V]) OpenChangeFeed(ctx context.Context, name string) (kv.ChangeFeed[K, V], error) {
+	return &changeFeed[K, V, K]{
+		name:         name,
+		state:        s.state(name),
+		unmarshalKey: identity[K],
+	}, ctx.Err()
+}
+
+func (s *Store[K, V]) state(name string) *state[K, V] {
 	st, ok := s.keyspaces.Load(name)
 
 	if !ok {
 		st, _ = s.keyspaces.LoadOrStore(
 			name,
-			&state[K, V]{},
+			&state[K, V]{MaxChanges: maxChanges(s.MaxChanges)},
 		)
 	}
 
-	return &keyspace[K, V, K]{
-		name:         name,
-		state:        st.(*state[K, V]),
-		marshalKey:   identity[K],
-		unmarshalKey: identity[K],
-	}, ctx.Err()
+	return st.(*state[K, V])
+}
+
+func maxChanges(n int) int {
+	if n <= 0 {
+		return DefaultMaxChanges
+	}
+	return n
 }
 
 func identity[K any](k K) K {
@@ -42,7 +93,15 @@ func identity[K any](k K) K {
 
 // BinaryStore is an implementation of [keyspace.BinaryStore] that stores
 // records in memory.
+//
+// It also implements [kv.BinaryChangeFeedStore], recording the most recent
+// changes made to each keyspace.
 type BinaryStore struct {
+	// MaxChanges is the maximum number of changes retained in the change feed
+	// of each keyspace. Older changes are discarded. If it is zero,
+	// [DefaultMaxChanges] is used.
+	MaxChanges int
+
 	keyspaces sync.Map // map[string]*state[string, []byte]
 }
 
@@ -51,21 +110,69 @@ func (s *BinaryStore) Provision(ctx context.Context) error {
 	return ctx.Err()
 }
 
+// Capabilities returns the limits and optional features of the store.
+func (s *BinaryStore) Capabilities() kv.Capabilities {
+	return capabilities
+}
+
+// Names returns the names of the keyspaces that have been opened, in lexical
+// order.
+func (s *BinaryStore) Names(ctx context.Context) ([]string, error) {
+	return names(&s.keyspaces), ctx.Err()
+}
+
 // Open returns the keyspace with the given name.
 func (s *BinaryStore) Open(ctx context.Context, name string) (kv.BinaryKeyspace, error) {
+	return &keyspace[[]byte, []byte, string]{
+		name:         name,
+		state:        s.state(name),
+		marshalKey:   marshalBinaryKey,
+		unmarshalKey: unmarshalBinaryKey,
+	}, ctx.Err()
+}
+
+// OpenChangeFeed returns the change feed of the keyspace with the given name.
+func (s *BinaryStore) OpenChangeFeed(ctx context.Context, name string) (kv.BinaryChangeFeed, error) {
+	return &changeFeed[[]byte, []byte, string]{
+		name:         name,
+		state:        s.state(name),
+		unmarshalKey: unmarshalBinaryKey,
+	}, ctx.Err()
+}
+
+func (s *BinaryStore) state(name string) *state[string, []byte] {
 	st, ok := s.keyspaces.Load(name)
 
 	if !ok {
 		st, _ = s.keyspaces.LoadOrStore(
 			name,
-			&state[string, []byte]{},
+			&state[string, []byte]{MaxChanges: maxChanges(s.MaxChanges)},
 		)
 	}
 
-	return &keyspace[[]byte, []byte, string]{
-		name:         name,
-		state:        st.(*state[string, []byte]),
-		marshalKey:   func(k []byte) string { return string(k) },
-		unmarshalKey: func(k string) []byte { return []byte(k) },
-	}, ctx.Err()
+	return st.(*state[string, []byte])
+}
+
+func marshalBinaryKey(k []byte) string {
+	return string(k)
+}
+
+func unmarshalBinaryKey(k string) []byte {
+	return []byte(k)
+}
+
+// names returns the keys of m, in lexical order.
+func names(m *sync.Map) []string {
+	var result []string
+
+	m.Range(
+		func(k, _ any) bool {
+			result = append(result, k.(string))
+			return true
+		},
+	)
+
+	slices.Sort(result)
+
+	return result
 }
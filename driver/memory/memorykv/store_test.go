@@ -1,6 +1,8 @@
 package memorykv_test
 
 import (
+	"context"
+	"slices"
 	"testing"
 
 	. "github.com/dogmatiq/persistencekit/driver/memory/memorykv"
@@ -14,6 +16,77 @@ func TestStore(t *testing.T) {
 	)
 }
 
+func TestChangeFeed(t *testing.T) {
+	store := &BinaryStore{}
+	kv.RunChangeFeedTests(t, store, store)
+}
+
+func TestChangeFeed_discarded(t *testing.T) {
+	store := &BinaryStore{MaxChanges: 2}
+
+	ks, err := store.Open(t.Context(), "<keyspace>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ks.Close()
+
+	for _, v := range []string{"<value-1>", "<value-2>", "<value-3>"} {
+		if err := ks.SetUnconditional(t.Context(), []byte("<key>"), []byte(v)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	feed, err := store.OpenChangeFeed(t.Context(), "<keyspace>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer feed.Close()
+
+	end, err := feed.End(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if end != 3 {
+		t.Fatalf("unexpected end: got %d, want 3", end)
+	}
+
+	t.Run("it returns an error when ranging from a discarded change", func(t *testing.T) {
+		err := feed.Range(
+			t.Context(),
+			0,
+			func(context.Context, kv.Cursor, kv.BinaryChange) (bool, error) {
+				t.Fatal("unexpected call")
+				return false, nil
+			},
+		)
+
+		expect := kv.ChangeNotFoundError{Keyspace: "<keyspace>", Cursor: 0}
+		if err != expect {
+			t.Fatalf("unexpected error: got %v, want %v", err, expect)
+		}
+	})
+
+	t.Run("it retains the most recent changes", func(t *testing.T) {
+		var values []string
+
+		if err := feed.Range(
+			t.Context(),
+			1,
+			func(_ context.Context, _ kv.Cursor, ch kv.BinaryChange) (bool, error) {
+				values = append(values, string(ch.Value))
+				return true, nil
+			},
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		expect := []string{"<value-2>", "<value-3>"}
+		if !slices.Equal(values, expect) {
+			t.Fatalf("unexpected values: got %q, want %q", values, expect)
+		}
+	})
+}
+
 func BenchmarkStore(b *testing.B) {
 	kv.RunBenchmarks(
 		b,
@@ -0,0 +1,2 @@
+// Package memorylease provides an in-memory implementation of [lease.Store].
+package memorylease
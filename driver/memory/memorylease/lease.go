@@ -0,0 +1,123 @@
+package memorylease
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/lease"
+)
+
+// state is the in-memory state of a lease.
+type state struct {
+	sync.Mutex
+	Holder    string
+	Token     lease.Token
+	ExpiresAt time.Time
+}
+
+// isHeld returns true if the lease is held at time t.
+func (st *state) isHeld(t time.Time) bool {
+	return st.ExpiresAt.After(t)
+}
+
+// leaseimpl is an implementation of [lease.Lease] that manipulates a lease's
+// in-memory [state].
+type leaseimpl struct {
+	name  string
+	state *state
+}
+
+func (l *leaseimpl) Name() string {
+	return l.name
+}
+
+func (l *leaseimpl) Acquire(
+	ctx context.Context,
+	holder string,
+	ttl time.Duration,
+) (lease.Grant, bool, error) {
+	if l.state == nil {
+		panic("lease is closed")
+	}
+
+	l.state.Lock()
+	defer l.state.Unlock()
+
+	now := time.Now()
+
+	if l.state.isHeld(now) {
+		if l.state.Holder != holder {
+			return lease.Grant{}, false, ctx.Err()
+		}
+	} else {
+		l.state.Holder = holder
+		l.state.Token++
+	}
+
+	l.state.ExpiresAt = now.Add(ttl)
+
+	return l.grant(), true, ctx.Err()
+}
+
+func (l *leaseimpl) Renew(
+	ctx context.Context,
+	g lease.Grant,
+	ttl time.Duration,
+) (lease.Grant, error) {
+	if l.state == nil {
+		panic("lease is closed")
+	}
+
+	l.state.Lock()
+	defer l.state.Unlock()
+
+	now := time.Now()
+
+	if !l.state.isHeld(now) || l.state.Token != g.Token {
+		return lease.Grant{}, lease.NotHeldError{
+			Lease: l.name,
+			Token: g.Token,
+		}
+	}
+
+	l.state.ExpiresAt = now.Add(ttl)
+
+	return l.grant(), ctx.Err()
+}
+
+func (l *leaseimpl) Release(ctx context.Context, g lease.Grant) error {
+	if l.state == nil {
+		panic("lease is closed")
+	}
+
+	l.state.Lock()
+	defer l.state.Unlock()
+
+	if l.state.Token == g.Token {
+		l.state.ExpiresAt = time.Time{}
+	}
+
+	return ctx.Err()
+}
+
+func (l *leaseimpl) Close() error {
+	if l.state == nil {
+		return errors.New("lease is already closed")
+	}
+
+	l.state = nil
+
+	return nil
+}
+
+// grant returns the [lease.Grant] that describes the current holder of the
+// lease. l.state must be locked.
+func (l *leaseimpl) grant() lease.Grant {
+	return lease.Grant{
+		Holder:    l.state.Holder,
+		Token:     l.state.Token,
+		ExpiresAt: l.state.ExpiresAt,
+	}
+}
@@ -0,0 +1,35 @@
+package memorylease
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dogmatiq/persistencekit/lease"
+)
+
+// Store is an in-memory implementation of [lease.Store].
+type Store struct {
+	state sync.Map // map[string]*state
+}
+
+// Provision is a no-op; memory stores do not require provisioning.
+func (s *Store) Provision(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Open returns the lease with the given name.
+func (s *Store) Open(ctx context.Context, name string) (lease.Lease, error) {
+	st, ok := s.state.Load(name)
+
+	if !ok {
+		st, _ = s.state.LoadOrStore(
+			name,
+			&state{},
+		)
+	}
+
+	return &leaseimpl{
+		name:  name,
+		state: st.(*state),
+	}, ctx.Err()
+}
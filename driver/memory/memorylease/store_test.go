@@ -0,0 +1,15 @@
+package memorylease_test
+
+import (
+	"testing"
+
+	. "github.com/dogmatiq/persistencekit/driver/memory/memorylease"
+	"github.com/dogmatiq/persistencekit/lease"
+)
+
+func TestStore(t *testing.T) {
+	lease.RunTests(
+		t,
+		&Store{},
+	)
+}
@@ -0,0 +1,2 @@
+// Package memoryqueue provides an in-memory implementation of [queue.Store].
+package memoryqueue
@@ -0,0 +1,51 @@
+package memoryqueue
+
+import "time"
+
+// message is a message within a queue's in-memory [state].
+type message[T any] struct {
+	ID        string
+	Seq       uint64
+	Value     T
+	Attempt   int
+	VisibleAt time.Time
+
+	// index is the index of the message within the [messageHeap].
+	index int
+}
+
+// messageHeap is a [heap.Interface] that orders messages by the time at which
+// they become visible, then by the order in which they were enqueued.
+type messageHeap[T any] []*message[T]
+
+func (h messageHeap[T]) Len() int {
+	return len(h)
+}
+
+func (h messageHeap[T]) Less(i, j int) bool {
+	if h[i].VisibleAt.Equal(h[j].VisibleAt) {
+		return h[i].Seq < h[j].Seq
+	}
+	return h[i].VisibleAt.Before(h[j].VisibleAt)
+}
+
+func (h messageHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *messageHeap[T]) Push(x any) {
+	m := x.(*message[T])
+	m.index = len(*h)
+	*h = append(*h, m)
+}
+
+func (h *messageHeap[T]) Pop() any {
+	old := *h
+	n := len(old) - 1
+	m := old[n]
+	old[n] = nil
+	*h = old[:n]
+	return m
+}
@@ -0,0 +1,188 @@
+package memoryqueue
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/driver/memory/internal/clone"
+	"github.com/dogmatiq/persistencekit/queue"
+)
+
+// state is the in-memory state of a queue.
+type state[T any] struct {
+	sync.Mutex
+	NextSeq  uint64
+	Messages messageHeap[T]
+	ByID     map[string]*message[T]
+}
+
+// push adds m to the queue. st must be locked.
+func (st *state[T]) push(m *message[T]) {
+	if st.ByID == nil {
+		st.ByID = map[string]*message[T]{}
+	}
+
+	st.ByID[m.ID] = m
+	heap.Push(&st.Messages, m)
+}
+
+// remove removes m from the queue. st must be locked.
+func (st *state[T]) remove(m *message[T]) {
+	delete(st.ByID, m.ID)
+	heap.Remove(&st.Messages, m.index)
+}
+
+// current returns the message identified by m if it has not been redelivered
+// or removed from the queue since m was dequeued. st must be locked.
+func (st *state[T]) current(m queue.Message[T]) (*message[T], bool) {
+	x, ok := st.ByID[m.ID]
+	return x, ok && x.Attempt == m.Attempt
+}
+
+// queueimpl is an implementation of [queue.Queue] that manipulates a queue's
+// in-memory [state].
+type queueimpl[T any] struct {
+	name  string
+	store *Store[T]
+	state *state[T]
+}
+
+func (q *queueimpl[T]) Name() string {
+	return q.name
+}
+
+func (q *queueimpl[T]) Enqueue(ctx context.Context, v T) error {
+	if q.state == nil {
+		panic("queue is closed")
+	}
+
+	q.state.Lock()
+	defer q.state.Unlock()
+
+	enqueue(q.state, clone.Clone(v))
+
+	return ctx.Err()
+}
+
+// enqueue adds a new message with the given value to st. st must be locked.
+func enqueue[T any](st *state[T], v T) {
+	st.NextSeq++
+
+	st.push(&message[T]{
+		ID:        strconv.FormatUint(st.NextSeq, 10),
+		Seq:       st.NextSeq,
+		Value:     v,
+		VisibleAt: time.Now(),
+	})
+}
+
+func (q *queueimpl[T]) Dequeue(ctx context.Context, timeout time.Duration) (queue.Message[T], bool, error) {
+	if q.state == nil {
+		panic("queue is closed")
+	}
+
+	q.state.Lock()
+	defer q.state.Unlock()
+
+	now := time.Now()
+
+	if len(q.state.Messages) == 0 || q.state.Messages[0].VisibleAt.After(now) {
+		return queue.Message[T]{}, false, ctx.Err()
+	}
+
+	m := q.state.Messages[0]
+	m.Attempt++
+	m.VisibleAt = now.Add(timeout)
+	heap.Fix(&q.state.Messages, m.index)
+
+	return queue.Message[T]{
+		ID:      m.ID,
+		Value:   clone.Clone(m.Value),
+		Attempt: m.Attempt,
+	}, true, ctx.Err()
+}
+
+func (q *queueimpl[T]) Ack(ctx context.Context, m queue.Message[T]) error {
+	if q.state == nil {
+		panic("queue is closed")
+	}
+
+	q.state.Lock()
+	defer q.state.Unlock()
+
+	x, ok := q.state.current(m)
+	if !ok {
+		return q.stale(m)
+	}
+
+	q.state.remove(x)
+
+	return ctx.Err()
+}
+
+func (q *queueimpl[T]) Nack(ctx context.Context, m queue.Message[T], delay time.Duration) error {
+	if q.state == nil {
+		panic("queue is closed")
+	}
+
+	q.state.Lock()
+	defer q.state.Unlock()
+
+	x, ok := q.state.current(m)
+	if !ok {
+		return q.stale(m)
+	}
+
+	x.VisibleAt = time.Now().Add(delay)
+	heap.Fix(&q.state.Messages, x.index)
+
+	return ctx.Err()
+}
+
+func (q *queueimpl[T]) DeadLetter(ctx context.Context, m queue.Message[T]) error {
+	if q.state == nil {
+		panic("queue is closed")
+	}
+
+	q.state.Lock()
+
+	x, ok := q.state.current(m)
+	if !ok {
+		q.state.Unlock()
+		return q.stale(m)
+	}
+
+	q.state.remove(x)
+	q.state.Unlock()
+
+	// The dead-letter queue's state is locked separately, after the source
+	// queue's state is unlocked, so that there is no lock-ordering hazard.
+	dlq := q.store.state(queue.DeadLetterName(q.name))
+	dlq.Lock()
+	enqueue(dlq, x.Value)
+	dlq.Unlock()
+
+	return ctx.Err()
+}
+
+func (q *queueimpl[T]) Close() error {
+	if q.state == nil {
+		return errors.New("queue is already closed")
+	}
+
+	q.state = nil
+
+	return nil
+}
+
+func (q *queueimpl[T]) stale(m queue.Message[T]) error {
+	return queue.StaleMessageError{
+		Queue:   q.name,
+		ID:      m.ID,
+		Attempt: m.Attempt,
+	}
+}
@@ -0,0 +1,45 @@
+package memoryqueue
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dogmatiq/persistencekit/queue"
+)
+
+// Store is an implementation of [queue.Store] that stores messages in memory.
+type Store[T any] struct {
+	queues sync.Map // map[string]*state[T]
+}
+
+// BinaryStore is an implementation of [queue.BinaryStore] that stores messages
+// in memory.
+type BinaryStore = Store[[]byte]
+
+// Provision is a no-op; memory stores do not require provisioning.
+func (s *Store[T]) Provision(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Open returns the queue with the given name.
+func (s *Store[T]) Open(ctx context.Context, name string) (queue.Queue[T], error) {
+	return &queueimpl[T]{
+		name:  name,
+		store: s,
+		state: s.state(name),
+	}, ctx.Err()
+}
+
+// state returns the state of the named queue.
+func (s *Store[T]) state(name string) *state[T] {
+	st, ok := s.queues.Load(name)
+
+	if !ok {
+		st, _ = s.queues.LoadOrStore(
+			name,
+			&state[T]{},
+		)
+	}
+
+	return st.(*state[T])
+}
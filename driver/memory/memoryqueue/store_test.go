@@ -0,0 +1,15 @@
+package memoryqueue_test
+
+import (
+	"testing"
+
+	. "github.com/dogmatiq/persistencekit/driver/memory/memoryqueue"
+	"github.com/dogmatiq/persistencekit/queue"
+)
+
+func TestStore(t *testing.T) {
+	queue.RunTests(
+		t,
+		&BinaryStore{},
+	)
+}
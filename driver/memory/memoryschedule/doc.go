@@ -0,0 +1,3 @@
+// Package memoryschedule provides an in-memory implementation of
+// [schedule.Store].
+package memoryschedule
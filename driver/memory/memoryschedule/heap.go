@@ -0,0 +1,49 @@
+package memoryschedule
+
+import "time"
+
+// item is an item within a schedule's in-memory [state].
+type item[T any] struct {
+	ID    string
+	DueAt time.Time
+	Value T
+
+	// index is the index of the item within the [itemHeap].
+	index int
+}
+
+// itemHeap is a [heap.Interface] that orders items by their due time, then by
+// their ID.
+type itemHeap[T any] []*item[T]
+
+func (h itemHeap[T]) Len() int {
+	return len(h)
+}
+
+func (h itemHeap[T]) Less(i, j int) bool {
+	if h[i].DueAt.Equal(h[j].DueAt) {
+		return h[i].ID < h[j].ID
+	}
+	return h[i].DueAt.Before(h[j].DueAt)
+}
+
+func (h itemHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *itemHeap[T]) Push(x any) {
+	it := x.(*item[T])
+	it.index = len(*h)
+	*h = append(*h, it)
+}
+
+func (h *itemHeap[T]) Pop() any {
+	old := *h
+	n := len(old) - 1
+	it := old[n]
+	old[n] = nil
+	*h = old[:n]
+	return it
+}
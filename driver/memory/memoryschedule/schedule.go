@@ -0,0 +1,213 @@
+package memoryschedule
+
+import (
+	"cmp"
+	"container/heap"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/driver/memory/internal/clone"
+	"github.com/dogmatiq/persistencekit/schedule"
+)
+
+// state is the in-memory state of a schedule.
+type state[T any] struct {
+	sync.Mutex
+	Items itemHeap[T]
+	ByID  map[string]*item[T]
+}
+
+// current returns the stored item identified by it if its due time has not
+// changed. st must be locked.
+func (st *state[T]) current(it schedule.Item[T]) (*item[T], bool) {
+	x, ok := st.ByID[it.ID]
+	return x, ok && x.DueAt.Equal(it.DueAt)
+}
+
+// remove removes x from the schedule. st must be locked.
+func (st *state[T]) remove(x *item[T]) {
+	delete(st.ByID, x.ID)
+	heap.Remove(&st.Items, x.index)
+}
+
+// due returns copies of the items that are due at or before t, in order of
+// their due time. st must be locked.
+func (st *state[T]) due(t time.Time) []schedule.Item[T] {
+	var items []schedule.Item[T]
+
+	// Any item that is not due has no descendants in the heap that are due,
+	// so only the due items, and their immediate children, are visited.
+	var visit func(i int)
+	visit = func(i int) {
+		if i >= len(st.Items) || st.Items[i].DueAt.After(t) {
+			return
+		}
+
+		x := st.Items[i]
+		items = append(items, schedule.Item[T]{
+			ID:    x.ID,
+			DueAt: x.DueAt,
+			Value: clone.Clone(x.Value),
+		})
+
+		visit(2*i + 1)
+		visit(2*i + 2)
+	}
+	visit(0)
+
+	slices.SortFunc(
+		items,
+		func(a, b schedule.Item[T]) int {
+			if c := a.DueAt.Compare(b.DueAt); c != 0 {
+				return c
+			}
+			return cmp.Compare(a.ID, b.ID)
+		},
+	)
+
+	return items
+}
+
+// scheduleimpl is an implementation of [schedule.Schedule] that manipulates a
+// schedule's in-memory [state].
+type scheduleimpl[T any] struct {
+	name  string
+	state *state[T]
+}
+
+func (s *scheduleimpl[T]) Name() string {
+	return s.name
+}
+
+func (s *scheduleimpl[T]) Schedule(ctx context.Context, id string, dueAt time.Time, v T) error {
+	if s.state == nil {
+		panic("schedule is closed")
+	}
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	dueAt = truncate(dueAt)
+	v = clone.Clone(v)
+
+	if x, ok := s.state.ByID[id]; ok {
+		x.DueAt = dueAt
+		x.Value = v
+		heap.Fix(&s.state.Items, x.index)
+		return ctx.Err()
+	}
+
+	if s.state.ByID == nil {
+		s.state.ByID = map[string]*item[T]{}
+	}
+
+	x := &item[T]{
+		ID:    id,
+		DueAt: dueAt,
+		Value: v,
+	}
+
+	s.state.ByID[id] = x
+	heap.Push(&s.state.Items, x)
+
+	return ctx.Err()
+}
+
+func (s *scheduleimpl[T]) Cancel(ctx context.Context, id string) error {
+	if s.state == nil {
+		panic("schedule is closed")
+	}
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	if x, ok := s.state.ByID[id]; ok {
+		s.state.remove(x)
+	}
+
+	return ctx.Err()
+}
+
+func (s *scheduleimpl[T]) RangeDue(ctx context.Context, t time.Time, fn schedule.RangeFunc[T]) error {
+	if s.state == nil {
+		panic("schedule is closed")
+	}
+
+	// The due items are copied so that the lock is not held while calling fn,
+	// which may modify the schedule.
+	s.state.Lock()
+	items := s.state.due(t)
+	s.state.Unlock()
+
+	for _, it := range items {
+		ok, err := fn(ctx, it)
+		if !ok || err != nil {
+			return err
+		}
+	}
+
+	return ctx.Err()
+}
+
+func (s *scheduleimpl[T]) Claim(
+	ctx context.Context,
+	it schedule.Item[T],
+	timeout time.Duration,
+) (schedule.Item[T], bool, error) {
+	if s.state == nil {
+		panic("schedule is closed")
+	}
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	now := time.Now()
+
+	x, ok := s.state.current(it)
+	if !ok || x.DueAt.After(now) {
+		return schedule.Item[T]{}, false, ctx.Err()
+	}
+
+	x.DueAt = truncate(now.Add(timeout))
+	heap.Fix(&s.state.Items, x.index)
+
+	it.DueAt = x.DueAt
+	return it, true, ctx.Err()
+}
+
+func (s *scheduleimpl[T]) Complete(ctx context.Context, it schedule.Item[T]) (bool, error) {
+	if s.state == nil {
+		panic("schedule is closed")
+	}
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	x, ok := s.state.current(it)
+	if !ok {
+		return false, ctx.Err()
+	}
+
+	s.state.remove(x)
+
+	return true, ctx.Err()
+}
+
+func (s *scheduleimpl[T]) Close() error {
+	if s.state == nil {
+		return errors.New("schedule is already closed")
+	}
+
+	s.state = nil
+
+	return nil
+}
+
+// truncate returns t truncated to the precision at which due times are
+// stored.
+func truncate(t time.Time) time.Time {
+	return time.UnixMilli(t.UnixMilli())
+}
@@ -0,0 +1,39 @@
+package memoryschedule
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dogmatiq/persistencekit/schedule"
+)
+
+// Store is an implementation of [schedule.Store] that stores items in memory.
+type Store[T any] struct {
+	schedules sync.Map // map[string]*state[T]
+}
+
+// BinaryStore is an implementation of [schedule.BinaryStore] that stores items
+// in memory.
+type BinaryStore = Store[[]byte]
+
+// Provision is a no-op; memory stores do not require provisioning.
+func (s *Store[T]) Provision(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Open returns the schedule with the given name.
+func (s *Store[T]) Open(ctx context.Context, name string) (schedule.Schedule[T], error) {
+	st, ok := s.schedules.Load(name)
+
+	if !ok {
+		st, _ = s.schedules.LoadOrStore(
+			name,
+			&state[T]{},
+		)
+	}
+
+	return &scheduleimpl[T]{
+		name:  name,
+		state: st.(*state[T]),
+	}, ctx.Err()
+}
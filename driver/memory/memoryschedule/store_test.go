@@ -0,0 +1,15 @@
+package memoryschedule_test
+
+import (
+	"testing"
+
+	. "github.com/dogmatiq/persistencekit/driver/memory/memoryschedule"
+	"github.com/dogmatiq/persistencekit/schedule"
+)
+
+func TestStore(t *testing.T) {
+	schedule.RunTests(
+		t,
+		&BinaryStore{},
+	)
+}
@@ -6,6 +6,7 @@ import (
 	"maps"
 	"sync"
 
+	"github.com/dogmatiq/persistencekit/driver/memory/internal/bytesize"
 	"github.com/dogmatiq/persistencekit/set"
 )
 
@@ -13,6 +14,7 @@ import (
 type state[C comparable] struct {
 	sync.RWMutex
 	Values map[C]struct{}
+	Bytes  uint64
 }
 
 // setimpl is an implementation of [kv.BinarySet] that manipulates a
@@ -42,6 +44,23 @@ func (s *setimpl[T, C]) Has(ctx context.Context, v T) (ok bool, err error) {
 	return ok, ctx.Err()
 }
 
+func (s *setimpl[T, C]) HasBatch(ctx context.Context, vs []T) ([]bool, error) {
+	if s.state == nil {
+		panic("set is closed")
+	}
+
+	result := make([]bool, len(vs))
+
+	s.state.RLock()
+	defer s.state.RUnlock()
+
+	for i, v := range vs {
+		_, result[i] = s.state.Values[s.marshalValue(v)]
+	}
+
+	return result, ctx.Err()
+}
+
 func (s *setimpl[T, C]) Add(ctx context.Context, v T) error {
 	_, err := s.TryAdd(ctx, v)
 	return err
@@ -61,11 +80,14 @@ func (s *setimpl[T, C]) TryAdd(ctx context.Context, v T) (bool, error) {
 		s.state.Values = map[C]struct{}{}
 	}
 
-	before := len(s.state.Values)
+	if _, ok := s.state.Values[c]; ok {
+		return false, ctx.Err()
+	}
+
 	s.state.Values[c] = struct{}{}
-	after := len(s.state.Values)
+	s.state.Bytes += bytesize.Of(v)
 
-	return before < after, ctx.Err()
+	return true, ctx.Err()
 }
 
 func (s *setimpl[T, C]) Remove(ctx context.Context, v T) error {
@@ -87,11 +109,14 @@ func (s *setimpl[T, C]) TryRemove(ctx context.Context, v T) (bool, error) {
 		return false, ctx.Err()
 	}
 
-	before := len(s.state.Values)
+	if _, ok := s.state.Values[c]; !ok {
+		return false, ctx.Err()
+	}
+
 	delete(s.state.Values, c)
-	after := len(s.state.Values)
+	s.state.Bytes -= bytesize.Of(v)
 
-	return before > after, ctx.Err()
+	return true, ctx.Err()
 }
 
 func (s *setimpl[T, C]) Range(ctx context.Context, fn set.RangeFunc[T]) error {
@@ -114,6 +139,20 @@ func (s *setimpl[T, C]) Range(ctx context.Context, fn set.RangeFunc[T]) error {
 	return ctx.Err()
 }
 
+func (s *setimpl[T, C]) Stats(ctx context.Context) (set.Stats, error) {
+	if s.state == nil {
+		panic("set is closed")
+	}
+
+	s.state.RLock()
+	defer s.state.RUnlock()
+
+	return set.Stats{
+		Members: uint64(len(s.state.Values)),
+		Bytes:   s.state.Bytes,
+	}, ctx.Err()
+}
+
 func (s *setimpl[T, C]) Close() error {
 	if s.state == nil {
 		return errors.New("set is already closed")
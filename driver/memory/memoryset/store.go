@@ -2,6 +2,7 @@ package memoryset
 
 import (
 	"context"
+	"slices"
 	"sync"
 
 	"github.com/dogmatiq/persistencekit/set"
@@ -17,6 +18,17 @@ func (s *Store[T]) Provision(ctx context.Context) error {
 	return ctx.Err()
 }
 
+// Capabilities returns the limits and optional features of the store.
+func (s *Store[T]) Capabilities() set.Capabilities {
+	return set.Capabilities{}
+}
+
+// Names returns the names of the sets that have been opened, in lexical
+// order.
+func (s *Store[T]) Names(ctx context.Context) ([]string, error) {
+	return names(&s.state), ctx.Err()
+}
+
 // Open returns the set with the given name.
 func (s *Store[T]) Open(ctx context.Context, name string) (set.Set[T], error) {
 	st, ok := s.state.Load(name)
@@ -51,6 +63,17 @@ func (s *BinaryStore) Provision(ctx context.Context) error {
 	return ctx.Err()
 }
 
+// Capabilities returns the limits and optional features of the store.
+func (s *BinaryStore) Capabilities() set.Capabilities {
+	return set.Capabilities{}
+}
+
+// Names returns the names of the sets that have been opened, in lexical
+// order.
+func (s *BinaryStore) Names(ctx context.Context) ([]string, error) {
+	return names(&s.state), ctx.Err()
+}
+
 // Open returns the keyspace with the given name.
 func (s *BinaryStore) Open(ctx context.Context, name string) (set.BinarySet, error) {
 	st, ok := s.state.Load(name)
@@ -69,3 +92,19 @@ func (s *BinaryStore) Open(ctx context.Context, name string) (set.BinarySet, err
 		unmarshalValue: func(k string) []byte { return []byte(k) },
 	}, ctx.Err()
 }
+
+// names returns the keys of m, in lexical order.
+func names(m *sync.Map) []string {
+	var result []string
+
+	m.Range(
+		func(k, _ any) bool {
+			result = append(result, k.(string))
+			return true
+		},
+	)
+
+	slices.Sort(result)
+
+	return result
+}
@@ -0,0 +1,24 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/dogmatiq/persistencekit/provision"
+)
+
+// PlanProvision returns an empty plan. In-memory stores do not require
+// provisioning.
+func (d *Driver) PlanProvision(context.Context) (provision.Plan, error) {
+	return provision.Plan{}, nil
+}
+
+// PlanDeprovision returns an empty plan. In-memory stores do not use any
+// infrastructure.
+func (d *Driver) PlanDeprovision(context.Context) (provision.Plan, error) {
+	return provision.Plan{}, nil
+}
+
+// Deprovision does nothing. In-memory stores do not use any infrastructure.
+func (d *Driver) Deprovision(context.Context) error {
+	return nil
+}
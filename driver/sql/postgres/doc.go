@@ -1,2 +1,15 @@
 // Package postgres provides a persistence [Driver] backed by PostgreSQL.
+//
+// Each store creates its tables the first time they are needed if they do not
+// already exist. In addition, the journal, key/value and set stores apply any
+// pending schema migrations when a journal, keyspace or set is opened. These
+// implicit changes are applied in a transaction, while holding a lock that
+// serializes them with any concurrent migration in the same schema. No DDL is
+// executed if the schema is already up to date.
+//
+// Applications that connect as a database user that is not permitted to modify
+// the schema must provision the stores, or use [Driver.PlanProvision] to obtain
+// the DDL to apply by other means, before opening any journals, keyspaces or
+// sets. Upgrading to a version of this package that adds a migration requires
+// the same.
 package postgres
@@ -6,12 +6,21 @@ import (
 	"fmt"
 	"net/url"
 
+	"github.com/dogmatiq/persistencekit/blob"
 	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgnamespace"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/pgblob"
 	"github.com/dogmatiq/persistencekit/driver/sql/postgres/pgjournal"
 	"github.com/dogmatiq/persistencekit/driver/sql/postgres/pgkv"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/pglease"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/pgqueue"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/pgschedule"
 	"github.com/dogmatiq/persistencekit/driver/sql/postgres/pgset"
 	"github.com/dogmatiq/persistencekit/journal"
 	"github.com/dogmatiq/persistencekit/kv"
+	"github.com/dogmatiq/persistencekit/lease"
+	"github.com/dogmatiq/persistencekit/queue"
+	"github.com/dogmatiq/persistencekit/schedule"
 	"github.com/dogmatiq/persistencekit/set"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/stdlib"
@@ -21,10 +30,20 @@ import (
 type Driver struct {
 	pool *pgxpool.Pool
 	db   *sql.DB
+	ns   pgnamespace.Namespace
 }
 
 // New returns a [Driver] described by the given configuration.
 func New(cfg *Config) (*Driver, error) {
+	ns := pgnamespace.Namespace{
+		Schema:      cfg.Schema,
+		TablePrefix: cfg.TablePrefix,
+	}
+
+	if err := ns.Validate(); err != nil {
+		return nil, err
+	}
+
 	pool, err := pgxpool.NewWithConfig(context.Background(), cfg.Pool)
 	if err != nil {
 		return nil, err
@@ -33,19 +52,73 @@ func New(cfg *Config) (*Driver, error) {
 	return &Driver{
 		pool: pool,
 		db:   stdlib.OpenDBFromPool(pool),
+		ns:   ns,
 	}, nil
 }
 
 // NewFromDB returns a [Driver] that uses the given [*sql.DB]. The caller
 // retains ownership of db; [Driver.Close] does not close it.
-func NewFromDB(db *sql.DB) *Driver {
-	return &Driver{db: db}
+//
+// The driver's tables are located in the "persistencekit" schema, unless
+// [WithSchema] or [WithTablePrefix] is used.
+func NewFromDB(db *sql.DB, options ...FromDBOption) *Driver {
+	d := &Driver{db: db}
+
+	for _, opt := range options {
+		opt(d)
+	}
+
+	return d
+}
+
+// FromDBOption is an option that changes the behavior of a [Driver] created by
+// [NewFromDB].
+type FromDBOption func(*Driver)
+
+// WithSchema is a [FromDBOption] that sets the name of the PostgreSQL schema
+// that contains the tables used by each store. See [Config.Schema].
+//
+// It panics if the name is invalid.
+func WithSchema(name string) FromDBOption {
+	if err := (pgnamespace.Namespace{Schema: name}).Validate(); err != nil {
+		panic(err)
+	}
+
+	return func(d *Driver) {
+		d.ns.Schema = name
+	}
+}
+
+// WithTablePrefix is a [FromDBOption] that sets a prefix added to the name of
+// each table. See [Config.TablePrefix].
+//
+// It panics if the prefix is invalid.
+func WithTablePrefix(prefix string) FromDBOption {
+	if err := (pgnamespace.Namespace{TablePrefix: prefix}).Validate(); err != nil {
+		panic(err)
+	}
+
+	return func(d *Driver) {
+		d.ns.TablePrefix = prefix
+	}
 }
 
 // Config holds the configuration for a PostgreSQL persistence driver.
 type Config struct {
 	// Pool is the pgxpool configuration used to establish connections.
 	Pool *pgxpool.Config
+
+	// Schema is the name of the PostgreSQL schema that contains the tables
+	// used by each store. If it is empty, the "persistencekit" schema is used.
+	//
+	// It must consist only of lower-case letters, digits and underscores.
+	Schema string
+
+	// TablePrefix is an optional prefix added to the name of each table. It
+	// allows multiple applications to share a single schema.
+	//
+	// It must consist only of lower-case letters, digits and underscores.
+	TablePrefix string
 }
 
 // NewDriver returns a [Driver] described by the given configuration.
@@ -61,6 +134,13 @@ func (c *Config) NewDriver(context.Context) (driver.Driver, error) {
 //
 // Pool and connection settings can be configured via URL parameters. See
 // [pgxpool.ParseConfig] for the full list of supported parameters.
+//
+// The following additional parameters are supported:
+//
+//   - persistencekit_schema: the name of the schema that contains the tables
+//     used by each store, see [Config.Schema].
+//   - persistencekit_table_prefix: a prefix added to the name of each table,
+//     see [Config.TablePrefix].
 func ParseURL(ctx context.Context, u string) (*Config, error) {
 	parsed, err := url.Parse(u)
 	if err != nil {
@@ -77,27 +157,112 @@ func FromURL(_ context.Context, u *url.URL) (*Config, error) {
 		return nil, fmt.Errorf("invalid postgres URL: unexpected scheme %q", u.Scheme)
 	}
 
-	cfg, err := pgxpool.ParseConfig(u.String())
+	var (
+		ns    pgnamespace.Namespace
+		query = u.Query()
+	)
+
+	for k, v := range query {
+		switch k {
+		case "persistencekit_schema":
+			ns.Schema = v[len(v)-1]
+		case "persistencekit_table_prefix":
+			ns.TablePrefix = v[len(v)-1]
+		default:
+			continue
+		}
+
+		// Remove the parameter so that it is not sent to the server as a
+		// run-time parameter.
+		query.Del(k)
+	}
+
+	if err := ns.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid postgres URL: %w", err)
+	}
+
+	stripped := *u
+	stripped.RawQuery = query.Encode()
+
+	pool, err := pgxpool.ParseConfig(stripped.String())
 	if err != nil {
 		return nil, fmt.Errorf("invalid postgres URL: %w", err)
 	}
 
-	return &Config{Pool: cfg}, nil
+	return &Config{
+		Pool:        pool,
+		Schema:      ns.Schema,
+		TablePrefix: ns.TablePrefix,
+	}, nil
 }
 
 // JournalStore returns a journal store backed by PostgreSQL.
 func (d *Driver) JournalStore() journal.BinaryStore {
-	return &pgjournal.BinaryStore{DB: d.db}
+	return &pgjournal.BinaryStore{
+		DB:          d.db,
+		Schema:      d.ns.Schema,
+		TablePrefix: d.ns.TablePrefix,
+	}
 }
 
 // KVStore returns a key/value store backed by PostgreSQL.
 func (d *Driver) KVStore() kv.BinaryStore {
-	return &pgkv.BinaryStore{DB: d.db}
+	return &pgkv.BinaryStore{
+		DB:          d.db,
+		Schema:      d.ns.Schema,
+		TablePrefix: d.ns.TablePrefix,
+	}
 }
 
 // SetStore returns a set store backed by PostgreSQL.
 func (d *Driver) SetStore() set.BinaryStore {
-	return &pgset.BinaryStore{DB: d.db}
+	return &pgset.BinaryStore{
+		DB:          d.db,
+		Schema:      d.ns.Schema,
+		TablePrefix: d.ns.TablePrefix,
+	}
+}
+
+// LeaseStore returns a lease store backed by PostgreSQL.
+func (d *Driver) LeaseStore() lease.Store {
+	return &pglease.Store{
+		DB:          d.db,
+		Schema:      d.ns.Schema,
+		TablePrefix: d.ns.TablePrefix,
+	}
+}
+
+// QueueStore returns a queue store backed by PostgreSQL.
+func (d *Driver) QueueStore() queue.BinaryStore {
+	return &pgqueue.BinaryStore{
+		DB:          d.db,
+		Schema:      d.ns.Schema,
+		TablePrefix: d.ns.TablePrefix,
+	}
+}
+
+// ScheduleStore returns a schedule store backed by PostgreSQL.
+func (d *Driver) ScheduleStore() schedule.BinaryStore {
+	return &pgschedule.BinaryStore{
+		DB:          d.db,
+		Schema:      d.ns.Schema,
+		TablePrefix: d.ns.TablePrefix,
+	}
+}
+
+// BlobStore returns a blob store backed by PostgreSQL.
+func (d *Driver) BlobStore() blob.Store {
+	return &pgblob.Store{
+		DB:          d.db,
+		Schema:      d.ns.Schema,
+		TablePrefix: d.ns.TablePrefix,
+	}
+}
+
+// Capabilities returns the limits and optional features of the driver's
+// stores.
+func (d *Driver) Capabilities() driver.Capabilities {
+	return driver.StoreCapabilities(d)
 }
 
 // Close closes the underlying connection pool.
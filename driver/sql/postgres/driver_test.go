@@ -6,8 +6,12 @@ import (
 
 	"github.com/dogmatiq/persistencekit/driver/sql/postgres"
 	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgtest"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/pgblob"
 	"github.com/dogmatiq/persistencekit/driver/sql/postgres/pgjournal"
 	"github.com/dogmatiq/persistencekit/driver/sql/postgres/pgkv"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/pglease"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/pgqueue"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/pgschedule"
 	"github.com/dogmatiq/persistencekit/driver/sql/postgres/pgset"
 	"github.com/dogmatiq/persistencekit/internal/drivertest"
 )
@@ -34,9 +38,60 @@ func TestNew(t *testing.T) {
 		&pgjournal.BinaryStore{DB: db},
 		&pgkv.BinaryStore{DB: db},
 		&pgset.BinaryStore{DB: db},
+		&pglease.Store{DB: db},
+		&pgqueue.BinaryStore{DB: db},
+		&pgschedule.BinaryStore{DB: db},
+		&pgblob.Store{DB: db},
 	)
 }
 
+func TestCheckHealth(t *testing.T) {
+	db, _ := pgtest.Setup(t)
+
+	d := postgres.NewFromDB(db)
+	t.Cleanup(func() {
+		d.Close()
+	})
+
+	drivertest.RunHealthTests(t, d)
+}
+
+func TestProvision(t *testing.T) {
+	db, _ := pgtest.Setup(t)
+
+	d := postgres.NewFromDB(db)
+	t.Cleanup(func() {
+		d.Close()
+	})
+
+	drivertest.RunProvisionTests(t, d)
+}
+
+func TestBackup(t *testing.T) {
+	sourceDB, _ := pgtest.Setup(t)
+	targetDB, _ := pgtest.Setup(t)
+
+	source := postgres.NewFromDB(sourceDB)
+	target := postgres.NewFromDB(targetDB)
+	t.Cleanup(func() {
+		source.Close()
+		target.Close()
+	})
+
+	drivertest.RunBackupTests(t, source, target)
+}
+
+func TestChangeFeed(t *testing.T) {
+	db, _ := pgtest.Setup(t)
+
+	d := postgres.NewFromDB(db)
+	t.Cleanup(func() {
+		d.Close()
+	})
+
+	drivertest.RunChangeFeedTests(t, d)
+}
+
 func TestNewFromDB(t *testing.T) {
 	db, _ := pgtest.Setup(t)
 
@@ -51,7 +106,127 @@ func TestNewFromDB(t *testing.T) {
 		&pgjournal.BinaryStore{DB: db},
 		&pgkv.BinaryStore{DB: db},
 		&pgset.BinaryStore{DB: db},
+		&pglease.Store{DB: db},
+		&pgqueue.BinaryStore{DB: db},
+		&pgschedule.BinaryStore{DB: db},
+		&pgblob.Store{DB: db},
+	)
+}
+
+func TestNew_namespace(t *testing.T) {
+	const (
+		schema = "tenant_a"
+		prefix = "app_"
+	)
+
+	db, dsn := pgtest.Setup(t)
+	d := newNamespacedDriver(t, dsn, schema, prefix)
+
+	drivertest.RunTests(
+		t,
+		d,
+		&pgjournal.BinaryStore{DB: db, Schema: schema, TablePrefix: prefix},
+		&pgkv.BinaryStore{DB: db, Schema: schema, TablePrefix: prefix},
+		&pgset.BinaryStore{DB: db, Schema: schema, TablePrefix: prefix},
+		&pglease.Store{DB: db, Schema: schema, TablePrefix: prefix},
+		&pgqueue.BinaryStore{DB: db, Schema: schema, TablePrefix: prefix},
+		&pgschedule.BinaryStore{DB: db, Schema: schema, TablePrefix: prefix},
+		&pgblob.Store{DB: db, Schema: schema, TablePrefix: prefix},
 	)
+
+	t.Run("it does not create tables in the default schema", func(t *testing.T) {
+		var n int
+		if err := db.QueryRowContext(
+			t.Context(),
+			`SELECT COUNT(*)
+			FROM information_schema.tables
+			WHERE table_schema = 'persistencekit'`,
+		).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+
+		if n != 0 {
+			t.Fatalf("unexpected number of tables in the persistencekit schema: got %d, want 0", n)
+		}
+	})
+}
+
+func TestNewFromDB_namespace(t *testing.T) {
+	const (
+		schema = "tenant_b"
+		prefix = "app_"
+	)
+
+	db, _ := pgtest.Setup(t)
+
+	d := postgres.NewFromDB(
+		db,
+		postgres.WithSchema(schema),
+		postgres.WithTablePrefix(prefix),
+	)
+	t.Cleanup(func() {
+		d.Close()
+	})
+
+	drivertest.RunTests(
+		t,
+		d,
+		&pgjournal.BinaryStore{DB: db, Schema: schema, TablePrefix: prefix},
+		&pgkv.BinaryStore{DB: db, Schema: schema, TablePrefix: prefix},
+		&pgset.BinaryStore{DB: db, Schema: schema, TablePrefix: prefix},
+		&pglease.Store{DB: db, Schema: schema, TablePrefix: prefix},
+		&pgqueue.BinaryStore{DB: db, Schema: schema, TablePrefix: prefix},
+		&pgschedule.BinaryStore{DB: db, Schema: schema, TablePrefix: prefix},
+		&pgblob.Store{DB: db, Schema: schema, TablePrefix: prefix},
+	)
+
+	t.Run("it panics if the schema is invalid", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected a panic")
+			}
+		}()
+
+		postgres.WithSchema("Invalid-Schema")
+	})
+}
+
+func TestProvision_namespace(t *testing.T) {
+	_, dsn := pgtest.Setup(t)
+
+	d := newNamespacedDriver(t, dsn, "tenant_a", "app_")
+	drivertest.RunProvisionTests(t, d)
+}
+
+// newNamespacedDriver returns a driver that stores its tables in the given
+// schema, with the given table prefix.
+func newNamespacedDriver(t *testing.T, dsn, schema, prefix string) *postgres.Driver {
+	t.Helper()
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := u.Query()
+	q.Set("persistencekit_schema", schema)
+	q.Set("persistencekit_table_prefix", prefix)
+	u.RawQuery = q.Encode()
+
+	cfg, err := postgres.FromURL(t.Context(), u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := postgres.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		d.Close()
+	})
+
+	return d
 }
 
 func TestParseURL(t *testing.T) {
@@ -76,6 +251,10 @@ func TestParseURL(t *testing.T) {
 		&pgjournal.BinaryStore{DB: db},
 		&pgkv.BinaryStore{DB: db},
 		&pgset.BinaryStore{DB: db},
+		&pglease.Store{DB: db},
+		&pgqueue.BinaryStore{DB: db},
+		&pgschedule.BinaryStore{DB: db},
+		&pgblob.Store{DB: db},
 	)
 }
 
@@ -107,9 +286,44 @@ func TestFromURL(t *testing.T) {
 			&pgjournal.BinaryStore{DB: db},
 			&pgkv.BinaryStore{DB: db},
 			&pgset.BinaryStore{DB: db},
+			&pglease.Store{DB: db},
+			&pgqueue.BinaryStore{DB: db},
+			&pgschedule.BinaryStore{DB: db},
+			&pgblob.Store{DB: db},
 		)
 	})
 
+	t.Run("it parses the schema and table prefix", func(t *testing.T) {
+		u := &url.URL{
+			Scheme:   "postgres",
+			Host:     "localhost",
+			Path:     "/db",
+			RawQuery: "persistencekit_schema=tenant_a&persistencekit_table_prefix=app_&application_name=test",
+		}
+
+		cfg, err := postgres.FromURL(t.Context(), u)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if cfg.Schema != "tenant_a" {
+			t.Fatalf("unexpected schema: got %q, want %q", cfg.Schema, "tenant_a")
+		}
+
+		if cfg.TablePrefix != "app_" {
+			t.Fatalf("unexpected table prefix: got %q, want %q", cfg.TablePrefix, "app_")
+		}
+
+		params := cfg.Pool.ConnConfig.RuntimeParams
+		if _, ok := params["persistencekit_schema"]; ok {
+			t.Fatal("expected the schema parameter to be removed from the run-time parameters")
+		}
+
+		if params["application_name"] != "test" {
+			t.Fatalf("unexpected application name: got %q, want %q", params["application_name"], "test")
+		}
+	})
+
 	t.Run("when the URL is invalid", func(t *testing.T) {
 		cases := []struct {
 			Name string
@@ -117,6 +331,8 @@ func TestFromURL(t *testing.T) {
 		}{
 			{"wrong scheme", &url.URL{Scheme: "other", Host: "localhost", Path: "/db"}},
 			{"invalid config", &url.URL{Scheme: "postgres", Host: "localhost:notaport", Path: "/db"}},
+			{"invalid schema", &url.URL{Scheme: "postgres", Host: "localhost", Path: "/db", RawQuery: "persistencekit_schema=Tenant-A"}},
+			{"invalid table prefix", &url.URL{Scheme: "postgres", Host: "localhost", Path: "/db", RawQuery: "persistencekit_table_prefix=app."}},
 		}
 		for _, tc := range cases {
 			t.Run(tc.Name, func(t *testing.T) {
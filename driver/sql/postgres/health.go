@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dogmatiq/persistencekit/health"
+)
+
+// tables is the set of tables that must exist for each store to be considered
+// provisioned. The names do not include the table prefix.
+var tables = map[health.Store][]string{
+	health.JournalStore:  {"journal", "journal_record"},
+	health.KVStore:       {"keyspace", "keyspace_pair"},
+	health.SetStore:      {"set", "set_member"},
+	health.LeaseStore:    {"lease"},
+	health.QueueStore:    {"queue", "queue_message"},
+	health.ScheduleStore: {"schedule", "schedule_item"},
+	health.BlobStore:     {"blob_container", "blob", "blob_chunk"},
+}
+
+// CheckHealth checks that the PostgreSQL server is reachable and that the
+// tables used by each store exist.
+func (d *Driver) CheckHealth(ctx context.Context) health.Report {
+	existing, err := d.existingTables(ctx)
+	if err != nil {
+		return health.NewReport(
+			func(health.Store) error {
+				return err
+			},
+		)
+	}
+
+	return health.NewReport(
+		func(s health.Store) error {
+			for _, t := range tables[s] {
+				if _, ok := existing[d.ns.TablePrefix+t]; !ok {
+					return fmt.Errorf("the %s table does not exist, the %s store has not been provisioned", d.ns.Table(t), s)
+				}
+			}
+			return nil
+		},
+	)
+}
+
+// existingTables returns the names of the tables in the driver's schema,
+// including those that do not have the driver's table prefix.
+func (d *Driver) existingTables(ctx context.Context) (map[string]struct{}, error) {
+	if err := d.db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("cannot reach the PostgreSQL server: %w", err)
+	}
+
+	rows, err := d.db.QueryContext(
+		ctx,
+		`SELECT table_name
+		FROM information_schema.tables
+		WHERE table_schema = $1`,
+		d.ns.SchemaName(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot query the %s schema: %w", d.ns.SchemaName(), err)
+	}
+	defer rows.Close()
+
+	existing := map[string]struct{}{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("cannot query the %s schema: %w", d.ns.SchemaName(), err)
+		}
+		existing[name] = struct{}{}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cannot query the %s schema: %w", d.ns.SchemaName(), err)
+	}
+
+	return existing, nil
+}
@@ -0,0 +1,12 @@
+// Package pgmigrate applies versioned schema migrations to a PostgreSQL
+// database.
+//
+// Each store has its own ordered sequence of migrations. The version of each
+// store's schema is recorded in the schema_version table of its namespace, and
+// migrations are applied while holding an advisory lock so that concurrent
+// attempts to provision the same namespace are serialized.
+//
+// Migrations are templates that refer to tables and indexes by their
+// unprefixed names, which are resolved within the configured namespace before
+// the migration is executed. See [Migration.Render].
+package pgmigrate
@@ -0,0 +1,411 @@
+package pgmigrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgerror"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgnamespace"
+)
+
+// Migration is a single change to the schema of a store.
+type Migration struct {
+	// Version is the version of the schema after the migration is applied.
+	Version int
+
+	// Name is a short description of the migration.
+	Name string
+
+	// DDL is the SQL executed to apply the migration.
+	//
+	// It is a [text/template] that refers to the objects within the namespace
+	// using the "schema", "table" and "index" functions, for example
+	// {{table "keyspace"}}. See [Migration.Render].
+	DDL string
+}
+
+// Render returns the DDL of the migration with the names of the objects that
+// it refers to resolved within the namespace ns.
+func (m Migration) Render(ns pgnamespace.Namespace) (string, error) {
+	t, err := parse(m.DDL, ns)
+	if err != nil {
+		return "", err
+	}
+
+	var w strings.Builder
+	if err := t.Execute(&w, nil); err != nil {
+		return "", err
+	}
+
+	return w.String(), nil
+}
+
+// parse parses ddl as a template that resolves names within ns.
+func parse(ddl string, ns pgnamespace.Namespace) (*template.Template, error) {
+	return template.
+		New("").
+		Funcs(template.FuncMap{
+			"schema": ns.SchemaName,
+			"table":  ns.Table,
+			"index":  ns.Index,
+		}).
+		Parse(ddl)
+}
+
+// Schema is the versioned schema of a single store.
+type Schema struct {
+	// Store is the name of the store, which identifies its schema in the
+	// schema_version table.
+	Store string
+
+	// Migrations is the ordered sequence of migrations that produce the latest
+	// version of the schema. The first migration produces version 1.
+	Migrations []Migration
+}
+
+// ErrSchemaTooNew indicates that a store's schema has been migrated to a
+// version that is newer than the running code supports.
+var ErrSchemaTooNew = errors.New("schema version is newer than supported")
+
+// versionTable is the migration that creates the schema_version table.
+var versionTable = Migration{
+	DDL: `
+CREATE SCHEMA IF NOT EXISTS {{schema}};
+
+CREATE TABLE
+    IF NOT EXISTS {{table "schema_version"}} (
+        store TEXT NOT NULL,
+        version INTEGER NOT NULL,
+        migrated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+        PRIMARY KEY (store)
+    );
+`,
+}
+
+// Load returns the schema of the named store. The migrations are read from
+// the .sql files in the "migrations" directory of fsys, each of which must be
+// named "<version>_<name>.sql", such as "0001_initial.sql".
+//
+// It panics if the migrations are not valid templates, or their versions are
+// not contiguous starting at 1.
+func Load(store string, fsys fs.FS) Schema {
+	entries, err := fs.ReadDir(fsys, "migrations")
+	if err != nil {
+		panic(fmt.Sprintf("unable to read %s migrations: %s", store, err))
+	}
+
+	s := Schema{Store: store}
+
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".sql")
+		if !ok {
+			continue
+		}
+
+		v, name, _ := strings.Cut(name, "_")
+
+		version, err := strconv.Atoi(v)
+		if err != nil || version != len(s.Migrations)+1 {
+			panic(fmt.Sprintf("unexpected %s migration %q, expected version %d", store, e.Name(), len(s.Migrations)+1))
+		}
+
+		ddl, err := fs.ReadFile(fsys, path.Join("migrations", e.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("unable to read %s migration %q: %s", store, e.Name(), err))
+		}
+
+		if _, err := parse(string(ddl), pgnamespace.Namespace{}); err != nil {
+			panic(fmt.Sprintf("unable to parse %s migration %q: %s", store, e.Name(), err))
+		}
+
+		s.Migrations = append(
+			s.Migrations,
+			Migration{
+				Version: version,
+				Name:    name,
+				DDL:     string(ddl),
+			},
+		)
+	}
+
+	if len(s.Migrations) == 0 {
+		panic(fmt.Sprintf("no %s migrations found", store))
+	}
+
+	return s
+}
+
+// Version returns the latest version of the schema.
+func (s Schema) Version() int {
+	return len(s.Migrations)
+}
+
+// CurrentVersion returns the version of the schema that has been applied to
+// the namespace ns in db, or zero if no migrations have been applied.
+func (s Schema) CurrentVersion(
+	ctx context.Context,
+	db *sql.DB,
+	ns pgnamespace.Namespace,
+) (int, error) {
+	if err := ns.Validate(); err != nil {
+		return 0, err
+	}
+	return currentVersion(ctx, db, ns, s.Store)
+}
+
+// Pending returns the migrations that have not yet been applied to the
+// namespace ns in db.
+//
+// It returns an error that wraps [ErrSchemaTooNew] if the schema in db is
+// newer than s.
+func (s Schema) Pending(
+	ctx context.Context,
+	db *sql.DB,
+	ns pgnamespace.Namespace,
+) ([]Migration, error) {
+	v, err := s.CurrentVersion(ctx, db, ns)
+	if err != nil {
+		return nil, err
+	}
+	return s.pending(v)
+}
+
+// PendingDDL returns the SQL that [Schema.Migrate] would execute to apply the
+// pending migrations, or an empty string if the schema is up to date.
+func (s Schema) PendingDDL(
+	ctx context.Context,
+	db *sql.DB,
+	ns pgnamespace.Namespace,
+) (string, error) {
+	pending, err := s.Pending(ctx, db, ns)
+	if err != nil || len(pending) == 0 {
+		return "", err
+	}
+
+	var w strings.Builder
+	for _, m := range pending {
+		ddl, err := m.Render(ns)
+		if err != nil {
+			return "", fmt.Errorf("cannot render %s migration %d (%s): %w", s.Store, m.Version, m.Name, err)
+		}
+
+		fmt.Fprintf(&w, "-- %s migration %d: %s\n", s.Store, m.Version, m.Name)
+		w.WriteString(strings.TrimSpace(ddl))
+		w.WriteString("\n")
+	}
+
+	return w.String(), nil
+}
+
+// Migrate applies any pending migrations to the namespace ns in db.
+//
+// The migrations are applied in a single transaction, while holding an
+// advisory lock that serializes concurrent migrations of any store within the
+// same namespace. If the schema is already up to date, no DDL is executed, so
+// the database user does not require permission to modify the schema.
+//
+// It returns an error that wraps [ErrSchemaTooNew] if the schema in db is
+// newer than s.
+func (s Schema) Migrate(
+	ctx context.Context,
+	db *sql.DB,
+	ns pgnamespace.Namespace,
+) error {
+	pending, err := s.Pending(ctx, db, ns)
+	if err != nil || len(pending) == 0 {
+		return err
+	}
+
+	return pgerror.Retry(
+		ctx,
+		db,
+		func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(
+				ctx,
+				`SELECT pg_advisory_xact_lock(hashtext($1))`,
+				ns.Table("schema_version"),
+			); err != nil {
+				return fmt.Errorf("cannot acquire schema migration lock: %w", err)
+			}
+
+			ddl, err := versionTable.Render(ns)
+			if err != nil {
+				return fmt.Errorf("cannot render schema version table: %w", err)
+			}
+
+			if _, err := tx.ExecContext(ctx, ddl); err != nil {
+				return fmt.Errorf("cannot create schema version table: %w", err)
+			}
+
+			// Re-read the version now that the lock is held, as another
+			// process may have applied some or all of the migrations.
+			var v int
+			if err := tx.QueryRowContext(
+				ctx,
+				`SELECT COALESCE(
+					(SELECT version FROM `+ns.Table("schema_version")+` WHERE store = $1),
+					0
+				)`,
+				s.Store,
+			).Scan(&v); err != nil {
+				return fmt.Errorf("cannot read %s schema version: %w", s.Store, err)
+			}
+
+			pending, err := s.pending(v)
+			if err != nil {
+				return err
+			}
+
+			for _, m := range pending {
+				ddl, err := m.Render(ns)
+				if err != nil {
+					return fmt.Errorf("cannot render %s migration %d (%s): %w", s.Store, m.Version, m.Name, err)
+				}
+
+				if _, err := tx.ExecContext(ctx, ddl); err != nil {
+					return fmt.Errorf("cannot apply %s migration %d (%s): %w", s.Store, m.Version, m.Name, err)
+				}
+			}
+
+			if _, err := tx.ExecContext(
+				ctx,
+				`INSERT INTO `+ns.Table("schema_version")+` (
+					store,
+					version
+				) VALUES (
+					$1,
+					$2
+				) ON CONFLICT (store) DO UPDATE SET
+					version = EXCLUDED.version,
+					migrated_at = now()`,
+				s.Store,
+				s.Version(),
+			); err != nil {
+				return fmt.Errorf("cannot record %s schema version: %w", s.Store, err)
+			}
+
+			return nil
+		},
+		// The advisory lock serializes migrations performed by this package,
+		// but the IF NOT EXISTS clauses in the DDL may still conflict with
+		// schemas created by earlier versions that did not take the lock.
+		pgerror.CodeUniqueViolation,
+	)
+}
+
+// pending returns the migrations that must be applied to a schema at version
+// v.
+func (s Schema) pending(v int) ([]Migration, error) {
+	if v > s.Version() {
+		return nil, fmt.Errorf(
+			"the %s schema is at version %d, but only versions up to %d are supported: %w",
+			s.Store,
+			v,
+			s.Version(),
+			ErrSchemaTooNew,
+		)
+	}
+	return s.Migrations[v:], nil
+}
+
+// Guard prevents a store from operating on a schema that is newer than it
+// supports. The zero value is ready to use.
+type Guard struct {
+	checkedAt atomic.Int64
+}
+
+// guardTTL is the amount of time for which a [Guard] trusts a successful
+// check before querying the database again, so that a schema migrated by a
+// newer version of the store is eventually detected.
+const guardTTL = 1 * time.Minute
+
+// Check returns an error that wraps [ErrSchemaTooNew] if the schema in the
+// namespace ns in db is newer than s, or if ns is invalid.
+//
+// A successful check is only cached if the schema is exactly at the latest
+// version of s, and only for a limited time.
+func (g *Guard) Check(
+	ctx context.Context,
+	db *sql.DB,
+	s Schema,
+	ns pgnamespace.Namespace,
+) error {
+	if t := g.checkedAt.Load(); t != 0 && time.Since(time.Unix(0, t)) < guardTTL {
+		return nil
+	}
+
+	v, err := s.CurrentVersion(ctx, db, ns)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.pending(v); err != nil {
+		return err
+	}
+
+	if v == s.Version() {
+		g.checkedAt.Store(time.Now().UnixNano())
+	}
+
+	return nil
+}
+
+// Migrate is like [Guard.Check], except that it also applies any pending
+// migrations, so that a store that has already been provisioned can begin
+// using a newer version of its schema without being provisioned again.
+//
+// If the schema is up to date, no DDL is executed.
+func (g *Guard) Migrate(
+	ctx context.Context,
+	db *sql.DB,
+	s Schema,
+	ns pgnamespace.Namespace,
+) error {
+	if t := g.checkedAt.Load(); t != 0 && time.Since(time.Unix(0, t)) < guardTTL {
+		return nil
+	}
+
+	if err := s.Migrate(ctx, db, ns); err != nil {
+		return err
+	}
+
+	g.checkedAt.Store(time.Now().UnixNano())
+
+	return nil
+}
+
+// currentVersion returns the version of the named store's schema within ns,
+// or zero if no migrations have been applied.
+func currentVersion(
+	ctx context.Context,
+	db *sql.DB,
+	ns pgnamespace.Namespace,
+	store string,
+) (int, error) {
+	var v int
+
+	err := db.QueryRowContext(
+		ctx,
+		`SELECT version
+		FROM `+ns.Table("schema_version")+`
+		WHERE store = $1`,
+		store,
+	).Scan(&v)
+
+	if errors.Is(err, sql.ErrNoRows) || pgerror.Is(err, pgerror.CodeUndefinedTable) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("cannot read %s schema version: %w", store, err)
+	}
+
+	return v, nil
+}
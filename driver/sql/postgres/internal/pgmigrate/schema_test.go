@@ -0,0 +1,301 @@
+package pgmigrate_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgmigrate"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgnamespace"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgtest"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("it loads migrations in version order", func(t *testing.T) {
+		s := Load(
+			"<store>",
+			fstest.MapFS{
+				"migrations/0002_second.sql": {Data: []byte("<ddl 2>")},
+				"migrations/0001_first.sql":  {Data: []byte("<ddl 1>")},
+				"migrations/README.md":       {Data: []byte("<ignored>")},
+			},
+		)
+
+		if s.Version() != 2 {
+			t.Fatalf("unexpected version: got %d, want 2", s.Version())
+		}
+
+		for i, want := range []Migration{
+			{Version: 1, Name: "first", DDL: "<ddl 1>"},
+			{Version: 2, Name: "second", DDL: "<ddl 2>"},
+		} {
+			if got := s.Migrations[i]; got != want {
+				t.Fatalf("unexpected migration: got %+v, want %+v", got, want)
+			}
+		}
+	})
+
+	cases := []struct {
+		Name string
+		FS   fstest.MapFS
+	}{
+		{
+			"there are no migrations",
+			fstest.MapFS{},
+		},
+		{
+			"a version is missing",
+			fstest.MapFS{
+				"migrations/0001_first.sql": {},
+				"migrations/0003_third.sql": {},
+			},
+		},
+		{
+			"the first version is not 1",
+			fstest.MapFS{
+				"migrations/0002_second.sql": {},
+			},
+		},
+		{
+			"a version is not numeric",
+			fstest.MapFS{
+				"migrations/first.sql": {},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run("it panics if "+c.Name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected a panic")
+				}
+			}()
+
+			Load("<store>", c.FS)
+		})
+	}
+}
+
+func TestMigration_Render(t *testing.T) {
+	m := Migration{
+		DDL: `CREATE SCHEMA IF NOT EXISTS {{schema}};
+CREATE TABLE IF NOT EXISTS {{table "queue"}} (id BIGSERIAL NOT NULL);
+CREATE INDEX IF NOT EXISTS {{index "queue_visible_at"}} ON {{table "queue"}} (id);`,
+	}
+
+	ns := pgnamespace.Namespace{
+		Schema:      "tenant",
+		TablePrefix: "app_",
+	}
+
+	got, err := m.Render(ns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `CREATE SCHEMA IF NOT EXISTS tenant;
+CREATE TABLE IF NOT EXISTS tenant.app_queue (id BIGSERIAL NOT NULL);
+CREATE INDEX IF NOT EXISTS app_queue_visible_at ON tenant.app_queue (id);`
+
+	if got != want {
+		t.Fatalf("unexpected DDL:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSchema_Migrate(t *testing.T) {
+	var ns pgnamespace.Namespace
+
+	v1 := Schema{
+		Store: "test",
+		Migrations: []Migration{
+			{1, "create", `CREATE TABLE IF NOT EXISTS {{table "migrate_test"}} (id INTEGER)`},
+		},
+	}
+
+	v2 := Schema{
+		Store: "test",
+		Migrations: append(
+			v1.Migrations,
+			Migration{2, "alter", `ALTER TABLE {{table "migrate_test"}} ADD COLUMN name TEXT`},
+		),
+	}
+
+	t.Run("it applies pending migrations", func(t *testing.T) {
+		db, _ := pgtest.Setup(t)
+
+		if err := v1.Migrate(t.Context(), db, ns); err != nil {
+			t.Fatal(err)
+		}
+
+		ddl, err := v2.PendingDDL(t.Context(), db, ns)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := "-- test migration 2: alter\nALTER TABLE persistencekit.migrate_test ADD COLUMN name TEXT\n"
+		if ddl != want {
+			t.Fatalf("unexpected DDL:\ngot:\n%s\nwant:\n%s", ddl, want)
+		}
+
+		if err := v2.Migrate(t.Context(), db, ns); err != nil {
+			t.Fatal(err)
+		}
+
+		v, err := v2.CurrentVersion(t.Context(), db, ns)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != 2 {
+			t.Fatalf("unexpected version: got %d, want 2", v)
+		}
+
+		if _, err := db.ExecContext(
+			t.Context(),
+			`INSERT INTO persistencekit.migrate_test (id, name) VALUES (1, 'one')`,
+		); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("it does nothing if the schema is up to date", func(t *testing.T) {
+		db, _ := pgtest.Setup(t)
+
+		for range 2 {
+			if err := v2.Migrate(t.Context(), db, ns); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		ddl, err := v2.PendingDDL(t.Context(), db, ns)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ddl != "" {
+			t.Fatalf("unexpected DDL: %s", ddl)
+		}
+	})
+
+	t.Run("it serializes concurrent migrations", func(t *testing.T) {
+		db, _ := pgtest.Setup(t)
+
+		var (
+			g    sync.WaitGroup
+			errs = make([]error, 10)
+		)
+
+		for i := range errs {
+			g.Go(func() {
+				errs[i] = v2.Migrate(t.Context(), db, ns)
+			})
+		}
+
+		g.Wait()
+
+		if err := errors.Join(errs...); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("it returns an error if the schema is newer than supported", func(t *testing.T) {
+		db, _ := pgtest.Setup(t)
+
+		if err := v2.Migrate(t.Context(), db, ns); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := v1.Migrate(t.Context(), db, ns); !errors.Is(err, ErrSchemaTooNew) {
+			t.Fatalf("unexpected error: got %v, want %v", err, ErrSchemaTooNew)
+		}
+
+		var g Guard
+		if err := g.Check(t.Context(), db, v1, ns); !errors.Is(err, ErrSchemaTooNew) {
+			t.Fatalf("unexpected error: got %v, want %v", err, ErrSchemaTooNew)
+		}
+
+		if err := g.Check(t.Context(), db, v2, ns); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("it does not cache a check against an unmigrated schema", func(t *testing.T) {
+		db, _ := pgtest.Setup(t)
+
+		var g Guard
+		if err := g.Check(t.Context(), db, v1, ns); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := v2.Migrate(t.Context(), db, ns); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := g.Check(t.Context(), db, v1, ns); !errors.Is(err, ErrSchemaTooNew) {
+			t.Fatalf("unexpected error: got %v, want %v", err, ErrSchemaTooNew)
+		}
+	})
+
+	t.Run("it applies pending migrations when guarding an outdated schema", func(t *testing.T) {
+		db, _ := pgtest.Setup(t)
+
+		if err := v1.Migrate(t.Context(), db, ns); err != nil {
+			t.Fatal(err)
+		}
+
+		var g Guard
+		if err := g.Migrate(t.Context(), db, v2, ns); err != nil {
+			t.Fatal(err)
+		}
+
+		v, err := v2.CurrentVersion(t.Context(), db, ns)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != 2 {
+			t.Fatalf("unexpected version: got %d, want 2", v)
+		}
+	})
+
+	t.Run("it migrates each namespace independently", func(t *testing.T) {
+		db, _ := pgtest.Setup(t)
+
+		other := pgnamespace.Namespace{
+			Schema:      "tenant",
+			TablePrefix: "app_",
+		}
+
+		if err := v2.Migrate(t.Context(), db, ns); err != nil {
+			t.Fatal(err)
+		}
+
+		v, err := v2.CurrentVersion(t.Context(), db, other)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != 0 {
+			t.Fatalf("unexpected version: got %d, want 0", v)
+		}
+
+		if err := v1.Migrate(t.Context(), db, other); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := db.ExecContext(
+			t.Context(),
+			`INSERT INTO tenant.app_migrate_test (id) VALUES (1)`,
+		); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("it returns an error if the namespace is invalid", func(t *testing.T) {
+		invalid := pgnamespace.Namespace{
+			Schema: "Invalid-Schema",
+		}
+
+		if err := v1.Migrate(t.Context(), nil, invalid); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
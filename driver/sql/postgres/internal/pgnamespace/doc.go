@@ -0,0 +1,3 @@
+// Package pgnamespace locates the tables used by the PostgreSQL stores within a
+// configurable schema and table name prefix.
+package pgnamespace
@@ -0,0 +1,73 @@
+package pgnamespace
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// DefaultSchema is the name of the schema that contains the tables used by
+// each store if no other schema is specified.
+const DefaultSchema = "persistencekit"
+
+// Namespace describes where the tables used by a store are located. The zero
+// value places the tables in the [DefaultSchema] without any prefix.
+type Namespace struct {
+	// Schema is the name of the PostgreSQL schema that contains the tables. If
+	// it is empty, [DefaultSchema] is used.
+	Schema string
+
+	// TablePrefix is a prefix added to the name of each table, sequence and
+	// index.
+	TablePrefix string
+}
+
+// identifier matches names that may be used as unquoted PostgreSQL
+// identifiers without being folded to lower case.
+var identifier = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// maxIdentifierLength is the maximum length of a PostgreSQL identifier, in
+// bytes.
+const maxIdentifierLength = 63
+
+// longestName is the length of the longest table, sequence or index name used
+// by any store, excluding the prefix.
+const longestName = len("queue_message_visible_at")
+
+// Validate returns an error if n cannot be used to name PostgreSQL objects.
+//
+// Schema names and prefixes are restricted to lower-case letters, digits and
+// underscores so that they never need to be quoted.
+func (n Namespace) Validate() error {
+	if !identifier.MatchString(n.SchemaName()) || len(n.SchemaName()) > maxIdentifierLength {
+		return fmt.Errorf("invalid schema name %q, expected at most %d lower-case letters, digits or underscores", n.Schema, maxIdentifierLength)
+	}
+
+	if n.TablePrefix != "" {
+		if !identifier.MatchString(n.TablePrefix) || len(n.TablePrefix)+longestName > maxIdentifierLength {
+			return fmt.Errorf("invalid table prefix %q, expected at most %d lower-case letters, digits or underscores", n.TablePrefix, maxIdentifierLength-longestName)
+		}
+	}
+
+	return nil
+}
+
+// SchemaName returns the name of the schema that contains the tables.
+func (n Namespace) SchemaName() string {
+	if n.Schema == "" {
+		return DefaultSchema
+	}
+	return n.Schema
+}
+
+// Table returns the schema-qualified name of the table (or sequence) with the
+// given unprefixed name.
+func (n Namespace) Table(name string) string {
+	return n.SchemaName() + "." + n.TablePrefix + name
+}
+
+// Index returns the name of the index with the given unprefixed name. Index
+// names are not schema-qualified, as an index always belongs to the same
+// schema as its table.
+func (n Namespace) Index(name string) string {
+	return n.TablePrefix + name
+}
@@ -0,0 +1,102 @@
+package pgnamespace_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgnamespace"
+)
+
+func TestNamespace_names(t *testing.T) {
+	cases := []struct {
+		Name      string
+		Namespace Namespace
+		Schema    string
+		Table     string
+		Index     string
+	}{
+		{
+			"default namespace",
+			Namespace{},
+			"persistencekit",
+			"persistencekit.queue",
+			"queue_visible_at",
+		},
+		{
+			"explicit default schema",
+			Namespace{Schema: DefaultSchema},
+			"persistencekit",
+			"persistencekit.queue",
+			"queue_visible_at",
+		},
+		{
+			"custom schema",
+			Namespace{Schema: "tenant_a"},
+			"tenant_a",
+			"tenant_a.queue",
+			"queue_visible_at",
+		},
+		{
+			"custom schema and table prefix",
+			Namespace{Schema: "tenant_a", TablePrefix: "app_"},
+			"tenant_a",
+			"tenant_a.app_queue",
+			"app_queue_visible_at",
+		},
+		{
+			"table prefix only",
+			Namespace{TablePrefix: "app_"},
+			"persistencekit",
+			"persistencekit.app_queue",
+			"app_queue_visible_at",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if got := c.Namespace.SchemaName(); got != c.Schema {
+				t.Fatalf("unexpected schema name: got %q, want %q", got, c.Schema)
+			}
+
+			if got := c.Namespace.Table("queue"); got != c.Table {
+				t.Fatalf("unexpected table name: got %q, want %q", got, c.Table)
+			}
+
+			if got := c.Namespace.Index("queue_visible_at"); got != c.Index {
+				t.Fatalf("unexpected index name: got %q, want %q", got, c.Index)
+			}
+		})
+	}
+}
+
+func TestNamespace_Validate(t *testing.T) {
+	valid := []Namespace{
+		{},
+		{Schema: "tenant_a"},
+		{TablePrefix: "app_"},
+		{Schema: "_tenant_1", TablePrefix: "_1"},
+	}
+
+	for _, ns := range valid {
+		if err := ns.Validate(); err != nil {
+			t.Errorf("%+v: unexpected error: %s", ns, err)
+		}
+	}
+
+	invalid := []Namespace{
+		{Schema: "Tenant"},
+		{Schema: "tenant-a"},
+		{Schema: `tenant"; DROP TABLE x; --`},
+		{Schema: "1tenant"},
+		{Schema: strings.Repeat("x", 64)},
+		{TablePrefix: "App_"},
+		{TablePrefix: "app."},
+		{TablePrefix: strings.Repeat("x", 40)},
+	}
+
+	for _, ns := range invalid {
+		if err := ns.Validate(); err == nil {
+			t.Errorf("%+v: expected an error", ns)
+		}
+	}
+}
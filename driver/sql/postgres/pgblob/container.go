@@ -0,0 +1,252 @@
+package pgblob
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dogmatiq/persistencekit/blob"
+)
+
+// chunkSize is the maximum size of each chunk of blob content, in bytes.
+const chunkSize = 1 << 20
+
+type container struct {
+	store *Store
+	q     *queries
+	id    uint64
+	name  string
+}
+
+func (c *container) Name() string {
+	return c.name
+}
+
+func (c *container) Put(ctx context.Context, r io.Reader) (_ blob.Ref, err error) {
+	var uploadID uint64
+	if err := c.store.DB.QueryRowContext(
+		ctx,
+		c.q.NextUploadID,
+	).Scan(&uploadID); err != nil {
+		return blob.Ref{}, fmt.Errorf("cannot allocate upload ID: %w", err)
+	}
+
+	// Remove the uploaded chunks if the blob is not stored successfully, or
+	// if identical content is already stored.
+	discard := true
+	defer func() {
+		if discard {
+			if e := c.deleteChunks(context.WithoutCancel(ctx), uploadID); e != nil && err == nil {
+				err = e
+			}
+		}
+	}()
+
+	hash := sha256.New()
+	r = io.TeeReader(r, hash)
+
+	var (
+		ref  blob.Ref
+		data = make([]byte, chunkSize)
+	)
+
+	for seq := 0; ; seq++ {
+		n, err := io.ReadFull(r, data)
+		if n != 0 {
+			if _, err := c.store.DB.ExecContext(
+				ctx,
+				c.q.InsertChunk,
+				uploadID,
+				seq,
+				data[:n],
+			); err != nil {
+				return blob.Ref{}, fmt.Errorf("cannot insert blob chunk: %w", err)
+			}
+
+			ref.Size += int64(n)
+		}
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return blob.Ref{}, err
+		}
+	}
+
+	hash.Sum(ref.Hash[:0])
+
+	var existingUploadID uint64
+	if err := c.store.DB.QueryRowContext(
+		ctx,
+		c.q.Insert,
+		c.id,
+		ref.Hash[:],
+		ref.Size,
+		uploadID,
+	).Scan(&existingUploadID); err != nil {
+		return blob.Ref{}, fmt.Errorf("cannot insert blob: %w", err)
+	}
+
+	discard = existingUploadID != uploadID
+
+	return ref, nil
+}
+
+func (c *container) Get(ctx context.Context, h blob.Hash) (io.ReadCloser, bool, error) {
+	var (
+		size     int64
+		uploadID uint64
+	)
+
+	err := c.store.DB.QueryRowContext(
+		ctx,
+		c.q.Get,
+		c.id,
+		h[:],
+	).Scan(&size, &uploadID)
+
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot query blob: %w", err)
+	}
+
+	return &reader{
+		ctx:       ctx,
+		db:        c.store.DB,
+		q:         c.q,
+		uploadID:  uploadID,
+		remaining: size,
+	}, true, nil
+}
+
+func (c *container) Has(ctx context.Context, h blob.Hash) (bool, error) {
+	var ok bool
+
+	if err := c.store.DB.QueryRowContext(
+		ctx,
+		c.q.Has,
+		c.id,
+		h[:],
+	).Scan(&ok); err != nil {
+		return false, fmt.Errorf("cannot query blob: %w", err)
+	}
+
+	return ok, nil
+}
+
+func (c *container) Release(ctx context.Context, h blob.Hash) error {
+	var (
+		refs     int64
+		uploadID uint64
+	)
+
+	err := c.store.DB.QueryRowContext(
+		ctx,
+		c.q.DecrementRefs,
+		c.id,
+		h[:],
+	).Scan(&refs, &uploadID)
+
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot release blob: %w", err)
+	}
+
+	if refs != 0 {
+		return nil
+	}
+
+	// The blob is only removed if it has not been referenced again by a
+	// concurrent call to Put() since the reference count reached zero.
+	res, err := c.store.DB.ExecContext(
+		ctx,
+		c.q.Delete,
+		c.id,
+		h[:],
+	)
+	if err != nil {
+		return fmt.Errorf("cannot delete blob: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("cannot determine affected rows: %w", err)
+	}
+
+	if n == 0 {
+		return nil
+	}
+
+	return c.deleteChunks(ctx, uploadID)
+}
+
+func (c *container) Close() error {
+	return nil
+}
+
+// deleteChunks deletes the chunks that were written by the upload with the
+// given ID.
+func (c *container) deleteChunks(ctx context.Context, uploadID uint64) error {
+	if _, err := c.store.DB.ExecContext(
+		ctx,
+		c.q.DeleteChunks,
+		uploadID,
+	); err != nil {
+		return fmt.Errorf("cannot delete blob chunks: %w", err)
+	}
+
+	return nil
+}
+
+// reader is an [io.ReadCloser] that reads blob content one chunk at a time.
+type reader struct {
+	ctx       context.Context
+	db        *sql.DB
+	q         *queries
+	uploadID  uint64
+	seq       int
+	chunk     []byte
+	remaining int64
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if len(r.chunk) == 0 {
+		if r.remaining == 0 {
+			return 0, io.EOF
+		}
+
+		err := r.db.QueryRowContext(
+			r.ctx,
+			r.q.ReadChunk,
+			r.uploadID,
+			r.seq,
+		).Scan(&r.chunk)
+
+		if err == sql.ErrNoRows {
+			return 0, errors.New("cannot read blob chunk: blob was removed while it was being read")
+		}
+		if err != nil {
+			return 0, fmt.Errorf("cannot read blob chunk: %w", err)
+		}
+
+		r.seq++
+		r.remaining -= int64(len(r.chunk))
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+
+	return n, nil
+}
+
+func (r *reader) Close() error {
+	return nil
+}
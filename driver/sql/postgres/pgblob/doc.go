@@ -0,0 +1,11 @@
+// Package pgblob provides an implementation of [blob.Store] that persists to a
+// PostgreSQL database.
+//
+// Blob content is stored as a sequence of fixed-size BYTEA chunks, such that
+// neither reading nor writing a blob requires the entire blob to be held in
+// memory.
+//
+// Content is written to the database before the hash of the content is known.
+// If a write is interrupted, for example by a process crash, the chunks that
+// were written remain in the database but are never read.
+package pgblob
@@ -0,0 +1,29 @@
+CREATE SCHEMA IF NOT EXISTS {{schema}};
+
+CREATE TABLE
+    IF NOT EXISTS {{table "blob_container"}} (
+        id BIGSERIAL NOT NULL,
+        name TEXT NOT NULL,
+        PRIMARY KEY (id),
+        UNIQUE (name)
+    );
+
+CREATE SEQUENCE IF NOT EXISTS {{table "blob_upload_id"}};
+
+CREATE TABLE
+    IF NOT EXISTS {{table "blob"}} (
+        container_id BIGINT NOT NULL,
+        hash BYTEA NOT NULL,
+        size BIGINT NOT NULL,
+        refs BIGINT NOT NULL,
+        upload_id BIGINT NOT NULL,
+        PRIMARY KEY (container_id, hash)
+    );
+
+CREATE TABLE
+    IF NOT EXISTS {{table "blob_chunk"}} (
+        upload_id BIGINT NOT NULL,
+        seq INTEGER NOT NULL,
+        data BYTEA NOT NULL,
+        PRIMARY KEY (upload_id, seq)
+    );
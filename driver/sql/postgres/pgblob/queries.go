@@ -0,0 +1,100 @@
+package pgblob
+
+import (
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgnamespace"
+)
+
+// queries is the set of SQL statements used by the store, with the names of
+// its tables resolved within a specific namespace.
+type queries struct {
+	ns pgnamespace.Namespace
+
+	InsertContainer string
+	NextUploadID    string
+	InsertChunk     string
+	Insert          string
+	Get             string
+	Has             string
+	DecrementRefs   string
+	Delete          string
+	DeleteChunks    string
+	ReadChunk       string
+}
+
+// newQueries returns the SQL statements used by the store within ns.
+func newQueries(ns pgnamespace.Namespace) *queries {
+	var (
+		blobContainer = ns.Table("blob_container")
+		blob          = ns.Table("blob")
+		blobChunk     = ns.Table("blob_chunk")
+		uploadID      = ns.Table("blob_upload_id")
+	)
+
+	return &queries{
+		ns: ns,
+
+		InsertContainer: `INSERT INTO ` + blobContainer + ` (
+			name
+		) VALUES (
+			$1
+		) ON CONFLICT (name) DO UPDATE SET
+			name = EXCLUDED.name
+		RETURNING id`,
+
+		NextUploadID: `SELECT nextval('` + uploadID + `')`,
+
+		InsertChunk: `INSERT INTO ` + blobChunk + ` (
+			upload_id,
+			seq,
+			data
+		) VALUES (
+			$1, $2, $3
+		)`,
+
+		Insert: `INSERT INTO ` + blob + ` AS b (
+			container_id,
+			hash,
+			size,
+			refs,
+			upload_id
+		) VALUES (
+			$1, $2, $3, 1, $4
+		) ON CONFLICT (container_id, hash) DO UPDATE SET
+			refs = b.refs + 1
+		RETURNING upload_id`,
+
+		Get: `SELECT size, upload_id
+		FROM ` + blob + `
+		WHERE container_id = $1
+		AND hash = $2
+		AND refs > 0`,
+
+		Has: `SELECT EXISTS (
+			SELECT 1
+			FROM ` + blob + `
+			WHERE container_id = $1
+			AND hash = $2
+			AND refs > 0
+		)`,
+
+		DecrementRefs: `UPDATE ` + blob + ` SET
+			refs = refs - 1
+		WHERE container_id = $1
+		AND hash = $2
+		AND refs > 0
+		RETURNING refs, upload_id`,
+
+		Delete: `DELETE FROM ` + blob + `
+		WHERE container_id = $1
+		AND hash = $2
+		AND refs = 0`,
+
+		DeleteChunks: `DELETE FROM ` + blobChunk + `
+		WHERE upload_id = $1`,
+
+		ReadChunk: `SELECT data
+		FROM ` + blobChunk + `
+		WHERE upload_id = $1
+		AND seq = $2`,
+	}
+}
@@ -0,0 +1,19 @@
+package pgblob_test
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/blob"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgtest"
+	. "github.com/dogmatiq/persistencekit/driver/sql/postgres/pgblob"
+)
+
+func TestStore(t *testing.T) {
+	db, _ := pgtest.Setup(t)
+	blob.RunTests(
+		t,
+		&Store{
+			DB: db,
+		},
+	)
+}
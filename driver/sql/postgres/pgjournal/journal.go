@@ -13,6 +13,7 @@ import (
 // database.
 type journ struct {
 	db   *sql.DB
+	q    *queries
 	id   uint64
 	name string
 }
@@ -24,11 +25,7 @@ func (j *journ) Name() string {
 func (j *journ) Bounds(ctx context.Context) (bounds journal.Interval, err error) {
 	row := j.db.QueryRowContext(
 		ctx,
-		`SELECT
-			j.encoded_begin,
-			j.encoded_end
-		FROM persistencekit.journal AS j
-		WHERE j.id = $1`,
+		j.q.Bounds,
 		j.id,
 	)
 
@@ -45,10 +42,7 @@ func (j *journ) Bounds(ctx context.Context) (bounds journal.Interval, err error)
 func (j *journ) Get(ctx context.Context, pos journal.Position) ([]byte, error) {
 	row := j.db.QueryRowContext(
 		ctx,
-		`SELECT record
-		FROM persistencekit.journal_record
-		WHERE journal_id = $1
-		AND encoded_position = $2`,
+		j.q.Get,
 		j.id,
 		bigint.ConvertUnsigned(&pos),
 	)
@@ -76,11 +70,7 @@ func (j *journ) Range(
 	// everything into memory at once.
 	rows, err := j.db.QueryContext(
 		ctx,
-		`SELECT encoded_position, record
-		FROM persistencekit.journal_record
-		WHERE journal_id = $1
-		AND encoded_position >= $2
-		ORDER BY encoded_position`,
+		j.q.Range,
 		j.id,
 		bigint.ConvertUnsigned(&pos),
 	)
@@ -141,12 +131,10 @@ func (j *journ) Append(ctx context.Context, pos journal.Position, rec []byte) er
 
 	res, err := tx.ExecContext(
 		ctx,
-		`UPDATE persistencekit.journal
-		SET encoded_end = encoded_end + 1
-		WHERE id = $1
-		AND encoded_end = $2`,
+		j.q.IncrementEnd,
 		j.id,
 		bigint.ConvertUnsigned(&pos),
+		len(rec),
 	)
 	if err != nil {
 		return fmt.Errorf("cannot update journal bounds: %w", err)
@@ -166,8 +154,7 @@ func (j *journ) Append(ctx context.Context, pos journal.Position, rec []byte) er
 
 	res, err = tx.ExecContext(
 		ctx,
-		`INSERT INTO persistencekit.journal_record
-		(journal_id, encoded_position, record) VALUES ($1, $2, $3)`,
+		j.q.InsertRecord,
 		j.id,
 		bigint.ConvertUnsigned(&pos),
 		rec,
@@ -192,10 +179,7 @@ func (j *journ) Truncate(ctx context.Context, pos journal.Position) error {
 
 	res, err := tx.ExecContext(
 		ctx,
-		`UPDATE persistencekit.journal
-		SET encoded_begin = $2
-		WHERE id = $1
-		AND encoded_begin < $2`,
+		j.q.SetBegin,
 		j.id,
 		bigint.ConvertUnsigned(&pos),
 	)
@@ -213,9 +197,7 @@ func (j *journ) Truncate(ctx context.Context, pos journal.Position) error {
 
 	if _, err := tx.ExecContext(
 		ctx,
-		`DELETE FROM persistencekit.journal_record
-		WHERE journal_id = $1
-		AND encoded_position < $2`,
+		j.q.DeleteRecords,
 		j.id,
 		bigint.ConvertUnsigned(&pos),
 	); err != nil {
@@ -229,6 +211,31 @@ func (j *journ) Truncate(ctx context.Context, pos journal.Position) error {
 	return nil
 }
 
+func (j *journ) Stats(ctx context.Context) (journal.Stats, error) {
+	row := j.db.QueryRowContext(
+		ctx,
+		j.q.Stats,
+		j.id,
+	)
+
+	var (
+		bounds journal.Interval
+		bytes  uint64
+	)
+	if err := row.Scan(
+		bigint.ConvertUnsigned(&bounds.Begin),
+		bigint.ConvertUnsigned(&bounds.End),
+		&bytes,
+	); err != nil {
+		return journal.Stats{}, fmt.Errorf("cannot query journal statistics: %w", err)
+	}
+
+	return journal.Stats{
+		Records: uint64(bounds.Len()),
+		Bytes:   bytes,
+	}, nil
+}
+
 func (j *journ) Close() error {
 	return nil
 }
@@ -1,7 +1,7 @@
-CREATE SCHEMA IF NOT EXISTS persistencekit;
+CREATE SCHEMA IF NOT EXISTS {{schema}};
 
 CREATE TABLE
-    IF NOT EXISTS persistencekit.journal (
+    IF NOT EXISTS {{table "journal"}} (
         id BIGSERIAL NOT NULL,
         name TEXT NOT NULL,
         encoded_begin BIGINT NOT NULL DEFAULT -1::BIGINT << 63, -- see `bigint` package
@@ -11,7 +11,7 @@ CREATE TABLE
     );
 
 CREATE TABLE
-    IF NOT EXISTS persistencekit.journal_record (
+    IF NOT EXISTS {{table "journal_record"}} (
         journal_id BIGINT NOT NULL,
         encoded_position BIGINT NOT NULL, -- see `bigint` package
         record BYTEA NOT NULL,
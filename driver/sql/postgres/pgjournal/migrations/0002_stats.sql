@@ -0,0 +1,12 @@
+-- record_bytes is the total size of the journal's records. It is maintained
+-- within the same transaction as each append and truncation so that the
+-- journal's statistics do not require scanning its records.
+ALTER TABLE {{table "journal"}}
+    ADD COLUMN IF NOT EXISTS record_bytes BIGINT NOT NULL DEFAULT 0;
+
+UPDATE {{table "journal"}} AS j SET
+    record_bytes = (
+        SELECT COALESCE(SUM(octet_length(r.record)), 0)
+        FROM {{table "journal_record"}} AS r
+        WHERE r.journal_id = j.id
+    );
@@ -0,0 +1,97 @@
+package pgjournal
+
+import (
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgnamespace"
+)
+
+// queries is the set of SQL statements used by the store, with the names of
+// its tables resolved within a specific namespace.
+type queries struct {
+	ns pgnamespace.Namespace
+
+	InsertJournal string
+	Names         string
+	Bounds        string
+	Get           string
+	Range         string
+	IncrementEnd  string
+	InsertRecord  string
+	SetBegin      string
+	DeleteRecords string
+	Stats         string
+}
+
+// newQueries returns the SQL statements used by the store within ns.
+func newQueries(ns pgnamespace.Namespace) *queries {
+	var (
+		journal       = ns.Table("journal")
+		journalRecord = ns.Table("journal_record")
+	)
+
+	return &queries{
+		ns: ns,
+
+		InsertJournal: `INSERT INTO ` + journal + ` (
+			name
+		) VALUES (
+			$1
+		) ON CONFLICT (name) DO UPDATE SET
+			name = EXCLUDED.name
+		RETURNING id`,
+
+		Names: `SELECT name
+		FROM ` + journal,
+
+		Bounds: `SELECT
+			j.encoded_begin,
+			j.encoded_end
+		FROM ` + journal + ` AS j
+		WHERE j.id = $1`,
+
+		Get: `SELECT record
+		FROM ` + journalRecord + `
+		WHERE journal_id = $1
+		AND encoded_position = $2`,
+
+		Range: `SELECT encoded_position, record
+		FROM ` + journalRecord + `
+		WHERE journal_id = $1
+		AND encoded_position >= $2
+		ORDER BY encoded_position`,
+
+		IncrementEnd: `UPDATE ` + journal + `
+		SET
+			encoded_end = encoded_end + 1,
+			record_bytes = record_bytes + $3
+		WHERE id = $1
+		AND encoded_end = $2`,
+
+		InsertRecord: `INSERT INTO ` + journalRecord + `
+		(journal_id, encoded_position, record) VALUES ($1, $2, $3)`,
+
+		SetBegin: `UPDATE ` + journal + `
+		SET encoded_begin = $2
+		WHERE id = $1
+		AND encoded_begin < $2`,
+
+		DeleteRecords: `WITH deleted AS (
+			DELETE FROM ` + journalRecord + `
+			WHERE journal_id = $1
+			AND encoded_position < $2
+			RETURNING octet_length(record) AS size
+		)
+		UPDATE ` + journal + `
+		SET record_bytes = record_bytes - (
+			SELECT COALESCE(SUM(size), 0)
+			FROM deleted
+		)
+		WHERE id = $1`,
+
+		Stats: `SELECT
+			encoded_begin,
+			encoded_end,
+			record_bytes
+		FROM ` + journal + `
+		WHERE id = $1`,
+	}
+}
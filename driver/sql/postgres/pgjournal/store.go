@@ -4,8 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
+	"sync/atomic"
 
 	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgerror"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgmigrate"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgnamespace"
 	"github.com/dogmatiq/persistencekit/journal"
 )
 
@@ -14,6 +18,17 @@ import (
 type BinaryStore struct {
 	// DB is the PostgreSQL database connection.
 	DB *sql.DB
+
+	// Schema is the name of the PostgreSQL schema that contains the store's
+	// tables. If it is empty, the "persistencekit" schema is used.
+	Schema string
+
+	// TablePrefix is an optional prefix added to the name of each of the
+	// store's tables.
+	TablePrefix string
+
+	guard    pgmigrate.Guard
+	prepared atomic.Pointer[queries]
 }
 
 // Open returns the journal with the given name.
@@ -22,20 +37,57 @@ func (s *BinaryStore) Open(ctx context.Context, name string) (journal.BinaryJour
 	if err != nil {
 		return nil, err
 	}
-	return &journ{s.DB, id, name}, nil
+	return &journ{s.DB, s.queries(), id, name}, nil
+}
+
+// Capabilities returns the limits and optional features of the store.
+func (s *BinaryStore) Capabilities() journal.Capabilities {
+	return journal.Capabilities{
+		// PostgreSQL limits BYTEA values to 1 GiB.
+		MaxRecordSize: 1<<30 - 1,
+	}
+}
+
+// Names returns the names of the journals that have been opened, in lexical
+// order.
+func (s *BinaryStore) Names(ctx context.Context) ([]string, error) {
+	rows, err := s.DB.QueryContext(ctx, s.queries().Names)
+	if pgerror.Is(err, pgerror.CodeUndefinedTable) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot query journal names: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("cannot scan journal name: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cannot query journal names: %w", err)
+	}
+
+	slices.Sort(names)
+
+	return names, nil
 }
 
 func (s *BinaryStore) getID(ctx context.Context, name string) (uint64, error) {
-	for {
+	if err := s.guard.Migrate(ctx, s.DB, schema, s.namespace()); err != nil {
+		return 0, err
+	}
+
+	for provisioned := false; ; provisioned = true {
 		row := s.DB.QueryRowContext(
 			ctx,
-			`INSERT INTO persistencekit.journal (
-				name
-			) VALUES (
-				$1
-			) ON CONFLICT (name) DO UPDATE SET
-				name = EXCLUDED.name
-			RETURNING id`,
+			s.queries().InsertJournal,
 			name,
 		)
 
@@ -46,7 +98,7 @@ func (s *BinaryStore) getID(ctx context.Context, name string) (uint64, error) {
 			return id, nil
 		}
 
-		if !pgerror.Is(err, pgerror.CodeUndefinedTable) {
+		if !pgerror.Is(err, pgerror.CodeUndefinedTable) || provisioned {
 			return 0, fmt.Errorf("cannot scan journal ID: %w", err)
 		}
 
@@ -55,3 +107,26 @@ func (s *BinaryStore) getID(ctx context.Context, name string) (uint64, error) {
 		}
 	}
 }
+
+// queries returns the SQL statements used by the store, preparing them if
+// necessary.
+func (s *BinaryStore) queries() *queries {
+	ns := s.namespace()
+
+	if q := s.prepared.Load(); q != nil && q.ns == ns {
+		return q
+	}
+
+	q := newQueries(ns)
+	s.prepared.Store(q)
+
+	return q
+}
+
+// namespace returns the location of the store's tables.
+func (s *BinaryStore) namespace() pgnamespace.Namespace {
+	return pgnamespace.Namespace{
+		Schema:      s.Schema,
+		TablePrefix: s.TablePrefix,
+	}
+}
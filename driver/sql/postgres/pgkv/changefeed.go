@@ -0,0 +1,121 @@
+package pgkv
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/bigint"
+	"github.com/dogmatiq/persistencekit/internal/kvrevision"
+	"github.com/dogmatiq/persistencekit/kv"
+)
+
+// changeFeed is an implementation of [kv.BinaryChangeFeed] that reads the
+// changes recorded in the keyspace_change table.
+type changeFeed struct {
+	db   *sql.DB
+	q    *queries
+	id   uint64
+	name string
+}
+
+func (f *changeFeed) Name() string {
+	return f.name
+}
+
+func (f *changeFeed) End(ctx context.Context) (kv.Cursor, error) {
+	row := f.db.QueryRowContext(
+		ctx,
+		f.q.ChangeEnd,
+		f.id,
+	)
+
+	var end kv.Cursor
+	if err := row.Scan(
+		bigint.ConvertUnsigned(&end),
+	); err != nil {
+		return 0, fmt.Errorf("cannot scan change feed end: %w", err)
+	}
+
+	return end, nil
+}
+
+func (f *changeFeed) Range(ctx context.Context, c kv.Cursor, fn kv.BinaryChangeFunc) error {
+	// The end is read before the changes so that every change before it is
+	// known to have been committed. Any such change that is not returned by
+	// the query must have been discarded.
+	end, err := f.End(ctx)
+	if err != nil {
+		return err
+	}
+
+	if c >= end {
+		return nil
+	}
+
+	rows, err := f.db.QueryContext(
+		ctx,
+		f.q.RangeChanges,
+		f.id,
+		bigint.ConvertUnsigned(&c),
+		bigint.ConvertUnsigned(&end),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot query keyspace changes: %w", err)
+	}
+	defer rows.Close()
+
+	next := c
+
+	for rows.Next() {
+		var (
+			cursor kv.Cursor
+			ch     kv.BinaryChange
+			gen    uint64
+		)
+		if err := rows.Scan(
+			bigint.ConvertUnsigned(&cursor),
+			&ch.Key,
+			&ch.Value,
+			bigint.ConvertUnsigned(&gen),
+		); err != nil {
+			return fmt.Errorf("cannot scan keyspace change: %w", err)
+		}
+
+		if cursor != next {
+			return kv.ChangeNotFoundError{
+				Keyspace: f.name,
+				Cursor:   next,
+			}
+		}
+		next++
+
+		if gen == 0 {
+			ch.Deleted = true
+		} else {
+			ch.Revision = kvrevision.MarshalGeneration(gen)
+		}
+
+		ok, err := fn(ctx, cursor, ch)
+		if !ok || err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("cannot range over keyspace changes: %w", err)
+	}
+
+	if next != end {
+		return kv.ChangeNotFoundError{
+			Keyspace: f.name,
+			Cursor:   next,
+		}
+	}
+
+	return nil
+}
+
+func (f *changeFeed) Close() error {
+	return nil
+}
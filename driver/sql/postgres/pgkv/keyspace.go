@@ -12,6 +12,7 @@ import (
 
 type keyspace struct {
 	db   *sql.DB
+	q    *queries
 	id   uint64
 	name string
 }
@@ -23,10 +24,7 @@ func (ks *keyspace) Name() string {
 func (ks *keyspace) Get(ctx context.Context, k []byte) (v []byte, r kv.Revision, err error) {
 	row := ks.db.QueryRowContext(
 		ctx,
-		`SELECT value, encoded_generation
-		FROM persistencekit.keyspace_pair
-		WHERE keyspace_id = $1
-		AND key = $2`,
+		ks.q.Get,
 		ks.id,
 		k,
 	)
@@ -48,10 +46,7 @@ func (ks *keyspace) Get(ctx context.Context, k []byte) (v []byte, r kv.Revision,
 func (ks *keyspace) Has(ctx context.Context, k []byte) (bool, error) {
 	row := ks.db.QueryRowContext(
 		ctx,
-		`SELECT COUNT(key) != 0
-		FROM persistencekit.keyspace_pair
-		WHERE keyspace_id = $1
-		AND key = $2`,
+		ks.q.Has,
 		ks.id,
 		k,
 	)
@@ -65,92 +60,95 @@ func (ks *keyspace) Has(ctx context.Context, k []byte) (bool, error) {
 }
 
 func (ks *keyspace) Set(ctx context.Context, k, v []byte, r kv.Revision) (kv.Revision, error) {
-	next, ok, err := ks.set(ctx, v, k, r)
+	next, ok, current, err := ks.set(ctx, v, k, r)
 	if ok || err != nil {
 		return next, err
 	}
 
-	return "", kv.ConflictError[[]byte]{
+	conflict := kv.ConflictError[[]byte, []byte]{
 		Keyspace: ks.name,
 		Key:      k,
 		Revision: r,
 	}
+
+	// The current pair is read from the same snapshot as the failed write. If
+	// it matches the supplied revision, the write must have conflicted with a
+	// concurrent transaction that is not visible in that snapshot, so we can't
+	// report the current state reliably.
+	if current.Revision != r {
+		conflict.CurrentValue = current.Value
+		conflict.CurrentRevision = current.Revision
+		conflict.CurrentKnown = true
+	}
+
+	return "", conflict
+}
+
+// pair is a key/value pair as observed by a conditional write.
+type pair struct {
+	Value    []byte
+	Revision kv.Revision
 }
 
 // set inserts, updates, or deletes a key/value pair based on the provided value
 // and revision.
 //
-// It returns the new revision and true on success, or empty and false on
-// conflict.
-func (ks *keyspace) set(ctx context.Context, v []byte, k []byte, r kv.Revision) (kv.Revision, bool, error) {
+// It returns the new revision and true on success. On conflict it returns
+// false and the pair as it existed when the write was attempted.
+func (ks *keyspace) set(ctx context.Context, v []byte, k []byte, r kv.Revision) (kv.Revision, bool, pair, error) {
 	isDelete := len(v) == 0
 	isNew := r == ""
 
 	if isDelete && isNew {
-		exists, err := ks.Has(ctx, k)
-		return "", !exists, err
+		v, r, err := ks.Get(ctx, k)
+		return "", v == nil, pair{v, r}, err
 	}
 
 	gen, ok := kvrevision.TryUnmarshalGeneration(r)
 	if !ok {
-		return "", false, nil
+		v, r, err := ks.Get(ctx, k)
+		return "", false, pair{v, r}, err
 	}
 
 	if isDelete {
-		ok, err := ks.execOne(
+		ok, current, err := ks.execConditional(
 			ctx,
-			`DELETE FROM persistencekit.keyspace_pair
-			WHERE keyspace_id = $1
-			AND key = $2
-			AND encoded_generation = $3`,
+			ks.q.DeleteConditional,
 			ks.id,
 			k,
 			bigint.ConvertUnsigned(&gen),
 		)
-		return "", ok, err
+		return "", ok, current, err
 	}
 
 	if isNew {
-		ok, err := ks.execOne(
+		ok, current, err := ks.execConditional(
 			ctx,
-			`INSERT INTO persistencekit.keyspace_pair AS o (
-				keyspace_id,
-				key,
-				value
-			) VALUES (
-				$1, $2, $3
-			) ON CONFLICT (keyspace_id, key) DO NOTHING`,
+			ks.q.InsertConditional,
 			ks.id,
 			k,
 			v,
 		)
-		return kvrevision.MarshalGeneration(1), ok, err
+		return kvrevision.MarshalGeneration(1), ok, current, err
 	}
 
-	ok, err := ks.execOne(
+	ok, current, err := ks.execConditional(
 		ctx,
-		`UPDATE persistencekit.keyspace_pair SET
-			value = $3,
-			encoded_generation = encoded_generation + 1
-		WHERE keyspace_id = $1
-			AND key = $2
-			AND encoded_generation = $4`,
+		ks.q.UpdateConditional,
 		ks.id,
 		k,
 		v,
 		bigint.ConvertUnsigned(&gen),
 	)
 
-	return kvrevision.MarshalGeneration(gen + 1), ok, err
+	return kvrevision.MarshalGeneration(gen + 1), ok, current, err
 }
 
 func (ks *keyspace) SetUnconditional(ctx context.Context, k, v []byte) error {
 	if len(v) == 0 {
 		_, err := ks.db.ExecContext(
 			ctx,
-			`DELETE FROM persistencekit.keyspace_pair
-			WHERE keyspace_id = $1
-			AND key = $2`,
+			ks.q.DeleteUnconditional,
 			ks.id,
 			k,
 		)
@@ -159,15 +157,7 @@ func (ks *keyspace) SetUnconditional(ctx context.Context, k, v []byte) error {
 
 	_, err := ks.db.ExecContext(
 		ctx,
-		`INSERT INTO persistencekit.keyspace_pair AS p (
-			keyspace_id,
-			key,
-			value
-		) VALUES (
-			$1, $2, $3
-		) ON CONFLICT (keyspace_id, key) DO UPDATE SET
-			value = EXCLUDED.value,
-			encoded_generation = p.encoded_generation + 1`,
+		ks.q.UpsertUnconditional,
 		ks.id,
 		k,
 		v,
@@ -178,9 +168,7 @@ func (ks *keyspace) SetUnconditional(ctx context.Context, k, v []byte) error {
 func (ks *keyspace) Range(ctx context.Context, fn kv.BinaryRangeFunc) error {
 	rows, err := ks.db.QueryContext(
 		ctx,
-		`SELECT key, value, encoded_generation
-		FROM persistencekit.keyspace_pair
-		WHERE keyspace_id = $1`,
+		ks.q.Range,
 		ks.id,
 	)
 	if err != nil {
@@ -214,25 +202,90 @@ func (ks *keyspace) Range(ctx context.Context, fn kv.BinaryRangeFunc) error {
 	return nil
 }
 
+func (ks *keyspace) RangeKeys(ctx context.Context, fn kv.BinaryKeyRangeFunc) error {
+	rows, err := ks.db.QueryContext(
+		ctx,
+		ks.q.RangeKeys,
+		ks.id,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot query keyspace keys: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			k   []byte
+			gen uint64
+		)
+		if err := rows.Scan(
+			&k,
+			bigint.ConvertUnsigned(&gen),
+		); err != nil {
+			return fmt.Errorf("cannot scan keyspace key: %w", err)
+		}
+
+		ok, err := fn(ctx, k, kvrevision.MarshalGeneration(gen))
+		if !ok || err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("cannot range over keyspace keys: %w", err)
+	}
+
+	return nil
+}
+
+func (ks *keyspace) Stats(ctx context.Context) (kv.Stats, error) {
+	row := ks.db.QueryRowContext(
+		ctx,
+		ks.q.Stats,
+		ks.id,
+	)
+
+	var stats kv.Stats
+	if err := row.Scan(&stats.Keys, &stats.Bytes); err != nil {
+		return kv.Stats{}, fmt.Errorf("cannot query keyspace statistics: %w", err)
+	}
+
+	return stats, nil
+}
+
 func (ks *keyspace) Close() error {
 	return nil
 }
 
-// execOne executes a query and returns whether exactly one row was affected.
-func (ks *keyspace) execOne(
+// execConditional executes a conditional write statement and returns whether
+// exactly one row was affected.
+//
+// The statement must be one of the conditional statements in [queries]. If no
+// rows are affected, it also returns the pair at ($1, $2) as it existed before
+// the statement was executed, in the same round trip.
+func (ks *keyspace) execConditional(
 	ctx context.Context,
 	query string,
 	args ...any,
-) (bool, error) {
-	res, err := ks.db.ExecContext(ctx, query, args...)
-	if err != nil {
-		return false, fmt.Errorf("cannot execute query: %w", err)
+) (bool, pair, error) {
+	row := ks.db.QueryRowContext(ctx, query, args...)
+
+	var (
+		ok      bool
+		current pair
+		gen     uint64
+	)
+	if err := row.Scan(
+		&ok,
+		&current.Value,
+		bigint.ConvertUnsigned(&gen),
+	); err != nil {
+		return false, pair{}, fmt.Errorf("cannot execute query: %w", err)
 	}
 
-	n, err := res.RowsAffected()
-	if err != nil {
-		return false, fmt.Errorf("cannot determine affected rows: %w", err)
+	if current.Value != nil {
+		current.Revision = kvrevision.MarshalGeneration(gen)
 	}
 
-	return n == 1, nil
+	return ok, current, nil
 }
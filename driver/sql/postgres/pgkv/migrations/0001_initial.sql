@@ -1,7 +1,7 @@
-CREATE SCHEMA IF NOT EXISTS persistencekit;
+CREATE SCHEMA IF NOT EXISTS {{schema}};
 
 CREATE TABLE
-    IF NOT EXISTS persistencekit.keyspace (
+    IF NOT EXISTS {{table "keyspace"}} (
         id BIGSERIAL NOT NULL,
         name TEXT NOT NULL,
         PRIMARY KEY (id),
@@ -9,7 +9,7 @@ CREATE TABLE
     );
 
 CREATE TABLE
-    IF NOT EXISTS persistencekit.keyspace_pair (
+    IF NOT EXISTS {{table "keyspace_pair"}} (
         keyspace_id BIGINT NOT NULL,
         key BYTEA NOT NULL,
         value BYTEA NOT NULL,
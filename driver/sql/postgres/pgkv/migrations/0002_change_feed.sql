@@ -0,0 +1,89 @@
+ALTER TABLE {{table "keyspace"}}
+    ADD COLUMN IF NOT EXISTS encoded_change_end BIGINT NOT NULL DEFAULT -1::BIGINT << 63, -- see `bigint` package
+    ADD COLUMN IF NOT EXISTS max_changes BIGINT NOT NULL DEFAULT 0; -- 0 if the change feed is disabled
+
+CREATE TABLE
+    IF NOT EXISTS {{table "keyspace_change"}} (
+        keyspace_id BIGINT NOT NULL,
+        encoded_cursor BIGINT NOT NULL, -- see `bigint` package
+        key BYTEA NOT NULL,
+        value BYTEA, -- NULL if the key was deleted
+        encoded_generation BIGINT, -- see `bigint` package, NULL if the key was deleted
+        PRIMARY KEY (keyspace_id, encoded_cursor)
+    );
+
+-- keyspace_record_change records each change to a key/value pair in the
+-- keyspace_change table, within the same transaction as the change itself.
+--
+-- Incrementing the keyspace's change counter locks its row until the
+-- transaction ends, so changes are assigned cursors in the order in which they
+-- are committed. Changes that fall outside of the keyspace's max_changes most
+-- recent changes are discarded.
+--
+-- If the keyspace's change feed is disabled, its row is neither locked nor
+-- modified, and no change is recorded.
+CREATE OR REPLACE FUNCTION {{table "keyspace_record_change"}}() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+DECLARE
+    change_keyspace_id BIGINT;
+    change_cursor BIGINT;
+    change_retention BIGINT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        change_keyspace_id := OLD.keyspace_id;
+    ELSE
+        change_keyspace_id := NEW.keyspace_id;
+    END IF;
+
+    UPDATE {{table "keyspace"}} SET
+        encoded_change_end = encoded_change_end + 1
+    WHERE id = change_keyspace_id
+    AND max_changes > 0
+    RETURNING encoded_change_end - 1, max_changes
+    INTO change_cursor, change_retention;
+
+    IF NOT FOUND THEN
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO {{table "keyspace_change"}} (
+            keyspace_id,
+            encoded_cursor,
+            key
+        ) VALUES (
+            change_keyspace_id,
+            change_cursor,
+            OLD.key
+        );
+    ELSE
+        INSERT INTO {{table "keyspace_change"}} (
+            keyspace_id,
+            encoded_cursor,
+            key,
+            value,
+            encoded_generation
+        ) VALUES (
+            change_keyspace_id,
+            change_cursor,
+            NEW.key,
+            NEW.value,
+            NEW.encoded_generation
+        );
+    END IF;
+
+    IF change_cursor >= (-1::BIGINT << 63) + change_retention THEN
+        DELETE FROM {{table "keyspace_change"}}
+        WHERE keyspace_id = change_keyspace_id
+        AND encoded_cursor <= change_cursor - change_retention;
+    END IF;
+
+    RETURN NULL;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS {{index "keyspace_pair_change"}} ON {{table "keyspace_pair"}};
+
+CREATE TRIGGER {{index "keyspace_pair_change"}}
+    AFTER INSERT OR UPDATE OR DELETE ON {{table "keyspace_pair"}}
+    FOR EACH ROW EXECUTE FUNCTION {{table "keyspace_record_change"}}();
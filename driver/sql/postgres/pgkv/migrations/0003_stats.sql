@@ -0,0 +1,83 @@
+-- keyspace_stats contains the number and total size of each keyspace's
+-- key/value pairs.
+--
+-- Each keyspace's statistics are spread across several rows, or shards, and
+-- each write updates a shard chosen at random, so that concurrent writers to
+-- the same keyspace rarely contend for the same row. The statistics of a
+-- keyspace are the sum of its shards.
+CREATE TABLE
+    IF NOT EXISTS {{table "keyspace_stats"}} (
+        keyspace_id BIGINT NOT NULL,
+        shard INT NOT NULL,
+        pair_count BIGINT NOT NULL,
+        pair_bytes BIGINT NOT NULL,
+        PRIMARY KEY (keyspace_id, shard)
+    );
+
+INSERT INTO {{table "keyspace_stats"}} (
+    keyspace_id,
+    shard,
+    pair_count,
+    pair_bytes
+)
+SELECT
+    keyspace_id,
+    0,
+    COUNT(*),
+    SUM(octet_length(key) + octet_length(value))
+FROM {{table "keyspace_pair"}}
+GROUP BY keyspace_id
+ON CONFLICT (keyspace_id, shard) DO NOTHING;
+
+-- keyspace_record_stats updates the statistics of a keyspace to reflect each
+-- change to its key/value pairs, within the same transaction as the change
+-- itself.
+CREATE OR REPLACE FUNCTION {{table "keyspace_record_stats"}}() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+DECLARE
+    stats_keyspace_id BIGINT;
+    count_delta BIGINT;
+    bytes_delta BIGINT;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        stats_keyspace_id := NEW.keyspace_id;
+        count_delta := 1;
+        bytes_delta := octet_length(NEW.key) + octet_length(NEW.value);
+    ELSIF TG_OP = 'UPDATE' THEN
+        stats_keyspace_id := NEW.keyspace_id;
+        count_delta := 0;
+        bytes_delta := octet_length(NEW.key) + octet_length(NEW.value)
+                     - octet_length(OLD.key) - octet_length(OLD.value);
+    ELSE
+        stats_keyspace_id := OLD.keyspace_id;
+        count_delta := -1;
+        bytes_delta := -(octet_length(OLD.key) + octet_length(OLD.value));
+    END IF;
+
+    IF count_delta = 0 AND bytes_delta = 0 THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO {{table "keyspace_stats"}} AS s (
+        keyspace_id,
+        shard,
+        pair_count,
+        pair_bytes
+    ) VALUES (
+        stats_keyspace_id,
+        floor(random() * 16)::INT,
+        count_delta,
+        bytes_delta
+    ) ON CONFLICT (keyspace_id, shard) DO UPDATE SET
+        pair_count = s.pair_count + EXCLUDED.pair_count,
+        pair_bytes = s.pair_bytes + EXCLUDED.pair_bytes;
+
+    RETURN NULL;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS {{index "keyspace_pair_stats"}} ON {{table "keyspace_pair"}};
+
+CREATE TRIGGER {{index "keyspace_pair_stats"}}
+    AFTER INSERT OR UPDATE OR DELETE ON {{table "keyspace_pair"}}
+    FOR EACH ROW EXECUTE FUNCTION {{table "keyspace_record_stats"}}();
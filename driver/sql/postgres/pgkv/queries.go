@@ -0,0 +1,150 @@
+package pgkv
+
+import (
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgnamespace"
+)
+
+// queries is the set of SQL statements used by the store, with the names of
+// its tables resolved within a specific namespace.
+type queries struct {
+	ns pgnamespace.Namespace
+
+	InsertKeyspace string
+	Names          string
+
+	Get       string
+	Has       string
+	Range     string
+	RangeKeys string
+	Stats     string
+
+	DeleteConditional string
+	InsertConditional string
+	UpdateConditional string
+
+	DeleteUnconditional string
+	UpsertUnconditional string
+
+	ChangeEnd    string
+	RangeChanges string
+}
+
+// newQueries returns the SQL statements used by the store within ns.
+func newQueries(ns pgnamespace.Namespace) *queries {
+	var (
+		keyspace = ns.Table("keyspace")
+		pair     = ns.Table("keyspace_pair")
+		change   = ns.Table("keyspace_change")
+		stats    = ns.Table("keyspace_stats")
+	)
+
+	// conditional wraps a statement that writes zero or one rows such that it
+	// also returns the pair at ($1, $2) as it existed before the statement was
+	// executed. See [keyspace.execConditional].
+	conditional := func(q string) string {
+		return `WITH op AS (
+			` + q + `
+			RETURNING 1
+		)
+		SELECT
+			EXISTS (SELECT FROM op),
+			p.value,
+			COALESCE(p.encoded_generation, -1::BIGINT << 63)
+		FROM (VALUES (1)) AS v
+		LEFT JOIN ` + pair + ` AS p
+			ON p.keyspace_id = $1
+			AND p.key = $2`
+	}
+
+	return &queries{
+		ns: ns,
+
+		InsertKeyspace: `INSERT INTO ` + keyspace + ` AS k (
+				name,
+				max_changes
+			) VALUES (
+				$1, $2
+			) ON CONFLICT (name) DO UPDATE SET
+				max_changes = CASE
+					WHEN EXCLUDED.max_changes > 0 THEN EXCLUDED.max_changes
+					ELSE k.max_changes
+				END
+			RETURNING id`,
+
+		Names: `SELECT name
+		FROM ` + keyspace,
+
+		Get: `SELECT value, encoded_generation
+		FROM ` + pair + `
+		WHERE keyspace_id = $1
+		AND key = $2`,
+
+		Has: `SELECT COUNT(key) != 0
+		FROM ` + pair + `
+		WHERE keyspace_id = $1
+		AND key = $2`,
+
+		Range: `SELECT key, value, encoded_generation
+		FROM ` + pair + `
+		WHERE keyspace_id = $1`,
+
+		RangeKeys: `SELECT key, encoded_generation
+		FROM ` + pair + `
+		WHERE keyspace_id = $1`,
+
+		Stats: `SELECT
+			COALESCE(SUM(pair_count), 0)::BIGINT,
+			COALESCE(SUM(pair_bytes), 0)::BIGINT
+		FROM ` + stats + `
+		WHERE keyspace_id = $1`,
+
+		DeleteConditional: conditional(`DELETE FROM ` + pair + `
+			WHERE keyspace_id = $1
+			AND key = $2
+			AND encoded_generation = $3`),
+
+		InsertConditional: conditional(`INSERT INTO ` + pair + ` AS o (
+				keyspace_id,
+				key,
+				value
+			) VALUES (
+				$1, $2, $3
+			) ON CONFLICT (keyspace_id, key) DO NOTHING`),
+
+		UpdateConditional: conditional(`UPDATE ` + pair + ` SET
+			value = $3,
+			encoded_generation = encoded_generation + 1
+		WHERE keyspace_id = $1
+			AND key = $2
+			AND encoded_generation = $4`),
+
+		DeleteUnconditional: `DELETE FROM ` + pair + `
+			WHERE keyspace_id = $1
+			AND key = $2`,
+
+		UpsertUnconditional: `INSERT INTO ` + pair + ` AS p (
+			keyspace_id,
+			key,
+			value
+		) VALUES (
+			$1, $2, $3
+		) ON CONFLICT (keyspace_id, key) DO UPDATE SET
+			value = EXCLUDED.value,
+			encoded_generation = p.encoded_generation + 1`,
+
+		ChangeEnd: `SELECT encoded_change_end
+		FROM ` + keyspace + `
+		WHERE id = $1`,
+
+		RangeChanges: `SELECT
+			encoded_cursor,
+			key,
+			value,
+			COALESCE(encoded_generation, -1::BIGINT << 63)
+		FROM ` + change + `
+		WHERE keyspace_id = $1
+		AND encoded_cursor >= $2
+		AND encoded_cursor < $3
+		ORDER BY encoded_cursor`,
+	}
+}
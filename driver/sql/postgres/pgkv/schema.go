@@ -2,32 +2,34 @@ package pgkv
 
 import (
 	"context"
-	"database/sql"
-	_ "embed"
+	"embed"
 
-	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgerror"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgmigrate"
 )
 
-//go:embed schema.sql
-var schema string
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// schema is the versioned schema of the store.
+var schema = pgmigrate.Load("kv", migrations)
 
 // Provision creates the PostgreSQL schema and tables used by the store if they
-// do not already exist.
+// do not already exist, and applies any pending schema migrations.
 //
 // The store also creates the schema on first use if it does not exist.
 // Provision allows infrastructure to be created ahead of time, for example as
 // part of a deployment pipeline, so that the application itself does not need
 // DDL permissions.
+//
+// It returns an error if the schema has been migrated to a newer version than
+// this version of the store supports.
 func (s *BinaryStore) Provision(ctx context.Context) error {
-	return pgerror.Retry(
-		ctx,
-		s.DB,
-		func(tx *sql.Tx) error {
-			_, err := tx.ExecContext(ctx, schema)
-			return err
-		},
-		// Even though we use IF NOT EXISTS in the DDL, we still need to handle
-		// conflicts due to a data race bug in PostgreSQL.
-		pgerror.CodeUniqueViolation,
-	)
+	return schema.Migrate(ctx, s.DB, s.namespace())
+}
+
+// PendingDDL returns the SQL that [BinaryStore.Provision] would execute to
+// bring the store's schema up to date, or an empty string if it is already up
+// to date.
+func (s *BinaryStore) PendingDDL(ctx context.Context) (string, error) {
+	return schema.PendingDDL(ctx, s.DB, s.namespace())
 }
@@ -3,17 +3,48 @@ package pgkv
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
+	"sync/atomic"
 
 	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgerror"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgmigrate"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgnamespace"
 	"github.com/dogmatiq/persistencekit/kv"
 )
 
 // BinaryStore is an implementation of [kv.BinaryStore] that persists to a
 // PostgreSQL database.
+//
+// It also implements [kv.BinaryChangeFeedStore] if [BinaryStore.MaxChanges] is
+// non-zero. Each change to a keyspace with an enabled change feed is recorded in
+// the same transaction as the change itself, so writes to the same keyspace are
+// serialized. Writes to keyspaces without a change feed are unaffected.
 type BinaryStore struct {
 	// DB is the PostgreSQL database connection.
 	DB *sql.DB
+
+	// Schema is the name of the PostgreSQL schema that contains the store's
+	// tables. If it is empty, the "persistencekit" schema is used.
+	Schema string
+
+	// TablePrefix is an optional prefix added to the name of each of the
+	// store's tables.
+	TablePrefix string
+
+	// MaxChanges is the maximum number of changes retained in the change feed
+	// of each keyspace. Older changes are discarded. If it is zero, the store
+	// does not provide change feeds.
+	//
+	// The limit is recorded when a keyspace is opened, so if stores with
+	// different non-zero limits open the same keyspace, the most recent one
+	// applies. Opening a keyspace with a store that does not provide change
+	// feeds leaves the keyspace's change feed, if any, enabled.
+	MaxChanges int
+
+	guard    pgmigrate.Guard
+	prepared atomic.Pointer[queries]
 }
 
 // Open returns the keyspace with the given name.
@@ -22,21 +53,79 @@ func (s *BinaryStore) Open(ctx context.Context, name string) (kv.BinaryKeyspace,
 	if err != nil {
 		return nil, err
 	}
-	return &keyspace{s.DB, id, name}, nil
+	return &keyspace{s.DB, s.queries(), id, name}, nil
+}
+
+// OpenChangeFeed returns the change feed of the keyspace with the given name.
+func (s *BinaryStore) OpenChangeFeed(ctx context.Context, name string) (kv.BinaryChangeFeed, error) {
+	if s.MaxChanges <= 0 {
+		return nil, errors.New("change feeds are disabled, set MaxChanges to enable them")
+	}
+
+	id, err := s.getID(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &changeFeed{s.DB, s.queries(), id, name}, nil
+}
+
+// Capabilities returns the limits and optional features of the store.
+func (s *BinaryStore) Capabilities() kv.Capabilities {
+	return kv.Capabilities{
+		// Keys are part of the primary key, and so must fit within a B-tree
+		// index entry, which is limited to roughly a third of a page.
+		MaxKeySize: 2048,
+
+		// PostgreSQL limits BYTEA values to 1 GiB.
+		MaxValueSize: 1<<30 - 1,
+
+		ChangeFeed:     s.MaxChanges > 0,
+		ConflictDetail: true,
+	}
+}
+
+// Names returns the names of the keyspaces that have been opened, in lexical
+// order.
+func (s *BinaryStore) Names(ctx context.Context) ([]string, error) {
+	rows, err := s.DB.QueryContext(ctx, s.queries().Names)
+	if pgerror.Is(err, pgerror.CodeUndefinedTable) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot query keyspace names: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("cannot scan keyspace name: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cannot query keyspace names: %w", err)
+	}
+
+	slices.Sort(names)
+
+	return names, nil
 }
 
 func (s *BinaryStore) getID(ctx context.Context, name string) (uint64, error) {
-	for {
+	if err := s.guard.Migrate(ctx, s.DB, schema, s.namespace()); err != nil {
+		return 0, err
+	}
+
+	for provisioned := false; ; provisioned = true {
 		row := s.DB.QueryRowContext(
 			ctx,
-			`INSERT INTO persistencekit.keyspace (
-				name
-			) VALUES (
-				$1
-			) ON CONFLICT (name) DO UPDATE SET
-				name = EXCLUDED.name
-			RETURNING id`,
+			s.queries().InsertKeyspace,
 			name,
+			max(s.MaxChanges, 0),
 		)
 
 		var id uint64
@@ -46,7 +135,7 @@ func (s *BinaryStore) getID(ctx context.Context, name string) (uint64, error) {
 			return id, nil
 		}
 
-		if !pgerror.Is(err, pgerror.CodeUndefinedTable) {
+		if !pgerror.Is(err, pgerror.CodeUndefinedTable) || provisioned {
 			return 0, fmt.Errorf("cannot scan keyspace ID: %w", err)
 		}
 
@@ -55,3 +144,26 @@ func (s *BinaryStore) getID(ctx context.Context, name string) (uint64, error) {
 		}
 	}
 }
+
+// queries returns the SQL statements used by the store, preparing them if
+// necessary.
+func (s *BinaryStore) queries() *queries {
+	ns := s.namespace()
+
+	if q := s.prepared.Load(); q != nil && q.ns == ns {
+		return q
+	}
+
+	q := newQueries(ns)
+	s.prepared.Store(q)
+
+	return q
+}
+
+// namespace returns the location of the store's tables.
+func (s *BinaryStore) namespace() pgnamespace.Namespace {
+	return pgnamespace.Namespace{
+		Schema:      s.Schema,
+		TablePrefix: s.TablePrefix,
+	}
+}
@@ -1,6 +1,8 @@
 package pgkv_test
 
 import (
+	"context"
+	"slices"
 	"testing"
 
 	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgtest"
@@ -18,6 +20,155 @@ func TestStore(t *testing.T) {
 	)
 }
 
+func TestStore_Names(t *testing.T) {
+	db, _ := pgtest.Setup(t)
+	store := &BinaryStore{
+		DB: db,
+	}
+
+	names, err := store.Names(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(names) != 0 {
+		t.Fatalf("unexpected names: got %q, want none", names)
+	}
+
+	for _, name := range []string{"<b>", "<a>"} {
+		x, err := store.Open(t.Context(), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		x.Close()
+	}
+
+	names, err = store.Names(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"<a>", "<b>"}; !slices.Equal(names, want) {
+		t.Fatalf("unexpected names: got %q, want %q", names, want)
+	}
+}
+
+func TestChangeFeed(t *testing.T) {
+	db, _ := pgtest.Setup(t)
+	store := &BinaryStore{
+		DB:         db,
+		MaxChanges: 10000,
+	}
+	kv.RunChangeFeedTests(t, store, store)
+}
+
+func TestChangeFeed_discarded(t *testing.T) {
+	db, _ := pgtest.Setup(t)
+	store := &BinaryStore{
+		DB:         db,
+		MaxChanges: 2,
+	}
+
+	ks, err := store.Open(t.Context(), "<keyspace>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ks.Close()
+
+	for _, v := range []string{"<value-1>", "<value-2>", "<value-3>"} {
+		if err := ks.SetUnconditional(t.Context(), []byte("<key>"), []byte(v)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	feed, err := store.OpenChangeFeed(t.Context(), "<keyspace>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer feed.Close()
+
+	t.Run("it returns an error when ranging from a discarded change", func(t *testing.T) {
+		err := feed.Range(
+			t.Context(),
+			0,
+			func(context.Context, kv.Cursor, kv.BinaryChange) (bool, error) {
+				t.Fatal("unexpected call")
+				return false, nil
+			},
+		)
+
+		expect := kv.ChangeNotFoundError{Keyspace: "<keyspace>", Cursor: 0}
+		if err != expect {
+			t.Fatalf("unexpected error: got %v, want %v", err, expect)
+		}
+	})
+
+	t.Run("it retains the most recent changes", func(t *testing.T) {
+		var values []string
+
+		if err := feed.Range(
+			t.Context(),
+			1,
+			func(_ context.Context, _ kv.Cursor, ch kv.BinaryChange) (bool, error) {
+				values = append(values, string(ch.Value))
+				return true, nil
+			},
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		expect := []string{"<value-2>", "<value-3>"}
+		if !slices.Equal(values, expect) {
+			t.Fatalf("unexpected values: got %q, want %q", values, expect)
+		}
+	})
+}
+
+func TestChangeFeed_disabled(t *testing.T) {
+	db, _ := pgtest.Setup(t)
+	store := &BinaryStore{
+		DB: db,
+	}
+
+	if kv.CapabilitiesOf(store).ChangeFeed {
+		t.Fatal("did not expect the store to report a change feed")
+	}
+
+	if _, err := store.OpenChangeFeed(t.Context(), "<keyspace>"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	ks, err := store.Open(t.Context(), "<keyspace>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ks.Close()
+
+	if err := ks.SetUnconditional(t.Context(), []byte("<key>"), []byte("<value>")); err != nil {
+		t.Fatal(err)
+	}
+
+	enabled := &BinaryStore{
+		DB:         db,
+		MaxChanges: 10,
+	}
+
+	feed, err := enabled.OpenChangeFeed(t.Context(), "<keyspace>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer feed.Close()
+
+	end, err := feed.End(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if end != 0 {
+		t.Fatalf("expected changes made while the change feed was disabled not to be recorded, got end of %d", end)
+	}
+}
+
 func BenchmarkStore(b *testing.B) {
 	db, _ := pgtest.Setup(b)
 	kv.RunBenchmarks(
@@ -0,0 +1,3 @@
+// Package pglease provides an implementation of [lease.Store] that persists to
+// a PostgreSQL database.
+package pglease
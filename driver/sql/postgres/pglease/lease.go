@@ -0,0 +1,96 @@
+package pglease
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/lease"
+)
+
+type leaseimpl struct {
+	db   *sql.DB
+	q    *queries
+	name string
+}
+
+func (l *leaseimpl) Name() string {
+	return l.name
+}
+
+func (l *leaseimpl) Acquire(
+	ctx context.Context,
+	holder string,
+	ttl time.Duration,
+) (lease.Grant, bool, error) {
+	// The row is locked by the UPDATE, so concurrent acquisitions are
+	// serialized, and each re-evaluates the WHERE clause against the result of
+	// the one before it.
+	row := l.db.QueryRowContext(
+		ctx,
+		l.q.Acquire,
+		l.name,
+		holder,
+		ttl.Microseconds(),
+	)
+
+	g := lease.Grant{Holder: holder}
+	err := row.Scan(&g.Token, &g.ExpiresAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return lease.Grant{}, false, nil
+	}
+	if err != nil {
+		return lease.Grant{}, false, fmt.Errorf("cannot acquire lease: %w", err)
+	}
+
+	return g, true, nil
+}
+
+func (l *leaseimpl) Renew(
+	ctx context.Context,
+	g lease.Grant,
+	ttl time.Duration,
+) (lease.Grant, error) {
+	row := l.db.QueryRowContext(
+		ctx,
+		l.q.Renew,
+		l.name,
+		g.Token,
+		ttl.Microseconds(),
+	)
+
+	renewed := lease.Grant{Token: g.Token}
+	err := row.Scan(&renewed.Holder, &renewed.ExpiresAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return lease.Grant{}, lease.NotHeldError{
+			Lease: l.name,
+			Token: g.Token,
+		}
+	}
+	if err != nil {
+		return lease.Grant{}, fmt.Errorf("cannot renew lease: %w", err)
+	}
+
+	return renewed, nil
+}
+
+func (l *leaseimpl) Release(ctx context.Context, g lease.Grant) error {
+	if _, err := l.db.ExecContext(
+		ctx,
+		l.q.Release,
+		l.name,
+		g.Token,
+	); err != nil {
+		return fmt.Errorf("cannot release lease: %w", err)
+	}
+
+	return nil
+}
+
+func (l *leaseimpl) Close() error {
+	return nil
+}
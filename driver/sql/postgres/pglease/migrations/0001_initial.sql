@@ -0,0 +1,12 @@
+CREATE SCHEMA IF NOT EXISTS {{schema}};
+
+CREATE TABLE
+    IF NOT EXISTS {{table "lease"}} (
+        name TEXT NOT NULL,
+        holder TEXT NOT NULL DEFAULT '',
+        token BIGINT NOT NULL DEFAULT 0,
+        expires_at TIMESTAMPTZ NOT NULL DEFAULT '-infinity',
+        PRIMARY KEY (name),
+
+        CHECK (token >= 0)
+    );
@@ -0,0 +1,55 @@
+package pglease
+
+import (
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgnamespace"
+)
+
+// queries is the set of SQL statements used by the store, with the names of
+// its tables resolved within a specific namespace.
+type queries struct {
+	ns pgnamespace.Namespace
+
+	Insert  string
+	Acquire string
+	Renew   string
+	Release string
+}
+
+// newQueries returns the SQL statements used by the store within ns.
+func newQueries(ns pgnamespace.Namespace) *queries {
+	lease := ns.Table("lease")
+
+	return &queries{
+		ns: ns,
+
+		Insert: `INSERT INTO ` + lease + ` (
+			name
+		) VALUES (
+			$1
+		) ON CONFLICT (name) DO NOTHING`,
+
+		Acquire: `UPDATE ` + lease + ` SET
+			holder = $2,
+			token = CASE
+				WHEN expires_at > now() THEN token
+				ELSE token + 1
+			END,
+			expires_at = now() + $3::BIGINT * INTERVAL '1 microsecond'
+		WHERE name = $1
+		AND (expires_at <= now() OR holder = $2)
+		RETURNING token, expires_at`,
+
+		Renew: `UPDATE ` + lease + ` SET
+			expires_at = now() + $3::BIGINT * INTERVAL '1 microsecond'
+		WHERE name = $1
+		AND token = $2
+		AND expires_at > now()
+		RETURNING holder, expires_at`,
+
+		Release: `UPDATE ` + lease + ` SET
+			expires_at = now()
+		WHERE name = $1
+		AND token = $2
+		AND expires_at > now()`,
+	}
+}
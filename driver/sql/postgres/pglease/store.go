@@ -0,0 +1,89 @@
+package pglease
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgerror"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgmigrate"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgnamespace"
+	"github.com/dogmatiq/persistencekit/lease"
+)
+
+// Store is an implementation of [lease.Store] that persists to a PostgreSQL
+// database.
+type Store struct {
+	// DB is the PostgreSQL database connection.
+	DB *sql.DB
+
+	// Schema is the name of the PostgreSQL schema that contains the store's
+	// tables. If it is empty, the "persistencekit" schema is used.
+	Schema string
+
+	// TablePrefix is an optional prefix added to the name of each of the
+	// store's tables.
+	TablePrefix string
+
+	guard    pgmigrate.Guard
+	prepared atomic.Pointer[queries]
+}
+
+// Open returns the lease with the given name.
+func (s *Store) Open(ctx context.Context, name string) (lease.Lease, error) {
+	if err := s.insert(ctx, name); err != nil {
+		return nil, err
+	}
+	return &leaseimpl{s.DB, s.queries(), name}, nil
+}
+
+// insert creates the row for the named lease, if it does not already exist.
+func (s *Store) insert(ctx context.Context, name string) error {
+	if err := s.guard.Check(ctx, s.DB, schema, s.namespace()); err != nil {
+		return err
+	}
+
+	for provisioned := false; ; provisioned = true {
+		_, err := s.DB.ExecContext(
+			ctx,
+			s.queries().Insert,
+			name,
+		)
+
+		if err == nil {
+			return nil
+		}
+
+		if !pgerror.Is(err, pgerror.CodeUndefinedTable) || provisioned {
+			return fmt.Errorf("cannot insert lease: %w", err)
+		}
+
+		if err := s.Provision(ctx); err != nil {
+			return fmt.Errorf("cannot create lease schema: %w", err)
+		}
+	}
+}
+
+// queries returns the SQL statements used by the store, preparing them if
+// necessary.
+func (s *Store) queries() *queries {
+	ns := s.namespace()
+
+	if q := s.prepared.Load(); q != nil && q.ns == ns {
+		return q
+	}
+
+	q := newQueries(ns)
+	s.prepared.Store(q)
+
+	return q
+}
+
+// namespace returns the location of the store's tables.
+func (s *Store) namespace() pgnamespace.Namespace {
+	return pgnamespace.Namespace{
+		Schema:      s.Schema,
+		TablePrefix: s.TablePrefix,
+	}
+}
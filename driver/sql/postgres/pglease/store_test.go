@@ -0,0 +1,19 @@
+package pglease_test
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgtest"
+	. "github.com/dogmatiq/persistencekit/driver/sql/postgres/pglease"
+	"github.com/dogmatiq/persistencekit/lease"
+)
+
+func TestStore(t *testing.T) {
+	db, _ := pgtest.Setup(t)
+	lease.RunTests(
+		t,
+		&Store{
+			DB: db,
+		},
+	)
+}
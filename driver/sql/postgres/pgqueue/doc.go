@@ -0,0 +1,3 @@
+// Package pgqueue provides an implementation of [queue.BinaryStore] that
+// persists to a PostgreSQL database.
+package pgqueue
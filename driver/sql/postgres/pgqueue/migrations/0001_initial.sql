@@ -0,0 +1,22 @@
+CREATE SCHEMA IF NOT EXISTS {{schema}};
+
+CREATE TABLE
+    IF NOT EXISTS {{table "queue"}} (
+        id BIGSERIAL NOT NULL,
+        name TEXT NOT NULL,
+        PRIMARY KEY (id),
+        UNIQUE (name)
+    );
+
+CREATE TABLE
+    IF NOT EXISTS {{table "queue_message"}} (
+        id BIGSERIAL NOT NULL,
+        queue_id BIGINT NOT NULL,
+        value BYTEA NOT NULL,
+        attempt INTEGER NOT NULL DEFAULT 0,
+        visible_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+        PRIMARY KEY (id)
+    );
+
+CREATE INDEX IF NOT EXISTS {{index "queue_message_visible_at"}}
+    ON {{table "queue_message"}} (queue_id, visible_at, id);
@@ -0,0 +1,78 @@
+package pgqueue
+
+import (
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgnamespace"
+)
+
+// queries is the set of SQL statements used by the store, with the names of
+// its tables resolved within a specific namespace.
+type queries struct {
+	ns pgnamespace.Namespace
+
+	InsertQueue string
+	Enqueue     string
+	Dequeue     string
+	Ack         string
+	Nack        string
+	DeadLetter  string
+}
+
+// newQueries returns the SQL statements used by the store within ns.
+func newQueries(ns pgnamespace.Namespace) *queries {
+	var (
+		queue        = ns.Table("queue")
+		queueMessage = ns.Table("queue_message")
+	)
+
+	return &queries{
+		ns: ns,
+
+		InsertQueue: `INSERT INTO ` + queue + ` (
+			name
+		) VALUES (
+			$1
+		) ON CONFLICT (name) DO UPDATE SET
+			name = EXCLUDED.name
+		RETURNING id`,
+
+		Enqueue: `INSERT INTO ` + queueMessage + ` (
+			queue_id,
+			value
+		) VALUES (
+			$1, $2
+		)`,
+
+		Dequeue: `UPDATE ` + queueMessage + ` SET
+			attempt = attempt + 1,
+			visible_at = now() + $2::BIGINT * INTERVAL '1 microsecond'
+		WHERE id = (
+			SELECT id
+			FROM ` + queueMessage + `
+			WHERE queue_id = $1
+			AND visible_at <= now()
+			ORDER BY visible_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, value, attempt`,
+
+		Ack: `DELETE FROM ` + queueMessage + `
+		WHERE id = $1
+		AND queue_id = $2
+		AND attempt = $3`,
+
+		Nack: `UPDATE ` + queueMessage + ` SET
+			visible_at = now() + $4::BIGINT * INTERVAL '1 microsecond'
+		WHERE id = $1
+		AND queue_id = $2
+		AND attempt = $3`,
+
+		DeadLetter: `UPDATE ` + queueMessage + ` SET
+			queue_id = $4,
+			attempt = 0,
+			visible_at = now()
+		WHERE id = $1
+		AND queue_id = $2
+		AND attempt = $3`,
+	}
+}
@@ -0,0 +1,166 @@
+package pgqueue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/queue"
+)
+
+type queueimpl struct {
+	store *BinaryStore
+	q     *queries
+	id    uint64
+	name  string
+}
+
+func (q *queueimpl) Name() string {
+	return q.name
+}
+
+func (q *queueimpl) Enqueue(ctx context.Context, v []byte) error {
+	if _, err := q.store.DB.ExecContext(
+		ctx,
+		q.q.Enqueue,
+		q.id,
+		v,
+	); err != nil {
+		return fmt.Errorf("cannot enqueue message: %w", err)
+	}
+
+	return nil
+}
+
+func (q *queueimpl) Dequeue(ctx context.Context, timeout time.Duration) (queue.BinaryMessage, bool, error) {
+	// SKIP LOCKED allows concurrent consumers to claim different messages
+	// without waiting for each other's transactions to complete.
+	row := q.store.DB.QueryRowContext(
+		ctx,
+		q.q.Dequeue,
+		q.id,
+		timeout.Microseconds(),
+	)
+
+	var (
+		id uint64
+		m  queue.BinaryMessage
+	)
+
+	err := row.Scan(&id, &m.Value, &m.Attempt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return queue.BinaryMessage{}, false, nil
+	}
+	if err != nil {
+		return queue.BinaryMessage{}, false, fmt.Errorf("cannot dequeue message: %w", err)
+	}
+
+	m.ID = strconv.FormatUint(id, 10)
+
+	return m, true, nil
+}
+
+func (q *queueimpl) Ack(ctx context.Context, m queue.BinaryMessage) error {
+	id, err := q.parseID(m)
+	if err != nil {
+		return err
+	}
+
+	res, err := q.store.DB.ExecContext(
+		ctx,
+		q.q.Ack,
+		id,
+		q.id,
+		m.Attempt,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot acknowledge message: %w", err)
+	}
+
+	return q.checkCurrent(res, m)
+}
+
+func (q *queueimpl) Nack(ctx context.Context, m queue.BinaryMessage, delay time.Duration) error {
+	id, err := q.parseID(m)
+	if err != nil {
+		return err
+	}
+
+	res, err := q.store.DB.ExecContext(
+		ctx,
+		q.q.Nack,
+		id,
+		q.id,
+		m.Attempt,
+		delay.Microseconds(),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot return message to queue: %w", err)
+	}
+
+	return q.checkCurrent(res, m)
+}
+
+func (q *queueimpl) DeadLetter(ctx context.Context, m queue.BinaryMessage) error {
+	id, err := q.parseID(m)
+	if err != nil {
+		return err
+	}
+
+	dlq, err := q.store.getID(ctx, queue.DeadLetterName(q.name))
+	if err != nil {
+		return err
+	}
+
+	// The message retains its ID when it is moved to the dead-letter queue, as
+	// IDs are unique across all queues.
+	res, err := q.store.DB.ExecContext(
+		ctx,
+		q.q.DeadLetter,
+		id,
+		q.id,
+		m.Attempt,
+		dlq,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot move message to dead-letter queue: %w", err)
+	}
+
+	return q.checkCurrent(res, m)
+}
+
+func (q *queueimpl) Close() error {
+	return nil
+}
+
+// parseID returns the numeric ID of m.
+func (q *queueimpl) parseID(m queue.BinaryMessage) (uint64, error) {
+	id, err := strconv.ParseUint(m.ID, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid message ID %q: %w", m.ID, err)
+	}
+	return id, nil
+}
+
+// checkCurrent returns a [queue.StaleMessageError] if res indicates that no
+// rows were affected by an operation on m.
+func (q *queueimpl) checkCurrent(res sql.Result, m queue.BinaryMessage) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("cannot determine affected rows: %w", err)
+	}
+
+	if n == 0 {
+		return queue.StaleMessageError{
+			Queue:   q.name,
+			ID:      m.ID,
+			Attempt: m.Attempt,
+		}
+	}
+
+	return nil
+}
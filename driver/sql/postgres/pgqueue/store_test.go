@@ -0,0 +1,19 @@
+package pgqueue_test
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgtest"
+	. "github.com/dogmatiq/persistencekit/driver/sql/postgres/pgqueue"
+	"github.com/dogmatiq/persistencekit/queue"
+)
+
+func TestStore(t *testing.T) {
+	db, _ := pgtest.Setup(t)
+	queue.RunTests(
+		t,
+		&BinaryStore{
+			DB: db,
+		},
+	)
+}
@@ -0,0 +1,3 @@
+// Package pgschedule provides an implementation of [schedule.BinaryStore] that
+// persists to a PostgreSQL database.
+package pgschedule
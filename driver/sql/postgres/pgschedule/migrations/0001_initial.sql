@@ -0,0 +1,21 @@
+CREATE SCHEMA IF NOT EXISTS {{schema}};
+
+CREATE TABLE
+    IF NOT EXISTS {{table "schedule"}} (
+        id BIGSERIAL NOT NULL,
+        name TEXT NOT NULL,
+        PRIMARY KEY (id),
+        UNIQUE (name)
+    );
+
+CREATE TABLE
+    IF NOT EXISTS {{table "schedule_item"}} (
+        schedule_id BIGINT NOT NULL,
+        id TEXT NOT NULL,
+        due_at TIMESTAMPTZ NOT NULL,
+        value BYTEA NOT NULL,
+        PRIMARY KEY (schedule_id, id)
+    );
+
+CREATE INDEX IF NOT EXISTS {{index "schedule_item_due_at"}}
+    ON {{table "schedule_item"}} (schedule_id, due_at, id);
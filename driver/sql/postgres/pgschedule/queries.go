@@ -0,0 +1,71 @@
+package pgschedule
+
+import (
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgnamespace"
+)
+
+// queries is the set of SQL statements used by the store, with the names of
+// its tables resolved within a specific namespace.
+type queries struct {
+	ns pgnamespace.Namespace
+
+	InsertSchedule string
+	Schedule       string
+	Cancel         string
+	RangeDue       string
+	Claim          string
+	Complete       string
+}
+
+// newQueries returns the SQL statements used by the store within ns.
+func newQueries(ns pgnamespace.Namespace) *queries {
+	var (
+		schedule     = ns.Table("schedule")
+		scheduleItem = ns.Table("schedule_item")
+	)
+
+	return &queries{
+		ns: ns,
+
+		InsertSchedule: `INSERT INTO ` + schedule + ` (
+			name
+		) VALUES (
+			$1
+		) ON CONFLICT (name) DO UPDATE SET
+			name = EXCLUDED.name
+		RETURNING id`,
+
+		Schedule: `INSERT INTO ` + scheduleItem + ` (
+			schedule_id,
+			id,
+			due_at,
+			value
+		) VALUES (
+			$1, $2, $3, $4
+		) ON CONFLICT (schedule_id, id) DO UPDATE SET
+			due_at = EXCLUDED.due_at,
+			value = EXCLUDED.value`,
+
+		Cancel: `DELETE FROM ` + scheduleItem + `
+		WHERE schedule_id = $1
+		AND id = $2`,
+
+		RangeDue: `SELECT id, due_at, value
+		FROM ` + scheduleItem + `
+		WHERE schedule_id = $1
+		AND due_at <= $2
+		ORDER BY due_at, id`,
+
+		Claim: `UPDATE ` + scheduleItem + ` SET
+			due_at = $4
+		WHERE schedule_id = $1
+		AND id = $2
+		AND due_at = $3
+		AND due_at <= $5`,
+
+		Complete: `DELETE FROM ` + scheduleItem + `
+		WHERE schedule_id = $1
+		AND id = $2
+		AND due_at = $3`,
+	}
+}
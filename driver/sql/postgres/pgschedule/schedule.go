@@ -0,0 +1,144 @@
+package pgschedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/schedule"
+)
+
+type scheduleimpl struct {
+	store *BinaryStore
+	q     *queries
+	id    uint64
+	name  string
+}
+
+func (s *scheduleimpl) Name() string {
+	return s.name
+}
+
+func (s *scheduleimpl) Schedule(ctx context.Context, id string, dueAt time.Time, v []byte) error {
+	if _, err := s.store.DB.ExecContext(
+		ctx,
+		s.q.Schedule,
+		s.id,
+		id,
+		truncate(dueAt),
+		v,
+	); err != nil {
+		return fmt.Errorf("cannot schedule item: %w", err)
+	}
+
+	return nil
+}
+
+func (s *scheduleimpl) Cancel(ctx context.Context, id string) error {
+	if _, err := s.store.DB.ExecContext(
+		ctx,
+		s.q.Cancel,
+		s.id,
+		id,
+	); err != nil {
+		return fmt.Errorf("cannot cancel item: %w", err)
+	}
+
+	return nil
+}
+
+func (s *scheduleimpl) RangeDue(ctx context.Context, t time.Time, fn schedule.BinaryRangeFunc) error {
+	rows, err := s.store.DB.QueryContext(
+		ctx,
+		s.q.RangeDue,
+		s.id,
+		t,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot query due items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item schedule.BinaryItem
+
+		if err := rows.Scan(
+			&item.ID,
+			&item.DueAt,
+			&item.Value,
+		); err != nil {
+			return fmt.Errorf("cannot scan due item: %w", err)
+		}
+
+		ok, err := fn(ctx, item)
+		if !ok || err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (s *scheduleimpl) Claim(
+	ctx context.Context,
+	item schedule.BinaryItem,
+	timeout time.Duration,
+) (schedule.BinaryItem, bool, error) {
+	now := time.Now()
+	dueAt := truncate(now.Add(timeout))
+
+	res, err := s.store.DB.ExecContext(
+		ctx,
+		s.q.Claim,
+		s.id,
+		item.ID,
+		item.DueAt,
+		dueAt,
+		now,
+	)
+	if err != nil {
+		return schedule.BinaryItem{}, false, fmt.Errorf("cannot claim item: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return schedule.BinaryItem{}, false, fmt.Errorf("cannot determine affected rows: %w", err)
+	}
+
+	if n == 0 {
+		return schedule.BinaryItem{}, false, nil
+	}
+
+	item.DueAt = dueAt
+	return item, true, nil
+}
+
+func (s *scheduleimpl) Complete(ctx context.Context, item schedule.BinaryItem) (bool, error) {
+	res, err := s.store.DB.ExecContext(
+		ctx,
+		s.q.Complete,
+		s.id,
+		item.ID,
+		item.DueAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("cannot complete item: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("cannot determine affected rows: %w", err)
+	}
+
+	return n != 0, nil
+}
+
+func (s *scheduleimpl) Close() error {
+	return nil
+}
+
+// truncate returns t truncated to the precision at which due times are
+// stored.
+func truncate(t time.Time) time.Time {
+	return time.UnixMilli(t.UnixMilli())
+}
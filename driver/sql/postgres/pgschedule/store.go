@@ -0,0 +1,92 @@
+package pgschedule
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgerror"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgmigrate"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgnamespace"
+	"github.com/dogmatiq/persistencekit/schedule"
+)
+
+// BinaryStore is an implementation of [schedule.BinaryStore] that persists to a
+// PostgreSQL database.
+type BinaryStore struct {
+	// DB is the PostgreSQL database connection.
+	DB *sql.DB
+
+	// Schema is the name of the PostgreSQL schema that contains the store's
+	// tables. If it is empty, the "persistencekit" schema is used.
+	Schema string
+
+	// TablePrefix is an optional prefix added to the name of each of the
+	// store's tables.
+	TablePrefix string
+
+	guard    pgmigrate.Guard
+	prepared atomic.Pointer[queries]
+}
+
+// Open returns the schedule with the given name.
+func (s *BinaryStore) Open(ctx context.Context, name string) (schedule.BinarySchedule, error) {
+	id, err := s.getID(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &scheduleimpl{s, s.queries(), id, name}, nil
+}
+
+func (s *BinaryStore) getID(ctx context.Context, name string) (uint64, error) {
+	if err := s.guard.Check(ctx, s.DB, schema, s.namespace()); err != nil {
+		return 0, err
+	}
+
+	for provisioned := false; ; provisioned = true {
+		row := s.DB.QueryRowContext(
+			ctx,
+			s.queries().InsertSchedule,
+			name,
+		)
+
+		var id uint64
+		err := row.Scan(&id)
+
+		if err == nil {
+			return id, nil
+		}
+
+		if !pgerror.Is(err, pgerror.CodeUndefinedTable) || provisioned {
+			return 0, fmt.Errorf("cannot scan schedule ID: %w", err)
+		}
+
+		if err := s.Provision(ctx); err != nil {
+			return 0, fmt.Errorf("cannot create schedule schema: %w", err)
+		}
+	}
+}
+
+// queries returns the SQL statements used by the store, preparing them if
+// necessary.
+func (s *BinaryStore) queries() *queries {
+	ns := s.namespace()
+
+	if q := s.prepared.Load(); q != nil && q.ns == ns {
+		return q
+	}
+
+	q := newQueries(ns)
+	s.prepared.Store(q)
+
+	return q
+}
+
+// namespace returns the location of the store's tables.
+func (s *BinaryStore) namespace() pgnamespace.Namespace {
+	return pgnamespace.Namespace{
+		Schema:      s.Schema,
+		TablePrefix: s.TablePrefix,
+	}
+}
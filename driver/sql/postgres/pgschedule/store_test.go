@@ -0,0 +1,19 @@
+package pgschedule_test
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgtest"
+	. "github.com/dogmatiq/persistencekit/driver/sql/postgres/pgschedule"
+	"github.com/dogmatiq/persistencekit/schedule"
+)
+
+func TestStore(t *testing.T) {
+	db, _ := pgtest.Setup(t)
+	schedule.RunTests(
+		t,
+		&BinaryStore{
+			DB: db,
+		},
+	)
+}
@@ -1,7 +1,7 @@
-CREATE SCHEMA IF NOT EXISTS persistencekit;
+CREATE SCHEMA IF NOT EXISTS {{schema}};
 
 CREATE TABLE
-    IF NOT EXISTS persistencekit.set (
+    IF NOT EXISTS {{table "set"}} (
         id BIGSERIAL NOT NULL,
         name TEXT NOT NULL,
         PRIMARY KEY (id),
@@ -9,7 +9,7 @@ CREATE TABLE
     );
 
 CREATE TABLE
-    IF NOT EXISTS persistencekit.set_member (
+    IF NOT EXISTS {{table "set_member"}} (
         set_id BIGINT NOT NULL,
         member BYTEA NOT NULL,
         PRIMARY KEY (set_id, member)
@@ -0,0 +1,29 @@
+-- set_stats contains the number and total size of each set's members.
+--
+-- Each set's statistics are spread across several rows, or shards, and each
+-- write updates a shard chosen at random, so that concurrent writers to the
+-- same set rarely contend for the same row. The statistics of a set are the sum
+-- of its shards.
+CREATE TABLE
+    IF NOT EXISTS {{table "set_stats"}} (
+        set_id BIGINT NOT NULL,
+        shard INT NOT NULL,
+        member_count BIGINT NOT NULL,
+        member_bytes BIGINT NOT NULL,
+        PRIMARY KEY (set_id, shard)
+    );
+
+INSERT INTO {{table "set_stats"}} (
+    set_id,
+    shard,
+    member_count,
+    member_bytes
+)
+SELECT
+    set_id,
+    0,
+    COUNT(*),
+    SUM(octet_length(member))
+FROM {{table "set_member"}}
+GROUP BY set_id
+ON CONFLICT (set_id, shard) DO NOTHING;
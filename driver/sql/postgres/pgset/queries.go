@@ -0,0 +1,132 @@
+package pgset
+
+import (
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgnamespace"
+)
+
+// queries is the set of SQL statements used by the store, with the names of
+// its tables resolved within a specific namespace.
+type queries struct {
+	ns pgnamespace.Namespace
+
+	InsertSet    string
+	Names        string
+	Has          string
+	HasBatch     string
+	Range        string
+	Union        string
+	Intersection string
+	Difference   string
+	Stats        string
+	Insert       string
+	Delete       string
+}
+
+// statsShards is the number of rows across which the statistics of each set
+// are spread.
+const statsShards = "16"
+
+// newQueries returns the SQL statements used by the store within ns.
+func newQueries(ns pgnamespace.Namespace) *queries {
+	var (
+		set       = ns.Table("set")
+		setMember = ns.Table("set_member")
+		setStats  = ns.Table("set_stats")
+	)
+
+	// updateStats returns a statement that adds the given deltas to one of
+	// the shards of the statistics of set $1, once for each row in the named
+	// common table expression.
+	updateStats := func(count, bytes, from string) string {
+		return `INSERT INTO ` + setStats + ` AS s (
+			set_id,
+			shard,
+			member_count,
+			member_bytes
+		)
+		SELECT
+			$1,
+			floor(random() * ` + statsShards + `)::INT,
+			` + count + `,
+			` + bytes + `
+		FROM ` + from + `
+		ON CONFLICT (set_id, shard) DO UPDATE SET
+			member_count = s.member_count + EXCLUDED.member_count,
+			member_bytes = s.member_bytes + EXCLUDED.member_bytes`
+	}
+
+	return &queries{
+		ns: ns,
+
+		InsertSet: `INSERT INTO ` + set + ` (
+			name
+		) VALUES (
+			$1
+		) ON CONFLICT (name) DO UPDATE SET
+			name = EXCLUDED.name
+		RETURNING id`,
+
+		Names: `SELECT name
+		FROM ` + set,
+
+		Has: `SELECT COUNT(member) != 0
+		FROM ` + setMember + `
+		WHERE set_id = $1
+		AND member = $2`,
+
+		HasBatch: `SELECT member
+		FROM ` + setMember + `
+		WHERE set_id = $1
+		AND member = ANY($2::BYTEA[])`,
+
+		Range: `SELECT member
+		FROM ` + setMember + `
+		WHERE set_id = $1`,
+
+		Union: `SELECT DISTINCT member
+		FROM ` + setMember + `
+		WHERE set_id = ANY($1::BIGINT[])`,
+
+		Intersection: `SELECT member
+		FROM ` + setMember + `
+		WHERE set_id = ANY($1::BIGINT[])
+		GROUP BY member
+		HAVING COUNT(*) = $2`,
+
+		Difference: `SELECT member
+		FROM ` + setMember + `
+		WHERE set_id = $1
+		EXCEPT
+		SELECT member
+		FROM ` + setMember + `
+		WHERE set_id = ANY($2::BIGINT[])`,
+
+		Stats: `SELECT
+			COALESCE(SUM(member_count), 0)::BIGINT,
+			COALESCE(SUM(member_bytes), 0)::BIGINT
+		FROM ` + setStats + `
+		WHERE set_id = $1`,
+
+		// Insert and Delete update the set's statistics in the same statement
+		// as the member itself, using a shard chosen at random. The number of
+		// rows affected is zero if the membership did not change.
+		Insert: `WITH inserted AS (
+			INSERT INTO ` + setMember + ` (
+				set_id,
+				member
+			) VALUES (
+				$1, $2
+			) ON CONFLICT (set_id, member) DO NOTHING
+			RETURNING octet_length(member) AS size
+		)
+		` + updateStats(`1`, `inserted.size`, `inserted`),
+
+		Delete: `WITH deleted AS (
+			DELETE FROM ` + setMember + `
+			WHERE set_id = $1
+			AND member = $2
+			RETURNING octet_length(member) AS size
+		)
+		` + updateStats(`-1`, `-deleted.size`, `deleted`),
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
 
 	"github.com/dogmatiq/persistencekit/set"
 )
 
 type setimpl struct {
 	db   *sql.DB
+	q    *queries
 	id   uint64
 	name string
 }
@@ -21,10 +23,7 @@ func (s *setimpl) Name() string {
 func (s *setimpl) Has(ctx context.Context, k []byte) (bool, error) {
 	row := s.db.QueryRowContext(
 		ctx,
-		`SELECT COUNT(member) != 0
-		FROM persistencekit.set_member
-		WHERE set_id = $1
-		AND member = $2`,
+		s.q.Has,
 		s.id,
 		k,
 	)
@@ -37,6 +36,30 @@ func (s *setimpl) Has(ctx context.Context, k []byte) (bool, error) {
 	return exists, nil
 }
 
+func (s *setimpl) HasBatch(ctx context.Context, vs [][]byte) ([]bool, error) {
+	members := map[string]struct{}{}
+
+	if err := s.query(
+		ctx,
+		func(_ context.Context, v []byte) (bool, error) {
+			members[string(v)] = struct{}{}
+			return true, nil
+		},
+		s.q.HasBatch,
+		s.id,
+		vs,
+	); err != nil {
+		return nil, err
+	}
+
+	result := make([]bool, len(vs))
+	for i, v := range vs {
+		_, result[i] = members[string(v)]
+	}
+
+	return result, nil
+}
+
 func (s *setimpl) Add(ctx context.Context, v []byte) error {
 	_, err := s.insert(ctx, v)
 	return err
@@ -64,13 +87,104 @@ func (s *setimpl) TryRemove(ctx context.Context, v []byte) (bool, error) {
 }
 
 func (s *setimpl) Range(ctx context.Context, fn set.BinaryRangeFunc) error {
-	rows, err := s.db.QueryContext(
+	return s.query(
+		ctx,
+		fn,
+		s.q.Range,
+		s.id,
+	)
+}
+
+func (s *setimpl) RangeAlgebra(
+	ctx context.Context,
+	op set.Operation,
+	others []set.BinarySet,
+	fn set.BinaryRangeFunc,
+) (bool, error) {
+	// ids contains the distinct IDs of all the sets, beginning with s.
+	ids := []int64{int64(s.id)}
+
+	for _, o := range others {
+		x, ok := o.(*setimpl)
+		if !ok || x.db != s.db || x.q.ns != s.q.ns {
+			return false, nil
+		}
+		if !slices.Contains(ids, int64(x.id)) {
+			ids = append(ids, int64(x.id))
+		}
+	}
+
+	switch op {
+	case set.UnionOperation:
+		return true, s.query(
+			ctx,
+			fn,
+			s.q.Union,
+			ids,
+		)
+
+	case set.IntersectionOperation:
+		return true, s.query(
+			ctx,
+			fn,
+			s.q.Intersection,
+			ids,
+			len(ids),
+		)
+
+	case set.DifferenceOperation:
+		if len(others) == 0 {
+			return true, s.Range(ctx, fn)
+		}
+
+		// Note that if s is also one of the others, it is not present in
+		// ids[1:]. In that case the difference is always empty.
+		for _, o := range others {
+			if o.(*setimpl).id == s.id {
+				return true, nil
+			}
+		}
+
+		return true, s.query(
+			ctx,
+			fn,
+			s.q.Difference,
+			ids[0],
+			ids[1:],
+		)
+
+	default:
+		return false, nil
+	}
+}
+
+func (s *setimpl) Stats(ctx context.Context) (set.Stats, error) {
+	row := s.db.QueryRowContext(
 		ctx,
-		`SELECT member
-		FROM persistencekit.set_member
-		WHERE set_id = $1`,
+		s.q.Stats,
 		s.id,
 	)
+
+	var stats set.Stats
+	if err := row.Scan(&stats.Members, &stats.Bytes); err != nil {
+		return set.Stats{}, fmt.Errorf("cannot query set statistics: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (s *setimpl) Close() error {
+	return nil
+}
+
+// query invokes fn for each member returned by the given query.
+func (s *setimpl) query(
+	ctx context.Context,
+	fn set.BinaryRangeFunc,
+	query string,
+	args ...any,
+) error {
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("cannot query set members: %w", err)
 	}
@@ -95,20 +209,10 @@ func (s *setimpl) Range(ctx context.Context, fn set.BinaryRangeFunc) error {
 	return nil
 }
 
-func (s *setimpl) Close() error {
-	return nil
-}
-
 func (s *setimpl) insert(ctx context.Context, v []byte) (sql.Result, error) {
 	res, err := s.db.ExecContext(
 		ctx,
-		`INSERT INTO persistencekit.set_member AS o (
-			set_id,
-			member
-		) VALUES (
-			$1, $2
-		) ON CONFLICT (set_id, member) DO NOTHING
-		`,
+		s.q.Insert,
 		s.id,
 		v,
 	)
@@ -122,9 +226,7 @@ func (s *setimpl) insert(ctx context.Context, v []byte) (sql.Result, error) {
 func (s *setimpl) delete(ctx context.Context, v []byte) (sql.Result, error) {
 	res, err := s.db.ExecContext(
 		ctx,
-		`DELETE FROM persistencekit.set_member
-		WHERE set_id = $1
-		AND member = $2`,
+		s.q.Delete,
 		s.id,
 		v,
 	)
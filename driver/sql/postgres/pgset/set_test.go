@@ -0,0 +1,56 @@
+package pgset_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgtest"
+	. "github.com/dogmatiq/persistencekit/driver/sql/postgres/pgset"
+	"github.com/dogmatiq/persistencekit/set"
+)
+
+func TestSet_algebraAcrossNamespaces(t *testing.T) {
+	db, _ := pgtest.Setup(t)
+
+	stores := []*BinaryStore{
+		{DB: db},
+		{DB: db, Schema: "tenant"},
+	}
+
+	var sets []set.BinarySet
+
+	for i, s := range stores {
+		// Use the same set name in each store so that the sets are likely to
+		// share the same internal ID.
+		x, err := s.Open(t.Context(), "<set>")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer x.Close()
+
+		if err := x.Add(t.Context(), []byte{byte('a' + i)}); err != nil {
+			t.Fatal(err)
+		}
+
+		sets = append(sets, x)
+	}
+
+	var got []string
+	if err := set.Union(
+		t.Context(),
+		sets,
+		func(_ context.Context, v []byte) (bool, error) {
+			got = append(got, string(v))
+			return true, nil
+		},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	slices.Sort(got)
+
+	if want := []string{"a", "b"}; !slices.Equal(got, want) {
+		t.Fatalf("unexpected members: got %q, want %q", got, want)
+	}
+}
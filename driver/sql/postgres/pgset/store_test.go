@@ -1,6 +1,7 @@
 package pgset_test
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgtest"
@@ -18,6 +19,39 @@ func TestStore(t *testing.T) {
 	)
 }
 
+func TestStore_Names(t *testing.T) {
+	db, _ := pgtest.Setup(t)
+	store := &BinaryStore{
+		DB: db,
+	}
+
+	names, err := store.Names(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(names) != 0 {
+		t.Fatalf("unexpected names: got %q, want none", names)
+	}
+
+	for _, name := range []string{"<b>", "<a>"} {
+		x, err := store.Open(t.Context(), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		x.Close()
+	}
+
+	names, err = store.Names(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"<a>", "<b>"}; !slices.Equal(names, want) {
+		t.Fatalf("unexpected names: got %q, want %q", names, want)
+	}
+}
+
 func BenchmarkStore(b *testing.B) {
 	db, _ := pgtest.Setup(b)
 	set.RunBenchmarks(
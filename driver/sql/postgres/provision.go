@@ -0,0 +1,232 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgerror"
+	"github.com/dogmatiq/persistencekit/driver/sql/postgres/internal/pgmigrate"
+	"github.com/dogmatiq/persistencekit/health"
+	"github.com/dogmatiq/persistencekit/provision"
+)
+
+// ErrSchemaTooNew indicates that a store's schema has been migrated to a
+// version that is newer than the running code supports. It is returned when
+// provisioning or opening a store.
+var ErrSchemaTooNew = pgmigrate.ErrSchemaTooNew
+
+// sequences is the set of sequences used by each store, other than those owned
+// by its tables. The names do not include the table prefix.
+var sequences = map[health.Store][]string{
+	health.BlobStore: {"blob_upload_id"},
+}
+
+// PlanProvision returns the DDL that would be executed to create the tables of
+// each store that has not been provisioned, and to apply any pending schema
+// migrations.
+func (d *Driver) PlanProvision(ctx context.Context) (provision.Plan, error) {
+	existing, err := d.existingTables(ctx)
+	if err != nil {
+		return provision.Plan{}, err
+	}
+
+	var plan provision.Plan
+
+	for _, s := range health.Stores() {
+		ddl, err := d.migrator(s).PendingDDL(ctx)
+		if err != nil {
+			return provision.Plan{}, err
+		}
+
+		if ddl == "" {
+			continue
+		}
+
+		action := provision.Create
+		for _, t := range tables[s] {
+			if _, ok := existing[d.ns.TablePrefix+t]; ok {
+				action = provision.Update
+				break
+			}
+		}
+
+		plan.Changes = append(
+			plan.Changes,
+			provision.Change{
+				Action:   action,
+				Resource: d.describeTables(s),
+				Detail:   ddl,
+			},
+		)
+	}
+
+	return plan, nil
+}
+
+// PlanDeprovision returns the DDL that [Driver.Deprovision] would execute to
+// drop the tables of each store, and the driver's schema itself.
+//
+// The schema is only dropped if it contains no tables other than those used by
+// the stores, and it is not the "public" schema.
+func (d *Driver) PlanDeprovision(ctx context.Context) (provision.Plan, error) {
+	existing, err := d.existingTables(ctx)
+	if err != nil {
+		return provision.Plan{}, err
+	}
+
+	var plan provision.Plan
+
+	for _, s := range health.Stores() {
+		var (
+			ddl   strings.Builder
+			found bool
+		)
+
+		for _, t := range tables[s] {
+			if _, ok := existing[d.ns.TablePrefix+t]; ok {
+				fmt.Fprintf(&ddl, "DROP TABLE IF EXISTS %s;\n", d.ns.Table(t))
+				delete(existing, d.ns.TablePrefix+t)
+				found = true
+			}
+		}
+
+		if !found {
+			continue
+		}
+
+		for _, seq := range sequences[s] {
+			fmt.Fprintf(&ddl, "DROP SEQUENCE IF EXISTS %s;\n", d.ns.Table(seq))
+		}
+
+		plan.Changes = append(
+			plan.Changes,
+			provision.Change{
+				Action:   provision.Delete,
+				Resource: d.describeTables(s),
+				Detail:   ddl.String(),
+			},
+		)
+	}
+
+	if _, ok := existing[d.ns.TablePrefix+"schema_version"]; ok {
+		delete(existing, d.ns.TablePrefix+"schema_version")
+
+		plan.Changes = append(
+			plan.Changes,
+			provision.Change{
+				Action:   provision.Delete,
+				Resource: "PostgreSQL table " + d.ns.Table("schema_version"),
+				Detail:   fmt.Sprintf("DROP TABLE IF EXISTS %s;\n", d.ns.Table("schema_version")),
+			},
+		)
+	}
+
+	exists, err := d.schemaExists(ctx)
+	if err != nil {
+		return provision.Plan{}, err
+	}
+
+	if exists && len(existing) == 0 && d.ns.SchemaName() != "public" {
+		plan.Changes = append(
+			plan.Changes,
+			provision.Change{
+				Action:   provision.Delete,
+				Resource: fmt.Sprintf("PostgreSQL schema %q", d.ns.SchemaName()),
+				Detail:   fmt.Sprintf("DROP SCHEMA IF EXISTS %s;\n", d.ns.SchemaName()),
+			},
+		)
+	}
+
+	return plan, nil
+}
+
+// Deprovision drops the tables used by each store, and the driver's schema
+// itself if it contains no other tables.
+//
+// The changes are applied in a single transaction.
+func (d *Driver) Deprovision(ctx context.Context) error {
+	plan, err := d.PlanDeprovision(ctx)
+	if err != nil {
+		return err
+	}
+
+	if plan.Empty() {
+		return nil
+	}
+
+	return pgerror.Retry(
+		ctx,
+		d.db,
+		func(tx *sql.Tx) error {
+			for _, c := range plan.Changes {
+				if _, err := tx.ExecContext(ctx, c.Detail); err != nil {
+					return fmt.Errorf("cannot %s: %w", c, err)
+				}
+			}
+			return nil
+		},
+	)
+}
+
+// migrator is the interface implemented by each of the PostgreSQL stores for
+// reporting pending schema migrations.
+type migrator interface {
+	PendingDDL(context.Context) (string, error)
+}
+
+// migrator returns the PostgreSQL implementation of the given store.
+func (d *Driver) migrator(s health.Store) migrator {
+	var store any
+
+	switch s {
+	case health.JournalStore:
+		store = d.JournalStore()
+	case health.KVStore:
+		store = d.KVStore()
+	case health.SetStore:
+		store = d.SetStore()
+	case health.LeaseStore:
+		store = d.LeaseStore()
+	case health.QueueStore:
+		store = d.QueueStore()
+	case health.ScheduleStore:
+		store = d.ScheduleStore()
+	case health.BlobStore:
+		store = d.BlobStore()
+	default:
+		panic(fmt.Sprintf("unsupported store: %s", s))
+	}
+
+	return store.(migrator)
+}
+
+// schemaExists returns true if the driver's schema exists.
+func (d *Driver) schemaExists(ctx context.Context) (bool, error) {
+	var exists bool
+
+	if err := d.db.QueryRowContext(
+		ctx,
+		`SELECT EXISTS (
+			SELECT 1
+			FROM pg_catalog.pg_namespace
+			WHERE nspname = $1
+		)`,
+		d.ns.SchemaName(),
+	).Scan(&exists); err != nil {
+		return false, fmt.Errorf("cannot query the %s schema: %w", d.ns.SchemaName(), err)
+	}
+
+	return exists, nil
+}
+
+// describeTables returns a human-readable description of the tables used by
+// the given store.
+func (d *Driver) describeTables(s health.Store) string {
+	names := make([]string, len(tables[s]))
+	for i, t := range tables[s] {
+		names[i] = d.ns.Table(t)
+	}
+	return fmt.Sprintf("PostgreSQL tables for the %s store (%s)", s, strings.Join(names, ", "))
+}
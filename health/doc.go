@@ -0,0 +1,3 @@
+// Package health checks whether persistence drivers can reach their backends
+// and have been provisioned.
+package health
@@ -0,0 +1,64 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dogmatiq/persistencekit/driver"
+)
+
+// Handler returns an [http.Handler] that reports the health of d, suitable for
+// use as a readiness probe.
+//
+// It responds with 200 OK if all stores are healthy, or 503 Service
+// Unavailable otherwise. The response body is a JSON object describing the
+// health of each store.
+func Handler(d driver.Driver) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				w.Header().Set("Allow", "GET, HEAD")
+				http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+				return
+			}
+
+			report := Check(r.Context(), d)
+
+			type storeResponse struct {
+				Healthy bool   `json:"healthy"`
+				Error   string `json:"error,omitempty"`
+			}
+
+			res := struct {
+				Healthy bool                    `json:"healthy"`
+				Stores  map[Store]storeResponse `json:"stores"`
+			}{
+				Healthy: report.Healthy(),
+				Stores:  map[Store]storeResponse{},
+			}
+
+			for _, r := range report.Results {
+				s := storeResponse{Healthy: r.Err == nil}
+				if r.Err != nil {
+					s.Error = r.Err.Error()
+				}
+				res.Stores[r.Store] = s
+			}
+
+			status := http.StatusOK
+			if !res.Healthy {
+				status = http.StatusServiceUnavailable
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Cache-Control", "no-store")
+			w.WriteHeader(status)
+
+			if r.Method == http.MethodGet {
+				// There is nothing useful to do with an error once the status
+				// code has been sent.
+				_ = json.NewEncoder(w).Encode(res)
+			}
+		},
+	)
+}
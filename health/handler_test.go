@@ -0,0 +1,105 @@
+package health_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/driver/memory"
+	. "github.com/dogmatiq/persistencekit/health"
+)
+
+func TestHandler(t *testing.T) {
+	type storeResponse struct {
+		Healthy bool   `json:"healthy"`
+		Error   string `json:"error"`
+	}
+
+	type response struct {
+		Healthy bool                     `json:"healthy"`
+		Stores  map[string]storeResponse `json:"stores"`
+	}
+
+	serve := func(t *testing.T, h http.Handler, method string) (*httptest.ResponseRecorder, response) {
+		t.Helper()
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequestWithContext(t.Context(), method, "/ready", nil))
+
+		var res response
+		if method == http.MethodGet && w.Code != http.StatusMethodNotAllowed {
+			if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return w, res
+	}
+
+	t.Run("it responds with 200 OK if all stores are healthy", func(t *testing.T) {
+		d := memory.New(&memory.Config{Silo: "health-test-handler"})
+
+		w, res := serve(t, Handler(d), http.MethodGet)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("unexpected status code: got %d, want %d", w.Code, http.StatusOK)
+		}
+
+		if got, want := w.Header().Get("Content-Type"), "application/json"; got != want {
+			t.Fatalf("unexpected content type: got %q, want %q", got, want)
+		}
+
+		if !res.Healthy {
+			t.Fatal("expected the response to be healthy")
+		}
+
+		for _, s := range Stores() {
+			if !res.Stores[string(s)].Healthy {
+				t.Fatalf("expected the %s store to be healthy", s)
+			}
+		}
+	})
+
+	t.Run("it responds with 503 Service Unavailable if any store is unhealthy", func(t *testing.T) {
+		d := unhealthyDriver{memory.New(&memory.Config{Silo: "health-test-handler"})}
+
+		w, res := serve(t, Handler(d), http.MethodGet)
+
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("unexpected status code: got %d, want %d", w.Code, http.StatusServiceUnavailable)
+		}
+
+		if res.Healthy {
+			t.Fatal("expected the response to be unhealthy")
+		}
+
+		if got, want := res.Stores["journal"].Error, "<error>"; got != want {
+			t.Fatalf("unexpected error: got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("it omits the body for HEAD requests", func(t *testing.T) {
+		d := memory.New(&memory.Config{Silo: "health-test-handler"})
+
+		w, _ := serve(t, Handler(d), http.MethodHead)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("unexpected status code: got %d, want %d", w.Code, http.StatusOK)
+		}
+
+		if w.Body.Len() != 0 {
+			t.Fatal("expected an empty body")
+		}
+	})
+
+	t.Run("it rejects other methods", func(t *testing.T) {
+		d := memory.New(&memory.Config{Silo: "health-test-handler"})
+
+		w, _ := serve(t, Handler(d), http.MethodPost)
+
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Fatalf("unexpected status code: got %d, want %d", w.Code, http.StatusMethodNotAllowed)
+		}
+	})
+}
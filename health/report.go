@@ -0,0 +1,125 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/persistencekit/driver"
+)
+
+// Store identifies one of the stores provided by a [driver.Driver].
+type Store string
+
+// The stores provided by a [driver.Driver].
+const (
+	JournalStore  Store = "journal"
+	KVStore       Store = "kv"
+	SetStore      Store = "set"
+	LeaseStore    Store = "lease"
+	QueueStore    Store = "queue"
+	ScheduleStore Store = "schedule"
+	BlobStore     Store = "blob"
+)
+
+// Stores returns all of the stores provided by a [driver.Driver].
+func Stores() []Store {
+	return []Store{
+		JournalStore,
+		KVStore,
+		SetStore,
+		LeaseStore,
+		QueueStore,
+		ScheduleStore,
+		BlobStore,
+	}
+}
+
+// Checker is an interface for drivers that can check the health of their
+// backend.
+type Checker interface {
+	// CheckHealth checks that each store can reach its backend and has been
+	// provisioned.
+	CheckHealth(ctx context.Context) Report
+}
+
+// Result is the result of checking the health of a single store.
+type Result struct {
+	// Store is the store that was checked.
+	Store Store
+
+	// Err is the reason the store is unhealthy, or nil if it is healthy.
+	Err error
+}
+
+// Report is the result of checking the health of each store provided by a
+// driver.
+type Report struct {
+	Results []Result
+}
+
+// NewReport returns a [Report] that contains a result for each store, as
+// determined by calling check.
+func NewReport(check func(Store) error) Report {
+	var r Report
+	for _, s := range Stores() {
+		r.Results = append(
+			r.Results,
+			Result{
+				Store: s,
+				Err:   check(s),
+			},
+		)
+	}
+	return r
+}
+
+// Healthy returns true if all stores in the report are healthy.
+func (r Report) Healthy() bool {
+	for _, res := range r.Results {
+		if res.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Err returns an error describing each unhealthy store, or nil if all stores
+// are healthy.
+func (r Report) Err() error {
+	var errs []error
+	for _, res := range r.Results {
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("%s store is unhealthy: %w", res.Store, res.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Result returns the result for the given store.
+func (r Report) Result(s Store) (Result, bool) {
+	for _, res := range r.Results {
+		if res.Store == s {
+			return res, true
+		}
+	}
+	return Result{}, false
+}
+
+// ErrUnsupported is the error reported for each store of a driver that does
+// not implement [Checker].
+var ErrUnsupported = errors.New("driver does not support health checks")
+
+// Check checks the health of each store provided by d.
+//
+// If d does not implement [Checker], each store is reported as unhealthy with
+// [ErrUnsupported].
+func Check(ctx context.Context, d driver.Driver) Report {
+	if c, ok := d.(Checker); ok {
+		return c.CheckHealth(ctx)
+	}
+
+	return NewReport(func(Store) error {
+		return ErrUnsupported
+	})
+}
@@ -0,0 +1,91 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/driver/memory"
+	. "github.com/dogmatiq/persistencekit/health"
+)
+
+func TestReport(t *testing.T) {
+	t.Run("it is healthy if all stores are healthy", func(t *testing.T) {
+		r := NewReport(func(Store) error { return nil })
+
+		if !r.Healthy() {
+			t.Fatal("expected the report to be healthy")
+		}
+
+		if err := r.Err(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if len(r.Results) != len(Stores()) {
+			t.Fatalf("unexpected number of results: got %d, want %d", len(r.Results), len(Stores()))
+		}
+	})
+
+	t.Run("it is unhealthy if any store is unhealthy", func(t *testing.T) {
+		r := NewReport(func(s Store) error {
+			if s == KVStore {
+				return errors.New("<error>")
+			}
+			return nil
+		})
+
+		if r.Healthy() {
+			t.Fatal("expected the report to be unhealthy")
+		}
+
+		want := "kv store is unhealthy: <error>"
+		if err := r.Err(); err == nil || err.Error() != want {
+			t.Fatalf("unexpected error: got %v, want %q", err, want)
+		}
+
+		res, ok := r.Result(KVStore)
+		if !ok {
+			t.Fatal("expected a result for the kv store")
+		}
+		if res.Err == nil {
+			t.Fatal("expected the kv store to be unhealthy")
+		}
+	})
+}
+
+func TestCheck(t *testing.T) {
+	t.Run("it uses the driver's health check", func(t *testing.T) {
+		d := memory.New(&memory.Config{Silo: "health-test-check"})
+
+		if err := Check(t.Context(), d).Err(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("it reports drivers without health checks as unhealthy", func(t *testing.T) {
+		d := uncheckedDriver{memory.New(&memory.Config{Silo: "health-test-check"})}
+
+		for _, res := range Check(t.Context(), d).Results {
+			if !errors.Is(res.Err, ErrUnsupported) {
+				t.Fatalf("unexpected error for the %s store: %v", res.Store, res.Err)
+			}
+		}
+	})
+}
+
+// uncheckedDriver is a [driver.Driver] that does not implement [Checker].
+type uncheckedDriver struct {
+	driver.Driver
+}
+
+// unhealthyDriver is a [driver.Driver] that reports each store as unhealthy.
+type unhealthyDriver struct {
+	driver.Driver
+}
+
+func (unhealthyDriver) CheckHealth(context.Context) Report {
+	return NewReport(func(Store) error {
+		return errors.New("<error>")
+	})
+}
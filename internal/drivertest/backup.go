@@ -0,0 +1,157 @@
+package drivertest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/backup"
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+	"github.com/dogmatiq/persistencekit/journal"
+	"github.com/dogmatiq/persistencekit/migrate"
+)
+
+// RunBackupTests verifies that the journals, keyspaces and sets of source can
+// be backed up and restored to target.
+//
+// source and target must not share any data.
+func RunBackupTests(t *testing.T, source, target driver.Driver) {
+	for _, d := range []driver.Driver{source, target} {
+		for _, provision := range []func(context.Context) error{
+			d.JournalStore().Provision,
+			d.KVStore().Provision,
+			d.SetStore().Provision,
+		} {
+			if err := provision(t.Context()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	populate := func(t *testing.T) migrate.Plan {
+		ctx := t.Context()
+
+		plan := migrate.Plan{
+			Journals:  []string{xtesting.UniqueName("journal"), xtesting.UniqueName("empty-journal")},
+			Keyspaces: []string{xtesting.UniqueName("keyspace")},
+			Sets:      []string{xtesting.UniqueName("set")},
+		}
+
+		j, err := source.JournalStore().Open(ctx, plan.Journals[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer j.Close()
+
+		for pos := range journal.Position(5) {
+			if err := j.Append(ctx, pos, fmt.Appendf(nil, "<record-%d>", pos)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := j.Truncate(ctx, 2); err != nil {
+			t.Fatal(err)
+		}
+
+		ks, err := source.KVStore().Open(ctx, plan.Keyspaces[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ks.Close()
+
+		s, err := source.SetStore().Open(ctx, plan.Sets[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+
+		for i := range 5 {
+			if err := ks.SetUnconditional(ctx, fmt.Appendf(nil, "<key-%d>", i), fmt.Appendf(nil, "<value-%d>", i)); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := s.Add(ctx, fmt.Appendf(nil, "<member-%d>", i)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return plan
+	}
+
+	options := func(plan migrate.Plan) []backup.Option {
+		return []backup.Option{
+			backup.Journals(plan.Journals...),
+			backup.Keyspaces(plan.Keyspaces...),
+			backup.Sets(plan.Sets...),
+		}
+	}
+
+	verify := func(t *testing.T, plan migrate.Plan) {
+		m := &migrate.Migrator{
+			Source: source,
+			Target: target,
+			Plan:   plan,
+		}
+
+		report, err := m.Verify(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := report.Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("it restores each store in the archive", func(t *testing.T) {
+		plan := populate(t)
+
+		var archive bytes.Buffer
+		if err := backup.Backup(t.Context(), source, &archive, options(plan)...); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := backup.Restore(t.Context(), &archive, target); err != nil {
+			t.Fatal(err)
+		}
+
+		verify(t, plan)
+	})
+
+	t.Run("it restores only the selected stores", func(t *testing.T) {
+		plan := populate(t)
+
+		var archive bytes.Buffer
+		if err := backup.Backup(t.Context(), source, &archive, options(plan)...); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := backup.Restore(
+			t.Context(),
+			&archive,
+			target,
+			backup.Keyspaces(plan.Keyspaces...),
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		verify(t, migrate.Plan{Keyspaces: plan.Keyspaces})
+
+		s, err := target.SetStore().Open(t.Context(), plan.Sets[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+
+		ok, err := s.Has(t.Context(), []byte("<member-0>"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ok {
+			t.Fatal("did not expect the set to be restored")
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package drivertest
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/kv"
+)
+
+// RunChangeFeedTests runs the keyspace change feed conformance tests against
+// the driver's key/value store.
+//
+// If the store reports a native change feed it is tested directly, otherwise
+// the changes are mirrored to the driver's journal store using
+// [kv.WithChangeJournal].
+func RunChangeFeedTests(t *testing.T, d Driver) {
+	store := d.KVStore()
+
+	if kv.CapabilitiesOf(store).ChangeFeed {
+		feeds, ok := store.(kv.BinaryChangeFeedStore)
+		if !ok {
+			t.Fatalf("%T reports a change feed but does not implement kv.BinaryChangeFeedStore", store)
+		}
+
+		kv.RunChangeFeedTests(t, store, feeds)
+		return
+	}
+
+	journals := d.JournalStore()
+
+	kv.RunChangeFeedTests(
+		t,
+		kv.WithChangeJournal(store, journals),
+		kv.NewJournalChangeFeedStore(journals),
+	)
+}
@@ -4,10 +4,17 @@ package drivertest
 
 import (
 	"bytes"
+	"context"
+	"io"
 	"testing"
+	"time"
 
+	"github.com/dogmatiq/persistencekit/blob"
 	"github.com/dogmatiq/persistencekit/journal"
 	"github.com/dogmatiq/persistencekit/kv"
+	"github.com/dogmatiq/persistencekit/lease"
+	"github.com/dogmatiq/persistencekit/queue"
+	"github.com/dogmatiq/persistencekit/schedule"
 	"github.com/dogmatiq/persistencekit/set"
 )
 
@@ -16,6 +23,10 @@ type Driver interface {
 	JournalStore() journal.BinaryStore
 	KVStore() kv.BinaryStore
 	SetStore() set.BinaryStore
+	LeaseStore() lease.Store
+	QueueStore() queue.BinaryStore
+	ScheduleStore() schedule.BinaryStore
+	BlobStore() blob.Store
 }
 
 // RunTests verifies that the driver's stores share the same data as the given
@@ -27,24 +38,50 @@ func RunTests(
 	journalStore journal.BinaryStore,
 	kvStore kv.BinaryStore,
 	setStore set.BinaryStore,
+	leaseStore lease.Store,
+	queueStore queue.BinaryStore,
+	scheduleStore schedule.BinaryStore,
+	blobStore blob.Store,
 ) {
 	t.Run("JournalStore", func(t *testing.T) {
 		t.Parallel()
-		testJournalStore(t, d.JournalStore(), journalStore)
+		RunJournalStoreTests(t, d.JournalStore(), journalStore)
 	})
 
 	t.Run("KVStore", func(t *testing.T) {
 		t.Parallel()
-		testKVStore(t, d.KVStore(), kvStore)
+		RunKVStoreTests(t, d.KVStore(), kvStore)
 	})
 
 	t.Run("SetStore", func(t *testing.T) {
 		t.Parallel()
-		testSetStore(t, d.SetStore(), setStore)
+		RunSetStoreTests(t, d.SetStore(), setStore)
+	})
+
+	t.Run("LeaseStore", func(t *testing.T) {
+		t.Parallel()
+		RunLeaseStoreTests(t, d.LeaseStore(), leaseStore)
+	})
+
+	t.Run("QueueStore", func(t *testing.T) {
+		t.Parallel()
+		RunQueueStoreTests(t, d.QueueStore(), queueStore)
+	})
+
+	t.Run("ScheduleStore", func(t *testing.T) {
+		t.Parallel()
+		RunScheduleStoreTests(t, d.ScheduleStore(), scheduleStore)
+	})
+
+	t.Run("BlobStore", func(t *testing.T) {
+		t.Parallel()
+		RunBlobStoreTests(t, d.BlobStore(), blobStore)
 	})
 }
 
-func testJournalStore(t *testing.T, writer, reader journal.BinaryStore) {
+// RunJournalStoreTests verifies that data written via writer can be read via
+// reader.
+func RunJournalStoreTests(t *testing.T, writer, reader journal.BinaryStore) {
 	ctx := t.Context()
 
 	w, err := writer.Open(ctx, "journal")
@@ -74,7 +111,9 @@ func testJournalStore(t *testing.T, writer, reader journal.BinaryStore) {
 	}
 }
 
-func testKVStore(t *testing.T, writer, reader kv.BinaryStore) {
+// RunKVStoreTests verifies that data written via writer can be read via
+// reader.
+func RunKVStoreTests(t *testing.T, writer, reader kv.BinaryStore) {
 	ctx := t.Context()
 
 	w, err := writer.Open(ctx, "keyspace")
@@ -103,7 +142,9 @@ func testKVStore(t *testing.T, writer, reader kv.BinaryStore) {
 	}
 }
 
-func testSetStore(t *testing.T, writer, reader set.BinaryStore) {
+// RunSetStoreTests verifies that data written via writer can be read via
+// reader.
+func RunSetStoreTests(t *testing.T, writer, reader set.BinaryStore) {
 	ctx := t.Context()
 
 	w, err := writer.Open(ctx, "set")
@@ -131,3 +172,145 @@ func testSetStore(t *testing.T, writer, reader set.BinaryStore) {
 		t.Fatal("set member not found via reader")
 	}
 }
+
+// RunLeaseStoreTests verifies that data written via writer can be read via
+// reader.
+func RunLeaseStoreTests(t *testing.T, writer, reader lease.Store) {
+	ctx := t.Context()
+
+	w, err := writer.Open(ctx, "lease")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if _, ok, err := w.Acquire(ctx, "<writer>", time.Minute); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatal("lease not acquired via writer")
+	}
+
+	r, err := reader.Open(ctx, "lease")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if _, ok, err := r.Acquire(ctx, "<reader>", time.Minute); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatal("lease acquired via reader while held via writer")
+	}
+}
+
+// RunQueueStoreTests verifies that data written via writer can be read via
+// reader.
+func RunQueueStoreTests(t *testing.T, writer, reader queue.BinaryStore) {
+	ctx := t.Context()
+
+	w, err := writer.Open(ctx, "queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if err := w.Enqueue(ctx, []byte("<value>")); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := reader.Open(ctx, "queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	m, ok, err := r.Dequeue(ctx, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("message not dequeued via reader")
+	}
+	if !bytes.Equal(m.Value, []byte("<value>")) {
+		t.Fatalf("unexpected value: got %q, want %q", m.Value, "<value>")
+	}
+}
+
+// RunScheduleStoreTests verifies that data written via writer can be read via
+// reader.
+func RunScheduleStoreTests(t *testing.T, writer, reader schedule.BinaryStore) {
+	ctx := t.Context()
+
+	w, err := writer.Open(ctx, "schedule")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if err := w.Schedule(ctx, "<id>", time.Now(), []byte("<value>")); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := reader.Open(ctx, "schedule")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	found := false
+	if err := r.RangeDue(
+		ctx,
+		time.Now(),
+		func(_ context.Context, item schedule.BinaryItem) (bool, error) {
+			found = item.ID == "<id>" && bytes.Equal(item.Value, []byte("<value>"))
+			return !found, nil
+		},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if !found {
+		t.Fatal("scheduled item not found via reader")
+	}
+}
+
+// RunBlobStoreTests verifies that data written via writer can be read via
+// reader.
+func RunBlobStoreTests(t *testing.T, writer, reader blob.Store) {
+	ctx := t.Context()
+
+	w, err := writer.Open(ctx, "blob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	ref, err := w.Put(ctx, bytes.NewReader([]byte("<content>")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := reader.Open(ctx, "blob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	rd, ok, err := r.Get(ctx, ref.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("blob not found via reader")
+	}
+	defer rd.Close()
+
+	content, err := io.ReadAll(rd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(content, []byte("<content>")) {
+		t.Fatalf("unexpected content: got %q, want %q", content, "<content>")
+	}
+}
@@ -0,0 +1,52 @@
+package drivertest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/health"
+)
+
+// HealthCheckingDriver is a [Driver] that can check the health of its
+// backend.
+type HealthCheckingDriver interface {
+	Driver
+	health.Checker
+}
+
+// RunHealthTests verifies that the driver reports each store as unhealthy
+// before it is provisioned, and as healthy afterwards.
+//
+// d must use backend resources that have not yet been provisioned.
+func RunHealthTests(t *testing.T, d HealthCheckingDriver) {
+	report := d.CheckHealth(t.Context())
+
+	for _, s := range health.Stores() {
+		res, ok := report.Result(s)
+		if !ok {
+			t.Fatalf("no result reported for the %s store", s)
+		}
+		if res.Err == nil {
+			t.Fatalf("expected the %s store to be unhealthy before it is provisioned", s)
+		}
+	}
+
+	for _, provision := range []func(context.Context) error{
+		d.JournalStore().Provision,
+		d.KVStore().Provision,
+		d.SetStore().Provision,
+		d.LeaseStore().Provision,
+		d.QueueStore().Provision,
+		d.ScheduleStore().Provision,
+		d.BlobStore().Provision,
+	} {
+		if err := provision(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report = d.CheckHealth(t.Context())
+	if err := report.Err(); err != nil {
+		t.Fatalf("expected all stores to be healthy after they are provisioned: %s", err)
+	}
+}
@@ -0,0 +1,87 @@
+package drivertest
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/health"
+	"github.com/dogmatiq/persistencekit/provision"
+)
+
+// ProvisioningDriver is a [Driver] that can plan its provisioning, and remove
+// its infrastructure.
+type ProvisioningDriver interface {
+	driver.Driver
+	health.Checker
+	provision.Planner
+	provision.Deprovisioner
+}
+
+// RunProvisionTests verifies that the driver's provisioning plans describe the
+// changes that are made by provisioning and deprovisioning its stores.
+//
+// d must use backend resources that have not yet been provisioned.
+func RunProvisionTests(t *testing.T, d ProvisioningDriver) {
+	planProvision := func(t *testing.T) provision.Plan {
+		t.Helper()
+
+		plan, err := d.PlanProvision(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return plan
+	}
+
+	planDeprovision := func(t *testing.T) provision.Plan {
+		t.Helper()
+
+		plan, err := d.PlanDeprovision(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return plan
+	}
+
+	if plan := planProvision(t); plan.Empty() {
+		t.Fatal("expected changes to be planned before the driver is provisioned")
+	}
+
+	if plan := planDeprovision(t); !plan.Empty() {
+		t.Fatalf("did not expect any changes to be planned before the driver is provisioned, got %v", plan.Changes)
+	}
+
+	if err := provision.Provision(t.Context(), d); err != nil {
+		t.Fatal(err)
+	}
+
+	if plan := planProvision(t); !plan.Empty() {
+		t.Fatalf("did not expect any changes to be planned after the driver is provisioned, got %v", plan.Changes)
+	}
+
+	if plan := planDeprovision(t); plan.Empty() {
+		t.Fatal("expected changes to be planned for deprovisioning after the driver is provisioned")
+	}
+
+	if err := d.Deprovision(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	if plan := planDeprovision(t); !plan.Empty() {
+		t.Fatalf("did not expect any changes to be planned after the driver is deprovisioned, got %v", plan.Changes)
+	}
+
+	if plan := planProvision(t); plan.Empty() {
+		t.Fatal("expected changes to be planned after the driver is deprovisioned")
+	}
+
+	report := d.CheckHealth(t.Context())
+	for _, s := range health.Stores() {
+		if res, _ := report.Result(s); res.Err == nil {
+			t.Fatalf("expected the %s store to be unhealthy after the driver is deprovisioned", s)
+		}
+	}
+
+	if err := d.Deprovision(t.Context()); err != nil {
+		t.Fatalf("expected deprovisioning to be idempotent: %s", err)
+	}
+}
@@ -0,0 +1,106 @@
+package linearizability
+
+import (
+	"strings"
+)
+
+// Model is a sequential specification of an object with state of type S that
+// accepts operations with inputs of type I and outputs of type O.
+type Model[S, I, O any] struct {
+	// Init returns the initial state of the object.
+	Init func() S
+
+	// Step applies an operation with the given input to s. It returns the
+	// resulting state, and false if out is not a valid output of the operation
+	// when applied to s. It must not modify s.
+	Step func(s S, in I, out O) (S, bool)
+
+	// Key returns a string that uniquely identifies s. It is used to avoid
+	// exploring equivalent states more than once.
+	Key func(s S) string
+}
+
+// Check returns true if the given history is linearizable with respect to the
+// model.
+//
+// The cost of checking grows exponentially with the amount of concurrency in
+// the history. Where the model permits, histories should be partitioned into
+// independent sub-histories (for example, by key) that are checked separately.
+func Check[S, I, O any](m Model[S, I, O], history []Operation[I, O]) bool {
+	c := &checker[S, I, O]{
+		model:   m,
+		history: history,
+		done:    make(bitset, (len(history)+63)/64),
+		visited: map[string]struct{}{},
+	}
+
+	return c.search(m.Init(), len(history))
+}
+
+type checker[S, I, O any] struct {
+	model   Model[S, I, O]
+	history []Operation[I, O]
+	done    bitset
+	visited map[string]struct{}
+}
+
+// search returns true if the remaining operations can be linearized starting
+// from state s.
+func (c *checker[S, I, O]) search(s S, remaining int) bool {
+	if remaining == 0 {
+		return true
+	}
+
+	// An operation may be linearized next only if it was called before every
+	// other remaining operation returned.
+	minReturn := int64(-1)
+	for i, op := range c.history {
+		if !c.done.Has(i) && (minReturn == -1 || op.Return < minReturn) {
+			minReturn = op.Return
+		}
+	}
+
+	for i, op := range c.history {
+		if c.done.Has(i) || op.Call > minReturn {
+			continue
+		}
+
+		next, ok := c.model.Step(s, op.Input, op.Output)
+		if !ok {
+			continue
+		}
+
+		c.done.Set(i)
+
+		key := c.done.String() + "|" + c.model.Key(next)
+		if _, seen := c.visited[key]; !seen {
+			c.visited[key] = struct{}{}
+
+			if c.search(next, remaining-1) {
+				return true
+			}
+		}
+
+		c.done.Clear(i)
+	}
+
+	return false
+}
+
+// bitset is a set of operation indices.
+type bitset []uint64
+
+func (b bitset) Has(i int) bool { return b[i/64]&(1<<(i%64)) != 0 }
+func (b bitset) Set(i int)      { b[i/64] |= 1 << (i % 64) }
+func (b bitset) Clear(i int)    { b[i/64] &^= 1 << (i % 64) }
+
+func (b bitset) String() string {
+	var w strings.Builder
+	for _, x := range b {
+		for range 8 {
+			w.WriteByte(byte(x))
+			x >>= 8
+		}
+	}
+	return w.String()
+}
@@ -0,0 +1,139 @@
+package linearizability_test
+
+import (
+	"strconv"
+	"testing"
+
+	. "github.com/dogmatiq/persistencekit/internal/linearizability"
+)
+
+// register is a model of a single integer register that supports reads and
+// writes.
+var register = Model[int, registerInput, int]{
+	Init: func() int { return 0 },
+	Step: func(s int, in registerInput, out int) (int, bool) {
+		if in.Write {
+			return in.Value, true
+		}
+		return s, out == s
+	},
+	Key: strconv.Itoa,
+}
+
+type registerInput struct {
+	Write bool
+	Value int
+}
+
+func write(client int, v int, call, ret int64) Operation[registerInput, int] {
+	return Operation[registerInput, int]{
+		Client: client,
+		Input:  registerInput{Write: true, Value: v},
+		Call:   call,
+		Return: ret,
+	}
+}
+
+func read(client int, v int, call, ret int64) Operation[registerInput, int] {
+	return Operation[registerInput, int]{
+		Client: client,
+		Output: v,
+		Call:   call,
+		Return: ret,
+	}
+}
+
+func TestCheck(t *testing.T) {
+	cases := []struct {
+		Desc    string
+		History []Operation[registerInput, int]
+		Want    bool
+	}{
+		{
+			"empty history",
+			nil,
+			true,
+		},
+		{
+			"sequential history",
+			[]Operation[registerInput, int]{
+				write(1, 1, 1, 2),
+				read(2, 1, 3, 4),
+			},
+			true,
+		},
+		{
+			"stale read after write has returned",
+			[]Operation[registerInput, int]{
+				write(1, 1, 1, 2),
+				read(2, 0, 3, 4),
+			},
+			false,
+		},
+		{
+			"read concurrent with write observes old value",
+			[]Operation[registerInput, int]{
+				write(1, 1, 1, 4),
+				read(2, 0, 2, 3),
+			},
+			true,
+		},
+		{
+			"read concurrent with write observes new value",
+			[]Operation[registerInput, int]{
+				write(1, 1, 1, 4),
+				read(2, 1, 2, 3),
+			},
+			true,
+		},
+		{
+			"reads observe concurrent writes in inconsistent orders",
+			[]Operation[registerInput, int]{
+				write(1, 1, 1, 10),
+				write(2, 2, 2, 11),
+				read(3, 1, 3, 4),
+				read(3, 2, 5, 6),
+				read(4, 2, 3, 4),
+				read(4, 1, 7, 8),
+			},
+			false,
+		},
+		{
+			"value reverts after a later read",
+			[]Operation[registerInput, int]{
+				write(1, 1, 1, 2),
+				read(2, 1, 3, 4),
+				read(3, 0, 5, 6),
+			},
+			false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			if got := Check(register, c.History); got != c.Want {
+				t.Fatalf("unexpected result: got %t, want %t", got, c.Want)
+			}
+		})
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	var r Recorder[registerInput, int]
+
+	r.Record(1, registerInput{Write: true, Value: 1}, func() int { return 0 })
+	r.Record(2, registerInput{}, func() int { return 1 })
+
+	history := r.History()
+	if len(history) != 2 {
+		t.Fatalf("unexpected history length: got %d, want 2", len(history))
+	}
+
+	if history[0].Return >= history[1].Call {
+		t.Fatal("expected the first operation to return before the second is called")
+	}
+
+	if !Check(register, history) {
+		t.Fatal("expected recorded history to be linearizable")
+	}
+}
@@ -0,0 +1,7 @@
+// Package linearizability checks concurrent histories of operations for
+// linearizability against a sequential model.
+//
+// A history is linearizable if each operation can be assigned a single point
+// in time, between its call and its return, such that applying the operations
+// to the model in that order produces the observed outputs.
+package linearizability
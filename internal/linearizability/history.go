@@ -0,0 +1,118 @@
+package linearizability
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Operation is a single operation within a concurrent history.
+type Operation[I, O any] struct {
+	// Client identifies the client that performed the operation.
+	Client int
+
+	// Input describes the operation and its arguments.
+	Input I
+
+	// Output describes the result of the operation.
+	Output O
+
+	// Call and Return are the logical times at which the operation was
+	// called and returned, respectively.
+	Call, Return int64
+}
+
+// Recorder records the operations performed by concurrent clients.
+//
+// It is safe for concurrent use.
+type Recorder[I, O any] struct {
+	clock atomic.Int64
+
+	m   sync.Mutex
+	ops []Operation[I, O]
+}
+
+// Record calls fn to perform an operation on behalf of the given client, and
+// records its input and output.
+func (r *Recorder[I, O]) Record(client int, in I, fn func() O) O {
+	call := r.clock.Add(1)
+	out := fn()
+	ret := r.clock.Add(1)
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.ops = append(r.ops, Operation[I, O]{
+		Client: client,
+		Input:  in,
+		Output: out,
+		Call:   call,
+		Return: ret,
+	})
+
+	return out
+}
+
+// History returns the operations that have been recorded.
+func (r *Recorder[I, O]) History() []Operation[I, O] {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return append([]Operation[I, O](nil), r.ops...)
+}
+
+// Run performs the operations in each of the given plans concurrently, one
+// goroutine per plan, and returns the resulting history.
+//
+// apply performs a single operation on behalf of a client, where the client is
+// identified by the index of its plan.
+func Run[I, O any](plans [][]I, apply func(client int, in I) O) []Operation[I, O] {
+	var (
+		r Recorder[I, O]
+		g sync.WaitGroup
+	)
+
+	for client, plan := range plans {
+		g.Go(func() {
+			for _, in := range plan {
+				r.Record(client, in, func() O {
+					return apply(client, in)
+				})
+			}
+		})
+	}
+
+	g.Wait()
+
+	return r.History()
+}
+
+// Describe returns a human-readable representation of a history, with the
+// operations in the order that they were called.
+func Describe[I, O any](history []Operation[I, O]) string {
+	history = slices.Clone(history)
+	slices.SortFunc(
+		history,
+		func(a, b Operation[I, O]) int {
+			return cmp.Compare(a.Call, b.Call)
+		},
+	)
+
+	var w strings.Builder
+	for _, op := range history {
+		fmt.Fprintf(
+			&w,
+			"[%3d, %3d] client %d: %v => %v\n",
+			op.Call,
+			op.Return,
+			op.Client,
+			op.Input,
+			op.Output,
+		)
+	}
+
+	return w.String()
+}
@@ -66,6 +66,9 @@ func Benchmark(
 		if err != nil {
 			b.Fatal(err)
 		}
+
+		// The timer must be running when b.Loop() is called.
+		b.StartTimer()
 	}
 }
 
@@ -0,0 +1,57 @@
+package journal
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+)
+
+// maxTestedRecordSize is the largest record size limit that the capability
+// tests exercise. Larger limits are assumed to be correct, as testing them
+// would be prohibitively slow.
+const maxTestedRecordSize = 4 << 20
+
+// runCapabilityTests runs tests that confirm that the store behaves as
+// described by its [Capabilities]. Tests for capabilities that the store does
+// not report are skipped.
+func runCapabilityTests(t *testing.T, store BinaryStore) {
+	caps := CapabilitiesOf(store)
+
+	t.Run("Capabilities", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("it accepts records of the maximum size", func(t *testing.T) {
+			t.Parallel()
+
+			if caps.MaxRecordSize == 0 {
+				t.Skip("the store does not report a maximum record size")
+			}
+
+			if caps.MaxRecordSize > maxTestedRecordSize {
+				t.Skip("the maximum record size is too large to test")
+			}
+
+			j, err := store.Open(t.Context(), xtesting.SequentialName("journal"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer j.Close()
+
+			rec := bytes.Repeat([]byte("r"), caps.MaxRecordSize)
+
+			if err := j.Append(t.Context(), 0, rec); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := j.Get(t.Context(), 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(got, rec) {
+				t.Fatalf("unexpected record: got %d byte(s), want %d", len(got), len(rec))
+			}
+		})
+	})
+}
@@ -52,6 +52,10 @@ func (s *interceptedStore[T]) Provision(ctx context.Context) error {
 	return s.Next.Provision(ctx)
 }
 
+func (s *interceptedStore[T]) Capabilities() Capabilities {
+	return CapabilitiesOf(s.Next)
+}
+
 func (s *interceptedStore[T]) Open(ctx context.Context, name string) (Journal[T], error) {
 	if fn := s.Interceptor.beforeOpen.Load(); fn != nil {
 		if err := fn(name); err != nil {
@@ -117,6 +121,10 @@ func (j *interceptedJournal[T]) Truncate(ctx context.Context, pos Position) erro
 	return j.Next.Truncate(ctx, pos)
 }
 
+func (j *interceptedJournal[T]) Stats(ctx context.Context) (Stats, error) {
+	return StatsOf(ctx, j.Next)
+}
+
 func (j *interceptedJournal[T]) Close() error {
 	return j.Next.Close()
 }
@@ -0,0 +1,241 @@
+package journal
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/internal/linearizability"
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+	"pgregory.net/rapid"
+)
+
+// runLinearizabilityTests runs randomized concurrent operations against
+// journals in the given store, and checks that the resulting histories are
+// linearizable.
+func runLinearizabilityTests(t *testing.T, store BinaryStore) {
+	t.Run("linearizability", func(t *testing.T) {
+		t.Parallel()
+
+		rapid.Check(t, func(t *rapid.T) {
+			name := xtesting.SequentialName("journal")
+
+			clients := rapid.IntRange(2, 4).Draw(t, "clients")
+			plans := make([][]journalInput, clients)
+			for i := range plans {
+				plans[i] = rapid.SliceOfN(journalInputGenerator, 1, 8).Draw(t, fmt.Sprintf("client %d", i))
+			}
+
+			// Each client uses its own handle, as it would if each were a
+			// separate process. Each client also remembers the bounds that it
+			// last observed, which determine the positions that it operates
+			// on.
+			states := make([]*journalClient, clients)
+			for i := range states {
+				j, err := store.Open(t.Context(), name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer j.Close()
+				states[i] = &journalClient{ID: i, Journal: j}
+			}
+
+			history := linearizability.Run(
+				plans,
+				func(client int, in journalInput) journalOutput {
+					return in.apply(t, states[client])
+				},
+			)
+
+			for _, op := range history {
+				if op.Output.Err != nil {
+					t.Fatalf("unexpected error: %v => %s\n%s", op.Input, op.Output.Err, linearizability.Describe(history))
+				}
+			}
+
+			if !linearizability.Check(journalModel, history) {
+				t.Fatalf("history is not linearizable:\n%s", linearizability.Describe(history))
+			}
+		})
+	})
+}
+
+// journalClient is the state of a single client that performs operations
+// on a journal.
+type journalClient struct {
+	ID      int
+	Journal BinaryJournal
+
+	// Bounds is the last known bounds of the journal.
+	Bounds Interval
+
+	// Appends is the number of records the client has attempted to append.
+	Appends int
+}
+
+// journalInput describes an operation performed on a journal.
+type journalInput struct {
+	Op string
+
+	// Offset is added to the beginning of the client's last known bounds to
+	// determine the position used by Get and Truncate.
+	Offset int
+}
+
+func (in journalInput) String() string {
+	return in.Op
+}
+
+// journalOutput describes the result of a [journalInput] operation.
+type journalOutput struct {
+	// Position is the position that was operated upon by Get, Append or
+	// Truncate.
+	Position Position
+
+	// Record is the record returned by Get, or appended by Append.
+	Record string
+
+	// Bounds is the result of Bounds.
+	Bounds Interval
+
+	// NotFound is true if Get returned a [RecordNotFoundError].
+	NotFound bool
+
+	// Conflict is true if Append returned a [ConflictError].
+	Conflict bool
+
+	Err error
+}
+
+func (out journalOutput) String() string {
+	switch {
+	case out.Err != nil:
+		return out.Err.Error()
+	case out.NotFound:
+		return fmt.Sprintf("position %d, not found", out.Position)
+	case out.Conflict:
+		return fmt.Sprintf("position %d, conflict", out.Position)
+	case out.Record != "":
+		return fmt.Sprintf("position %d, record %q", out.Position, out.Record)
+	default:
+		return fmt.Sprintf("position %d, bounds %s", out.Position, out.Bounds)
+	}
+}
+
+// journalInputGenerator generates operations on a journal.
+var journalInputGenerator = rapid.Custom(func(t *rapid.T) journalInput {
+	return journalInput{
+		Op:     rapid.SampledFrom([]string{"Bounds", "Get", "Append", "Append", "Truncate"}).Draw(t, "op"),
+		Offset: rapid.IntRange(0, 3).Draw(t, "offset"),
+	}
+})
+
+// apply performs the operation on behalf of the client c.
+//
+// Appends are always made at the end of the client's last known bounds, and
+// truncations never extend beyond it, such that no operation has undefined
+// behavior regardless of concurrent appends made by other clients.
+func (in journalInput) apply(t *rapid.T, c *journalClient) (out journalOutput) {
+	ctx := t.Context()
+
+	switch in.Op {
+	case "Bounds":
+		out.Bounds, out.Err = c.Journal.Bounds(ctx)
+		if out.Err == nil {
+			c.Bounds = out.Bounds
+		}
+
+	case "Get":
+		out.Position = c.Bounds.Begin + Position(in.Offset)
+
+		var rec []byte
+		rec, out.Err = c.Journal.Get(ctx, out.Position)
+		out.Record = string(rec)
+		if IsNotFound(out.Err) {
+			out.NotFound = true
+			out.Err = nil
+		}
+
+	case "Append":
+		c.Appends++
+		out.Position = c.Bounds.End
+		out.Record = fmt.Sprintf("client %d, record %d", c.ID, c.Appends)
+
+		out.Err = c.Journal.Append(ctx, out.Position, []byte(out.Record))
+		if IsConflict(out.Err) {
+			out.Conflict = true
+			out.Err = nil
+		} else if out.Err == nil {
+			c.Bounds.End++
+		}
+
+	case "Truncate":
+		out.Position = min(c.Bounds.Begin+Position(in.Offset), c.Bounds.End)
+
+		out.Err = c.Journal.Truncate(ctx, out.Position)
+		if out.Err == nil {
+			c.Bounds.Begin = max(c.Bounds.Begin, out.Position)
+		}
+
+	default:
+		panic("unrecognized operation: " + in.Op)
+	}
+
+	return out
+}
+
+// journalState is the state of [journalModel].
+type journalState struct {
+	Begin Position
+
+	// Records contains every record that has been appended, including those
+	// that have since been truncated.
+	Records []string
+}
+
+// journalModel is a sequential model of a journal.
+var journalModel = linearizability.Model[journalState, journalInput, journalOutput]{
+	Init: func() journalState {
+		return journalState{}
+	},
+	Step: func(s journalState, in journalInput, out journalOutput) (journalState, bool) {
+		end := Position(len(s.Records))
+
+		switch in.Op {
+		case "Bounds":
+			return s, out.Bounds == Interval{s.Begin, end}
+
+		case "Get":
+			if out.Position < s.Begin || out.Position >= end {
+				return s, out.NotFound
+			}
+			return s, !out.NotFound && out.Record == s.Records[out.Position]
+
+		case "Append":
+			if out.Position < end {
+				return s, out.Conflict
+			}
+			if out.Position > end || out.Conflict {
+				return s, false
+			}
+
+			// Clip the capacity of the slice so that append always copies, as
+			// Step must not modify s.
+			s.Records = append(s.Records[:end:end], out.Record)
+			return s, true
+
+		case "Truncate":
+			if out.Position > end {
+				return s, false
+			}
+			s.Begin = max(s.Begin, out.Position)
+			return s, true
+
+		default:
+			panic("unrecognized operation: " + in.Op)
+		}
+	},
+	Key: func(s journalState) string {
+		return fmt.Sprintf("%d %s", s.Begin, strings.Join(s.Records, "\x00"))
+	},
+}
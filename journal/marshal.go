@@ -22,6 +22,10 @@ type mstore[T any] struct {
 	m marshaler.Marshaler[T]
 }
 
+func (s *mstore[T]) Capabilities() Capabilities {
+	return CapabilitiesOf(s.BinaryStore)
+}
+
 func (s *mstore[T]) Open(ctx context.Context, name string) (Journal[T], error) {
 	j, err := s.BinaryStore.Open(ctx, name)
 	if err != nil {
@@ -70,3 +74,7 @@ func (j *mjourn[T]) Append(ctx context.Context, pos Position, rec T) error {
 
 	return j.BinaryJournal.Append(ctx, pos, data)
 }
+
+func (j *mjourn[T]) Stats(ctx context.Context) (Stats, error) {
+	return StatsOf(ctx, j.BinaryJournal)
+}
@@ -18,6 +18,10 @@ type nameTransformStore[T any] struct {
 	transform func(string) string
 }
 
+func (s *nameTransformStore[T]) Capabilities() Capabilities {
+	return CapabilitiesOf(s.Store)
+}
+
 func (s *nameTransformStore[T]) Open(ctx context.Context, name string) (Journal[T], error) {
 	j, err := s.Store.Open(ctx, s.transform(name))
 	if err != nil {
@@ -35,3 +39,7 @@ type nameTransformJournal[T any] struct {
 func (j *nameTransformJournal[T]) Name() string {
 	return j.name
 }
+
+func (j *nameTransformJournal[T]) Stats(ctx context.Context) (Stats, error) {
+	return StatsOf(ctx, j.Journal)
+}
@@ -0,0 +1,67 @@
+package journal
+
+import "context"
+
+// Stats contains statistics about the records in a [Journal].
+type Stats struct {
+	// Records is the number of records in the journal, excluding any that have
+	// been truncated.
+	Records uint64
+
+	// Bytes is the total size of the binary representation of the records in
+	// the journal.
+	Bytes uint64
+}
+
+// StatsReporter is an interface for journals that can report their [Stats]
+// more efficiently than by ranging over every record.
+type StatsReporter interface {
+	// Stats returns statistics about the records in the journal.
+	Stats(ctx context.Context) (Stats, error)
+}
+
+// StatsOf returns statistics about the records in j.
+//
+// If j implements [StatsReporter] its statistics are used. Otherwise, if j is a
+// [BinaryJournal], they are computed by ranging over every record. If j is
+// neither, only [Stats.Records] is populated, based on the journal's bounds.
+//
+// The statistics are a snapshot, and may be outdated by the time they are
+// returned.
+func StatsOf[T any](ctx context.Context, j Journal[T]) (Stats, error) {
+	if r, ok := j.(StatsReporter); ok {
+		return r.Stats(ctx)
+	}
+
+	bj, ok := any(j).(BinaryJournal)
+	if !ok {
+		bounds, err := j.Bounds(ctx)
+		return Stats{Records: uint64(bounds.Len())}, err
+	}
+
+	for {
+		bounds, err := bj.Bounds(ctx)
+		if bounds.IsEmpty() || err != nil {
+			return Stats{}, err
+		}
+
+		var stats Stats
+
+		err = bj.Range(
+			ctx,
+			bounds.Begin,
+			func(_ context.Context, _ Position, rec []byte) (bool, error) {
+				stats.Records++
+				stats.Bytes += uint64(len(rec))
+				return true, nil
+			},
+		)
+
+		if !IsNotFound(err) {
+			return stats, err
+		}
+
+		// The journal was truncated after the call to Bounds() but before the
+		// records were ranged over, so we re-read the bounds and try again.
+	}
+}
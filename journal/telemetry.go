@@ -37,6 +37,10 @@ func (s *instrumentedStore) Provision(ctx context.Context) error {
 	return s.Next.Provision(ctx)
 }
 
+func (s *instrumentedStore) Capabilities() Capabilities {
+	return CapabilitiesOf(s.Next)
+}
+
 // Open returns the journal with the given name.
 func (s *instrumentedStore) Open(ctx context.Context, name string) (BinaryJournal, error) {
 	telem := s.Telemetry.Recorder(
@@ -256,6 +260,26 @@ func (j *instrumentedJournal) Truncate(ctx context.Context, pos Position) error
 	return nil
 }
 
+func (j *instrumentedJournal) Stats(ctx context.Context) (Stats, error) {
+	ctx, span := j.Telemetry.StartSpan(ctx, "journal.stats")
+	defer span.End()
+
+	stats, err := StatsOf(ctx, j.Next)
+	if err != nil {
+		j.Telemetry.Error(ctx, "journal.stats.error", "unable to fetch journal statistics", err)
+		return Stats{}, err
+	}
+
+	span.SetAttributes(
+		telemetry.Int("records", stats.Records),
+		telemetry.Int("bytes", stats.Bytes),
+	)
+
+	j.Telemetry.Info(ctx, "journal.stats.ok", "fetched journal statistics")
+
+	return stats, nil
+}
+
 func (j *instrumentedJournal) Close() error {
 	if j.Next == nil {
 		// Closing an already-closed resource is not an error, allowing Close()
@@ -689,6 +689,55 @@ func RunTests(
 			})
 		})
 
+		t.Run("StatsOf", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it returns zero statistics for an empty journal", func(t *testing.T) {
+				t.Parallel()
+
+				j := setup(t)
+
+				stats, err := StatsOf(t.Context(), j)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if stats != (Stats{}) {
+					t.Fatalf("unexpected statistics: got %+v, want zero", stats)
+				}
+			})
+
+			t.Run("it excludes truncated records", func(t *testing.T) {
+				t.Parallel()
+
+				j := setup(t)
+
+				for pos := range Position(3) {
+					if err := j.Append(t.Context(), pos, []byte("<record>")); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				if err := j.Truncate(t.Context(), 1); err != nil {
+					t.Fatal(err)
+				}
+
+				stats, err := StatsOf(t.Context(), j)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				expect := Stats{
+					Records: 2,
+					Bytes:   2 * uint64(len("<record>")),
+				}
+
+				if stats != expect {
+					t.Fatalf("unexpected statistics: got %+v, want %+v", stats, expect)
+				}
+			})
+		})
+
 		t.Run("Truncate", func(t *testing.T) {
 			t.Parallel()
 
@@ -1032,6 +1081,9 @@ func RunTests(
 			)
 		})
 	})
+
+	runCapabilityTests(t, store)
+	runLinearizabilityTests(t, store)
 }
 
 // appendRecords appends records to j.
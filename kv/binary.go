@@ -16,6 +16,50 @@ type BinaryKeyspace = Keyspace[[]byte, []byte]
 // Otherwise, if ok is false, ranging stops without any error being propagated.
 type BinaryRangeFunc = RangeFunc[[]byte, []byte]
 
+// A BinaryKeyRangeFunc is a function used to range over the keys in a
+// [BinaryKeyspace] without their associated values.
+//
+// If err is non-nil, ranging stops and err is propagated up the stack.
+// Otherwise, if ok is false, ranging stops without any error being propagated.
+type BinaryKeyRangeFunc = KeyRangeFunc[[]byte]
+
 // BinaryInterceptor is an [Interceptor] that can be used to intercept
 // operations on a [BinaryKeyspace].
 type BinaryInterceptor = Interceptor[[]byte, []byte]
+
+// BinaryChange describes a modification to a key/value pair within a
+// [BinaryKeyspace].
+type BinaryChange = Change[[]byte, []byte]
+
+// A BinaryChangeFeed is an ordered log of the changes made to the key/value
+// pairs in a [BinaryKeyspace].
+type BinaryChangeFeed = ChangeFeed[[]byte, []byte]
+
+// BinaryChangeFeedStore is a collection of [BinaryChangeFeed] values, one for
+// each keyspace in a [BinaryStore].
+type BinaryChangeFeedStore = ChangeFeedStore[[]byte, []byte]
+
+// A BinaryChangeFunc is a function used to range over the changes in a
+// [BinaryChangeFeed].
+//
+// If err is non-nil, ranging stops and err is propagated up the stack.
+// Otherwise, if ok is false, ranging stops without any error being propagated.
+type BinaryChangeFunc = ChangeFunc[[]byte, []byte]
+
+// A BinaryVersionedKeyspace is a [BinaryKeyspace] that retains the previous
+// versions of each key.
+type BinaryVersionedKeyspace = VersionedKeyspace[[]byte, []byte]
+
+// BinaryVersionedStore is a collection of [BinaryVersionedKeyspace] values.
+type BinaryVersionedStore = VersionedStore[[]byte, []byte]
+
+// BinaryVersion is the value associated with a key in a
+// [BinaryVersionedKeyspace] at a specific revision.
+type BinaryVersion = Version[[]byte]
+
+// A BinaryHistoryFunc is a function used to range over the previous versions
+// of a key in a [BinaryVersionedKeyspace].
+//
+// If err is non-nil, ranging stops and err is propagated up the stack.
+// Otherwise, if ok is false, ranging stops without any error being propagated.
+type BinaryHistoryFunc = HistoryFunc[[]byte]
@@ -0,0 +1,58 @@
+package kv
+
+// Capabilities describes the limits and optional features of a [Store]
+// implementation.
+//
+// The zero value makes no claims; it describes a store with no known limits
+// and no optional features.
+type Capabilities struct {
+	// MaxKeySize is the maximum size of a key's binary representation, in
+	// bytes. Zero means that no limit is known.
+	MaxKeySize int
+
+	// MaxValueSize is the maximum size of a value's binary representation, in
+	// bytes. Zero means that no limit is known.
+	MaxValueSize int
+
+	// OrderedRange is true if [Keyspace.Range] visits the key/value pairs in
+	// ascending lexicographical order of the keys' binary representation.
+	OrderedRange bool
+
+	// ChangeFeed is true if the store also implements [ChangeFeedStore]
+	// natively.
+	ChangeFeed bool
+
+	// ConflictDetail is true if the store populates the CurrentValue,
+	// CurrentRevision and CurrentKnown fields of the [ConflictError] values
+	// returned by [Keyspace.Set].
+	ConflictDetail bool
+
+	// MeteredRequests is true if each request to the store's backend is
+	// billed individually. Callers should prefer fewer, larger operations.
+	MeteredRequests bool
+}
+
+// CapabilityReporter is an interface for stores that describe their
+// [Capabilities].
+type CapabilityReporter interface {
+	// Capabilities returns the limits and optional features of the store.
+	Capabilities() Capabilities
+}
+
+// CapabilitiesOf returns the [Capabilities] of s.
+//
+// If s does not implement [CapabilityReporter], it returns the zero value.
+func CapabilitiesOf[K, V any](s Store[K, V]) Capabilities {
+	if r, ok := s.(CapabilityReporter); ok {
+		return r.Capabilities()
+	}
+	return Capabilities{}
+}
+
+// wrappedCapabilities returns the capabilities of a store that wraps s
+// without providing a change feed of its own.
+func wrappedCapabilities[K, V any](s Store[K, V]) Capabilities {
+	c := CapabilitiesOf(s)
+	c.ChangeFeed = false
+	return c
+}
@@ -0,0 +1,183 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+)
+
+// maxTestedValueSize is the largest value size limit that the capability tests
+// exercise. Larger limits are assumed to be correct, as testing them would be
+// prohibitively slow.
+const maxTestedValueSize = 4 << 20
+
+// runCapabilityTests runs tests that confirm that the store behaves as
+// described by its [Capabilities]. Tests for capabilities that the store does
+// not report are skipped.
+func runCapabilityTests(t *testing.T, store BinaryStore) {
+	caps := CapabilitiesOf(store)
+
+	setup := func(t *testing.T) BinaryKeyspace {
+		ks, err := store.Open(t.Context(), xtesting.SequentialName("keyspace"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() {
+			if err := ks.Close(); err != nil {
+				t.Error(err)
+			}
+		})
+
+		return ks
+	}
+
+	t.Run("Capabilities", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("it accepts keys of the maximum size", func(t *testing.T) {
+			t.Parallel()
+
+			if caps.MaxKeySize == 0 {
+				t.Skip("the store does not report a maximum key size")
+			}
+
+			ks := setup(t)
+			k := bytes.Repeat([]byte("k"), caps.MaxKeySize)
+
+			if err := ks.SetUnconditional(t.Context(), k, []byte("<value>")); err != nil {
+				t.Fatal(err)
+			}
+
+			v, _, err := ks.Get(t.Context(), k)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(v, []byte("<value>")) {
+				t.Fatalf("unexpected value: got %q, want %q", v, "<value>")
+			}
+		})
+
+		t.Run("it accepts values of the maximum size", func(t *testing.T) {
+			t.Parallel()
+
+			if caps.MaxValueSize == 0 {
+				t.Skip("the store does not report a maximum value size")
+			}
+
+			if caps.MaxValueSize > maxTestedValueSize {
+				t.Skip("the maximum value size is too large to test")
+			}
+
+			ks := setup(t)
+			v := bytes.Repeat([]byte("v"), caps.MaxValueSize)
+
+			if err := ks.SetUnconditional(t.Context(), []byte("<key>"), v); err != nil {
+				t.Fatal(err)
+			}
+
+			got, _, err := ks.Get(t.Context(), []byte("<key>"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(got, v) {
+				t.Fatalf("unexpected value: got %d byte(s), want %d", len(got), len(v))
+			}
+		})
+
+		t.Run("it ranges over key/value pairs in key order", func(t *testing.T) {
+			t.Parallel()
+
+			if !caps.OrderedRange {
+				t.Skip("the store does not report ordered ranging")
+			}
+
+			ks := setup(t)
+
+			want := [][]byte{
+				{0x00},
+				{0x00, 0x00},
+				{0x01},
+				[]byte("a"),
+				[]byte("ab"),
+				[]byte("b"),
+				{0xff},
+			}
+
+			for _, i := range []int{4, 0, 6, 2, 5, 1, 3} {
+				if err := ks.SetUnconditional(t.Context(), want[i], []byte("<value>")); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var got [][]byte
+			if err := ks.Range(
+				t.Context(),
+				func(_ context.Context, k, _ []byte, _ Revision) (bool, error) {
+					got = append(got, k)
+					return true, nil
+				},
+			); err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.EqualFunc(got, want, bytes.Equal) {
+				t.Fatalf("unexpected key order: got %q, want %q", got, want)
+			}
+		})
+
+		t.Run("it implements ChangeFeedStore if it reports a change feed", func(t *testing.T) {
+			t.Parallel()
+
+			if !caps.ChangeFeed {
+				t.Skip("the store does not report a change feed")
+			}
+
+			if _, ok := store.(BinaryChangeFeedStore); !ok {
+				t.Fatalf("%T reports a change feed but does not implement BinaryChangeFeedStore", store)
+			}
+		})
+
+		t.Run("it populates conflict details if it reports them", func(t *testing.T) {
+			t.Parallel()
+
+			if !caps.ConflictDetail {
+				t.Skip("the store does not report conflict details")
+			}
+
+			ks := setup(t)
+
+			r, err := ks.Set(t.Context(), []byte("<key>"), []byte("<value>"), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = ks.Set(t.Context(), []byte("<key>"), []byte("<other>"), "")
+
+			var conflict ConflictError[[]byte, []byte]
+			if !errors.As(err, &conflict) {
+				t.Fatalf("expected a conflict error, got %v", err)
+			}
+
+			if !conflict.CurrentKnown {
+				t.Fatal("expected the conflict error to include the current value")
+			}
+
+			if !bytes.Equal(conflict.CurrentValue, []byte("<value>")) || conflict.CurrentRevision != r {
+				t.Fatalf(
+					"unexpected current value: got %q at revision %q, want %q at revision %q",
+					conflict.CurrentValue,
+					conflict.CurrentRevision,
+					"<value>",
+					r,
+				)
+			}
+		})
+	})
+}
@@ -0,0 +1,67 @@
+package kv
+
+import (
+	"context"
+)
+
+// Cursor is the position of a [Change] within a [ChangeFeed]. The first change
+// in a feed is always at cursor 0.
+type Cursor uint64
+
+// Change describes a modification to a key/value pair within a [Keyspace].
+type Change[K, V any] struct {
+	// Key is the key that was modified.
+	Key K
+
+	// Value is the new value associated with Key. It is the zero-value of V
+	// if the key was deleted.
+	Value V
+
+	// Revision is the new revision of Key. It is empty if the key was deleted.
+	Revision Revision
+
+	// Deleted is true if the change removed Key from the keyspace.
+	Deleted bool
+}
+
+// A ChangeFunc is a function used to range over the changes in a
+// [ChangeFeed].
+//
+// c is the cursor of the change. Ranging can be resumed after the change by
+// starting at c + 1.
+//
+// If err is non-nil, ranging stops and err is propagated up the stack.
+// Otherwise, if ok is false, ranging stops without any error being propagated.
+type ChangeFunc[K, V any] func(ctx context.Context, c Cursor, ch Change[K, V]) (ok bool, err error)
+
+// A ChangeFeed is an ordered log of the changes made to the key/value pairs in
+// a [Keyspace].
+type ChangeFeed[K, V any] interface {
+	// Name returns the name of the keyspace.
+	Name() string
+
+	// End returns the cursor at which the next change will be recorded.
+	//
+	// Ranging from the end visits only those changes made after End returns.
+	End(ctx context.Context) (Cursor, error)
+
+	// Range invokes fn for each change in the feed, in order, starting with
+	// the change at c.
+	//
+	// It returns without error once it reaches the end of the feed. If c is
+	// at or beyond the end, fn is not called.
+	//
+	// It returns a [ChangeNotFoundError] if the change at c has been discarded.
+	Range(ctx context.Context, c Cursor, fn ChangeFunc[K, V]) error
+
+	// Close closes the feed.
+	Close() error
+}
+
+// ChangeFeedStore is a collection of [ChangeFeed] values, one for each
+// keyspace in a [Store].
+type ChangeFeedStore[K, V any] interface {
+	// OpenChangeFeed returns the change feed of the keyspace with the given
+	// name.
+	OpenChangeFeed(ctx context.Context, name string) (ChangeFeed[K, V], error)
+}
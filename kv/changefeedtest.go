@@ -0,0 +1,221 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+	"github.com/google/go-cmp/cmp"
+)
+
+// RunChangeFeedTests runs tests that confirm a [BinaryChangeFeedStore]
+// implementation records the changes made to the keyspaces in a [BinaryStore]
+// correctly.
+func RunChangeFeedTests(
+	t *testing.T,
+	store BinaryStore,
+	feeds BinaryChangeFeedStore,
+) {
+	setup := func(t *testing.T) (BinaryKeyspace, BinaryChangeFeed) {
+		name := xtesting.SequentialName("keyspace")
+
+		ks, err := store.Open(t.Context(), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() {
+			if err := ks.Close(); err != nil {
+				t.Error(err)
+			}
+		})
+
+		f, err := feeds.OpenChangeFeed(t.Context(), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() {
+			if err := f.Close(); err != nil {
+				t.Error(err)
+			}
+		})
+
+		if f.Name() != name {
+			t.Fatalf("unexpected change feed name: got %q, want %q", f.Name(), name)
+		}
+
+		return ks, f
+	}
+
+	readAll := func(t *testing.T, f BinaryChangeFeed, c Cursor) []BinaryChange {
+		t.Helper()
+
+		var changes []BinaryChange
+
+		if err := f.Range(
+			t.Context(),
+			c,
+			func(_ context.Context, actual Cursor, ch BinaryChange) (bool, error) {
+				if expect := c + Cursor(len(changes)); actual != expect {
+					t.Fatalf("unexpected cursor: got %d, want %d", actual, expect)
+				}
+				changes = append(changes, ch)
+				return true, nil
+			},
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		return changes
+	}
+
+	t.Run("ChangeFeed", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("it records each change in order", func(t *testing.T) {
+			t.Parallel()
+
+			ks, f := setup(t)
+
+			r1, err := ks.Set(t.Context(), []byte("<key-1>"), []byte("<value-1>"), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r2, err := ks.Set(t.Context(), []byte("<key-2>"), []byte("<value-2>"), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r3, err := ks.Set(t.Context(), []byte("<key-1>"), []byte("<value-3>"), r1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := ks.Set(t.Context(), []byte("<key-2>"), nil, r2); err != nil {
+				t.Fatal(err)
+			}
+
+			expect := []BinaryChange{
+				{Key: []byte("<key-1>"), Value: []byte("<value-1>"), Revision: r1},
+				{Key: []byte("<key-2>"), Value: []byte("<value-2>"), Revision: r2},
+				{Key: []byte("<key-1>"), Value: []byte("<value-3>"), Revision: r3},
+				{Key: []byte("<key-2>"), Deleted: true},
+			}
+
+			if diff := cmp.Diff(expect, readAll(t, f, 0)); diff != "" {
+				t.Fatalf("unexpected changes (-want +got):\n%s", diff)
+			}
+		})
+
+		t.Run("it records unconditional changes", func(t *testing.T) {
+			t.Parallel()
+
+			ks, f := setup(t)
+
+			if err := ks.SetUnconditional(t.Context(), []byte("<key>"), []byte("<value>")); err != nil {
+				t.Fatal(err)
+			}
+
+			_, r, err := ks.Get(t.Context(), []byte("<key>"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := ks.SetUnconditional(t.Context(), []byte("<key>"), nil); err != nil {
+				t.Fatal(err)
+			}
+
+			expect := []BinaryChange{
+				{Key: []byte("<key>"), Value: []byte("<value>"), Revision: r},
+				{Key: []byte("<key>"), Deleted: true},
+			}
+
+			if diff := cmp.Diff(expect, readAll(t, f, 0)); diff != "" {
+				t.Fatalf("unexpected changes (-want +got):\n%s", diff)
+			}
+		})
+
+		t.Run("it does not record failed or no-op changes", func(t *testing.T) {
+			t.Parallel()
+
+			ks, f := setup(t)
+
+			if _, err := ks.Set(t.Context(), []byte("<key>"), nil, ""); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := ks.Set(t.Context(), []byte("<key>"), []byte("<value>"), "<wrong>"); !IsConflict(err) {
+				t.Fatalf("expected a conflict error, got %v", err)
+			}
+
+			if changes := readAll(t, f, 0); len(changes) != 0 {
+				t.Fatalf("unexpected changes: %v", changes)
+			}
+		})
+
+		t.Run("it can be resumed from a cursor", func(t *testing.T) {
+			t.Parallel()
+
+			ks, f := setup(t)
+
+			if _, err := ks.Set(t.Context(), []byte("<key-1>"), []byte("<value-1>"), ""); err != nil {
+				t.Fatal(err)
+			}
+
+			end, err := f.End(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if end != 1 {
+				t.Fatalf("unexpected end: got %d, want 1", end)
+			}
+
+			if changes := readAll(t, f, end); len(changes) != 0 {
+				t.Fatalf("unexpected changes: %v", changes)
+			}
+
+			r, err := ks.Set(t.Context(), []byte("<key-2>"), []byte("<value-2>"), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect := []BinaryChange{
+				{Key: []byte("<key-2>"), Value: []byte("<value-2>"), Revision: r},
+			}
+
+			if diff := cmp.Diff(expect, readAll(t, f, end)); diff != "" {
+				t.Fatalf("unexpected changes (-want +got):\n%s", diff)
+			}
+		})
+
+		t.Run("it stops ranging if the function returns false", func(t *testing.T) {
+			t.Parallel()
+
+			ks, f := setup(t)
+
+			for _, k := range []string{"<key-1>", "<key-2>"} {
+				if _, err := ks.Set(t.Context(), []byte(k), []byte("<value>"), ""); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			called := false
+			if err := f.Range(
+				t.Context(),
+				0,
+				func(context.Context, Cursor, BinaryChange) (bool, error) {
+					if called {
+						t.Fatal("unexpected call")
+					}
+					called = true
+					return false, nil
+				},
+			); err != nil {
+				t.Fatal(err)
+			}
+		})
+	})
+}
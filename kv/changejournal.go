@@ -0,0 +1,329 @@
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/persistencekit/journal"
+)
+
+// WithChangeJournal returns a [BinaryStore] that records each change made to
+// the keyspaces in s to a journal of the same name within j.
+//
+// It provides a change feed for drivers that do not offer one natively. Use
+// [NewJournalChangeFeedStore] to read the changes. j should be dedicated to
+// this purpose, for example by using [journal.WithNameTransform] to prefix the
+// journal names.
+//
+// Each change is appended to the journal after it has been applied to the
+// keyspace, so a change may be lost if the process fails between the two
+// operations. Changes made concurrently via separate keyspace handles may be
+// recorded in a different order than they were applied; consumers that require
+// the latest value should treat each change as a notification and re-read the
+// key. Changes made directly to s, without using the returned store, are not
+// recorded.
+//
+// Because the new revision must be known in order to record the change,
+// [Keyspace.SetUnconditional] is performed as a read followed by a conditional
+// [Keyspace.Set], retried until it succeeds.
+func WithChangeJournal(s BinaryStore, j journal.BinaryStore) BinaryStore {
+	return &changeJournalStore{s, j}
+}
+
+// NewJournalChangeFeedStore returns a [BinaryChangeFeedStore] that reads the
+// changes recorded by a store returned by [WithChangeJournal].
+//
+// j must be the same journal store that was passed to [WithChangeJournal].
+func NewJournalChangeFeedStore(j journal.BinaryStore) BinaryChangeFeedStore {
+	return &journalChangeFeedStore{j}
+}
+
+// WithChangeFeed returns a [BinaryStore] and a [BinaryChangeFeedStore] that
+// provides the changes made to its keyspaces.
+//
+// If s provides a change feed natively, as reported by its [Capabilities], s
+// is returned unchanged along with its native change feed, and j is unused.
+// Otherwise, s is wrapped using [WithChangeJournal] to record changes to j, and
+// the change feed is read from j using [NewJournalChangeFeedStore].
+//
+// Callers must use the returned [BinaryStore] in place of s for their changes
+// to appear in the change feed.
+func WithChangeFeed(s BinaryStore, j journal.BinaryStore) (BinaryStore, BinaryChangeFeedStore) {
+	if CapabilitiesOf(s).ChangeFeed {
+		if feeds, ok := s.(BinaryChangeFeedStore); ok {
+			return s, feeds
+		}
+	}
+
+	return WithChangeJournal(s, j), NewJournalChangeFeedStore(j)
+}
+
+type changeJournalStore struct {
+	Next     BinaryStore
+	Journals journal.BinaryStore
+}
+
+func (s *changeJournalStore) Provision(ctx context.Context) error {
+	if err := s.Next.Provision(ctx); err != nil {
+		return err
+	}
+	return s.Journals.Provision(ctx)
+}
+
+func (s *changeJournalStore) Capabilities() Capabilities {
+	return wrappedCapabilities(s.Next)
+}
+
+func (s *changeJournalStore) Open(ctx context.Context, name string) (BinaryKeyspace, error) {
+	ks, err := s.Next.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	j, err := s.Journals.Open(ctx, name)
+	if err != nil {
+		ks.Close()
+		return nil, err
+	}
+
+	bounds, err := j.Bounds(ctx)
+	if err != nil {
+		ks.Close()
+		j.Close()
+		return nil, err
+	}
+
+	return &changeJournalKeyspace{
+		BinaryKeyspace: ks,
+		journal:        j,
+		end:            bounds.End,
+	}, nil
+}
+
+type changeJournalKeyspace struct {
+	BinaryKeyspace
+	journal journal.BinaryJournal
+	end     journal.Position
+}
+
+func (ks *changeJournalKeyspace) Set(ctx context.Context, k, v []byte, r Revision) (Revision, error) {
+	next, err := ks.BinaryKeyspace.Set(ctx, k, v, r)
+	if err != nil {
+		return "", err
+	}
+
+	if len(v) == 0 && r == "" {
+		// Deleting a key that does not exist is a no-op, not a change.
+		return "", nil
+	}
+
+	if err := ks.record(
+		ctx,
+		BinaryChange{
+			Key:      k,
+			Value:    v,
+			Revision: next,
+			Deleted:  len(v) == 0,
+		},
+	); err != nil {
+		return "", err
+	}
+
+	return next, nil
+}
+
+func (ks *changeJournalKeyspace) SetUnconditional(ctx context.Context, k, v []byte) error {
+	for {
+		_, r, err := ks.BinaryKeyspace.Get(ctx, k)
+		if err != nil {
+			return err
+		}
+
+		if _, err := ks.Set(ctx, k, v, r); !IsConflict(err) {
+			return err
+		}
+	}
+}
+
+func (ks *changeJournalKeyspace) Close() error {
+	return errors.Join(
+		ks.BinaryKeyspace.Close(),
+		ks.journal.Close(),
+	)
+}
+
+func (ks *changeJournalKeyspace) RangeKeys(ctx context.Context, fn BinaryKeyRangeFunc) error {
+	return RangeKeys(ctx, ks.BinaryKeyspace, fn)
+}
+
+func (ks *changeJournalKeyspace) Stats(ctx context.Context) (Stats, error) {
+	return StatsOf(ctx, ks.BinaryKeyspace)
+}
+
+// record appends ch to the change journal.
+func (ks *changeJournalKeyspace) record(ctx context.Context, ch BinaryChange) error {
+	end, err := journal.AppendWithConflictResolution(
+		ctx,
+		ks.journal,
+		ks.end,
+		marshalChange(ch),
+		func(ctx context.Context, _ journal.Position) (journal.Position, error) {
+			bounds, err := ks.journal.Bounds(ctx)
+			return bounds.End, err
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("key/value pair was modified, but the change could not be recorded: %w", err)
+	}
+
+	ks.end = end
+
+	return nil
+}
+
+type journalChangeFeedStore struct {
+	Journals journal.BinaryStore
+}
+
+func (s *journalChangeFeedStore) OpenChangeFeed(ctx context.Context, name string) (BinaryChangeFeed, error) {
+	j, err := s.Journals.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &journalChangeFeed{j}, nil
+}
+
+type journalChangeFeed struct {
+	journal journal.BinaryJournal
+}
+
+func (f *journalChangeFeed) Name() string {
+	return f.journal.Name()
+}
+
+func (f *journalChangeFeed) End(ctx context.Context) (Cursor, error) {
+	bounds, err := f.journal.Bounds(ctx)
+	return Cursor(bounds.End), err
+}
+
+func (f *journalChangeFeed) Range(ctx context.Context, c Cursor, fn BinaryChangeFunc) error {
+	bounds, err := f.journal.Bounds(ctx)
+	if err != nil {
+		return err
+	}
+
+	pos := journal.Position(c)
+
+	if pos >= bounds.End {
+		return nil
+	}
+
+	if pos < bounds.Begin {
+		return ChangeNotFoundError{f.Name(), c}
+	}
+
+	err = f.journal.Range(
+		ctx,
+		pos,
+		func(ctx context.Context, pos journal.Position, rec []byte) (bool, error) {
+			ch, err := unmarshalChange(rec)
+			if err != nil {
+				return false, fmt.Errorf("cannot unmarshal change at cursor %d: %w", pos, err)
+			}
+			return fn(ctx, Cursor(pos), ch)
+		},
+	)
+
+	// The journal may have been truncated between the call to Bounds() and
+	// Range().
+	if errors.As(err, &journal.RecordNotFoundError{}) {
+		return ChangeNotFoundError{f.Name(), c}
+	}
+
+	return err
+}
+
+func (f *journalChangeFeed) Close() error {
+	return f.journal.Close()
+}
+
+// changeRecordVersion is the version of the binary encoding used to store
+// changes in a change journal.
+const changeRecordVersion = 1
+
+// marshalChange returns the binary representation of ch, as stored in a change
+// journal.
+//
+// The encoding is a version byte, a flags byte, the length-prefixed key and
+// revision, followed by the value.
+func marshalChange(ch BinaryChange) []byte {
+	var flags byte
+	if ch.Deleted {
+		flags |= 1
+	}
+
+	data := make(
+		[]byte,
+		0,
+		2+2*binary.MaxVarintLen64+len(ch.Key)+len(ch.Revision)+len(ch.Value),
+	)
+
+	data = append(data, changeRecordVersion, flags)
+	data = binary.AppendUvarint(data, uint64(len(ch.Key)))
+	data = append(data, ch.Key...)
+	data = binary.AppendUvarint(data, uint64(len(ch.Revision)))
+	data = append(data, ch.Revision...)
+
+	if !ch.Deleted {
+		data = append(data, ch.Value...)
+	}
+
+	return data
+}
+
+// unmarshalChange parses a change from its binary representation, as produced
+// by [marshalChange].
+func unmarshalChange(data []byte) (BinaryChange, error) {
+	if len(data) < 2 {
+		return BinaryChange{}, errors.New("record is too short")
+	}
+
+	if data[0] != changeRecordVersion {
+		return BinaryChange{}, fmt.Errorf("unsupported record version (%d)", data[0])
+	}
+
+	ch := BinaryChange{
+		Deleted: data[1]&1 != 0,
+	}
+	data = data[2:]
+
+	field := func() ([]byte, error) {
+		n, size := binary.Uvarint(data)
+		if size <= 0 || uint64(len(data)-size) < n {
+			return nil, errors.New("record is malformed")
+		}
+		v := data[size : size+int(n)]
+		data = data[size+int(n):]
+		return v, nil
+	}
+
+	var err error
+
+	if ch.Key, err = field(); err != nil {
+		return BinaryChange{}, err
+	}
+
+	rev, err := field()
+	if err != nil {
+		return BinaryChange{}, err
+	}
+	ch.Revision = Revision(rev)
+
+	if !ch.Deleted {
+		ch.Value = data
+	}
+
+	return ch, nil
+}
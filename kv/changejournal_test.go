@@ -0,0 +1,46 @@
+package kv_test
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/driver/memory/memoryjournal"
+	"github.com/dogmatiq/persistencekit/driver/memory/memorykv"
+	. "github.com/dogmatiq/persistencekit/kv"
+)
+
+func TestWithChangeJournal(t *testing.T) {
+	journals := &memoryjournal.BinaryStore{}
+	store := WithChangeJournal(&memorykv.BinaryStore{}, journals)
+
+	RunTests(t, store)
+	RunChangeFeedTests(t, store, NewJournalChangeFeedStore(journals))
+}
+
+func TestWithChangeFeed(t *testing.T) {
+	t.Run("when the store provides a change feed natively", func(t *testing.T) {
+		native := &memorykv.BinaryStore{}
+		store, feeds := WithChangeFeed(native, &memoryjournal.BinaryStore{})
+
+		if store != BinaryStore(native) {
+			t.Fatal("expected the store to be returned unchanged")
+		}
+
+		if feeds != BinaryChangeFeedStore(native) {
+			t.Fatal("expected the native change feed to be used")
+		}
+
+		RunChangeFeedTests(t, store, feeds)
+	})
+
+	t.Run("when the store does not provide a change feed natively", func(t *testing.T) {
+		// The name transform hides the native change feed of the underlying
+		// store.
+		store, feeds := WithChangeFeed(
+			WithNameTransform[[]byte, []byte](&memorykv.BinaryStore{}, func(name string) string { return name }),
+			&memoryjournal.BinaryStore{},
+		)
+
+		RunTests(t, store)
+		RunChangeFeedTests(t, store, feeds)
+	})
+}
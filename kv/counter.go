@@ -0,0 +1,52 @@
+package kv
+
+import (
+	"context"
+)
+
+// integer is a constraint that permits any integer type.
+type integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Increment atomically adds one to the counter associated with k and returns
+// its new value.
+//
+// See [IncrementBy] for details.
+func Increment[K any, V integer](
+	ctx context.Context,
+	ks Keyspace[K, V],
+	k K,
+	options ...UpdateOption,
+) (V, error) {
+	return IncrementBy(ctx, ks, k, 1, options...)
+}
+
+// IncrementBy atomically adds delta to the counter associated with k and
+// returns its new value.
+//
+// A key that is not present in the keyspace is treated as a counter with a
+// value of zero. As with any other value, a counter that reaches zero is
+// removed from the keyspace. Overflow wraps around according to the usual
+// rules for V.
+//
+// It is built on [Update], and so retries on optimistic concurrency conflicts.
+func IncrementBy[K any, V integer](
+	ctx context.Context,
+	ks Keyspace[K, V],
+	k K,
+	delta V,
+	options ...UpdateOption,
+) (V, error) {
+	v, _, err := Update(
+		ctx,
+		ks,
+		k,
+		func(v V, _ bool) (V, error) {
+			return v + delta, nil
+		},
+		options...,
+	)
+	return v, err
+}
@@ -0,0 +1,93 @@
+package kv_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/driver/memory/memorykv"
+	. "github.com/dogmatiq/persistencekit/kv"
+)
+
+func TestIncrementBy(t *testing.T) {
+	t.Parallel()
+
+	store := &memorykv.Store[string, int64]{}
+
+	ks, err := store.Open(t.Context(), "<keyspace>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ks.Close()
+
+	t.Run("it treats a missing key as zero", func(t *testing.T) {
+		v, err := IncrementBy(t.Context(), ks, "<missing>", 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v != 5 {
+			t.Fatalf("unexpected value: got %d, want 5", v)
+		}
+	})
+
+	t.Run("it removes the key when the counter reaches zero", func(t *testing.T) {
+		if _, err := IncrementBy(t.Context(), ks, "<zero>", 3); err != nil {
+			t.Fatal(err)
+		}
+
+		v, err := IncrementBy(t.Context(), ks, "<zero>", -3)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v != 0 {
+			t.Fatalf("unexpected value: got %d, want 0", v)
+		}
+
+		ok, err := ks.Has(t.Context(), "<zero>")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Fatal("expected key to be removed")
+		}
+	})
+
+	t.Run("it is safe for concurrent use", func(t *testing.T) {
+		const (
+			workers    = 10
+			increments = 20
+		)
+
+		var g sync.WaitGroup
+
+		for range workers {
+			g.Go(func() {
+				for range increments {
+					if _, err := Increment(
+						t.Context(),
+						ks,
+						"<concurrent>",
+						WithMaxAttempts(1000),
+						WithBackoff(0, time.Millisecond),
+					); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			})
+		}
+
+		g.Wait()
+
+		v, _, err := ks.Get(t.Context(), "<concurrent>")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v != workers*increments {
+			t.Fatalf("unexpected value: got %d, want %d", v, workers*increments)
+		}
+	})
+}
@@ -16,7 +16,7 @@ func IsConflict(err error) bool {
 
 // ConflictError is returned by [Keyspace.Set] if the supplied revision does not
 // match the key's actual revision.
-type ConflictError[K any] struct {
+type ConflictError[K, V any] struct {
 	// Keyspace is the name of the keyspace in which the conflict occurred.
 	Keyspace string
 
@@ -25,9 +25,28 @@ type ConflictError[K any] struct {
 
 	// Revision is the (incorrect) revision supplied to [Keyspace.Set].
 	Revision Revision
+
+	// CurrentValue is the value that was associated with Key when the conflict
+	// occurred. It is the zero-value of V if the key was not present.
+	//
+	// It is only meaningful if CurrentKnown is true.
+	CurrentValue V
+
+	// CurrentRevision is the actual revision of Key when the conflict occurred.
+	// It is empty if the key was not present.
+	//
+	// It is only meaningful if CurrentKnown is true.
+	CurrentRevision Revision
+
+	// CurrentKnown is true if the driver was able to determine the current
+	// value and revision of Key without an additional round trip.
+	//
+	// The current value is a snapshot taken at the time of the conflict. It
+	// may have already been modified again by the time the error is returned.
+	CurrentKnown bool
 }
 
-func (e ConflictError[K]) Error() string {
+func (e ConflictError[K, V]) Error() string {
 	return fmt.Sprintf(
 		"the supplied revision (%q) for key %v in the %q keyspace does not match the current revision",
 		e.Revision,
@@ -36,4 +55,19 @@ func (e ConflictError[K]) Error() string {
 	)
 }
 
-func (ConflictError[K]) isConflictError() {}
+func (ConflictError[K, V]) isConflictError() {}
+
+// ChangeNotFoundError is returned by [ChangeFeed.Range] if the change at the
+// requested cursor has been discarded.
+type ChangeNotFoundError struct {
+	Keyspace string
+	Cursor   Cursor
+}
+
+func (e ChangeNotFoundError) Error() string {
+	return fmt.Sprintf(
+		"the change at cursor %d of the %q keyspace has been discarded",
+		e.Cursor,
+		e.Keyspace,
+	)
+}
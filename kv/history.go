@@ -0,0 +1,725 @@
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Version is the value associated with a key at a specific revision.
+type Version[V any] struct {
+	// Value is the value associated with the key. It is the zero-value of V
+	// if the key was deleted.
+	Value V
+
+	// Revision is the revision of the key. It is empty if the key was deleted.
+	Revision Revision
+
+	// Deleted is true if this version represents the removal of the key.
+	Deleted bool
+
+	// ModifiedAt is the time at which the key was modified to this version.
+	//
+	// It is the zero time if the version was not recorded in the key's history,
+	// which is only possible for the current version.
+	ModifiedAt time.Time
+}
+
+// A HistoryFunc is a function used to range over the previous versions of a
+// key in a [VersionedKeyspace].
+//
+// If err is non-nil, ranging stops and err is propagated up the stack.
+// Otherwise, if ok is false, ranging stops without any error being propagated.
+type HistoryFunc[V any] func(ctx context.Context, ver Version[V]) (ok bool, err error)
+
+// A VersionedKeyspace is a [Keyspace] that retains the previous versions of
+// each key.
+type VersionedKeyspace[K, V any] interface {
+	Keyspace[K, V]
+
+	// GetAt returns the value that was associated with k at revision r.
+	//
+	// ok is false if r is not the current revision of k and has not been
+	// retained in the key's history.
+	GetAt(ctx context.Context, k K, r Revision) (v V, ok bool, err error)
+
+	// History invokes fn for each retained version of k, from newest to
+	// oldest, including the current version.
+	History(ctx context.Context, k K, fn HistoryFunc[V]) error
+}
+
+// VersionedStore is a collection of [VersionedKeyspace] values.
+type VersionedStore[K, V any] interface {
+	// Open returns the keyspace with the given name.
+	Open(ctx context.Context, name string) (VersionedKeyspace[K, V], error)
+
+	// Provision creates the infrastructure used by the store if it does not
+	// already exist.
+	Provision(ctx context.Context) error
+}
+
+// HistoryPolicy determines which versions of a key are retained by a
+// [VersionedKeyspace].
+//
+// At least one limit must be set. The current version of a key is always
+// retained.
+type HistoryPolicy struct {
+	// MaxVersions is the maximum number of versions retained for each key,
+	// including the current version. Zero means no limit.
+	MaxVersions int
+
+	// MaxAge is the maximum amount of time that a version is retained after it
+	// has been superseded by a newer version. Zero means no limit.
+	//
+	// Expired versions are excluded whenever the history is read, and are
+	// discarded from storage when a newer version of the key is recorded.
+	MaxAge time.Duration
+}
+
+// WithHistory returns a [BinaryVersionedStore] that retains the previous
+// versions of each key in the keyspaces in s, according to policy p.
+//
+// Each version of a key is stored in h as a separate value, in a keyspace with
+// the same name, along with a small record of the range of sequence numbers
+// allocated to the key's versions. h should be dedicated to this purpose, for
+// example by using [WithNameTransform] to prefix the keyspace names. Each
+// version must not exceed the maximum value size of the driver used for h.
+//
+// Versions are ordered by the sequence in which they were written, rather than
+// by their modification time, and so the order is unaffected by clock skew
+// between writers.
+//
+// Writes to s and h are not atomic. Each version is recorded after it has been
+// applied to the keyspace. If the process fails between the two operations, the
+// version is not recorded in the history, although it remains accessible via
+// [VersionedKeyspace.GetAt] and [VersionedKeyspace.History] for as long as it
+// is the current version. If a write fails, the sequence number reserved for it
+// is released on a best-effort basis, and otherwise remains as a gap in the
+// history. Versions that are discarded by the policy may also be left in h if
+// the process fails before they are removed. Changes made directly to s,
+// without using the returned store, are not recorded.
+//
+// Each write to a key costs several operations, which is significant for
+// drivers that bill each request or have high latency:
+//
+//   - a read and a conditional write of the key's sequence range in h, repeated
+//     if it is modified concurrently
+//   - the write to s itself
+//   - a write of the version to h
+//   - a read of the key's sequence range in h
+//   - when versions are discarded, a read of each version that is checked
+//     against [HistoryPolicy.MaxAge], a write to remove each discarded
+//     version, and another read and conditional write of the sequence range
+//
+// Because the new revision must be known in order to record the version,
+// [Keyspace.SetUnconditional] is performed as a read followed by a conditional
+// [Keyspace.Set], retried until it succeeds. Reading the history of a key
+// costs one read of its sequence range, one read of each retained version, and
+// one read of its current value.
+func WithHistory(s, h BinaryStore, p HistoryPolicy) BinaryVersionedStore {
+	if p.MaxVersions < 0 || p.MaxAge < 0 {
+		panic("history policy limits must not be negative")
+	}
+
+	if p.MaxVersions == 0 && p.MaxAge == 0 {
+		panic("history policy must limit the number of versions or their age")
+	}
+
+	return &historyStore{s, h, p}
+}
+
+type historyStore struct {
+	Next    BinaryStore
+	History BinaryStore
+	Policy  HistoryPolicy
+}
+
+func (s *historyStore) Provision(ctx context.Context) error {
+	if err := s.Next.Provision(ctx); err != nil {
+		return err
+	}
+	return s.History.Provision(ctx)
+}
+
+func (s *historyStore) Open(ctx context.Context, name string) (BinaryVersionedKeyspace, error) {
+	ks, err := s.Next.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := s.History.Open(ctx, name)
+	if err != nil {
+		ks.Close()
+		return nil, err
+	}
+
+	return &historyKeyspace{
+		BinaryKeyspace: ks,
+		history:        h,
+		policy:         s.Policy,
+	}, nil
+}
+
+type historyKeyspace struct {
+	BinaryKeyspace
+	history BinaryKeyspace
+	policy  HistoryPolicy
+}
+
+func (ks *historyKeyspace) Set(ctx context.Context, k, v []byte, r Revision) (Revision, error) {
+	if len(v) == 0 && r == "" {
+		// Deleting a key that does not exist is a no-op, not a new version.
+		return ks.BinaryKeyspace.Set(ctx, k, v, r)
+	}
+
+	// The version's sequence number is reserved before the write is applied.
+	// The write is conditional on r, so the write that produced r must have
+	// completed, and therefore reserved its own sequence number, before this
+	// one. This guarantees that the sequence numbers are in the same order as
+	// the writes themselves.
+	head, headRev, err := ks.reserve(ctx, k)
+	if err != nil {
+		return "", fmt.Errorf("cannot reserve a version of key %v in the %q keyspace: %w", k, ks.Name(), err)
+	}
+	seq := head.End
+
+	// The modification time is captured before the write so that it is
+	// ordered after any prior write that the caller observed.
+	now := time.Now()
+
+	next, err := ks.BinaryKeyspace.Set(ctx, k, v, r)
+	if err != nil {
+		ks.release(ctx, k, head, headRev)
+		return "", err
+	}
+
+	if err := ks.record(
+		ctx,
+		k,
+		seq,
+		BinaryVersion{
+			Value:      v,
+			Revision:   next,
+			Deleted:    len(v) == 0,
+			ModifiedAt: now,
+		},
+	); err != nil {
+		return "", fmt.Errorf("key/value pair was modified, but the version could not be recorded: %w", err)
+	}
+
+	return next, nil
+}
+
+func (ks *historyKeyspace) SetUnconditional(ctx context.Context, k, v []byte) error {
+	for {
+		_, r, err := ks.BinaryKeyspace.Get(ctx, k)
+		if err != nil {
+			return err
+		}
+
+		if _, err := ks.Set(ctx, k, v, r); !IsConflict(err) {
+			return err
+		}
+	}
+}
+
+func (ks *historyKeyspace) GetAt(ctx context.Context, k []byte, r Revision) ([]byte, bool, error) {
+	if r == "" {
+		return nil, false, nil
+	}
+
+	v, current, err := ks.BinaryKeyspace.Get(ctx, k)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if current == r {
+		return v, true, nil
+	}
+
+	versions, err := ks.versions(ctx, k)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, ver := range versions {
+		if !ver.Deleted && ver.Revision == r {
+			return ver.Value, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+func (ks *historyKeyspace) History(ctx context.Context, k []byte, fn BinaryHistoryFunc) error {
+	versions, err := ks.versions(ctx, k)
+	if err != nil {
+		return err
+	}
+
+	for _, ver := range versions {
+		ok, err := fn(ctx, ver)
+		if !ok || err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ks *historyKeyspace) Close() error {
+	return errors.Join(
+		ks.BinaryKeyspace.Close(),
+		ks.history.Close(),
+	)
+}
+
+func (ks *historyKeyspace) RangeKeys(ctx context.Context, fn BinaryKeyRangeFunc) error {
+	return RangeKeys(ctx, ks.BinaryKeyspace, fn)
+}
+
+func (ks *historyKeyspace) Stats(ctx context.Context) (Stats, error) {
+	return StatsOf(ctx, ks.BinaryKeyspace)
+}
+
+// historyHead describes the range of sequence numbers allocated to the versions
+// of a key. Versions with sequence numbers in the half-open interval [Begin,
+// End) may be present in the history keyspace.
+type historyHead struct {
+	Begin, End uint64
+}
+
+// versions returns the retained versions of k, newest first, including its
+// current version.
+func (ks *historyKeyspace) versions(ctx context.Context, k []byte) ([]BinaryVersion, error) {
+	head, _, err := ks.loadHead(ctx, k)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []BinaryVersion
+
+	for seq := head.End; seq > head.Begin; seq-- {
+		if ks.policy.MaxVersions != 0 && len(versions) == ks.policy.MaxVersions {
+			break
+		}
+
+		// A version may be missing if it is still being recorded, or if its
+		// writer failed before it was recorded.
+		ver, ok, err := ks.loadVersion(ctx, k, seq-1)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			versions = append(versions, ver)
+		}
+	}
+
+	// The current value is read after the recorded versions, so that it is at
+	// least as new as all of them. If it is newer, it has not been recorded
+	// (yet), so it is added to the front of the history.
+	v, r, err := ks.BinaryKeyspace.Get(ctx, k)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(versions) == 0 {
+		if r == "" {
+			return nil, nil
+		}
+	} else if newest := versions[0]; newest.Revision == r && newest.Deleted == (r == "") {
+		return ks.policy.apply(versions, time.Now()), nil
+	}
+
+	versions = slices.Insert(
+		versions,
+		0,
+		BinaryVersion{
+			Value:    v,
+			Revision: r,
+			Deleted:  r == "",
+		},
+	)
+
+	return ks.policy.apply(versions, time.Now()), nil
+}
+
+// reserve allocates the next sequence number in the history of k.
+//
+// It returns the head as it was before the allocation, such that the reserved
+// sequence number is head.End, along with the revision of the updated head.
+func (ks *historyKeyspace) reserve(ctx context.Context, k []byte) (historyHead, Revision, error) {
+	var head historyHead
+
+	_, r, err := Update(
+		ctx,
+		ks.history,
+		historyHeadKey(k),
+		func(data []byte, _ bool) ([]byte, error) {
+			var err error
+			head, err = unmarshalHistoryHead(data)
+			if err != nil {
+				return nil, err
+			}
+
+			next := head
+			next.End++
+
+			return marshalHistoryHead(next), nil
+		},
+	)
+
+	return head, r, err
+}
+
+// release returns a sequence number obtained from [historyKeyspace.reserve]
+// that was not used because the write failed.
+//
+// The sequence number is only returned if no other sequence number has been
+// reserved since. Otherwise, it is left as a gap in the history. Errors are
+// ignored for the same reason.
+func (ks *historyKeyspace) release(ctx context.Context, k []byte, head historyHead, r Revision) {
+	_, _ = ks.history.Set(ctx, historyHeadKey(k), marshalHistoryHead(head), r)
+}
+
+// record stores ver as the version of k with the given sequence number and
+// discards any versions that are no longer retained by the policy.
+func (ks *historyKeyspace) record(ctx context.Context, k []byte, seq uint64, ver BinaryVersion) error {
+	if err := ks.history.SetUnconditional(
+		ctx,
+		historyVersionKey(k, seq),
+		marshalHistoryVersion(ver),
+	); err != nil {
+		return err
+	}
+
+	head, _, err := ks.loadHead(ctx, k)
+	if err != nil {
+		return err
+	}
+
+	if seq < head.Begin {
+		// The version was discarded by a concurrent writer before it was
+		// recorded.
+		return ks.history.SetUnconditional(ctx, historyVersionKey(k, seq), nil)
+	}
+
+	begin, err := ks.retainedFrom(ctx, k, head.Begin, seq, time.Now())
+	if err != nil || begin == head.Begin {
+		return err
+	}
+
+	for s := head.Begin; s < begin; s++ {
+		if err := ks.history.SetUnconditional(ctx, historyVersionKey(k, s), nil); err != nil {
+			return err
+		}
+	}
+
+	_, _, err = Update(
+		ctx,
+		ks.history,
+		historyHeadKey(k),
+		func(data []byte, _ bool) ([]byte, error) {
+			head, err := unmarshalHistoryHead(data)
+			if err != nil {
+				return nil, err
+			}
+
+			head.Begin = max(head.Begin, begin)
+
+			return marshalHistoryHead(head), nil
+		},
+	)
+
+	return err
+}
+
+// retainedFrom returns the sequence number of the oldest version of k that is
+// retained by the policy, where seq is the sequence number of its newest
+// version and begin is that of its oldest stored version.
+func (ks *historyKeyspace) retainedFrom(
+	ctx context.Context,
+	k []byte,
+	begin, seq uint64,
+	now time.Time,
+) (uint64, error) {
+	if n := uint64(ks.policy.MaxVersions); n != 0 && seq-begin >= n {
+		begin = seq - n + 1
+	}
+
+	if ks.policy.MaxAge == 0 {
+		return begin, nil
+	}
+
+	// Each version is superseded at the time that the next newer version was
+	// made. Therefore, if a version was made more than MaxAge ago, all of the
+	// versions that precede it have expired.
+	for s := begin + 1; s <= seq; s++ {
+		ver, ok, err := ks.loadVersion(ctx, k, s)
+		if err != nil {
+			return 0, err
+		}
+
+		if !ok || now.Sub(ver.ModifiedAt) <= ks.policy.MaxAge {
+			break
+		}
+
+		begin = s
+	}
+
+	return begin, nil
+}
+
+// loadHead returns the head of the history of k and its revision.
+func (ks *historyKeyspace) loadHead(ctx context.Context, k []byte) (historyHead, Revision, error) {
+	data, r, err := ks.history.Get(ctx, historyHeadKey(k))
+	if err != nil {
+		return historyHead{}, "", err
+	}
+
+	head, err := unmarshalHistoryHead(data)
+	if err != nil {
+		return historyHead{}, "", fmt.Errorf("cannot unmarshal history of key %v in the %q keyspace: %w", k, ks.Name(), err)
+	}
+
+	return head, r, nil
+}
+
+// loadVersion returns the version of k with the given sequence number. ok is
+// false if it is not present.
+func (ks *historyKeyspace) loadVersion(ctx context.Context, k []byte, seq uint64) (ver BinaryVersion, ok bool, err error) {
+	data, _, err := ks.history.Get(ctx, historyVersionKey(k, seq))
+	if err != nil || len(data) == 0 {
+		return BinaryVersion{}, false, err
+	}
+
+	ver, err = unmarshalHistoryVersion(data)
+	if err != nil {
+		return BinaryVersion{}, false, fmt.Errorf("cannot unmarshal version %d of key %v in the %q keyspace: %w", seq, k, ks.Name(), err)
+	}
+
+	return ver, true, nil
+}
+
+// apply returns the subset of versions (sorted newest first) that are retained
+// by the policy.
+func (p HistoryPolicy) apply(versions []BinaryVersion, now time.Time) []BinaryVersion {
+	if p.MaxVersions != 0 && len(versions) > p.MaxVersions {
+		versions = versions[:p.MaxVersions]
+	}
+
+	if p.MaxAge != 0 {
+		// Each version is superseded at the time that the next newer version
+		// was made, so the newest version is always retained. The time is
+		// unknown if the newer version was not recorded, in which case the
+		// version is retained.
+		for i := 1; i < len(versions); i++ {
+			supersededAt := versions[i-1].ModifiedAt
+			if !supersededAt.IsZero() && now.Sub(supersededAt) > p.MaxAge {
+				versions = versions[:i]
+				break
+			}
+		}
+	}
+
+	return versions
+}
+
+// Each key in the history keyspace begins with a byte that identifies the type
+// of record that it contains.
+const (
+	historyHeadKeyType    byte = 'h'
+	historyVersionKeyType byte = 'v'
+)
+
+// historyHeadKey returns the key of the record that contains the head of the
+// history of k.
+func historyHeadKey(k []byte) []byte {
+	key := make([]byte, 0, 1+len(k))
+	key = append(key, historyHeadKeyType)
+	return append(key, k...)
+}
+
+// historyVersionKey returns the key of the record that contains the version of
+// k with the given sequence number.
+//
+// The length of k is included so that the keys of different keys' versions
+// never collide.
+func historyVersionKey(k []byte, seq uint64) []byte {
+	key := make([]byte, 0, 1+binary.MaxVarintLen64+len(k)+8)
+	key = append(key, historyVersionKeyType)
+	key = binary.AppendUvarint(key, uint64(len(k)))
+	key = append(key, k...)
+	return binary.BigEndian.AppendUint64(key, seq)
+}
+
+// historyRecordVersion is the version of the binary encoding used to store the
+// history of a key.
+const historyRecordVersion = 1
+
+// errMalformedHistoryRecord is returned when a history record cannot be
+// parsed.
+var errMalformedHistoryRecord = errors.New("record is malformed")
+
+// marshalHistoryHead returns the binary representation of a key's history
+// head.
+//
+// The encoding is a version byte followed by the beginning and end of the range
+// of sequence numbers.
+func marshalHistoryHead(head historyHead) []byte {
+	data := []byte{historyRecordVersion}
+	data = binary.AppendUvarint(data, head.Begin)
+	return binary.AppendUvarint(data, head.End)
+}
+
+// unmarshalHistoryHead parses a key's history head from its binary
+// representation, as produced by [marshalHistoryHead]. An empty slice is
+// treated as an empty history.
+func unmarshalHistoryHead(data []byte) (historyHead, error) {
+	if len(data) == 0 {
+		return historyHead{}, nil
+	}
+
+	r, err := newHistoryReader(data)
+	if err != nil {
+		return historyHead{}, err
+	}
+
+	var head historyHead
+
+	if head.Begin, err = r.uvarint(); err != nil {
+		return historyHead{}, err
+	}
+
+	if head.End, err = r.uvarint(); err != nil {
+		return historyHead{}, err
+	}
+
+	if head.Begin > head.End {
+		return historyHead{}, errMalformedHistoryRecord
+	}
+
+	return head, nil
+}
+
+// marshalHistoryVersion returns the binary representation of a single version
+// of a key.
+//
+// The encoding is a version byte, followed by a flags byte, the modification
+// time as nanoseconds since the Unix epoch, and the length-prefixed revision
+// and value.
+func marshalHistoryVersion(ver BinaryVersion) []byte {
+	var flags byte
+	if ver.Deleted {
+		flags |= 1
+	}
+
+	data := []byte{historyRecordVersion, flags}
+	data = binary.AppendVarint(data, ver.ModifiedAt.UnixNano())
+	data = binary.AppendUvarint(data, uint64(len(ver.Revision)))
+	data = append(data, ver.Revision...)
+	data = binary.AppendUvarint(data, uint64(len(ver.Value)))
+	return append(data, ver.Value...)
+}
+
+// unmarshalHistoryVersion parses a single version of a key from its binary
+// representation, as produced by [marshalHistoryVersion].
+func unmarshalHistoryVersion(data []byte) (BinaryVersion, error) {
+	r, err := newHistoryReader(data)
+	if err != nil {
+		return BinaryVersion{}, err
+	}
+
+	flags, err := r.byte()
+	if err != nil {
+		return BinaryVersion{}, err
+	}
+
+	ver := BinaryVersion{
+		Deleted: flags&1 != 0,
+	}
+
+	nanos, err := r.varint()
+	if err != nil {
+		return BinaryVersion{}, err
+	}
+	ver.ModifiedAt = time.Unix(0, nanos)
+
+	rev, err := r.field()
+	if err != nil {
+		return BinaryVersion{}, err
+	}
+	ver.Revision = Revision(rev)
+
+	if ver.Value, err = r.field(); err != nil {
+		return BinaryVersion{}, err
+	}
+
+	if len(ver.Value) == 0 {
+		ver.Value = nil
+	}
+
+	return ver, nil
+}
+
+// historyReader reads the fields of a history record.
+type historyReader struct {
+	data []byte
+}
+
+// newHistoryReader returns a reader for the given record, after checking its
+// version byte.
+func newHistoryReader(data []byte) (*historyReader, error) {
+	if len(data) == 0 {
+		return nil, errMalformedHistoryRecord
+	}
+
+	if data[0] != historyRecordVersion {
+		return nil, fmt.Errorf("unsupported record version (%d)", data[0])
+	}
+
+	return &historyReader{data[1:]}, nil
+}
+
+func (r *historyReader) byte() (byte, error) {
+	if len(r.data) == 0 {
+		return 0, errMalformedHistoryRecord
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b, nil
+}
+
+func (r *historyReader) uvarint() (uint64, error) {
+	n, size := binary.Uvarint(r.data)
+	if size <= 0 {
+		return 0, errMalformedHistoryRecord
+	}
+	r.data = r.data[size:]
+	return n, nil
+}
+
+func (r *historyReader) varint() (int64, error) {
+	n, size := binary.Varint(r.data)
+	if size <= 0 {
+		return 0, errMalformedHistoryRecord
+	}
+	r.data = r.data[size:]
+	return n, nil
+}
+
+func (r *historyReader) field() ([]byte, error) {
+	n, err := r.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(r.data)) < n {
+		return nil, errMalformedHistoryRecord
+	}
+	v := r.data[:n:n]
+	r.data = r.data[n:]
+	return v, nil
+}
@@ -0,0 +1,350 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/driver/memory/memorykv"
+	. "github.com/dogmatiq/persistencekit/kv"
+	"github.com/dogmatiq/persistencekit/marshaler"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestWithHistory(t *testing.T) {
+	newKeyspace := func(t *testing.T, p HistoryPolicy) VersionedKeyspace[string, int] {
+		store := NewMarshalingVersionedStore(
+			WithHistory(
+				&memorykv.BinaryStore{},
+				&memorykv.BinaryStore{},
+				p,
+			),
+			marshaler.NewJSON[string](),
+			marshaler.NewJSON[int](),
+		)
+
+		ks, err := store.Open(t.Context(), "<name>")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() {
+			if err := ks.Close(); err != nil {
+				t.Error(err)
+			}
+		})
+
+		return ks
+	}
+
+	history := func(t *testing.T, ks VersionedKeyspace[string, int], k string) []Version[int] {
+		t.Helper()
+
+		var versions []Version[int]
+		if err := ks.History(
+			t.Context(),
+			k,
+			func(_ context.Context, ver Version[int]) (bool, error) {
+				versions = append(versions, ver)
+				return true, nil
+			},
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		return versions
+	}
+
+	ignoreTime := cmpopts.IgnoreFields(Version[int]{}, "ModifiedAt")
+
+	t.Run("it returns the value at a previous revision", func(t *testing.T) {
+		ks := newKeyspace(t, HistoryPolicy{MaxVersions: 10})
+
+		var revisions []Revision
+		var r Revision
+		for v := range 3 {
+			var err error
+			r, err = ks.Set(t.Context(), "<key>", v+1, r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			revisions = append(revisions, r)
+		}
+
+		for i, r := range revisions {
+			v, ok, err := ks.GetAt(t.Context(), "<key>", r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !ok {
+				t.Fatalf("expected revision %q to be retained", r)
+			}
+
+			if v != i+1 {
+				t.Fatalf("unexpected value at revision %q: got %d, want %d", r, v, i+1)
+			}
+		}
+
+		if _, ok, err := ks.GetAt(t.Context(), "<key>", "<unknown>"); err != nil {
+			t.Fatal(err)
+		} else if ok {
+			t.Fatal("did not expect an unknown revision to be found")
+		}
+	})
+
+	t.Run("it returns the versions newest first", func(t *testing.T) {
+		ks := newKeyspace(t, HistoryPolicy{MaxVersions: 10})
+
+		r1, err := ks.Set(t.Context(), "<key>", 1, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ks.SetUnconditional(t.Context(), "<key>", 2); err != nil {
+			t.Fatal(err)
+		}
+
+		_, r2, err := ks.Get(t.Context(), "<key>")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := ks.Set(t.Context(), "<key>", 0, r2); err != nil {
+			t.Fatal(err)
+		}
+
+		expect := []Version[int]{
+			{Deleted: true},
+			{Value: 2, Revision: r2},
+			{Value: 1, Revision: r1},
+		}
+
+		actual := history(t, ks, "<key>")
+
+		if diff := cmp.Diff(expect, actual, ignoreTime); diff != "" {
+			t.Fatalf("unexpected history (-want +got):\n%s", diff)
+		}
+
+		for i := 1; i < len(actual); i++ {
+			if actual[i].ModifiedAt.After(actual[i-1].ModifiedAt) {
+				t.Fatal("expected versions to be ordered by modification time")
+			}
+		}
+	})
+
+	t.Run("it retains the configured number of versions", func(t *testing.T) {
+		ks := newKeyspace(t, HistoryPolicy{MaxVersions: 2})
+
+		var revisions []Revision
+		var r Revision
+		for v := range 3 {
+			var err error
+			r, err = ks.Set(t.Context(), "<key>", v+1, r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			revisions = append(revisions, r)
+		}
+
+		expect := []Version[int]{
+			{Value: 3, Revision: revisions[2]},
+			{Value: 2, Revision: revisions[1]},
+		}
+
+		if diff := cmp.Diff(expect, history(t, ks, "<key>"), ignoreTime); diff != "" {
+			t.Fatalf("unexpected history (-want +got):\n%s", diff)
+		}
+
+		if _, ok, err := ks.GetAt(t.Context(), "<key>", revisions[0]); err != nil {
+			t.Fatal(err)
+		} else if ok {
+			t.Fatal("did not expect a discarded revision to be found")
+		}
+	})
+
+	t.Run("it discards versions superseded before the configured age", func(t *testing.T) {
+		ks := newKeyspace(t, HistoryPolicy{MaxAge: 50 * time.Millisecond})
+
+		r1, err := ks.Set(t.Context(), "<key>", 1, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r2, err := ks.Set(t.Context(), "<key>", 2, r1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(100 * time.Millisecond)
+
+		r3, err := ks.Set(t.Context(), "<key>", 3, r2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expect := []Version[int]{
+			{Value: 3, Revision: r3},
+			{Value: 2, Revision: r2},
+		}
+
+		if diff := cmp.Diff(expect, history(t, ks, "<key>"), ignoreTime); diff != "" {
+			t.Fatalf("unexpected history (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("it discards expired versions without a subsequent write", func(t *testing.T) {
+		ks := newKeyspace(t, HistoryPolicy{MaxAge: 50 * time.Millisecond})
+
+		r1, err := ks.Set(t.Context(), "<key>", 1, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r2, err := ks.Set(t.Context(), "<key>", 2, r1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(100 * time.Millisecond)
+
+		expect := []Version[int]{
+			{Value: 2, Revision: r2},
+		}
+
+		if diff := cmp.Diff(expect, history(t, ks, "<key>"), ignoreTime); diff != "" {
+			t.Fatalf("unexpected history (-want +got):\n%s", diff)
+		}
+
+		if _, ok, err := ks.GetAt(t.Context(), "<key>", r1); err != nil {
+			t.Fatal(err)
+		} else if ok {
+			t.Fatal("did not expect an expired revision to be found")
+		}
+	})
+
+	t.Run("it includes the current version if it was not recorded", func(t *testing.T) {
+		next := &memorykv.BinaryStore{}
+		store := WithHistory(next, &memorykv.BinaryStore{}, HistoryPolicy{MaxVersions: 10})
+
+		ks, err := store.Open(t.Context(), "<name>")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ks.Close()
+
+		r1, err := ks.Set(t.Context(), []byte("<key>"), []byte("<value-1>"), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		direct, err := next.Open(t.Context(), "<name>")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer direct.Close()
+
+		r2, err := direct.Set(t.Context(), []byte("<key>"), []byte("<value-2>"), r1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var versions []BinaryVersion
+		if err := ks.History(
+			t.Context(),
+			[]byte("<key>"),
+			func(_ context.Context, ver BinaryVersion) (bool, error) {
+				versions = append(versions, ver)
+				return true, nil
+			},
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		expect := []BinaryVersion{
+			{Value: []byte("<value-2>"), Revision: r2},
+			{Value: []byte("<value-1>"), Revision: r1},
+		}
+
+		if diff := cmp.Diff(expect, versions, cmpopts.IgnoreFields(BinaryVersion{}, "ModifiedAt")); diff != "" {
+			t.Fatalf("unexpected history (-want +got):\n%s", diff)
+		}
+
+		if !versions[0].ModifiedAt.IsZero() {
+			t.Fatal("expected the modification time of the unrecorded version to be zero")
+		}
+	})
+
+	t.Run("it stores each version as a separate value", func(t *testing.T) {
+		history := &memorykv.BinaryStore{}
+		store := WithHistory(&memorykv.BinaryStore{}, history, HistoryPolicy{MaxVersions: 100})
+
+		ks, err := store.Open(t.Context(), "<name>")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ks.Close()
+
+		value := []byte("<value>")
+
+		for range 50 {
+			if err := ks.SetUnconditional(t.Context(), []byte("<key>"), value); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		h, err := history.Open(t.Context(), "<name>")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer h.Close()
+
+		if err := h.Range(
+			t.Context(),
+			func(_ context.Context, k, v []byte, _ Revision) (bool, error) {
+				if len(v) > 2*len(value)+64 {
+					t.Fatalf("unexpected value size for history key %q: %d bytes", k, len(v))
+				}
+				return true, nil
+			},
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		stats, err := StatsOf(t.Context(), h)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if stats.Keys != 51 {
+			t.Fatalf("unexpected number of history keys: got %d, want 51", stats.Keys)
+		}
+	})
+
+	t.Run("it does not record failed or no-op changes", func(t *testing.T) {
+		ks := newKeyspace(t, HistoryPolicy{MaxVersions: 10})
+
+		if _, err := ks.Set(t.Context(), "<key>", 0, ""); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := ks.Set(t.Context(), "<key>", 1, "<wrong>"); !IsConflict(err) {
+			t.Fatalf("expected a conflict error, got %v", err)
+		}
+
+		if versions := history(t, ks, "<key>"); len(versions) != 0 {
+			t.Fatalf("unexpected history: %v", versions)
+		}
+	})
+
+	t.Run("it panics if the policy has no limits", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected a panic")
+			}
+		}()
+
+		WithHistory(&memorykv.BinaryStore{}, &memorykv.BinaryStore{}, HistoryPolicy{})
+	})
+}
@@ -50,6 +50,10 @@ func (s *interceptedStore[K, V]) Provision(ctx context.Context) error {
 	return s.Next.Provision(ctx)
 }
 
+func (s *interceptedStore[K, V]) Capabilities() Capabilities {
+	return wrappedCapabilities(s.Next)
+}
+
 func (s *interceptedStore[K, V]) Open(ctx context.Context, name string) (Keyspace[K, V], error) {
 	if fn := s.Interceptor.beforeOpen.Load(); fn != nil {
 		if err := fn(name); err != nil {
@@ -132,6 +136,14 @@ func (ks *interceptedKeyspace[K, V]) Range(ctx context.Context, fn RangeFunc[K,
 	return ks.Next.Range(ctx, fn)
 }
 
+func (ks *interceptedKeyspace[K, V]) RangeKeys(ctx context.Context, fn KeyRangeFunc[K]) error {
+	return RangeKeys(ctx, ks.Next, fn)
+}
+
+func (ks *interceptedKeyspace[K, V]) Stats(ctx context.Context) (Stats, error) {
+	return StatsOf(ctx, ks.Next)
+}
+
 func (ks *interceptedKeyspace[K, V]) Close() error {
 	return ks.Next.Close()
 }
@@ -0,0 +1,240 @@
+package kv
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/internal/linearizability"
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+	"pgregory.net/rapid"
+)
+
+// runLinearizabilityTests runs randomized concurrent operations against
+// keyspaces in the given store, and checks that the resulting histories are
+// linearizable.
+func runLinearizabilityTests(t *testing.T, store BinaryStore) {
+	t.Run("linearizability", func(t *testing.T) {
+		t.Parallel()
+
+		rapid.Check(t, func(t *rapid.T) {
+			name := xtesting.SequentialName("keyspace")
+
+			clients := rapid.IntRange(2, 4).Draw(t, "clients")
+			plans := make([][]keyspaceInput, clients)
+			for i := range plans {
+				plans[i] = rapid.SliceOfN(keyspaceInputGenerator, 1, 10).Draw(t, fmt.Sprintf("client %d", i))
+
+				// Each write uses a distinct value. Some drivers derive
+				// revisions from the content of the value, so writing the same
+				// value twice could reinstate a revision that a client has
+				// already observed.
+				for j, in := range plans[i] {
+					if in.Op == "Set" || in.Op == "SetUnconditional" {
+						plans[i][j].Value = fmt.Sprintf("<client-%d-op-%d>", i, j)
+					}
+				}
+			}
+
+			// Each client uses its own handle, as it would if each were a
+			// separate process. Each client also remembers the last revision
+			// it observed for each key, which it uses for conditional writes.
+			keyspaces := make([]BinaryKeyspace, clients)
+			revisions := make([]map[string]Revision, clients)
+			for i := range keyspaces {
+				ks, err := store.Open(t.Context(), name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer ks.Close()
+				keyspaces[i] = ks
+				revisions[i] = map[string]Revision{}
+			}
+
+			history := linearizability.Run(
+				plans,
+				func(client int, in keyspaceInput) keyspaceOutput {
+					return in.apply(t, keyspaces[client], revisions[client])
+				},
+			)
+
+			// Operations on different keys are independent, so the history for
+			// each key is checked separately.
+			byKey := map[string][]linearizability.Operation[keyspaceInput, keyspaceOutput]{}
+			for _, op := range history {
+				if op.Output.Err != nil {
+					t.Fatalf("unexpected error: %v => %s\n%s", op.Input, op.Output.Err, linearizability.Describe(history))
+				}
+				byKey[op.Input.Key] = append(byKey[op.Input.Key], op)
+			}
+
+			for k, ops := range byKey {
+				if !linearizability.Check(keyspaceModel, ops) {
+					t.Fatalf("history for key %q is not linearizable:\n%s", k, linearizability.Describe(ops))
+				}
+			}
+		})
+	})
+}
+
+// keyspaceInput describes an operation performed on a keyspace.
+type keyspaceInput struct {
+	Op    string
+	Key   string
+	Value string
+}
+
+func (in keyspaceInput) String() string {
+	if in.Value == "" {
+		return fmt.Sprintf("%s(%q)", in.Op, in.Key)
+	}
+	return fmt.Sprintf("%s(%q, %q)", in.Op, in.Key, in.Value)
+}
+
+// keyspaceOutput describes the result of a [keyspaceInput] operation.
+type keyspaceOutput struct {
+	// Value and Revision are the value and revision returned by Get, or the
+	// value written and the new revision returned by Set.
+	Value    string
+	Revision Revision
+
+	// Expected is the revision passed to Set.
+	Expected Revision
+
+	// OK is the result of Has.
+	OK bool
+
+	// Conflict is true if Set returned a [ConflictError].
+	Conflict bool
+
+	Err error
+}
+
+func (out keyspaceOutput) String() string {
+	switch {
+	case out.Err != nil:
+		return out.Err.Error()
+	case out.Conflict:
+		return fmt.Sprintf("conflict (expected revision %q)", out.Expected)
+	case out.Expected != "" || out.Revision != "":
+		return fmt.Sprintf("value %q, revision %q (expected revision %q)", out.Value, out.Revision, out.Expected)
+	default:
+		return fmt.Sprintf("value %q, ok %t", out.Value, out.OK)
+	}
+}
+
+// keyspaceInputGenerator generates operations on a small number of keys, such
+// that concurrent operations frequently affect the same key.
+//
+// The values written are assigned by the caller once the plan for each client
+// is known, such that each write uses a value that is unique within the test.
+// Values are never empty, so keys are never deleted. Together, this ensures
+// revisions are never reused, even by drivers that derive revisions from the
+// content of the value, which would otherwise make the outcome of a
+// conditional write that uses a stale revision unpredictable.
+var keyspaceInputGenerator = rapid.Custom(func(t *rapid.T) keyspaceInput {
+	return keyspaceInput{
+		Op:  rapid.SampledFrom([]string{"Get", "Has", "Set", "SetUnconditional"}).Draw(t, "op"),
+		Key: rapid.SampledFrom([]string{"a", "b"}).Draw(t, "key"),
+	}
+})
+
+// apply performs the operation on ks. revisions contains the last revision the
+// client observed for each key.
+func (in keyspaceInput) apply(
+	t *rapid.T,
+	ks BinaryKeyspace,
+	revisions map[string]Revision,
+) (out keyspaceOutput) {
+	ctx := t.Context()
+	k := []byte(in.Key)
+
+	switch in.Op {
+	case "Get":
+		var v []byte
+		v, out.Revision, out.Err = ks.Get(ctx, k)
+		out.Value = string(v)
+		if out.Err == nil {
+			revisions[in.Key] = out.Revision
+		}
+
+	case "Has":
+		out.OK, out.Err = ks.Has(ctx, k)
+
+	case "Set":
+		out.Value = in.Value
+		out.Expected = revisions[in.Key]
+		out.Revision, out.Err = ks.Set(ctx, k, []byte(in.Value), out.Expected)
+		if IsConflict(out.Err) {
+			out.Conflict = true
+			out.Err = nil
+		} else if out.Err == nil {
+			revisions[in.Key] = out.Revision
+		}
+
+	case "SetUnconditional":
+		out.Value = in.Value
+		out.Err = ks.SetUnconditional(ctx, k, []byte(in.Value))
+
+	default:
+		panic("unrecognized operation: " + in.Op)
+	}
+
+	return out
+}
+
+// keyspaceState is the state of a single key within [keyspaceModel].
+type keyspaceState struct {
+	Value    string
+	Revision Revision
+
+	// RevisionKnown is false if the key has been written by SetUnconditional,
+	// which does not return the new revision, and the new revision has not yet
+	// been observed.
+	RevisionKnown bool
+}
+
+// keyspaceModel is a sequential model of a single key within a keyspace.
+var keyspaceModel = linearizability.Model[keyspaceState, keyspaceInput, keyspaceOutput]{
+	Init: func() keyspaceState {
+		return keyspaceState{RevisionKnown: true}
+	},
+	Step: func(s keyspaceState, in keyspaceInput, out keyspaceOutput) (keyspaceState, bool) {
+		switch in.Op {
+		case "Get":
+			if out.Value != s.Value {
+				return s, false
+			}
+			if s.RevisionKnown {
+				return s, out.Revision == s.Revision
+			}
+			s.Revision = out.Revision
+			s.RevisionKnown = true
+			return s, out.Revision != ""
+
+		case "Has":
+			return s, out.OK == (s.Value != "")
+
+		case "Set":
+			// A client can only know the current revision if it was known at
+			// the time the client observed it.
+			matches := s.RevisionKnown && out.Expected == s.Revision
+			if out.Conflict {
+				return s, !matches
+			}
+			if !matches || out.Revision == "" {
+				return s, false
+			}
+			return keyspaceState{in.Value, out.Revision, true}, true
+
+		case "SetUnconditional":
+			return keyspaceState{Value: in.Value}, true
+
+		default:
+			panic("unrecognized operation: " + in.Op)
+		}
+	},
+	Key: func(s keyspaceState) string {
+		return fmt.Sprintf("%q %q %s", s.Value, s.Revision, strconv.FormatBool(s.RevisionKnown))
+	},
+}
@@ -26,6 +26,10 @@ type mstore[K, V any] struct {
 	vm marshaler.Marshaler[V]
 }
 
+func (s *mstore[K, V]) Capabilities() Capabilities {
+	return wrappedCapabilities(s.BinaryStore)
+}
+
 func (s *mstore[K, V]) Open(ctx context.Context, name string) (Keyspace[K, V], error) {
 	ks, err := s.BinaryStore.Open(ctx, name)
 	if err != nil {
@@ -81,14 +85,11 @@ func (ks *mkeyspace[K, V]) Set(ctx context.Context, k K, v V, r Revision) (Revis
 
 	r, err = ks.BinaryKeyspace.Set(ctx, keyData, valueData, r)
 	if err != nil {
-		// Re-package conflict errors to use a key of type K, instead of []byte.
-		var conflict ConflictError[[]byte]
+		// Re-package conflict errors to use a key of type K and a value of type
+		// V, instead of []byte.
+		var conflict ConflictError[[]byte, []byte]
 		if errors.As(err, &conflict) {
-			return "", ConflictError[K]{
-				Keyspace: conflict.Keyspace,
-				Key:      k,
-				Revision: conflict.Revision,
-			}
+			return "", ks.unmarshalConflict(k, conflict)
 		}
 
 		return "", err
@@ -97,6 +98,36 @@ func (ks *mkeyspace[K, V]) Set(ctx context.Context, k K, v V, r Revision) (Revis
 	return r, nil
 }
 
+// unmarshalConflict converts a binary [ConflictError] into one that uses keys
+// of type K and values of type V.
+//
+// If the current value can not be unmarshaled it is reported as unknown, such
+// that the conflict itself is not obscured by the unmarshaling error.
+func (ks *mkeyspace[K, V]) unmarshalConflict(k K, conflict ConflictError[[]byte, []byte]) ConflictError[K, V] {
+	err := ConflictError[K, V]{
+		Keyspace: conflict.Keyspace,
+		Key:      k,
+		Revision: conflict.Revision,
+	}
+
+	if !conflict.CurrentKnown {
+		return err
+	}
+
+	if len(conflict.CurrentValue) != 0 {
+		v, unmarshalErr := ks.vm.Unmarshal(conflict.CurrentValue)
+		if unmarshalErr != nil {
+			return err
+		}
+		err.CurrentValue = v
+	}
+
+	err.CurrentRevision = conflict.CurrentRevision
+	err.CurrentKnown = true
+
+	return err
+}
+
 func (ks *mkeyspace[K, V]) SetUnconditional(ctx context.Context, k K, v V) error {
 	keyData, err := ks.km.Marshal(k)
 	if err != nil {
@@ -118,6 +149,10 @@ func (ks *mkeyspace[K, V]) marshalValue(v V) ([]byte, error) {
 	return ks.vm.Marshal(v)
 }
 
+func (ks *mkeyspace[K, V]) Stats(ctx context.Context) (Stats, error) {
+	return StatsOf(ctx, ks.BinaryKeyspace)
+}
+
 func (ks *mkeyspace[K, V]) Range(ctx context.Context, fn RangeFunc[K, V]) error {
 	return ks.BinaryKeyspace.Range(
 		ctx,
@@ -136,3 +171,164 @@ func (ks *mkeyspace[K, V]) Range(ctx context.Context, fn RangeFunc[K, V]) error
 		},
 	)
 }
+
+func (ks *mkeyspace[K, V]) RangeKeys(ctx context.Context, fn KeyRangeFunc[K]) error {
+	return RangeKeys(
+		ctx,
+		ks.BinaryKeyspace,
+		func(ctx context.Context, keyData []byte, r Revision) (bool, error) {
+			k, err := ks.km.Unmarshal(keyData)
+			if err != nil {
+				return false, err
+			}
+
+			return fn(ctx, k, r)
+		},
+	)
+}
+
+// NewMarshalingChangeFeedStore returns a new [ChangeFeedStore] that unmarshals
+// changes from an underlying [BinaryChangeFeedStore].
+func NewMarshalingChangeFeedStore[K, V any](
+	s BinaryChangeFeedStore,
+	km marshaler.Marshaler[K],
+	vm marshaler.Marshaler[V],
+) ChangeFeedStore[K, V] {
+	return &mchangeFeedStore[K, V]{s, km, vm}
+}
+
+// mchangeFeedStore is an implementation of [ChangeFeedStore] that unmarshals
+// changes from an underlying [BinaryChangeFeedStore].
+type mchangeFeedStore[K, V any] struct {
+	BinaryChangeFeedStore
+	km marshaler.Marshaler[K]
+	vm marshaler.Marshaler[V]
+}
+
+func (s *mchangeFeedStore[K, V]) OpenChangeFeed(ctx context.Context, name string) (ChangeFeed[K, V], error) {
+	f, err := s.BinaryChangeFeedStore.OpenChangeFeed(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mchangeFeed[K, V]{f, s.km, s.vm}, nil
+}
+
+// mchangeFeed is an implementation of [ChangeFeed] that unmarshals changes
+// from an underlying [BinaryChangeFeed].
+type mchangeFeed[K, V any] struct {
+	BinaryChangeFeed
+	km marshaler.Marshaler[K]
+	vm marshaler.Marshaler[V]
+}
+
+func (f *mchangeFeed[K, V]) Range(ctx context.Context, c Cursor, fn ChangeFunc[K, V]) error {
+	return f.BinaryChangeFeed.Range(
+		ctx,
+		c,
+		func(ctx context.Context, c Cursor, ch BinaryChange) (bool, error) {
+			k, err := f.km.Unmarshal(ch.Key)
+			if err != nil {
+				return false, err
+			}
+
+			change := Change[K, V]{
+				Key:      k,
+				Revision: ch.Revision,
+				Deleted:  ch.Deleted,
+			}
+
+			if !ch.Deleted {
+				change.Value, err = f.vm.Unmarshal(ch.Value)
+				if err != nil {
+					return false, err
+				}
+			}
+
+			return fn(ctx, c, change)
+		},
+	)
+}
+
+// NewMarshalingVersionedStore returns a new [VersionedStore] that
+// marshals/unmarshals key/value pairs to/from an underlying
+// [BinaryVersionedStore].
+func NewMarshalingVersionedStore[K, V any](
+	s BinaryVersionedStore,
+	km marshaler.Marshaler[K],
+	vm marshaler.Marshaler[V],
+) VersionedStore[K, V] {
+	return &mversionedStore[K, V]{s, km, vm}
+}
+
+// mversionedStore is an implementation of [VersionedStore] that
+// marshals/unmarshals key/value pairs to/from an underlying
+// [BinaryVersionedStore].
+type mversionedStore[K, V any] struct {
+	BinaryVersionedStore
+	km marshaler.Marshaler[K]
+	vm marshaler.Marshaler[V]
+}
+
+func (s *mversionedStore[K, V]) Open(ctx context.Context, name string) (VersionedKeyspace[K, V], error) {
+	ks, err := s.BinaryVersionedStore.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mversionedKeyspace[K, V]{
+		mkeyspace[K, V]{ks, s.km, s.vm},
+		ks,
+	}, nil
+}
+
+// mversionedKeyspace is an implementation of [VersionedKeyspace] that
+// marshals/unmarshals key/value pairs to/from an underlying
+// [BinaryVersionedKeyspace].
+type mversionedKeyspace[K, V any] struct {
+	mkeyspace[K, V]
+	versioned BinaryVersionedKeyspace
+}
+
+func (ks *mversionedKeyspace[K, V]) GetAt(ctx context.Context, k K, r Revision) (v V, ok bool, err error) {
+	keyData, err := ks.km.Marshal(k)
+	if err != nil {
+		return v, false, err
+	}
+
+	valueData, ok, err := ks.versioned.GetAt(ctx, keyData, r)
+	if err != nil || len(valueData) == 0 {
+		return v, ok, err
+	}
+
+	v, err = ks.vm.Unmarshal(valueData)
+	return v, ok, err
+}
+
+func (ks *mversionedKeyspace[K, V]) History(ctx context.Context, k K, fn HistoryFunc[V]) error {
+	keyData, err := ks.km.Marshal(k)
+	if err != nil {
+		return err
+	}
+
+	return ks.versioned.History(
+		ctx,
+		keyData,
+		func(ctx context.Context, ver BinaryVersion) (bool, error) {
+			version := Version[V]{
+				Revision:   ver.Revision,
+				Deleted:    ver.Deleted,
+				ModifiedAt: ver.ModifiedAt,
+			}
+
+			if !ver.Deleted {
+				version.Value, err = ks.vm.Unmarshal(ver.Value)
+				if err != nil {
+					return false, err
+				}
+			}
+
+			return fn(ctx, version)
+		},
+	)
+}
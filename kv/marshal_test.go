@@ -2,6 +2,7 @@ package kv_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -57,6 +58,25 @@ func TestNewMarshalingStore(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	if err := RangeKeys(
+		t.Context(),
+		ks,
+		func(_ context.Context, k string, r Revision) (bool, error) {
+			p, ok := pairs[k]
+			if !ok {
+				t.Fatalf("unexpected key %q", k)
+			}
+
+			if r != p.Revision {
+				t.Fatalf("unexpected revision for key %q: got %q, want %q", k, r, p.Revision)
+			}
+
+			return true, nil
+		},
+	); err != nil {
+		t.Fatal(err)
+	}
+
 	for k := range pairs {
 		ok, err := ks.Has(t.Context(), k)
 		if err != nil {
@@ -102,3 +122,42 @@ func TestNewMarshalingStore(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestNewMarshalingStoreConflictError(t *testing.T) {
+	store := NewMarshalingStore(
+		&memorykv.BinaryStore{},
+		marshaler.NewJSON[string](),
+		marshaler.NewJSON[int](),
+	)
+
+	ks, err := store.Open(t.Context(), "<name>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ks.Close()
+
+	r, err := ks.Set(t.Context(), "<key>", 123, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ks.Set(t.Context(), "<key>", 456, "")
+
+	var conflict ConflictError[string, int]
+	if !errors.As(err, &conflict) {
+		t.Fatalf("unexpected error: got %v, want %T", err, conflict)
+	}
+
+	expect := ConflictError[string, int]{
+		Keyspace:        "<name>",
+		Key:             "<key>",
+		Revision:        "",
+		CurrentValue:    123,
+		CurrentRevision: r,
+		CurrentKnown:    true,
+	}
+
+	if conflict != expect {
+		t.Fatalf("unexpected conflict: got %#v, want %#v", conflict, expect)
+	}
+}
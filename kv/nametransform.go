@@ -18,6 +18,10 @@ type nameTransformStore[K, V any] struct {
 	transform func(string) string
 }
 
+func (s *nameTransformStore[K, V]) Capabilities() Capabilities {
+	return wrappedCapabilities(s.Store)
+}
+
 func (s *nameTransformStore[K, V]) Open(ctx context.Context, name string) (Keyspace[K, V], error) {
 	ks, err := s.Store.Open(ctx, s.transform(name))
 	if err != nil {
@@ -35,3 +39,11 @@ type nameTransformKeyspace[K, V any] struct {
 func (ks *nameTransformKeyspace[K, V]) Name() string {
 	return ks.name
 }
+
+func (ks *nameTransformKeyspace[K, V]) RangeKeys(ctx context.Context, fn KeyRangeFunc[K]) error {
+	return RangeKeys(ctx, ks.Keyspace, fn)
+}
+
+func (ks *nameTransformKeyspace[K, V]) Stats(ctx context.Context) (Stats, error) {
+	return StatsOf(ctx, ks.Keyspace)
+}
@@ -0,0 +1,37 @@
+package kv
+
+import "context"
+
+// A KeyRangeFunc is a function used to range over the keys in a [Keyspace]
+// without their associated values.
+//
+// If err is non-nil, ranging stops and err is propagated up the stack.
+// Otherwise, if ok is false, ranging stops without any error being propagated.
+//
+// k is the key and r is the current revision.
+type KeyRangeFunc[K any] func(ctx context.Context, k K, r Revision) (ok bool, err error)
+
+// KeyRanger is an interface for keyspaces that can range over their keys more
+// efficiently than by loading every key/value pair.
+type KeyRanger[K any] interface {
+	// RangeKeys invokes fn for each key in the keyspace in an undefined order.
+	RangeKeys(ctx context.Context, fn KeyRangeFunc[K]) error
+}
+
+// RangeKeys invokes fn for each key in ks in an undefined order.
+//
+// If ks implements [KeyRanger] the keys are read without loading their values,
+// otherwise it is equivalent to calling [Keyspace.Range] and discarding the
+// values.
+func RangeKeys[K, V any](ctx context.Context, ks Keyspace[K, V], fn KeyRangeFunc[K]) error {
+	if r, ok := ks.(KeyRanger[K]); ok {
+		return r.RangeKeys(ctx, fn)
+	}
+
+	return ks.Range(
+		ctx,
+		func(ctx context.Context, k K, _ V, r Revision) (bool, error) {
+			return fn(ctx, k, r)
+		},
+	)
+}
@@ -0,0 +1,55 @@
+package kv
+
+import "context"
+
+// Stats contains statistics about the key/value pairs in a [Keyspace].
+type Stats struct {
+	// Keys is the number of keys in the keyspace.
+	Keys uint64
+
+	// Bytes is the total size of the binary representation of the keys and
+	// values in the keyspace.
+	Bytes uint64
+}
+
+// StatsReporter is an interface for keyspaces that can report their [Stats]
+// more efficiently than by ranging over every key/value pair.
+type StatsReporter interface {
+	// Stats returns statistics about the key/value pairs in the keyspace.
+	Stats(ctx context.Context) (Stats, error)
+}
+
+// StatsOf returns statistics about the key/value pairs in ks.
+//
+// If ks implements [StatsReporter] its statistics are used, otherwise they are
+// computed by ranging over every key/value pair. When ranging, the size of the
+// keys and values is only known if ks is a [BinaryKeyspace]; otherwise
+// [Stats.Bytes] is zero.
+//
+// The statistics are a snapshot, and may be outdated by the time they are
+// returned.
+func StatsOf[K, V any](ctx context.Context, ks Keyspace[K, V]) (Stats, error) {
+	if r, ok := ks.(StatsReporter); ok {
+		return r.Stats(ctx)
+	}
+
+	size := func(K, V) int { return 0 }
+	if _, ok := any(ks).(BinaryKeyspace); ok {
+		size = func(k K, v V) int {
+			return len(any(k).([]byte)) + len(any(v).([]byte))
+		}
+	}
+
+	var stats Stats
+
+	err := ks.Range(
+		ctx,
+		func(_ context.Context, k K, v V, _ Revision) (bool, error) {
+			stats.Keys++
+			stats.Bytes += uint64(size(k, v))
+			return true, nil
+		},
+	)
+
+	return stats, err
+}
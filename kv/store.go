@@ -14,3 +14,13 @@ type Store[K, V any] interface {
 	// already exist.
 	Provision(ctx context.Context) error
 }
+
+// NameLister is an interface for stores that record the names of their
+// keyspaces.
+//
+// A name is recorded when the keyspace is first opened, regardless of whether
+// it contains any data.
+type NameLister interface {
+	// Names returns the names of the keyspaces in the store, in lexical order.
+	Names(ctx context.Context) ([]string, error)
+}
@@ -37,6 +37,10 @@ func (s *instrumentedStore) Provision(ctx context.Context) error {
 	return s.Next.Provision(ctx)
 }
 
+func (s *instrumentedStore) Capabilities() Capabilities {
+	return wrappedCapabilities(s.Next)
+}
+
 // Open returns the keyspace with the given name.
 func (s *instrumentedStore) Open(ctx context.Context, name string) (BinaryKeyspace, error) {
 	telem := s.Telemetry.Recorder(
@@ -332,6 +336,80 @@ func (ks *instrumentedKeyspace) Range(ctx context.Context, fn BinaryRangeFunc) e
 	return nil
 }
 
+func (ks *instrumentedKeyspace) RangeKeys(ctx context.Context, fn BinaryKeyRangeFunc) error {
+	ctx, span := ks.Telemetry.StartSpan(ctx, "keyspace.range-keys")
+	defer span.End()
+
+	var (
+		count     uint64
+		totalSize int64
+		brokeLoop bool
+	)
+
+	ks.Telemetry.Info(ctx, "keyspace.range-keys.start", "reading keys")
+
+	err := RangeKeys(
+		ctx,
+		ks.Next,
+		func(ctx context.Context, k []byte, r Revision) (bool, error) {
+			count++
+
+			keySize := int64(len(k))
+			totalSize += keySize
+
+			ks.KeyIO(ctx, keySize, telemetry.ReadDirection)
+			ks.KeySize(ctx, keySize, telemetry.ReadDirection)
+
+			ok, err := fn(ctx, k, r)
+			if ok || err != nil {
+				return ok, err
+			}
+
+			brokeLoop = true
+			return false, nil
+		},
+	)
+
+	span.SetAttributes(
+		telemetry.Int("keys_read", count),
+		telemetry.Int("bytes_read", totalSize),
+		telemetry.Bool("reached_end", !brokeLoop && err == nil),
+	)
+
+	if err != nil {
+		ks.Telemetry.Error(ctx, "keyspace.range-keys.error", "unable to range over keys", err)
+		return err
+	}
+
+	if brokeLoop {
+		ks.Telemetry.Info(ctx, "keyspace.range-keys.break", "range aborted cleanly before visiting all keys")
+	} else {
+		ks.Telemetry.Info(ctx, "keyspace.range-keys.end", "range visited all keys")
+	}
+
+	return nil
+}
+
+func (ks *instrumentedKeyspace) Stats(ctx context.Context) (Stats, error) {
+	ctx, span := ks.Telemetry.StartSpan(ctx, "keyspace.stats")
+	defer span.End()
+
+	stats, err := StatsOf(ctx, ks.Next)
+	if err != nil {
+		ks.Telemetry.Error(ctx, "keyspace.stats.error", "unable to fetch keyspace statistics", err)
+		return Stats{}, err
+	}
+
+	span.SetAttributes(
+		telemetry.Int("keys", stats.Keys),
+		telemetry.Int("bytes", stats.Bytes),
+	)
+
+	ks.Telemetry.Info(ctx, "keyspace.stats.ok", "fetched keyspace statistics")
+
+	return stats, nil
+}
+
 func (ks *instrumentedKeyspace) Close() error {
 	if ks.Next == nil {
 		// Closing an already-closed resource is not an error, allowing Close()
@@ -5,7 +5,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"reflect"
 	"slices"
 	"testing"
 
@@ -221,24 +220,25 @@ func RunTests(
 							k := []byte("<key>")
 							v := []byte("<value>")
 
-							if _, err := ks.Set(t.Context(), k, v, ""); err != nil {
+							r, err := ks.Set(t.Context(), k, v, "")
+							if err != nil {
 								t.Fatal(err)
 							}
 
-							_, err := ks.Set(t.Context(), k, v, c.Revision)
-
-							expect := ConflictError[[]byte]{
-								Keyspace: ks.Name(),
-								Key:      k,
-								Revision: c.Revision,
-							}
-							if !reflect.DeepEqual(err, expect) {
-								t.Fatalf("unexpected error: got %q, want %q", err, expect)
-							}
-
-							if !IsConflict(err) {
-								t.Fatalf("expected IsConflict to return true")
-							}
+							_, err = ks.Set(t.Context(), k, v, c.Revision)
+
+							expectConflict(
+								t,
+								err,
+								ConflictError[[]byte, []byte]{
+									Keyspace:        ks.Name(),
+									Key:             k,
+									Revision:        c.Revision,
+									CurrentValue:    v,
+									CurrentRevision: r,
+									CurrentKnown:    true,
+								},
+							)
 						})
 					}
 				})
@@ -255,18 +255,16 @@ func RunTests(
 
 					_, err := ks.Set(t.Context(), k, v, "<wrong>")
 
-					expect := ConflictError[[]byte]{
-						Keyspace: ks.Name(),
-						Key:      k,
-						Revision: "<wrong>",
-					}
-					if !reflect.DeepEqual(err, expect) {
-						t.Fatalf("unexpected error: got %q, want %q", err, expect)
-					}
-
-					if !IsConflict(err) {
-						t.Fatalf("expected IsConflict to return true")
-					}
+					expectConflict(
+						t,
+						err,
+						ConflictError[[]byte, []byte]{
+							Keyspace:     ks.Name(),
+							Key:          k,
+							Revision:     "<wrong>",
+							CurrentKnown: true,
+						},
+					)
 				})
 
 				t.Run("it allows deletion with an empty revision", func(t *testing.T) {
@@ -338,18 +336,16 @@ func RunTests(
 
 					_, err = ks.Set(t.Context(), k, []byte("<value>"), "<wrong>")
 
-					expect := ConflictError[[]byte]{
-						Keyspace: ks.Name(),
-						Key:      k,
-						Revision: "<wrong>",
-					}
-					if !reflect.DeepEqual(err, expect) {
-						t.Fatalf("unexpected error: got %q, want %q", err, expect)
-					}
-
-					if !IsConflict(err) {
-						t.Fatalf("expected IsConflict to return true")
-					}
+					expectConflict(
+						t,
+						err,
+						ConflictError[[]byte, []byte]{
+							Keyspace:     ks.Name(),
+							Key:          k,
+							Revision:     "<wrong>",
+							CurrentKnown: true,
+						},
+					)
 				})
 			})
 
@@ -557,6 +553,160 @@ func RunTests(
 			})
 		})
 
+		t.Run("RangeKeys", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it calls the function for each key with its current revision", func(t *testing.T) {
+				t.Parallel()
+
+				ks := setup(t)
+
+				expect := map[string]Revision{}
+
+				for n := range 10 {
+					k := fmt.Sprintf("<key-%d>", n)
+
+					r, err := ks.Set(t.Context(), []byte(k), []byte("<value>"), "")
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if n%2 != 0 {
+						if _, err := ks.Set(t.Context(), []byte(k), nil, r); err != nil {
+							t.Fatal(err)
+						}
+						continue
+					}
+
+					expect[k] = r
+				}
+
+				actual := map[string]Revision{}
+
+				if err := RangeKeys(
+					t.Context(),
+					ks,
+					func(_ context.Context, k []byte, r Revision) (bool, error) {
+						actual[string(k)] = r
+						return true, nil
+					},
+				); err != nil {
+					t.Fatal(err)
+				}
+
+				if diff := cmp.Diff(expect, actual); diff != "" {
+					t.Fatalf("unexpected keys (-want +got):\n%s", diff)
+				}
+			})
+
+			t.Run("it stops iterating if the function returns false", func(t *testing.T) {
+				t.Parallel()
+
+				ks := setup(t)
+
+				for n := range 2 {
+					k := []byte(fmt.Sprintf("<key-%d>", n))
+					if err := ks.SetUnconditional(t.Context(), k, []byte("<value>")); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				called := false
+				if err := RangeKeys(
+					t.Context(),
+					ks,
+					func(context.Context, []byte, Revision) (bool, error) {
+						if called {
+							t.Fatal("unexpected call")
+						}
+						called = true
+						return false, nil
+					},
+				); err != nil {
+					t.Fatal(err)
+				}
+			})
+		})
+
+		t.Run("StatsOf", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it returns zero statistics for an empty keyspace", func(t *testing.T) {
+				t.Parallel()
+
+				ks := setup(t)
+
+				stats, err := StatsOf(t.Context(), ks)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if stats != (Stats{}) {
+					t.Fatalf("unexpected statistics: got %+v, want zero", stats)
+				}
+			})
+
+			t.Run("it returns the number of keys and their size", func(t *testing.T) {
+				t.Parallel()
+
+				ks := setup(t)
+
+				for _, k := range []string{"<key-1>", "<key-2>", "<key-3>"} {
+					if err := ks.SetUnconditional(t.Context(), []byte(k), []byte("<value>")); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				if err := ks.SetUnconditional(t.Context(), []byte("<key-3>"), nil); err != nil {
+					t.Fatal(err)
+				}
+
+				stats, err := StatsOf(t.Context(), ks)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				expect := Stats{
+					Keys:  2,
+					Bytes: 2 * uint64(len("<key-1>")+len("<value>")),
+				}
+
+				if stats != expect {
+					t.Fatalf("unexpected statistics: got %+v, want %+v", stats, expect)
+				}
+			})
+
+			t.Run("it reflects the size of replaced values", func(t *testing.T) {
+				t.Parallel()
+
+				ks := setup(t)
+
+				for _, v := range []string{"<value>", "<longer-value>", "<v>"} {
+					if err := ks.SetUnconditional(t.Context(), []byte("<key>"), []byte(v)); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				if err := ks.SetUnconditional(t.Context(), []byte("<missing>"), nil); err != nil {
+					t.Fatal(err)
+				}
+
+				stats, err := StatsOf(t.Context(), ks)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				expect := Stats{
+					Keys:  1,
+					Bytes: uint64(len("<key>") + len("<v>")),
+				}
+
+				if stats != expect {
+					t.Fatalf("unexpected statistics: got %+v, want %+v", stats, expect)
+				}
+			})
+		})
+
 		t.Run("Range", func(t *testing.T) {
 			t.Parallel()
 
@@ -1107,4 +1257,38 @@ func RunTests(
 			)
 		})
 	})
+
+	runCapabilityTests(t, store)
+	runLinearizabilityTests(t, store)
+}
+
+// expectConflict asserts that err is a [ConflictError] equivalent to expect.
+//
+// Drivers are not required to report the current value and revision of the
+// key, so these are only compared if the driver reports them as known.
+func expectConflict(
+	t *testing.T,
+	err error,
+	expect ConflictError[[]byte, []byte],
+) {
+	t.Helper()
+
+	if !IsConflict(err) {
+		t.Fatalf("expected IsConflict to return true, got %v", err)
+	}
+
+	var actual ConflictError[[]byte, []byte]
+	if !errors.As(err, &actual) {
+		t.Fatalf("unexpected error: got %T, want %T", err, expect)
+	}
+
+	if !actual.CurrentKnown {
+		expect.CurrentValue = nil
+		expect.CurrentRevision = ""
+		expect.CurrentKnown = false
+	}
+
+	if diff := cmp.Diff(expect, actual); diff != "" {
+		t.Fatalf("unexpected conflict error (-want +got):\n%s", diff)
+	}
 }
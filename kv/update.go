@@ -0,0 +1,178 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// Update atomically replaces the value associated with k with the value
+// returned by fn.
+//
+// fn is called with the current value associated with k, and a boolean that
+// indicates whether k is present in the keyspace. If fn returns the zero-value
+// of V the key is deleted.
+//
+// If the value is modified concurrently, fn is called again with the new value
+// and the update is retried, up to a limited number of attempts. fn must
+// therefore be safe to call multiple times. Between attempts, Update waits for
+// a randomized, exponentially increasing delay. If the [ConflictError] returned
+// by the keyspace includes the current value, it is passed to fn without
+// re-reading the key.
+//
+// If fn returns an error the update is abandoned and the error is returned.
+//
+// On success, it returns the new value and revision.
+func Update[K, V any](
+	ctx context.Context,
+	ks Keyspace[K, V],
+	k K,
+	fn func(v V, exists bool) (V, error),
+	options ...UpdateOption,
+) (V, Revision, error) {
+	opts := updateOptions{
+		MaxAttempts: DefaultUpdateMaxAttempts,
+		MinBackoff:  DefaultUpdateMinBackoff,
+		MaxBackoff:  DefaultUpdateMaxBackoff,
+	}
+
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	var zero V
+
+	v, r, err := ks.Get(ctx, k)
+	if err != nil {
+		return zero, "", err
+	}
+
+	for attempt := 1; ; attempt++ {
+		next, err := fn(v, r != "")
+		if err != nil {
+			return zero, "", err
+		}
+
+		nextRev, err := ks.Set(ctx, k, next, r)
+		if err == nil {
+			return next, nextRev, nil
+		}
+
+		var conflict ConflictError[K, V]
+		if !errors.As(err, &conflict) {
+			return zero, "", err
+		}
+
+		if attempt >= opts.MaxAttempts {
+			return zero, "", fmt.Errorf(
+				"unable to update key %v in the %q keyspace after %d attempt(s): %w",
+				k,
+				ks.Name(),
+				attempt,
+				err,
+			)
+		}
+
+		if err := sleep(ctx, opts.backoff(attempt)); err != nil {
+			return zero, "", err
+		}
+
+		if conflict.CurrentKnown {
+			v, r = conflict.CurrentValue, conflict.CurrentRevision
+		} else if v, r, err = ks.Get(ctx, k); err != nil {
+			return zero, "", err
+		}
+	}
+}
+
+const (
+	// DefaultUpdateMaxAttempts is the default maximum number of times that
+	// [Update] attempts to set the new value.
+	DefaultUpdateMaxAttempts = 10
+
+	// DefaultUpdateMinBackoff is the default upper bound on the delay before
+	// the first retry performed by [Update].
+	DefaultUpdateMinBackoff = 5 * time.Millisecond
+
+	// DefaultUpdateMaxBackoff is the default upper bound on the delay between
+	// any two attempts performed by [Update].
+	DefaultUpdateMaxBackoff = 1 * time.Second
+)
+
+// UpdateOption is a functional option that changes the behavior of [Update].
+type UpdateOption func(*updateOptions)
+
+// WithMaxAttempts is an [UpdateOption] that sets the maximum number of times
+// that [Update] attempts to set the new value before giving up.
+//
+// n must be at least 1.
+func WithMaxAttempts(n int) UpdateOption {
+	if n < 1 {
+		panic("the maximum number of attempts must be at least 1")
+	}
+
+	return func(o *updateOptions) {
+		o.MaxAttempts = n
+	}
+}
+
+// WithBackoff is an [UpdateOption] that sets the bounds of the randomized
+// delay between attempts.
+//
+// The upper bound of the delay starts at min and doubles after each attempt,
+// but never exceeds max. The actual delay is chosen uniformly at random between
+// zero and the current upper bound.
+func WithBackoff(min, max time.Duration) UpdateOption {
+	if min < 0 || max < min {
+		panic("the backoff bounds must satisfy 0 <= min <= max")
+	}
+
+	return func(o *updateOptions) {
+		o.MinBackoff = min
+		o.MaxBackoff = max
+	}
+}
+
+type updateOptions struct {
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+}
+
+// backoff returns the delay to use after the given (1-based) attempt.
+func (o updateOptions) backoff(attempt int) time.Duration {
+	limit := o.MinBackoff
+
+	for range attempt - 1 {
+		if limit >= o.MaxBackoff/2 {
+			limit = o.MaxBackoff
+			break
+		}
+		limit *= 2
+	}
+
+	if limit <= 0 {
+		return 0
+	}
+
+	return rand.N(limit + 1)
+}
+
+// sleep blocks for d, or until ctx is canceled.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,189 @@
+package kv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/driver/memory/memorykv"
+	. "github.com/dogmatiq/persistencekit/kv"
+)
+
+func TestUpdate(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) Keyspace[string, string] {
+		store := &memorykv.Store[string, string]{}
+
+		ks, err := store.Open(t.Context(), "<keyspace>")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			ks.Close()
+		})
+
+		return ks
+	}
+
+	t.Run("it sets the value returned by the function", func(t *testing.T) {
+		t.Parallel()
+
+		ks := setup(t)
+
+		v, r, err := Update(
+			t.Context(),
+			ks,
+			"<key>",
+			func(v string, exists bool) (string, error) {
+				if exists {
+					t.Fatal("did not expect key to exist")
+				}
+				return "<value>", nil
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v != "<value>" {
+			t.Fatalf("unexpected value: got %q, want %q", v, "<value>")
+		}
+
+		actualValue, actualRevision, err := ks.Get(t.Context(), "<key>")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if actualValue != "<value>" {
+			t.Fatalf("unexpected value: got %q, want %q", actualValue, "<value>")
+		}
+
+		if actualRevision != r {
+			t.Fatalf("unexpected revision: got %q, want %q", actualRevision, r)
+		}
+	})
+
+	t.Run("it retries on conflict", func(t *testing.T) {
+		t.Parallel()
+
+		ks := setup(t)
+
+		if err := ks.SetUnconditional(t.Context(), "<key>", "<value-1>"); err != nil {
+			t.Fatal(err)
+		}
+
+		var calls []string
+
+		v, _, err := Update(
+			t.Context(),
+			ks,
+			"<key>",
+			func(v string, exists bool) (string, error) {
+				if !exists {
+					t.Fatal("expected key to exist")
+				}
+
+				calls = append(calls, v)
+
+				if len(calls) == 1 {
+					// Cause a conflict by modifying the value "concurrently".
+					if err := ks.SetUnconditional(t.Context(), "<key>", "<value-2>"); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				return v + "!", nil
+			},
+			WithBackoff(0, 0),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v != "<value-2>!" {
+			t.Fatalf("unexpected value: got %q, want %q", v, "<value-2>!")
+		}
+
+		if len(calls) != 2 || calls[0] != "<value-1>" || calls[1] != "<value-2>" {
+			t.Fatalf("unexpected calls: %q", calls)
+		}
+	})
+
+	t.Run("it returns the error returned by the function", func(t *testing.T) {
+		t.Parallel()
+
+		ks := setup(t)
+
+		want := errors.New("<error>")
+
+		_, _, err := Update(
+			t.Context(),
+			ks,
+			"<key>",
+			func(string, bool) (string, error) {
+				return "", want
+			},
+		)
+		if err != want {
+			t.Fatalf("unexpected error: got %v, want %v", err, want)
+		}
+	})
+
+	t.Run("it returns a conflict error if the maximum number of attempts is exceeded", func(t *testing.T) {
+		t.Parallel()
+
+		ks := setup(t)
+
+		calls := 0
+
+		_, _, err := Update(
+			t.Context(),
+			ks,
+			"<key>",
+			func(v string, _ bool) (string, error) {
+				calls++
+				if err := ks.SetUnconditional(t.Context(), "<key>", "<other>"); err != nil {
+					t.Fatal(err)
+				}
+				return "<value>", nil
+			},
+			WithMaxAttempts(3),
+			WithBackoff(0, 0),
+		)
+		if !IsConflict(err) {
+			t.Fatalf("expected a conflict error, got %v", err)
+		}
+
+		if calls != 3 {
+			t.Fatalf("unexpected number of attempts: got %d, want 3", calls)
+		}
+	})
+
+	t.Run("it stops retrying when the context is canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ks := setup(t)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		_, _, err := Update(
+			ctx,
+			ks,
+			"<key>",
+			func(v string, _ bool) (string, error) {
+				if err := ks.SetUnconditional(t.Context(), "<key>", "<other>"); err != nil {
+					t.Fatal(err)
+				}
+				cancel()
+				return "<value>", nil
+			},
+			WithBackoff(time.Minute, time.Minute),
+		)
+		if err != context.Canceled {
+			t.Fatalf("unexpected error: got %v, want %v", err, context.Canceled)
+		}
+	})
+}
@@ -0,0 +1,8 @@
+// Package lease provides an abstraction of a persisted, time-limited lock that
+// grants exclusive ownership of a named resource to a single holder.
+//
+// Each time ownership of a lease changes hands it is assigned a new fencing
+// token that is greater than any token previously assigned to that lease. The
+// token can be passed to other systems so that they can reject requests made
+// by a holder whose lease has since expired.
+package lease
@@ -0,0 +1,26 @@
+package lease
+
+import (
+	"errors"
+	"fmt"
+)
+
+// IsNotHeld returns true if err is caused by [NotHeldError].
+func IsNotHeld(err error) bool {
+	return errors.As(err, &NotHeldError{})
+}
+
+// NotHeldError is returned by [Lease.Renew] if the lease is no longer held
+// under the given fencing token.
+type NotHeldError struct {
+	Lease string
+	Token Token
+}
+
+func (e NotHeldError) Error() string {
+	return fmt.Sprintf(
+		"the %q lease is no longer held with fencing token %d",
+		e.Lease,
+		e.Token,
+	)
+}
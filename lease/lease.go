@@ -0,0 +1,55 @@
+package lease
+
+import (
+	"context"
+	"time"
+)
+
+// Token is a fencing token that identifies a specific acquisition of a lease.
+//
+// Tokens are strictly increasing within each lease. The first acquisition of a
+// lease is assigned a token of 1.
+type Token uint64
+
+// Grant describes a holder's ownership of a lease.
+type Grant struct {
+	// Holder is an application-defined identifier of the lease's owner.
+	Holder string
+
+	// Token is the fencing token assigned when the lease was acquired.
+	Token Token
+
+	// ExpiresAt is the time at which the lease expires unless it is renewed.
+	ExpiresAt time.Time
+}
+
+// Lease is a time-limited lock that is held by at most one holder at a time.
+type Lease interface {
+	// Name returns the name of the lease.
+	Name() string
+
+	// Acquire attempts to acquire the lease on behalf of holder for the given
+	// duration.
+	//
+	// If the lease is not held, or has expired, it is acquired and assigned a
+	// new fencing token. If the lease is already held by the same holder, its
+	// expiry time is extended and the existing token is retained. ok is false
+	// if the lease is held by a different holder.
+	Acquire(ctx context.Context, holder string, ttl time.Duration) (g Grant, ok bool, err error)
+
+	// Renew extends the expiry time of the lease described by g, such that it
+	// expires ttl from now.
+	//
+	// It returns a [NotHeldError] if the lease has expired or has been
+	// released, even if it has not been acquired by some other holder.
+	Renew(ctx context.Context, g Grant, ttl time.Duration) (Grant, error)
+
+	// Release relinquishes ownership of the lease described by g, allowing it
+	// to be acquired by another holder without waiting for it to expire.
+	//
+	// It is not an error to release a lease that is no longer held.
+	Release(ctx context.Context, g Grant) error
+
+	// Close closes the lease. It does not release the lease.
+	Close() error
+}
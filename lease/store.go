@@ -0,0 +1,15 @@
+package lease
+
+import (
+	"context"
+)
+
+// Store is a collection of leases.
+type Store interface {
+	// Open returns the lease with the given name.
+	Open(ctx context.Context, name string) (Lease, error)
+
+	// Provision creates the infrastructure used by the store if it does not
+	// already exist.
+	Provision(ctx context.Context) error
+}
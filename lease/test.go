@@ -0,0 +1,363 @@
+package lease
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+)
+
+// RunTests runs tests that confirm a [Store] implementation behaves correctly.
+func RunTests(
+	t *testing.T,
+	store Store,
+) {
+	const (
+		// ttl is a lease duration that does not elapse during a test.
+		ttl = time.Minute
+
+		// shortTTL is a lease duration that is allowed to elapse during a test.
+		shortTTL = 100 * time.Millisecond
+	)
+
+	setup := func(t *testing.T) Lease {
+		name := xtesting.SequentialName("lease")
+
+		l, err := store.Open(t.Context(), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() {
+			if err := l.Close(); err != nil {
+				t.Error(err)
+			}
+		})
+
+		if l.Name() != name {
+			t.Fatalf("unexpected lease name: got %q, want %q", l.Name(), name)
+		}
+
+		return l
+	}
+
+	acquire := func(t *testing.T, l Lease, holder string, ttl time.Duration) Grant {
+		t.Helper()
+
+		g, ok, err := l.Acquire(t.Context(), holder, ttl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatalf("expected %q to acquire the lease", holder)
+		}
+
+		return g
+	}
+
+	expectNotAcquired := func(t *testing.T, l Lease, holder string) {
+		t.Helper()
+
+		_, ok, err := l.Acquire(t.Context(), holder, ttl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Fatalf("did not expect %q to acquire the lease", holder)
+		}
+	}
+
+	expire := func() {
+		time.Sleep(2 * shortTTL)
+	}
+
+	t.Run("Store", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("Open", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("allows leases to be opened multiple times", func(t *testing.T) {
+				t.Parallel()
+
+				name := xtesting.SequentialName("lease")
+
+				l1, err := store.Open(t.Context(), name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer l1.Close()
+
+				l2, err := store.Open(t.Context(), name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer l2.Close()
+
+				acquire(t, l1, "<holder-1>", ttl)
+				expectNotAcquired(t, l2, "<holder-2>")
+			})
+
+			t.Run("does not share state between leases with different names", func(t *testing.T) {
+				t.Parallel()
+
+				l1 := setup(t)
+				l2 := setup(t)
+
+				acquire(t, l1, "<holder-1>", ttl)
+				acquire(t, l2, "<holder-2>", ttl)
+			})
+		})
+	})
+
+	t.Run("Lease", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("Acquire", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it acquires a lease that has never been held", func(t *testing.T) {
+				t.Parallel()
+
+				l := setup(t)
+				g := acquire(t, l, "<holder>", ttl)
+
+				if g.Holder != "<holder>" {
+					t.Fatalf("unexpected holder: got %q, want %q", g.Holder, "<holder>")
+				}
+
+				if g.Token != 1 {
+					t.Fatalf("unexpected token: got %d, want 1", g.Token)
+				}
+
+				if g.ExpiresAt.IsZero() {
+					t.Fatal("expected a non-zero expiry time")
+				}
+			})
+
+			t.Run("it does not acquire a lease that is held by another holder", func(t *testing.T) {
+				t.Parallel()
+
+				l := setup(t)
+				acquire(t, l, "<holder-1>", ttl)
+				expectNotAcquired(t, l, "<holder-2>")
+			})
+
+			t.Run("it extends a lease that is already held by the same holder", func(t *testing.T) {
+				t.Parallel()
+
+				l := setup(t)
+				first := acquire(t, l, "<holder>", shortTTL)
+				second := acquire(t, l, "<holder>", ttl)
+
+				if second.Token != first.Token {
+					t.Fatalf("unexpected token: got %d, want %d", second.Token, first.Token)
+				}
+
+				if !second.ExpiresAt.After(first.ExpiresAt) {
+					t.Fatalf("expected expiry time to be extended beyond %s, got %s", first.ExpiresAt, second.ExpiresAt)
+				}
+
+				expire()
+				expectNotAcquired(t, l, "<other>")
+			})
+
+			t.Run("it acquires a lease that has expired", func(t *testing.T) {
+				t.Parallel()
+
+				l := setup(t)
+				first := acquire(t, l, "<holder-1>", shortTTL)
+
+				expire()
+
+				second := acquire(t, l, "<holder-2>", ttl)
+
+				if second.Token <= first.Token {
+					t.Fatalf("expected token to increase beyond %d, got %d", first.Token, second.Token)
+				}
+			})
+
+			t.Run("it assigns a new token when the same holder acquires an expired lease", func(t *testing.T) {
+				t.Parallel()
+
+				l := setup(t)
+				first := acquire(t, l, "<holder>", shortTTL)
+
+				expire()
+
+				second := acquire(t, l, "<holder>", ttl)
+
+				if second.Token <= first.Token {
+					t.Fatalf("expected token to increase beyond %d, got %d", first.Token, second.Token)
+				}
+			})
+
+			t.Run("it acquires a lease that has been released", func(t *testing.T) {
+				t.Parallel()
+
+				l := setup(t)
+				first := acquire(t, l, "<holder-1>", ttl)
+
+				if err := l.Release(t.Context(), first); err != nil {
+					t.Fatal(err)
+				}
+
+				second := acquire(t, l, "<holder-2>", ttl)
+
+				if second.Token <= first.Token {
+					t.Fatalf("expected token to increase beyond %d, got %d", first.Token, second.Token)
+				}
+			})
+
+			t.Run("it grants the lease to exactly one of many concurrent holders", func(t *testing.T) {
+				t.Parallel()
+
+				name := xtesting.SequentialName("lease")
+
+				var (
+					g      sync.WaitGroup
+					m      sync.Mutex
+					grants []Grant
+				)
+
+				for n := range 10 {
+					g.Go(func() {
+						// Each holder uses its own handle, as it would if each
+						// were a separate process.
+						l, err := store.Open(t.Context(), name)
+						if err != nil {
+							t.Error(err)
+							return
+						}
+						defer l.Close()
+
+						gr, ok, err := l.Acquire(t.Context(), fmt.Sprintf("<holder-%d>", n), ttl)
+						if err != nil {
+							t.Error(err)
+							return
+						}
+
+						if ok {
+							m.Lock()
+							grants = append(grants, gr)
+							m.Unlock()
+						}
+					})
+				}
+
+				g.Wait()
+
+				if len(grants) != 1 {
+					t.Fatalf("expected exactly one holder to acquire the lease, got %d", len(grants))
+				}
+			})
+		})
+
+		t.Run("Renew", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it extends the expiry time of the lease", func(t *testing.T) {
+				t.Parallel()
+
+				l := setup(t)
+				g := acquire(t, l, "<holder>", shortTTL)
+
+				renewed, err := l.Renew(t.Context(), g, ttl)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if renewed.Holder != g.Holder || renewed.Token != g.Token {
+					t.Fatalf("unexpected grant: got %+v, want holder %q and token %d", renewed, g.Holder, g.Token)
+				}
+
+				if !renewed.ExpiresAt.After(g.ExpiresAt) {
+					t.Fatalf("expected expiry time to be extended beyond %s, got %s", g.ExpiresAt, renewed.ExpiresAt)
+				}
+
+				expire()
+				expectNotAcquired(t, l, "<other>")
+			})
+
+			t.Run("it returns an error if the lease has expired", func(t *testing.T) {
+				t.Parallel()
+
+				l := setup(t)
+				g := acquire(t, l, "<holder>", shortTTL)
+
+				expire()
+
+				_, err := l.Renew(t.Context(), g, ttl)
+				if !IsNotHeld(err) {
+					t.Fatalf("expected a not-held error, got %v", err)
+				}
+			})
+
+			t.Run("it returns an error if the lease has been released", func(t *testing.T) {
+				t.Parallel()
+
+				l := setup(t)
+				g := acquire(t, l, "<holder>", ttl)
+
+				if err := l.Release(t.Context(), g); err != nil {
+					t.Fatal(err)
+				}
+
+				_, err := l.Renew(t.Context(), g, ttl)
+				if !IsNotHeld(err) {
+					t.Fatalf("expected a not-held error, got %v", err)
+				}
+			})
+
+			t.Run("it returns an error if the lease has been acquired by another holder", func(t *testing.T) {
+				t.Parallel()
+
+				l := setup(t)
+				g := acquire(t, l, "<holder-1>", shortTTL)
+
+				expire()
+				acquire(t, l, "<holder-2>", ttl)
+
+				_, err := l.Renew(t.Context(), g, ttl)
+				if !IsNotHeld(err) {
+					t.Fatalf("expected a not-held error, got %v", err)
+				}
+			})
+		})
+
+		t.Run("Release", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it does not return an error if the lease is not held", func(t *testing.T) {
+				t.Parallel()
+
+				l := setup(t)
+				g := acquire(t, l, "<holder>", ttl)
+
+				for range 2 {
+					if err := l.Release(t.Context(), g); err != nil {
+						t.Fatal(err)
+					}
+				}
+			})
+
+			t.Run("it does not release a lease that has been acquired by another holder", func(t *testing.T) {
+				t.Parallel()
+
+				l := setup(t)
+				g := acquire(t, l, "<holder-1>", shortTTL)
+
+				expire()
+				acquire(t, l, "<holder-2>", ttl)
+
+				if err := l.Release(t.Context(), g); err != nil {
+					t.Fatal(err)
+				}
+
+				expectNotAcquired(t, l, "<holder-3>")
+			})
+		})
+	})
+}
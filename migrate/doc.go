@@ -0,0 +1,13 @@
+// Package migrate copies journals, keyspaces and sets from one persistence
+// driver to another without downtime.
+//
+// A migration is performed by a [Migrator] in three phases. First, each store
+// is bulk-copied from the source to the target. Next, the target catches up
+// with changes made to the source during the copy; journals are tailed from the
+// position at which the copy ended, and keyspaces and sets are reconciled with
+// the source. Finally, the target is verified against the source.
+//
+// Use [WithDualWrite] while migrating to apply each change made by the
+// application to both drivers, so that the target converges with the source
+// and the application can be cut over to the target once verification passes.
+package migrate
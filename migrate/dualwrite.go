@@ -0,0 +1,451 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/persistencekit/blob"
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/health"
+	"github.com/dogmatiq/persistencekit/journal"
+	"github.com/dogmatiq/persistencekit/kv"
+	"github.com/dogmatiq/persistencekit/lease"
+	"github.com/dogmatiq/persistencekit/queue"
+	"github.com/dogmatiq/persistencekit/schedule"
+	"github.com/dogmatiq/persistencekit/set"
+)
+
+// WithDualWrite returns a [driver.Driver] that applies each change made to its
+// journals, keyspaces and sets to both primary and secondary.
+//
+// Reads are served by primary. Each change is applied to primary first, then
+// mirrored to secondary. If mirroring fails the change remains applied to
+// primary and an error is returned; [Migrator.CatchUp] corrects any
+// difference that results.
+//
+// It is intended to be used during the cutover from one driver to another,
+// with the source of the migration as primary and the target as secondary.
+// The lease, queue, schedule and blob stores are those of primary. Closing the
+// returned driver closes both primary and secondary.
+func WithDualWrite(primary, secondary driver.Driver) driver.Driver {
+	return &dualWriteDriver{primary, secondary}
+}
+
+type dualWriteDriver struct {
+	Primary   driver.Driver
+	Secondary driver.Driver
+}
+
+func (d *dualWriteDriver) JournalStore() journal.BinaryStore {
+	return &dualWriteJournalStore{d.Primary.JournalStore(), d.Secondary.JournalStore()}
+}
+
+func (d *dualWriteDriver) KVStore() kv.BinaryStore {
+	return &dualWriteKVStore{d.Primary.KVStore(), d.Secondary.KVStore()}
+}
+
+func (d *dualWriteDriver) SetStore() set.BinaryStore {
+	return &dualWriteSetStore{d.Primary.SetStore(), d.Secondary.SetStore()}
+}
+
+func (d *dualWriteDriver) LeaseStore() lease.Store {
+	return d.Primary.LeaseStore()
+}
+
+func (d *dualWriteDriver) QueueStore() queue.BinaryStore {
+	return d.Primary.QueueStore()
+}
+
+func (d *dualWriteDriver) ScheduleStore() schedule.BinaryStore {
+	return d.Primary.ScheduleStore()
+}
+
+func (d *dualWriteDriver) BlobStore() blob.Store {
+	return d.Primary.BlobStore()
+}
+
+func (d *dualWriteDriver) Capabilities() driver.Capabilities {
+	return driver.StoreCapabilities(d)
+}
+
+// CheckHealth reports the journal, key/value and set stores as healthy only
+// if they are healthy in both drivers. The remaining stores are checked using
+// the primary driver.
+func (d *dualWriteDriver) CheckHealth(ctx context.Context) health.Report {
+	primary := health.Check(ctx, d.Primary)
+	secondary := health.Check(ctx, d.Secondary)
+
+	return health.NewReport(
+		func(s health.Store) error {
+			var errs []error
+
+			if res, ok := primary.Result(s); ok && res.Err != nil {
+				errs = append(errs, fmt.Errorf("primary: %w", res.Err))
+			}
+
+			switch s {
+			case health.JournalStore, health.KVStore, health.SetStore:
+				if res, ok := secondary.Result(s); ok && res.Err != nil {
+					errs = append(errs, fmt.Errorf("secondary: %w", res.Err))
+				}
+			}
+
+			return errors.Join(errs...)
+		},
+	)
+}
+
+func (d *dualWriteDriver) Close() error {
+	return errors.Join(
+		d.Primary.Close(),
+		d.Secondary.Close(),
+	)
+}
+
+// minLimit returns the smaller of two size limits, where zero means that no
+// limit is known.
+func minLimit(a, b int) int {
+	if a == 0 {
+		return b
+	}
+	if b == 0 {
+		return a
+	}
+	return min(a, b)
+}
+
+type dualWriteJournalStore struct {
+	Primary   journal.BinaryStore
+	Secondary journal.BinaryStore
+}
+
+func (s *dualWriteJournalStore) Provision(ctx context.Context) error {
+	if err := s.Primary.Provision(ctx); err != nil {
+		return err
+	}
+	return s.Secondary.Provision(ctx)
+}
+
+func (s *dualWriteJournalStore) Capabilities() journal.Capabilities {
+	p := journal.CapabilitiesOf(s.Primary)
+	q := journal.CapabilitiesOf(s.Secondary)
+
+	return journal.Capabilities{
+		MaxRecordSize:   minLimit(p.MaxRecordSize, q.MaxRecordSize),
+		MeteredRequests: p.MeteredRequests || q.MeteredRequests,
+	}
+}
+
+func (s *dualWriteJournalStore) Open(ctx context.Context, name string) (journal.BinaryJournal, error) {
+	p, err := s.Primary.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := s.Secondary.Open(ctx, name)
+	if err != nil {
+		p.Close()
+		return nil, err
+	}
+
+	bounds, err := q.Bounds(ctx)
+	if err != nil {
+		p.Close()
+		q.Close()
+		return nil, err
+	}
+
+	return &dualWriteJournal{
+		BinaryJournal: p,
+		secondary:     q,
+		end:           bounds.End,
+	}, nil
+}
+
+type dualWriteJournal struct {
+	journal.BinaryJournal
+	secondary journal.BinaryJournal
+	end       journal.Position
+}
+
+func (j *dualWriteJournal) Append(ctx context.Context, pos journal.Position, rec []byte) error {
+	if err := j.BinaryJournal.Append(ctx, pos, rec); err != nil {
+		return err
+	}
+
+	if pos == j.end {
+		err := j.secondary.Append(ctx, pos, rec)
+		if err == nil {
+			j.end = pos + 1
+			return nil
+		}
+		if !journal.IsConflict(err) {
+			return fmt.Errorf("record was appended to the primary journal, but not the secondary: %w", err)
+		}
+	}
+
+	// The secondary journal is not at the expected position, so copy any
+	// records it is missing from the primary.
+	if _, err := syncJournal(ctx, j.BinaryJournal, j.secondary); err != nil {
+		return fmt.Errorf("record was appended to the primary journal, but not the secondary: %w", err)
+	}
+
+	j.end = pos + 1
+
+	return nil
+}
+
+func (j *dualWriteJournal) Truncate(ctx context.Context, pos journal.Position) error {
+	if err := j.BinaryJournal.Truncate(ctx, pos); err != nil {
+		return err
+	}
+
+	if _, err := syncJournal(ctx, j.BinaryJournal, j.secondary); err != nil {
+		return fmt.Errorf("journal was truncated in the primary, but not the secondary: %w", err)
+	}
+
+	return nil
+}
+
+func (j *dualWriteJournal) Stats(ctx context.Context) (journal.Stats, error) {
+	return journal.StatsOf(ctx, j.BinaryJournal)
+}
+
+func (j *dualWriteJournal) Close() error {
+	return errors.Join(
+		j.BinaryJournal.Close(),
+		j.secondary.Close(),
+	)
+}
+
+type dualWriteKVStore struct {
+	Primary   kv.BinaryStore
+	Secondary kv.BinaryStore
+}
+
+func (s *dualWriteKVStore) Provision(ctx context.Context) error {
+	if err := s.Primary.Provision(ctx); err != nil {
+		return err
+	}
+	return s.Secondary.Provision(ctx)
+}
+
+func (s *dualWriteKVStore) Capabilities() kv.Capabilities {
+	p := kv.CapabilitiesOf(s.Primary)
+	q := kv.CapabilitiesOf(s.Secondary)
+
+	return kv.Capabilities{
+		MaxKeySize:      minLimit(p.MaxKeySize, q.MaxKeySize),
+		MaxValueSize:    minLimit(p.MaxValueSize, q.MaxValueSize),
+		OrderedRange:    p.OrderedRange,
+		ConflictDetail:  p.ConflictDetail,
+		MeteredRequests: p.MeteredRequests || q.MeteredRequests,
+	}
+}
+
+func (s *dualWriteKVStore) Open(ctx context.Context, name string) (kv.BinaryKeyspace, error) {
+	p, err := s.Primary.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := s.Secondary.Open(ctx, name)
+	if err != nil {
+		p.Close()
+		return nil, err
+	}
+
+	return &dualWriteKeyspace{p, q}, nil
+}
+
+type dualWriteKeyspace struct {
+	kv.BinaryKeyspace
+	secondary kv.BinaryKeyspace
+}
+
+func (ks *dualWriteKeyspace) Set(ctx context.Context, k, v []byte, r kv.Revision) (kv.Revision, error) {
+	next, err := ks.BinaryKeyspace.Set(ctx, k, v, r)
+	if err != nil {
+		return "", err
+	}
+
+	if err := ks.mirror(ctx, k, v, next); err != nil {
+		return "", err
+	}
+
+	return next, nil
+}
+
+func (ks *dualWriteKeyspace) SetUnconditional(ctx context.Context, k, v []byte) error {
+	if err := ks.BinaryKeyspace.SetUnconditional(ctx, k, v); err != nil {
+		return err
+	}
+
+	v, r, err := ks.BinaryKeyspace.Get(ctx, k)
+	if err != nil {
+		return fmt.Errorf("key/value pair was modified in the primary keyspace, but not the secondary: %w", err)
+	}
+
+	return ks.mirror(ctx, k, v, r)
+}
+
+// mirror sets the value of k in the secondary keyspace to v, the value of k in
+// the primary keyspace at revision r.
+//
+// If the revision of k in the primary keyspace has changed by the time the
+// value has been written, the process is repeated with the new value, so that
+// concurrent changes are mirrored in the order they were applied to the
+// primary keyspace.
+func (ks *dualWriteKeyspace) mirror(ctx context.Context, k, v []byte, r kv.Revision) error {
+	for {
+		if err := ks.secondary.SetUnconditional(ctx, k, v); err != nil {
+			return fmt.Errorf("key/value pair was modified in the primary keyspace, but not the secondary: %w", err)
+		}
+
+		cv, cr, err := ks.BinaryKeyspace.Get(ctx, k)
+		if err != nil {
+			return fmt.Errorf("key/value pair was modified in the primary keyspace, but not the secondary: %w", err)
+		}
+
+		if cr == r {
+			return nil
+		}
+
+		v, r = cv, cr
+	}
+}
+
+func (ks *dualWriteKeyspace) RangeKeys(ctx context.Context, fn kv.BinaryKeyRangeFunc) error {
+	return kv.RangeKeys(ctx, ks.BinaryKeyspace, fn)
+}
+
+func (ks *dualWriteKeyspace) Stats(ctx context.Context) (kv.Stats, error) {
+	return kv.StatsOf(ctx, ks.BinaryKeyspace)
+}
+
+func (ks *dualWriteKeyspace) Close() error {
+	return errors.Join(
+		ks.BinaryKeyspace.Close(),
+		ks.secondary.Close(),
+	)
+}
+
+type dualWriteSetStore struct {
+	Primary   set.BinaryStore
+	Secondary set.BinaryStore
+}
+
+func (s *dualWriteSetStore) Provision(ctx context.Context) error {
+	if err := s.Primary.Provision(ctx); err != nil {
+		return err
+	}
+	return s.Secondary.Provision(ctx)
+}
+
+func (s *dualWriteSetStore) Capabilities() set.Capabilities {
+	p := set.CapabilitiesOf(s.Primary)
+	q := set.CapabilitiesOf(s.Secondary)
+
+	return set.Capabilities{
+		MaxValueSize:    minLimit(p.MaxValueSize, q.MaxValueSize),
+		OrderedRange:    p.OrderedRange,
+		MeteredRequests: p.MeteredRequests || q.MeteredRequests,
+	}
+}
+
+func (s *dualWriteSetStore) Open(ctx context.Context, name string) (set.BinarySet, error) {
+	p, err := s.Primary.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := s.Secondary.Open(ctx, name)
+	if err != nil {
+		p.Close()
+		return nil, err
+	}
+
+	return &dualWriteSet{p, q}, nil
+}
+
+type dualWriteSet struct {
+	set.BinarySet
+	secondary set.BinarySet
+}
+
+func (s *dualWriteSet) Add(ctx context.Context, v []byte) error {
+	if err := s.BinarySet.Add(ctx, v); err != nil {
+		return err
+	}
+	return s.mirror(ctx, v, true)
+}
+
+func (s *dualWriteSet) TryAdd(ctx context.Context, v []byte) (bool, error) {
+	ok, err := s.BinarySet.TryAdd(ctx, v)
+	if err != nil {
+		return false, err
+	}
+	return ok, s.mirror(ctx, v, true)
+}
+
+func (s *dualWriteSet) Remove(ctx context.Context, v []byte) error {
+	if err := s.BinarySet.Remove(ctx, v); err != nil {
+		return err
+	}
+	return s.mirror(ctx, v, false)
+}
+
+func (s *dualWriteSet) TryRemove(ctx context.Context, v []byte) (bool, error) {
+	ok, err := s.BinarySet.TryRemove(ctx, v)
+	if err != nil {
+		return false, err
+	}
+	return ok, s.mirror(ctx, v, false)
+}
+
+// mirror sets v's membership of the secondary set to match its membership of
+// the primary set, which is expected to be the given value.
+//
+// If v's membership of the primary set has changed by the time the secondary
+// set has been updated, the process is repeated, so that concurrent changes
+// are mirrored in the order they were applied to the primary set.
+func (s *dualWriteSet) mirror(ctx context.Context, v []byte, member bool) error {
+	for {
+		var err error
+		if member {
+			err = s.secondary.Add(ctx, v)
+		} else {
+			err = s.secondary.Remove(ctx, v)
+		}
+		if err != nil {
+			return fmt.Errorf("set was modified in the primary, but not the secondary: %w", err)
+		}
+
+		ok, err := s.BinarySet.Has(ctx, v)
+		if err != nil {
+			return fmt.Errorf("set was modified in the primary, but not the secondary: %w", err)
+		}
+
+		if ok == member {
+			return nil
+		}
+
+		member = ok
+	}
+}
+
+func (s *dualWriteSet) HasBatch(ctx context.Context, vs [][]byte) ([]bool, error) {
+	return set.HasBatch(ctx, s.BinarySet, vs)
+}
+
+func (s *dualWriteSet) Stats(ctx context.Context) (set.Stats, error) {
+	return set.StatsOf(ctx, s.BinarySet)
+}
+
+func (s *dualWriteSet) Close() error {
+	return errors.Join(
+		s.BinarySet.Close(),
+		s.secondary.Close(),
+	)
+}
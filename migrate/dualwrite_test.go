@@ -0,0 +1,117 @@
+package migrate_test
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/driver/memory"
+	"github.com/dogmatiq/persistencekit/health"
+	"github.com/dogmatiq/persistencekit/internal/drivertest"
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+	"github.com/dogmatiq/persistencekit/journal"
+	"github.com/dogmatiq/persistencekit/kv"
+	. "github.com/dogmatiq/persistencekit/migrate"
+	"github.com/dogmatiq/persistencekit/set"
+)
+
+func TestWithDualWrite(t *testing.T) {
+	setup := func(t *testing.T) (primary, secondary *memory.Driver, d driver.Driver) {
+		primary = memory.New(&memory.Config{Silo: xtesting.UniqueName("dual-write-primary")})
+		secondary = memory.New(&memory.Config{Silo: xtesting.UniqueName("dual-write-secondary")})
+
+		d = WithDualWrite(primary, secondary)
+		t.Cleanup(func() {
+			d.Close()
+		})
+
+		return primary, secondary, d
+	}
+
+	t.Run("it satisfies the journal store conformance tests", func(t *testing.T) {
+		_, _, d := setup(t)
+		journal.RunTests(t, d.JournalStore())
+	})
+
+	t.Run("it satisfies the key/value store conformance tests", func(t *testing.T) {
+		_, _, d := setup(t)
+		kv.RunTests(t, d.KVStore())
+	})
+
+	t.Run("it satisfies the set store conformance tests", func(t *testing.T) {
+		_, _, d := setup(t)
+		set.RunTests(t, d.SetStore())
+	})
+
+	t.Run("it writes to the primary driver", func(t *testing.T) {
+		primary, _, d := setup(t)
+
+		drivertest.RunTests(
+			t,
+			d,
+			primary.JournalStore(),
+			primary.KVStore(),
+			primary.SetStore(),
+			primary.LeaseStore(),
+			primary.QueueStore(),
+			primary.ScheduleStore(),
+			primary.BlobStore(),
+		)
+	})
+
+	t.Run("it mirrors journal, key/value and set changes to the secondary driver", func(t *testing.T) {
+		_, secondary, d := setup(t)
+
+		t.Run("JournalStore", func(t *testing.T) {
+			drivertest.RunJournalStoreTests(t, d.JournalStore(), secondary.JournalStore())
+		})
+
+		t.Run("KVStore", func(t *testing.T) {
+			drivertest.RunKVStoreTests(t, d.KVStore(), secondary.KVStore())
+		})
+
+		t.Run("SetStore", func(t *testing.T) {
+			drivertest.RunSetStoreTests(t, d.SetStore(), secondary.SetStore())
+		})
+	})
+
+	t.Run("it copies journal records that are missing from the secondary driver", func(t *testing.T) {
+		primary, secondary, d := setup(t)
+		ctx := t.Context()
+
+		j := openJournal(t, primary, "<journal>")
+		for pos := range journal.Position(3) {
+			if err := j.Append(ctx, pos, []byte("<record>")); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := openJournal(t, d, "<journal>").Append(ctx, 3, []byte("<record>")); err != nil {
+			t.Fatal(err)
+		}
+
+		bounds, err := openJournal(t, secondary, "<journal>").Bounds(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := (journal.Interval{Begin: 0, End: 4}); bounds != want {
+			t.Fatalf("unexpected bounds: got %s, want %s", bounds, want)
+		}
+	})
+
+	t.Run("it does not report a change feed", func(t *testing.T) {
+		_, _, d := setup(t)
+
+		if d.Capabilities().KV.ChangeFeed {
+			t.Fatal("expected the change feed capability to be cleared")
+		}
+	})
+
+	t.Run("it checks the health of both drivers", func(t *testing.T) {
+		_, _, d := setup(t)
+
+		if err := health.Check(t.Context(), d).Err(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
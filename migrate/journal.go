@@ -0,0 +1,177 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/dogmatiq/persistencekit/journal"
+)
+
+// verifyBatchSize is the maximum number of journal records that are hashed
+// before they are compared with the target.
+const verifyBatchSize = 1000
+
+// withJournals opens the journal with the given name in both the source and
+// target, and calls fn with each of them.
+func withJournals[T any](
+	ctx context.Context,
+	m *Migrator,
+	name string,
+	fn func(ctx context.Context, src, dst journal.BinaryJournal) (T, error),
+) (T, error) {
+	var zero T
+
+	src, err := m.Source.JournalStore().Open(ctx, name)
+	if err != nil {
+		return zero, err
+	}
+	defer src.Close()
+
+	dst, err := m.Target.JournalStore().Open(ctx, name)
+	if err != nil {
+		return zero, err
+	}
+	defer dst.Close()
+
+	return fn(ctx, src, dst)
+}
+
+// syncJournal appends the records in src that are not yet in dst, such that
+// each record is stored at the same position in both journals. It returns the
+// number of records appended.
+//
+// If src has been truncated beyond the end of dst, dst is padded with empty
+// records in order to preserve the positions of the records that follow. Any
+// records in dst that precede the beginning of src are truncated.
+func syncJournal(ctx context.Context, src, dst journal.BinaryJournal) (int, error) {
+	sb, err := src.Bounds(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	db, err := dst.Bounds(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if db.End > sb.End {
+		return 0, fmt.Errorf(
+			"target journal ends at position %d, beyond the end of the source journal at position %d",
+			db.End,
+			sb.End,
+		)
+	}
+
+	for pos := db.End; pos < sb.Begin; pos++ {
+		if err := appendRecord(ctx, dst, pos, nil); err != nil {
+			return 0, err
+		}
+	}
+
+	n := 0
+
+	if pos := max(db.End, sb.Begin); pos < sb.End {
+		if err := src.Range(
+			ctx,
+			pos,
+			func(ctx context.Context, pos journal.Position, rec []byte) (bool, error) {
+				if err := appendRecord(ctx, dst, pos, rec); err != nil {
+					return false, err
+				}
+				n++
+				return true, nil
+			},
+		); err != nil {
+			return n, err
+		}
+	}
+
+	if db.Begin < sb.Begin {
+		if err := dst.Truncate(ctx, sb.Begin); err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// appendRecord appends rec to j at the given position.
+//
+// A conflict is not treated as an error, as it indicates that the record has
+// already been appended, for example by [WithDualWrite]. Any difference in the
+// content of the record is detected during verification.
+func appendRecord(ctx context.Context, j journal.BinaryJournal, pos journal.Position, rec []byte) error {
+	if err := j.Append(ctx, pos, rec); !journal.IsConflict(err) {
+		return err
+	}
+	return nil
+}
+
+// verifyJournal compares the records in dst with those in src. It returns a
+// description of each difference.
+func verifyJournal(ctx context.Context, src, dst journal.BinaryJournal) ([]string, error) {
+	sb, err := src.Bounds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := dst.Bounds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reasons []string
+
+	if db != sb {
+		reasons = append(
+			reasons,
+			fmt.Sprintf("target bounds %s differ from source bounds %s", db, sb),
+		)
+	}
+
+	begin := max(sb.Begin, db.Begin)
+	end := min(sb.End, db.End)
+
+	for begin < end {
+		batchEnd := min(begin+verifyBatchSize, end)
+
+		var hashes [][sha256.Size]byte
+		if err := src.Range(
+			ctx,
+			begin,
+			func(_ context.Context, pos journal.Position, rec []byte) (bool, error) {
+				hashes = append(hashes, sha256.Sum256(rec))
+				return pos+1 < batchEnd, nil
+			},
+		); err != nil {
+			return nil, err
+		}
+
+		var reason string
+		if err := dst.Range(
+			ctx,
+			begin,
+			func(_ context.Context, pos journal.Position, rec []byte) (bool, error) {
+				i := int(pos - begin)
+				if i >= len(hashes) || sha256.Sum256(rec) != hashes[i] {
+					reason = fmt.Sprintf("record at position %d differs", pos)
+					return false, nil
+				}
+				return pos+1 < batchEnd, nil
+			},
+		); err != nil {
+			return nil, err
+		}
+
+		if reason != "" {
+			// Once a single record differs, the remainder of the journal is of
+			// little diagnostic value.
+			return append(reasons, reason), nil
+		}
+
+		begin = batchEnd
+	}
+
+	return reasons, nil
+}
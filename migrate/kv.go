@@ -0,0 +1,205 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/dogmatiq/persistencekit/kv"
+)
+
+// withKeyspaces opens the keyspace with the given name in both the source and
+// target, and calls fn with each of them.
+func withKeyspaces[T any](
+	ctx context.Context,
+	m *Migrator,
+	name string,
+	fn func(ctx context.Context, src, dst kv.BinaryKeyspace) (T, error),
+) (T, error) {
+	var zero T
+
+	src, err := m.Source.KVStore().Open(ctx, name)
+	if err != nil {
+		return zero, err
+	}
+	defer src.Close()
+
+	dst, err := m.Target.KVStore().Open(ctx, name)
+	if err != nil {
+		return zero, err
+	}
+	defer dst.Close()
+
+	return fn(ctx, src, dst)
+}
+
+// copyKeyspace writes each key/value pair in src to dst. It returns the number
+// of pairs written.
+func copyKeyspace(ctx context.Context, src, dst kv.BinaryKeyspace) (int, error) {
+	n := 0
+	err := src.Range(
+		ctx,
+		func(ctx context.Context, k, v []byte, _ kv.Revision) (bool, error) {
+			if err := dst.SetUnconditional(ctx, k, v); err != nil {
+				return false, err
+			}
+			n++
+			return true, nil
+		},
+	)
+	return n, err
+}
+
+// syncKeyspace updates each key in dst that has a different value in src, and
+// deletes each key in dst that is not present in src. It returns the number of
+// keys that were changed.
+func syncKeyspace(ctx context.Context, src, dst kv.BinaryKeyspace) (int, error) {
+	n := 0
+
+	if err := src.Range(
+		ctx,
+		func(ctx context.Context, k, v []byte, _ kv.Revision) (bool, error) {
+			dv, _, err := dst.Get(ctx, k)
+			if err != nil || bytes.Equal(v, dv) {
+				return err == nil, err
+			}
+
+			changed, err := reconcileKey(ctx, src, dst, k)
+			if changed {
+				n++
+			}
+			return err == nil, err
+		},
+	); err != nil {
+		return n, err
+	}
+
+	// Collect the keys before deleting them, as not all keyspaces can be
+	// modified while they are being ranged over.
+	var extra [][]byte
+	if err := kv.RangeKeys(
+		ctx,
+		dst,
+		func(ctx context.Context, k []byte, _ kv.Revision) (bool, error) {
+			ok, err := src.Has(ctx, k)
+			if err == nil && !ok {
+				extra = append(extra, k)
+			}
+			return err == nil, err
+		},
+	); err != nil {
+		return n, err
+	}
+
+	for _, k := range extra {
+		changed, err := reconcileKey(ctx, src, dst, k)
+		if err != nil {
+			return n, err
+		}
+		if changed {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// reconcileKey sets the value of k in dst to its current value in src. It
+// returns true if the value in dst was changed.
+//
+// The value is set conditionally on the revision of k in dst, so that a
+// concurrent change made by [WithDualWrite] is not overwritten with an older
+// value.
+func reconcileKey(ctx context.Context, src, dst kv.BinaryKeyspace, k []byte) (bool, error) {
+	for {
+		v, _, err := src.Get(ctx, k)
+		if err != nil {
+			return false, err
+		}
+
+		dv, dr, err := dst.Get(ctx, k)
+		if err != nil {
+			return false, err
+		}
+
+		if bytes.Equal(v, dv) {
+			return false, nil
+		}
+
+		if _, err := dst.Set(ctx, k, v, dr); !kv.IsConflict(err) {
+			return err == nil, err
+		}
+	}
+}
+
+// verifyKeyspace compares the key/value pairs in dst with those in src. It
+// returns a description of each difference.
+func verifyKeyspace(ctx context.Context, src, dst kv.BinaryKeyspace) ([]string, error) {
+	var reasons []string
+
+	if err := src.Range(
+		ctx,
+		func(ctx context.Context, k, v []byte, r kv.Revision) (bool, error) {
+			ok, err := verifyKey(ctx, src, dst, k, v, r)
+			if err == nil && !ok {
+				reasons = append(reasons, fmt.Sprintf("value of key %q differs", k))
+			}
+			return err == nil, err
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	if err := kv.RangeKeys(
+		ctx,
+		dst,
+		func(ctx context.Context, k []byte, _ kv.Revision) (bool, error) {
+			ok, err := src.Has(ctx, k)
+			if err != nil || ok {
+				// Keys present in the source have already been verified.
+				return err == nil, err
+			}
+
+			ok, err = verifyKey(ctx, src, dst, k, nil, "")
+			if err == nil && !ok {
+				reasons = append(reasons, fmt.Sprintf("key %q is present in the target but not the source", k))
+			}
+			return err == nil, err
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	return reasons, nil
+}
+
+// verifyKey returns true if the value of k in dst matches v, the value of k
+// in src at revision r.
+//
+// If the values differ, the revision of k in src is checked again. If it has
+// changed, the value in dst is compared with the new value in src instead, so
+// that changes made during verification are not reported as differences.
+func verifyKey(ctx context.Context, src, dst kv.BinaryKeyspace, k, v []byte, r kv.Revision) (bool, error) {
+	for {
+		dv, _, err := dst.Get(ctx, k)
+		if err != nil {
+			return false, err
+		}
+
+		if sha256.Sum256(v) == sha256.Sum256(dv) {
+			return true, nil
+		}
+
+		cv, cr, err := src.Get(ctx, k)
+		if err != nil {
+			return false, err
+		}
+
+		if cr == r {
+			return false, nil
+		}
+
+		v, r = cv, cr
+	}
+}
@@ -0,0 +1,372 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dogmatiq/enginekit/telemetry"
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/kv"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Kind identifies the type of a store that is migrated.
+type Kind string
+
+// The kinds of store that are migrated.
+const (
+	Journal  Kind = "journal"
+	Keyspace Kind = "keyspace"
+	Set      Kind = "set"
+)
+
+// Plan describes the stores to migrate.
+//
+// Drivers do not provide a way to enumerate the journals, keyspaces and sets
+// that they contain, so the names of the stores must be provided explicitly.
+type Plan struct {
+	// Journals is the names of the journals to migrate.
+	Journals []string
+
+	// Keyspaces is the names of the keyspaces to migrate.
+	Keyspaces []string
+
+	// Sets is the names of the sets to migrate.
+	Sets []string
+}
+
+// Migrator copies the stores described by a [Plan] from one driver to
+// another.
+//
+// The target's stores must already be provisioned.
+//
+// Each record is copied to the same position in the target journal as it
+// occupies in the source. Drivers do not provide a way to create a journal
+// that begins at a specific position, so if the source journal has been
+// truncated, the target is first padded with an empty record for each
+// truncated position, one append at a time, and then truncated to match the
+// source. The cost of copying a journal is therefore proportional to its end
+// position, rather than to the number of records it contains.
+type Migrator struct {
+	// Source is the driver to copy from.
+	Source driver.Driver
+
+	// Target is the driver to copy to.
+	Target driver.Driver
+
+	// Plan describes the stores to migrate.
+	Plan Plan
+
+	// Checkpoints is an optional keyspace used to record which stores have
+	// been copied, so that an interrupted migration can be resumed without
+	// copying them again. It must not be one of the keyspaces being migrated.
+	//
+	// Journals are always resumed from the end of the target journal, even if
+	// Checkpoints is nil. Keyspaces and sets that were partially copied when
+	// the migration was interrupted are copied again from the beginning.
+	Checkpoints kv.BinaryKeyspace
+
+	// TracerProvider, MeterProvider and LoggerProvider are used to report the
+	// progress of the migration. Any of them may be nil.
+	TracerProvider trace.TracerProvider
+	MeterProvider  metric.MeterProvider
+	LoggerProvider log.LoggerProvider
+}
+
+// Run performs a complete migration.
+//
+// It copies each store, catches up with changes made to the source until a
+// pass makes no further changes, then verifies the target against the source.
+//
+// Catching up only converges if the source stops changing, or if changes are
+// applied to both drivers using [WithDualWrite].
+func (m *Migrator) Run(ctx context.Context) (Report, error) {
+	if err := m.Copy(ctx); err != nil {
+		return Report{}, err
+	}
+
+	for {
+		n, err := m.CatchUp(ctx)
+		if err != nil {
+			return Report{}, err
+		}
+		if n == 0 {
+			break
+		}
+	}
+
+	return m.Verify(ctx)
+}
+
+// Copy copies each store that has not already been copied from the source to
+// the target.
+func (m *Migrator) Copy(ctx context.Context) error {
+	r := m.recorder()
+
+	return m.each(
+		ctx,
+		func(ctx context.Context, k Kind, name string) error {
+			done, err := m.isCopied(ctx, k, name)
+			if err != nil || done {
+				return err
+			}
+
+			ctx, span := r.rec.StartSpan(
+				ctx,
+				"migration.copy",
+				telemetry.String("kind", k),
+				telemetry.String("name", name),
+			)
+			defer span.End()
+
+			n, err := m.copy(ctx, k, name)
+			r.changes(ctx, k, n)
+			if err != nil {
+				r.rec.Error(ctx, "migration.copy.error", "unable to copy store", err)
+				return fmt.Errorf("unable to copy %s %q: %w", k, name, err)
+			}
+
+			span.SetAttributes(telemetry.Int("changes", n))
+
+			if err := m.markCopied(ctx, k, name); err != nil {
+				return err
+			}
+
+			r.stores(ctx, 1, telemetry.String("phase", "copy"))
+			r.rec.Info(ctx, "migration.copy.ok", "store copied")
+
+			return nil
+		},
+	)
+}
+
+// CatchUp applies the changes made to the source since it was copied to the
+// target. It returns the number of changes applied.
+//
+// Journals are tailed from the end of the target journal. Keyspaces and sets
+// are reconciled by comparing each key or member with the target.
+func (m *Migrator) CatchUp(ctx context.Context) (int, error) {
+	r := m.recorder()
+	total := 0
+
+	err := m.each(
+		ctx,
+		func(ctx context.Context, k Kind, name string) error {
+			ctx, span := r.rec.StartSpan(
+				ctx,
+				"migration.catch_up",
+				telemetry.String("kind", k),
+				telemetry.String("name", name),
+			)
+			defer span.End()
+
+			n, err := m.catchUp(ctx, k, name)
+			total += n
+			r.changes(ctx, k, n)
+			if err != nil {
+				r.rec.Error(ctx, "migration.catch_up.error", "unable to catch up with changes to store", err)
+				return fmt.Errorf("unable to catch up %s %q: %w", k, name, err)
+			}
+
+			span.SetAttributes(telemetry.Int("changes", n))
+			r.stores(ctx, 1, telemetry.String("phase", "catch_up"))
+			r.rec.Info(ctx, "migration.catch_up.ok", "caught up with changes to store")
+
+			return nil
+		},
+	)
+
+	return total, err
+}
+
+// Verify compares each store in the target with the source.
+//
+// Journal records are compared by their SHA-256 hashes. Each key/value pair is
+// compared with the value associated with the key in the target, and
+// re-compared if the key's revision changes in the source during
+// verification.
+func (m *Migrator) Verify(ctx context.Context) (Report, error) {
+	r := m.recorder()
+	var report Report
+
+	err := m.each(
+		ctx,
+		func(ctx context.Context, k Kind, name string) error {
+			ctx, span := r.rec.StartSpan(
+				ctx,
+				"migration.verify",
+				telemetry.String("kind", k),
+				telemetry.String("name", name),
+			)
+			defer span.End()
+
+			reasons, err := m.verify(ctx, k, name)
+			if err != nil {
+				r.rec.Error(ctx, "migration.verify.error", "unable to verify store", err)
+				return fmt.Errorf("unable to verify %s %q: %w", k, name, err)
+			}
+
+			for _, reason := range reasons {
+				report.Mismatches = append(report.Mismatches, Mismatch{k, name, reason})
+			}
+			report.Verified++
+
+			span.SetAttributes(telemetry.Int("mismatches", len(reasons)))
+			r.stores(ctx, 1, telemetry.String("phase", "verify"))
+
+			if len(reasons) == 0 {
+				r.rec.Info(ctx, "migration.verify.ok", "store verified")
+			} else {
+				r.mismatches(ctx, int64(len(reasons)), telemetry.String("kind", k))
+				r.rec.Info(
+					ctx,
+					"migration.verify.mismatch",
+					"store differs between source and target",
+					telemetry.Int("mismatches", len(reasons)),
+				)
+			}
+
+			return nil
+		},
+	)
+
+	return report, err
+}
+
+// each calls fn for each store in the plan.
+func (m *Migrator) each(
+	ctx context.Context,
+	fn func(context.Context, Kind, string) error,
+) error {
+	for _, group := range []struct {
+		Kind  Kind
+		Names []string
+	}{
+		{Journal, m.Plan.Journals},
+		{Keyspace, m.Plan.Keyspaces},
+		{Set, m.Plan.Sets},
+	} {
+		for _, name := range group.Names {
+			if err := fn(ctx, group.Kind, name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) copy(ctx context.Context, k Kind, name string) (int, error) {
+	switch k {
+	case Journal:
+		return withJournals(ctx, m, name, syncJournal)
+	case Keyspace:
+		return withKeyspaces(ctx, m, name, copyKeyspace)
+	default:
+		return withSets(ctx, m, name, copySet)
+	}
+}
+
+func (m *Migrator) catchUp(ctx context.Context, k Kind, name string) (int, error) {
+	switch k {
+	case Journal:
+		return withJournals(ctx, m, name, syncJournal)
+	case Keyspace:
+		return withKeyspaces(ctx, m, name, syncKeyspace)
+	default:
+		return withSets(ctx, m, name, syncSet)
+	}
+}
+
+func (m *Migrator) verify(ctx context.Context, k Kind, name string) ([]string, error) {
+	switch k {
+	case Journal:
+		return withJournals(ctx, m, name, verifyJournal)
+	case Keyspace:
+		return withKeyspaces(ctx, m, name, verifyKeyspace)
+	default:
+		return withSets(ctx, m, name, verifySet)
+	}
+}
+
+// checkpointCopied is the value stored in [Migrator.Checkpoints] for stores
+// that have been copied.
+var checkpointCopied = []byte("copied")
+
+func checkpointKey(k Kind, name string) []byte {
+	return []byte(string(k) + "/" + name)
+}
+
+func (m *Migrator) isCopied(ctx context.Context, k Kind, name string) (bool, error) {
+	if m.Checkpoints == nil {
+		return false, nil
+	}
+
+	ok, err := m.Checkpoints.Has(ctx, checkpointKey(k, name))
+	if err != nil {
+		return false, fmt.Errorf("unable to load checkpoint: %w", err)
+	}
+	return ok, nil
+}
+
+func (m *Migrator) markCopied(ctx context.Context, k Kind, name string) error {
+	if m.Checkpoints == nil {
+		return nil
+	}
+
+	if err := m.Checkpoints.SetUnconditional(ctx, checkpointKey(k, name), checkpointCopied); err != nil {
+		return fmt.Errorf("unable to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// recorder bundles the telemetry instruments used to report the progress of
+// a migration.
+type recorder struct {
+	rec        *telemetry.Recorder
+	records    telemetry.Instrument[int64]
+	keys       telemetry.Instrument[int64]
+	members    telemetry.Instrument[int64]
+	stores     telemetry.Instrument[int64]
+	mismatches telemetry.Instrument[int64]
+}
+
+func (m *Migrator) recorder() *recorder {
+	p := &telemetry.Provider{
+		TracerProvider: m.TracerProvider,
+		MeterProvider:  m.MeterProvider,
+		LoggerProvider: m.LoggerProvider,
+	}
+
+	rec := p.Recorder(
+		"github.com/dogmatiq/persistencekit/migrate",
+		telemetry.Type("migration.source", m.Source),
+		telemetry.Type("migration.target", m.Target),
+	)
+
+	return &recorder{
+		rec:        rec,
+		records:    rec.Counter("persistence.migration.records", "{record}", "The number of journal records copied to the target."),
+		keys:       rec.Counter("persistence.migration.keys", "{key}", "The number of key/value pairs written to the target."),
+		members:    rec.Counter("persistence.migration.members", "{member}", "The number of set members written to the target."),
+		stores:     rec.Counter("persistence.migration.stores", "{store}", "The number of stores that have completed each phase of the migration."),
+		mismatches: rec.Counter("persistence.migration.mismatches", "{mismatch}", "The number of differences between the source and target found during verification."),
+	}
+}
+
+// changes records n changes made to a store of kind k.
+func (r *recorder) changes(ctx context.Context, k Kind, n int) {
+	if n == 0 {
+		return
+	}
+
+	switch k {
+	case Journal:
+		r.records(ctx, int64(n))
+	case Keyspace:
+		r.keys(ctx, int64(n))
+	default:
+		r.members(ctx, int64(n))
+	}
+}
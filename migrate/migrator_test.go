@@ -0,0 +1,355 @@
+package migrate_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/dogmatiq/enginekit/telemetry"
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/driver/memory"
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+	"github.com/dogmatiq/persistencekit/journal"
+	"github.com/dogmatiq/persistencekit/kv"
+	. "github.com/dogmatiq/persistencekit/migrate"
+	"github.com/dogmatiq/persistencekit/set"
+)
+
+func TestMigrator(t *testing.T) {
+	setup := func(t *testing.T) (*Migrator, driver.Driver, driver.Driver) {
+		source := memory.New(&memory.Config{Silo: xtesting.UniqueName("migrate-source")})
+		target := memory.New(&memory.Config{Silo: xtesting.UniqueName("migrate-target")})
+		t.Cleanup(func() {
+			source.Close()
+			target.Close()
+		})
+
+		ctx := t.Context()
+
+		j := openJournal(t, source, "<journal>")
+		for pos := range journal.Position(5) {
+			if err := j.Append(ctx, pos, fmt.Appendf(nil, "<record-%d>", pos)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := j.Truncate(ctx, 2); err != nil {
+			t.Fatal(err)
+		}
+
+		ks := openKeyspace(t, source, "<keyspace>")
+		for i := range 5 {
+			if err := ks.SetUnconditional(ctx, fmt.Appendf(nil, "<key-%d>", i), fmt.Appendf(nil, "<value-%d>", i)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		s := openSet(t, source, "<set>")
+		for i := range 5 {
+			if err := s.Add(ctx, fmt.Appendf(nil, "<member-%d>", i)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		provider := telemetry.NewTestProvider(t)
+
+		m := &Migrator{
+			Source: source,
+			Target: target,
+			Plan: Plan{
+				Journals:  []string{"<journal>"},
+				Keyspaces: []string{"<keyspace>"},
+				Sets:      []string{"<set>"},
+			},
+			TracerProvider: provider.TracerProvider,
+			MeterProvider:  provider.MeterProvider,
+			LoggerProvider: provider.LoggerProvider,
+		}
+
+		return m, source, target
+	}
+
+	t.Run("Run", func(t *testing.T) {
+		t.Run("it copies each store to the target", func(t *testing.T) {
+			m, _, target := setup(t)
+			ctx := t.Context()
+
+			report, err := m.Run(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := report.Err(); err != nil {
+				t.Fatal(err)
+			}
+
+			if report.Verified != 3 {
+				t.Fatalf("unexpected number of verified stores: got %d, want 3", report.Verified)
+			}
+
+			j := openJournal(t, target, "<journal>")
+			bounds, err := j.Bounds(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if want := (journal.Interval{Begin: 2, End: 5}); bounds != want {
+				t.Fatalf("unexpected journal bounds: got %s, want %s", bounds, want)
+			}
+
+			rec, err := j.Get(ctx, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(rec, []byte("<record-3>")) {
+				t.Fatalf("unexpected record: got %q, want %q", rec, "<record-3>")
+			}
+
+			v, _, err := openKeyspace(t, target, "<keyspace>").Get(ctx, []byte("<key-3>"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(v, []byte("<value-3>")) {
+				t.Fatalf("unexpected value: got %q, want %q", v, "<value-3>")
+			}
+
+			ok, err := openSet(t, target, "<set>").Has(ctx, []byte("<member-3>"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !ok {
+				t.Fatal("expected member to be copied")
+			}
+		})
+	})
+
+	t.Run("Copy", func(t *testing.T) {
+		t.Run("it does not copy stores that have already been copied", func(t *testing.T) {
+			m, _, target := setup(t)
+			ctx := t.Context()
+
+			m.Checkpoints = openKeyspace(t, target, "<checkpoints>")
+
+			if err := m.Copy(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			ks := openKeyspace(t, target, "<keyspace>")
+			if err := ks.SetUnconditional(ctx, []byte("<key-0>"), nil); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := m.Copy(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			ok, err := ks.Has(ctx, []byte("<key-0>"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if ok {
+				t.Fatal("expected the keyspace not to be copied again")
+			}
+		})
+
+		t.Run("it resumes copying a journal from the end of the target journal", func(t *testing.T) {
+			m, _, target := setup(t)
+			ctx := t.Context()
+
+			j := openJournal(t, target, "<journal>")
+			for pos := range journal.Position(3) {
+				if err := j.Append(ctx, pos, fmt.Appendf(nil, "<record-%d>", pos)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := m.Copy(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			report, err := m.Verify(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := report.Err(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	})
+
+	t.Run("CatchUp", func(t *testing.T) {
+		t.Run("it applies changes made to the source after it was copied", func(t *testing.T) {
+			m, source, _ := setup(t)
+			ctx := t.Context()
+
+			if err := m.Copy(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := openJournal(t, source, "<journal>").Append(ctx, 5, []byte("<record-5>")); err != nil {
+				t.Fatal(err)
+			}
+
+			ks := openKeyspace(t, source, "<keyspace>")
+			if err := ks.SetUnconditional(ctx, []byte("<key-0>"), []byte("<updated>")); err != nil {
+				t.Fatal(err)
+			}
+			if err := ks.SetUnconditional(ctx, []byte("<key-1>"), nil); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := openSet(t, source, "<set>").Remove(ctx, []byte("<member-0>")); err != nil {
+				t.Fatal(err)
+			}
+
+			n, err := m.CatchUp(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if n != 4 {
+				t.Fatalf("unexpected number of changes: got %d, want 4", n)
+			}
+
+			n, err = m.CatchUp(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if n != 0 {
+				t.Fatalf("unexpected number of changes: got %d, want 0", n)
+			}
+
+			report, err := m.Verify(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := report.Err(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	})
+
+	t.Run("Verify", func(t *testing.T) {
+		t.Run("it reports differences between the source and target", func(t *testing.T) {
+			m, _, target := setup(t)
+			ctx := t.Context()
+
+			if err := m.Copy(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := openJournal(t, target, "<journal>").Append(ctx, 5, []byte("<extra>")); err != nil {
+				t.Fatal(err)
+			}
+
+			ks := openKeyspace(t, target, "<keyspace>")
+			if err := ks.SetUnconditional(ctx, []byte("<key-0>"), []byte("<different>")); err != nil {
+				t.Fatal(err)
+			}
+			if err := ks.SetUnconditional(ctx, []byte("<extra>"), []byte("<value>")); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := openSet(t, target, "<set>").Remove(ctx, []byte("<member-0>")); err != nil {
+				t.Fatal(err)
+			}
+
+			report, err := m.Verify(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if report.Consistent() {
+				t.Fatal("expected differences to be reported")
+			}
+
+			var got []string
+			for _, m := range report.Mismatches {
+				got = append(got, m.Error())
+			}
+
+			want := []string{
+				`journal "<journal>": target bounds [2, 6) differ from source bounds [2, 5)`,
+				`keyspace "<keyspace>": value of key "<key-0>" differs`,
+				`keyspace "<keyspace>": key "<extra>" is present in the target but not the source`,
+				`set "<set>": member "<member-0>" is missing from the target`,
+			}
+
+			if !slices.Equal(got, want) {
+				t.Fatalf("unexpected mismatches:\ngot:  %q\nwant: %q", got, want)
+			}
+		})
+
+		t.Run("it reports the first journal record that differs", func(t *testing.T) {
+			m, _, target := setup(t)
+			ctx := t.Context()
+
+			j := openJournal(t, target, "<journal>")
+			for pos := range journal.Position(5) {
+				if err := j.Append(ctx, pos, fmt.Appendf(nil, "<different-%d>", pos)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := j.Truncate(ctx, 2); err != nil {
+				t.Fatal(err)
+			}
+
+			report, err := m.Verify(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := Mismatch{
+				Kind:   Journal,
+				Name:   "<journal>",
+				Reason: "record at position 2 differs",
+			}
+
+			if !slices.Contains(report.Mismatches, want) {
+				t.Fatalf("expected %q to be reported, got %q", want.Error(), report.Err())
+			}
+		})
+	})
+}
+
+func openJournal(t *testing.T, d driver.Driver, name string) journal.BinaryJournal {
+	t.Helper()
+	return open(t, d.JournalStore().Open, name)
+}
+
+func openKeyspace(t *testing.T, d driver.Driver, name string) kv.BinaryKeyspace {
+	t.Helper()
+	return open(t, d.KVStore().Open, name)
+}
+
+func openSet(t *testing.T, d driver.Driver, name string) set.BinarySet {
+	t.Helper()
+	return open(t, d.SetStore().Open, name)
+}
+
+func open[T interface{ Close() error }](
+	t *testing.T,
+	fn func(context.Context, string) (T, error),
+	name string,
+) T {
+	t.Helper()
+
+	v, err := fn(t.Context(), name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		v.Close()
+	})
+
+	return v
+}
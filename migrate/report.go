@@ -0,0 +1,49 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Mismatch describes a difference between a store in the source and the same
+// store in the target.
+type Mismatch struct {
+	// Kind is the kind of store that differs.
+	Kind Kind
+
+	// Name is the name of the store that differs.
+	Name string
+
+	// Reason describes the difference.
+	Reason string
+}
+
+func (m Mismatch) Error() string {
+	return fmt.Sprintf("%s %q: %s", m.Kind, m.Name, m.Reason)
+}
+
+// Report is the result of verifying the target of a migration against its
+// source.
+type Report struct {
+	// Verified is the number of stores that were verified.
+	Verified int
+
+	// Mismatches is the differences found between the source and target.
+	Mismatches []Mismatch
+}
+
+// Consistent returns true if no differences were found between the source and
+// target.
+func (r Report) Consistent() bool {
+	return len(r.Mismatches) == 0
+}
+
+// Err returns an error describing each difference between the source and
+// target, or nil if there are none.
+func (r Report) Err() error {
+	var errs []error
+	for _, m := range r.Mismatches {
+		errs = append(errs, m)
+	}
+	return errors.Join(errs...)
+}
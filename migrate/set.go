@@ -0,0 +1,163 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dogmatiq/persistencekit/set"
+)
+
+// withSets opens the set with the given name in both the source and target,
+// and calls fn with each of them.
+func withSets[T any](
+	ctx context.Context,
+	m *Migrator,
+	name string,
+	fn func(ctx context.Context, src, dst set.BinarySet) (T, error),
+) (T, error) {
+	var zero T
+
+	src, err := m.Source.SetStore().Open(ctx, name)
+	if err != nil {
+		return zero, err
+	}
+	defer src.Close()
+
+	dst, err := m.Target.SetStore().Open(ctx, name)
+	if err != nil {
+		return zero, err
+	}
+	defer dst.Close()
+
+	return fn(ctx, src, dst)
+}
+
+// copySet adds each member of src to dst. It returns the number of members
+// added.
+func copySet(ctx context.Context, src, dst set.BinarySet) (int, error) {
+	n := 0
+	err := src.Range(
+		ctx,
+		func(ctx context.Context, v []byte) (bool, error) {
+			if err := dst.Add(ctx, v); err != nil {
+				return false, err
+			}
+			n++
+			return true, nil
+		},
+	)
+	return n, err
+}
+
+// syncSet adds each member of src that is missing from dst, and removes each
+// member of dst that is not a member of src. It returns the number of members
+// that were added or removed.
+func syncSet(ctx context.Context, src, dst set.BinarySet) (int, error) {
+	n := 0
+
+	if err := src.Range(
+		ctx,
+		func(ctx context.Context, v []byte) (bool, error) {
+			added, err := dst.TryAdd(ctx, v)
+			if added {
+				n++
+			}
+			return err == nil, err
+		},
+	); err != nil {
+		return n, err
+	}
+
+	// Collect the members before removing them, as not all sets can be
+	// modified while they are being ranged over.
+	var extra [][]byte
+	if err := dst.Range(
+		ctx,
+		func(ctx context.Context, v []byte) (bool, error) {
+			ok, err := src.Has(ctx, v)
+			if err == nil && !ok {
+				extra = append(extra, v)
+			}
+			return err == nil, err
+		},
+	); err != nil {
+		return n, err
+	}
+
+	for _, v := range extra {
+		// The member may have been added to the source since it was checked.
+		ok, err := src.Has(ctx, v)
+		if err != nil {
+			return n, err
+		}
+		if ok {
+			continue
+		}
+
+		removed, err := dst.TryRemove(ctx, v)
+		if err != nil {
+			return n, err
+		}
+		if removed {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// verifySet compares the members of dst with those of src. It returns a
+// description of each difference.
+func verifySet(ctx context.Context, src, dst set.BinarySet) ([]string, error) {
+	var reasons []string
+
+	if err := src.Range(
+		ctx,
+		func(ctx context.Context, v []byte) (bool, error) {
+			ok, err := verifyMember(ctx, src, dst, v)
+			if err == nil && !ok {
+				reasons = append(reasons, fmt.Sprintf("member %q is missing from the target", v))
+			}
+			return err == nil, err
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	if err := dst.Range(
+		ctx,
+		func(ctx context.Context, v []byte) (bool, error) {
+			ok, err := src.Has(ctx, v)
+			if err != nil || ok {
+				// Members of the source have already been verified.
+				return err == nil, err
+			}
+
+			ok, err = verifyMember(ctx, src, dst, v)
+			if err == nil && !ok {
+				reasons = append(reasons, fmt.Sprintf("member %q is present in the target but not the source", v))
+			}
+			return err == nil, err
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	return reasons, nil
+}
+
+// verifyMember returns true if v's membership of dst matches its membership
+// of src.
+func verifyMember(ctx context.Context, src, dst set.BinarySet, v []byte) (bool, error) {
+	want, err := src.Has(ctx, v)
+	if err != nil {
+		return false, err
+	}
+
+	got, err := dst.Has(ctx, v)
+	if err != nil {
+		return false, err
+	}
+
+	return want == got, nil
+}
@@ -0,0 +1,116 @@
+package persistencekittest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Capability is a set of driver features that are exercised by the
+// conformance test suite.
+type Capability uint
+
+const (
+	// JournalStore is the capability to provide a [journal.BinaryStore] via
+	// [driver.Driver.JournalStore].
+	JournalStore Capability = 1 << iota
+
+	// KVStore is the capability to provide a [kv.BinaryStore] via
+	// [driver.Driver.KVStore].
+	KVStore
+
+	// SetStore is the capability to provide a [set.BinaryStore] via
+	// [driver.Driver.SetStore].
+	SetStore
+
+	// LeaseStore is the capability to provide a [lease.Store] via
+	// [driver.Driver.LeaseStore].
+	LeaseStore
+
+	// QueueStore is the capability to provide a [queue.BinaryStore] via
+	// [driver.Driver.QueueStore].
+	QueueStore
+
+	// ScheduleStore is the capability to provide a [schedule.BinaryStore] via
+	// [driver.Driver.ScheduleStore].
+	ScheduleStore
+
+	// BlobStore is the capability to provide a [blob.Store] via
+	// [driver.Driver.BlobStore].
+	BlobStore
+
+	// KVChangeFeed is the capability of the driver's [kv.BinaryStore] to
+	// also implement [kv.BinaryChangeFeedStore] natively.
+	//
+	// The change feed tests are skipped if the driver does not report this
+	// capability via [driver.Driver.Capabilities].
+	KVChangeFeed
+
+	// AllCapabilities is the set of all capabilities.
+	AllCapabilities = JournalStore |
+		KVStore |
+		SetStore |
+		LeaseStore |
+		QueueStore |
+		ScheduleStore |
+		BlobStore |
+		KVChangeFeed
+)
+
+// Has returns true if c includes all of the capabilities in x.
+func (c Capability) Has(x Capability) bool {
+	return c&x == x
+}
+
+// String returns a human-readable representation of the capabilities in c.
+func (c Capability) String() string {
+	if c == 0 {
+		return "<none>"
+	}
+
+	var names []string
+	for _, x := range capabilities {
+		if c.Has(x.Capability) {
+			names = append(names, x.Name)
+			c &^= x.Capability
+		}
+	}
+
+	if c != 0 {
+		names = append(names, fmt.Sprintf("%#x", uint(c)))
+	}
+
+	return strings.Join(names, "|")
+}
+
+// capabilities is the list of individual capabilities, in order, with their
+// names.
+var capabilities = []struct {
+	Capability Capability
+	Name       string
+}{
+	{JournalStore, "JournalStore"},
+	{KVStore, "KVStore"},
+	{SetStore, "SetStore"},
+	{LeaseStore, "LeaseStore"},
+	{QueueStore, "QueueStore"},
+	{ScheduleStore, "ScheduleStore"},
+	{BlobStore, "BlobStore"},
+	{KVChangeFeed, "KVChangeFeed"},
+}
+
+// Option is a functional option that changes the behavior of [Run].
+type Option func(*options)
+
+type options struct {
+	Capabilities Capability
+}
+
+// Without is an [Option] that excludes the tests and benchmarks that exercise
+// the given capabilities.
+//
+// Use it to opt out of features that the driver under test does not support.
+func Without(c Capability) Option {
+	return func(opts *options) {
+		opts.Capabilities &^= c
+	}
+}
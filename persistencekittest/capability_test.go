@@ -0,0 +1,43 @@
+package persistencekittest_test
+
+import (
+	"testing"
+
+	. "github.com/dogmatiq/persistencekit/persistencekittest"
+)
+
+func TestCapability(t *testing.T) {
+	t.Run("func Has()", func(t *testing.T) {
+		c := JournalStore | KVStore
+
+		if !c.Has(JournalStore) {
+			t.Fatal("expected c to have JournalStore")
+		}
+
+		if !c.Has(JournalStore | KVStore) {
+			t.Fatal("expected c to have JournalStore|KVStore")
+		}
+
+		if c.Has(JournalStore | SetStore) {
+			t.Fatal("did not expect c to have JournalStore|SetStore")
+		}
+	})
+
+	t.Run("func String()", func(t *testing.T) {
+		cases := []struct {
+			Capability Capability
+			Want       string
+		}{
+			{0, "<none>"},
+			{KVStore, "KVStore"},
+			{JournalStore | BlobStore, "JournalStore|BlobStore"},
+			{KVChangeFeed | 1<<31, "KVChangeFeed|0x80000000"},
+		}
+
+		for _, c := range cases {
+			if got := c.Capability.String(); got != c.Want {
+				t.Errorf("unexpected string: got %q, want %q", got, c.Want)
+			}
+		}
+	})
+}
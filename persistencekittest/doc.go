@@ -0,0 +1,20 @@
+// Package persistencekittest provides a conformance test suite for persistence
+// drivers, including drivers maintained outside of this module.
+//
+// The suite is run by calling [Run] from a test function with a [driver.Config]
+// that describes the driver under test:
+//
+//	func TestDriver(t *testing.T) {
+//		persistencekittest.Run(t, &mydriver.Config{...})
+//	}
+//
+// The same entry point runs the benchmarks when called from a benchmark
+// function:
+//
+//	func BenchmarkDriver(b *testing.B) {
+//		persistencekittest.Run(b, &mydriver.Config{...})
+//	}
+//
+// Drivers that do not support some features can opt out of the associated
+// tests using [Without].
+package persistencekittest
@@ -0,0 +1,205 @@
+package persistencekittest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/blob"
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/internal/drivertest"
+	"github.com/dogmatiq/persistencekit/journal"
+	"github.com/dogmatiq/persistencekit/kv"
+	"github.com/dogmatiq/persistencekit/lease"
+	"github.com/dogmatiq/persistencekit/queue"
+	"github.com/dogmatiq/persistencekit/schedule"
+	"github.com/dogmatiq/persistencekit/set"
+)
+
+// Run runs the conformance test suite against the driver described by cfg.
+//
+// If tb is a [*testing.T], it runs the conformance tests. If tb is a
+// [*testing.B], it runs the benchmarks.
+//
+// The tests verify that each of the driver's stores behaves correctly, and
+// that separate drivers created from the same configuration share the same
+// data. By default every capability is exercised; use [Without] to opt out of
+// unsupported features. Tests for optional features that the driver does not
+// report via [driver.Driver.Capabilities] are skipped.
+func Run(
+	tb testing.TB,
+	cfg driver.Config,
+	options ...Option,
+) {
+	opts := resolveOptions(options)
+
+	switch tb := tb.(type) {
+	case *testing.T:
+		runTests(tb, cfg, opts)
+	case *testing.B:
+		runBenchmarks(tb, cfg, opts)
+	default:
+		panic(fmt.Sprintf("unsupported testing.TB implementation: %T", tb))
+	}
+}
+
+func resolveOptions(opts []Option) options {
+	o := options{
+		Capabilities: AllCapabilities,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// newDriver returns a new driver described by cfg, which is closed when the
+// test completes.
+func newDriver(tb testing.TB, cfg driver.Config) driver.Driver {
+	tb.Helper()
+
+	d, err := cfg.NewDriver(tb.Context())
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	tb.Cleanup(func() {
+		if err := d.Close(); err != nil {
+			tb.Error(err)
+		}
+	})
+
+	return d
+}
+
+func runTests(t *testing.T, cfg driver.Config, opts options) {
+	d := newDriver(t, cfg)
+
+	// ref is a second driver created from the same configuration, used to
+	// verify that the stores of both drivers operate on the same data.
+	ref := newDriver(t, cfg)
+
+	caps := opts.Capabilities
+
+	if caps.Has(JournalStore) {
+		t.Run("JournalStore", func(t *testing.T) {
+			t.Parallel()
+			provision(t, d.JournalStore())
+			journal.RunTests(t, d.JournalStore())
+			drivertest.RunJournalStoreTests(t, d.JournalStore(), ref.JournalStore())
+		})
+	}
+
+	if caps.Has(KVStore) {
+		t.Run("KVStore", func(t *testing.T) {
+			t.Parallel()
+			provision(t, d.KVStore())
+			kv.RunTests(t, d.KVStore())
+			drivertest.RunKVStoreTests(t, d.KVStore(), ref.KVStore())
+
+			if caps.Has(KVChangeFeed) {
+				t.Run("ChangeFeed", func(t *testing.T) {
+					if !d.Capabilities().KV.ChangeFeed {
+						t.Skip("the driver's key/value store does not report a change feed")
+					}
+
+					feeds, ok := d.KVStore().(kv.BinaryChangeFeedStore)
+					if !ok {
+						t.Fatalf(
+							"%T reports a change feed but does not implement kv.BinaryChangeFeedStore",
+							d.KVStore(),
+						)
+					}
+					kv.RunChangeFeedTests(t, d.KVStore(), feeds)
+				})
+			}
+		})
+	}
+
+	if caps.Has(SetStore) {
+		t.Run("SetStore", func(t *testing.T) {
+			t.Parallel()
+			provision(t, d.SetStore())
+			set.RunTests(t, d.SetStore())
+			drivertest.RunSetStoreTests(t, d.SetStore(), ref.SetStore())
+		})
+	}
+
+	if caps.Has(LeaseStore) {
+		t.Run("LeaseStore", func(t *testing.T) {
+			t.Parallel()
+			provision(t, d.LeaseStore())
+			lease.RunTests(t, d.LeaseStore())
+			drivertest.RunLeaseStoreTests(t, d.LeaseStore(), ref.LeaseStore())
+		})
+	}
+
+	if caps.Has(QueueStore) {
+		t.Run("QueueStore", func(t *testing.T) {
+			t.Parallel()
+			provision(t, d.QueueStore())
+			queue.RunTests(t, d.QueueStore())
+			drivertest.RunQueueStoreTests(t, d.QueueStore(), ref.QueueStore())
+		})
+	}
+
+	if caps.Has(ScheduleStore) {
+		t.Run("ScheduleStore", func(t *testing.T) {
+			t.Parallel()
+			provision(t, d.ScheduleStore())
+			schedule.RunTests(t, d.ScheduleStore())
+			drivertest.RunScheduleStoreTests(t, d.ScheduleStore(), ref.ScheduleStore())
+		})
+	}
+
+	if caps.Has(BlobStore) {
+		t.Run("BlobStore", func(t *testing.T) {
+			t.Parallel()
+			provision(t, d.BlobStore())
+			blob.RunTests(t, d.BlobStore())
+			drivertest.RunBlobStoreTests(t, d.BlobStore(), ref.BlobStore())
+		})
+	}
+}
+
+func runBenchmarks(b *testing.B, cfg driver.Config, opts options) {
+	d := newDriver(b, cfg)
+	caps := opts.Capabilities
+
+	if caps.Has(JournalStore) {
+		b.Run("JournalStore", func(b *testing.B) {
+			journal.RunBenchmarks(b, d.JournalStore())
+		})
+	}
+
+	if caps.Has(KVStore) {
+		b.Run("KVStore", func(b *testing.B) {
+			kv.RunBenchmarks(b, d.KVStore())
+		})
+	}
+
+	if caps.Has(SetStore) {
+		b.Run("SetStore", func(b *testing.B) {
+			set.RunBenchmarks(b, d.SetStore())
+		})
+	}
+}
+
+// provision verifies that a store can be provisioned, and that doing so is
+// idempotent.
+func provision(
+	t *testing.T,
+	s interface {
+		Provision(ctx context.Context) error
+	},
+) {
+	t.Helper()
+
+	for range 2 {
+		if err := s.Provision(t.Context()); err != nil {
+			t.Fatalf("unable to provision store: %s", err)
+		}
+	}
+}
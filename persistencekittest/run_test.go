@@ -0,0 +1,16 @@
+package persistencekittest_test
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/driver/memory"
+	. "github.com/dogmatiq/persistencekit/persistencekittest"
+)
+
+func TestRun(t *testing.T) {
+	Run(t, &memory.Config{Silo: "persistencekittest"})
+}
+
+func BenchmarkRun(b *testing.B) {
+	Run(b, &memory.Config{Silo: "persistencekittest-benchmark"})
+}
@@ -0,0 +1,9 @@
+// Package provision creates and removes the infrastructure used by persistence
+// drivers, and reports the changes that doing so would make without applying
+// them.
+//
+// Each store creates its infrastructure on first use, so explicit provisioning
+// is optional. It allows infrastructure to be created ahead of time, for
+// example as part of a deployment pipeline, and to be removed again when an
+// environment is torn down.
+package provision
@@ -0,0 +1,47 @@
+package provision
+
+import "fmt"
+
+// Action is the kind of change made to a resource.
+type Action string
+
+const (
+	// Create indicates that a resource is created.
+	Create Action = "create"
+
+	// Update indicates that an existing resource is modified, such as by
+	// migrating it to a newer schema.
+	Update Action = "update"
+
+	// Delete indicates that a resource, and any data it contains, is deleted.
+	Delete Action = "delete"
+)
+
+// Change describes a change to a single backend resource.
+type Change struct {
+	// Action is the kind of change made to the resource.
+	Action Action
+
+	// Resource is a human-readable description of the resource, such as
+	// `DynamoDB table "app-journal"`.
+	Resource string
+
+	// Detail is the backend-specific definition of the change, such as SQL DDL
+	// or the parameters of an API request. It may be empty.
+	Detail string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s %s", c.Action, c.Resource)
+}
+
+// Plan is the set of changes that provisioning or deprovisioning a driver's
+// infrastructure would make, in the order they would be applied.
+type Plan struct {
+	Changes []Change
+}
+
+// Empty returns true if the plan contains no changes.
+func (p Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
@@ -0,0 +1,85 @@
+package provision
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dogmatiq/persistencekit/driver"
+)
+
+// Planner is an interface for drivers that can report the changes that
+// provisioning or deprovisioning their infrastructure would make, without
+// applying them.
+type Planner interface {
+	// PlanProvision returns the changes that provisioning each of the driver's
+	// stores would make.
+	PlanProvision(ctx context.Context) (Plan, error)
+
+	// PlanDeprovision returns the changes that [Deprovisioner.Deprovision]
+	// would make.
+	PlanDeprovision(ctx context.Context) (Plan, error)
+}
+
+// Deprovisioner is an interface for drivers that can remove their
+// infrastructure.
+type Deprovisioner interface {
+	// Deprovision removes the infrastructure used by each of the driver's
+	// stores, permanently deleting all of the data they contain.
+	Deprovision(ctx context.Context) error
+}
+
+// ErrUnsupported is returned when planning or deprovisioning a driver that does
+// not implement [Planner] or [Deprovisioner], respectively.
+var ErrUnsupported = errors.New("driver does not support provisioning plans")
+
+// Provision creates the infrastructure used by each of the stores provided by
+// d, if it does not already exist.
+func Provision(ctx context.Context, d driver.Driver) error {
+	for _, provision := range []func(context.Context) error{
+		d.JournalStore().Provision,
+		d.KVStore().Provision,
+		d.SetStore().Provision,
+		d.LeaseStore().Provision,
+		d.QueueStore().Provision,
+		d.ScheduleStore().Provision,
+		d.BlobStore().Provision,
+	} {
+		if err := provision(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PlanProvision returns the changes that [Provision] would make to the
+// infrastructure used by d.
+//
+// It returns [ErrUnsupported] if d does not implement [Planner].
+func PlanProvision(ctx context.Context, d driver.Driver) (Plan, error) {
+	if p, ok := d.(Planner); ok {
+		return p.PlanProvision(ctx)
+	}
+	return Plan{}, ErrUnsupported
+}
+
+// PlanDeprovision returns the changes that [Deprovision] would make to the
+// infrastructure used by d.
+//
+// It returns [ErrUnsupported] if d does not implement [Planner].
+func PlanDeprovision(ctx context.Context, d driver.Driver) (Plan, error) {
+	if p, ok := d.(Planner); ok {
+		return p.PlanDeprovision(ctx)
+	}
+	return Plan{}, ErrUnsupported
+}
+
+// Deprovision removes the infrastructure used by each of the stores provided
+// by d, permanently deleting all of the data they contain.
+//
+// It returns [ErrUnsupported] if d does not implement [Deprovisioner].
+func Deprovision(ctx context.Context, d driver.Driver) error {
+	if p, ok := d.(Deprovisioner); ok {
+		return p.Deprovision(ctx)
+	}
+	return ErrUnsupported
+}
@@ -0,0 +1,99 @@
+package provision_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/driver"
+	"github.com/dogmatiq/persistencekit/driver/memory"
+	. "github.com/dogmatiq/persistencekit/provision"
+)
+
+func TestProvision(t *testing.T) {
+	d := memory.New(&memory.Config{Silo: "provision-test-provision"})
+
+	if err := Provision(t.Context(), d); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlanProvision(t *testing.T) {
+	t.Run("it uses the driver's planner", func(t *testing.T) {
+		d := memory.New(&memory.Config{Silo: "provision-test-plan-provision"})
+
+		plan, err := PlanProvision(t.Context(), d)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !plan.Empty() {
+			t.Fatalf("unexpected changes: %v", plan.Changes)
+		}
+	})
+
+	t.Run("it returns an error if the driver does not support planning", func(t *testing.T) {
+		d := unplannedDriver{memory.New(&memory.Config{Silo: "provision-test-plan-provision"})}
+
+		if _, err := PlanProvision(t.Context(), d); !errors.Is(err, ErrUnsupported) {
+			t.Fatalf("unexpected error: got %v, want %v", err, ErrUnsupported)
+		}
+	})
+}
+
+func TestPlanDeprovision(t *testing.T) {
+	t.Run("it uses the driver's planner", func(t *testing.T) {
+		d := memory.New(&memory.Config{Silo: "provision-test-plan-deprovision"})
+
+		plan, err := PlanDeprovision(t.Context(), d)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !plan.Empty() {
+			t.Fatalf("unexpected changes: %v", plan.Changes)
+		}
+	})
+
+	t.Run("it returns an error if the driver does not support planning", func(t *testing.T) {
+		d := unplannedDriver{memory.New(&memory.Config{Silo: "provision-test-plan-deprovision"})}
+
+		if _, err := PlanDeprovision(t.Context(), d); !errors.Is(err, ErrUnsupported) {
+			t.Fatalf("unexpected error: got %v, want %v", err, ErrUnsupported)
+		}
+	})
+}
+
+func TestDeprovision(t *testing.T) {
+	t.Run("it uses the driver's deprovisioner", func(t *testing.T) {
+		d := memory.New(&memory.Config{Silo: "provision-test-deprovision"})
+
+		if err := Deprovision(t.Context(), d); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("it returns an error if the driver does not support deprovisioning", func(t *testing.T) {
+		d := unplannedDriver{memory.New(&memory.Config{Silo: "provision-test-deprovision"})}
+
+		if err := Deprovision(t.Context(), d); !errors.Is(err, ErrUnsupported) {
+			t.Fatalf("unexpected error: got %v, want %v", err, ErrUnsupported)
+		}
+	})
+}
+
+func TestChange(t *testing.T) {
+	c := Change{
+		Action:   Create,
+		Resource: `DynamoDB table "<table>"`,
+	}
+
+	if got, want := c.String(), `create DynamoDB table "<table>"`; got != want {
+		t.Fatalf("unexpected string: got %q, want %q", got, want)
+	}
+}
+
+// unplannedDriver is a [driver.Driver] that does not implement [Planner] or
+// [Deprovisioner].
+type unplannedDriver struct {
+	driver.Driver
+}
@@ -0,0 +1,10 @@
+package queue
+
+// BinaryStore is a collection of queues that contain opaque binary messages.
+type BinaryStore = Store[[]byte]
+
+// A BinaryQueue is a durable queue of opaque binary messages.
+type BinaryQueue = Queue[[]byte]
+
+// A BinaryMessage is a message that has been dequeued from a [BinaryQueue].
+type BinaryMessage = Message[[]byte]
@@ -0,0 +1,8 @@
+// Package queue provides an abstraction of a persisted work queue with
+// at-least-once delivery.
+//
+// A message that is dequeued is hidden from other consumers for a visibility
+// timeout. If it is not acknowledged before the timeout elapses it becomes
+// visible again and is redelivered. Messages that repeatedly fail to be
+// processed can be moved to a dead-letter queue.
+package queue
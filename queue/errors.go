@@ -0,0 +1,33 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+)
+
+// IsStale returns true if err is caused by [StaleMessageError].
+func IsStale(err error) bool {
+	return errors.As(err, &StaleMessageError{})
+}
+
+// StaleMessageError is returned by [Queue.Ack], [Queue.Nack] and
+// [Queue.DeadLetter] if the message has been redelivered, acknowledged or
+// dead-lettered since it was dequeued.
+//
+// Messages are identified by their ID and attempt number, so a message is
+// only considered stale once it has been dequeued again, not merely because
+// its visibility timeout has elapsed.
+type StaleMessageError struct {
+	Queue   string
+	ID      string
+	Attempt int
+}
+
+func (e StaleMessageError) Error() string {
+	return fmt.Sprintf(
+		"delivery attempt %d of message %q is no longer current in the %q queue",
+		e.Attempt,
+		e.ID,
+		e.Queue,
+	)
+}
@@ -0,0 +1,88 @@
+package queue
+
+import (
+	"context"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/marshaler"
+)
+
+// NewMarshalingStore returns a new [Store] that marshals/unmarshals values of
+// type T to/from an underlying [BinaryStore].
+func NewMarshalingStore[T any](
+	s BinaryStore,
+	m marshaler.Marshaler[T],
+) Store[T] {
+	return &mstore[T]{s, m}
+}
+
+// mstore is an implementation of [Store] that marshals/unmarshals values to/from
+// an underlying [BinaryStore].
+type mstore[T any] struct {
+	BinaryStore
+	m marshaler.Marshaler[T]
+}
+
+func (s *mstore[T]) Open(ctx context.Context, name string) (Queue[T], error) {
+	q, err := s.BinaryStore.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mqueue[T]{q, s.m}, nil
+}
+
+// mqueue is an implementation of [Queue] that marshals/unmarshals values
+// to/from an underlying [BinaryQueue].
+type mqueue[T any] struct {
+	BinaryQueue
+	m marshaler.Marshaler[T]
+}
+
+func (q *mqueue[T]) Enqueue(ctx context.Context, v T) error {
+	data, err := q.m.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return q.BinaryQueue.Enqueue(ctx, data)
+}
+
+func (q *mqueue[T]) Dequeue(ctx context.Context, timeout time.Duration) (Message[T], bool, error) {
+	m, ok, err := q.BinaryQueue.Dequeue(ctx, timeout)
+	if !ok || err != nil {
+		return Message[T]{}, false, err
+	}
+
+	v, err := q.m.Unmarshal(m.Value)
+	if err != nil {
+		return Message[T]{}, false, err
+	}
+
+	return Message[T]{
+		ID:      m.ID,
+		Value:   v,
+		Attempt: m.Attempt,
+	}, true, nil
+}
+
+func (q *mqueue[T]) Ack(ctx context.Context, m Message[T]) error {
+	return q.BinaryQueue.Ack(ctx, withoutValue(m))
+}
+
+func (q *mqueue[T]) Nack(ctx context.Context, m Message[T], delay time.Duration) error {
+	return q.BinaryQueue.Nack(ctx, withoutValue(m), delay)
+}
+
+func (q *mqueue[T]) DeadLetter(ctx context.Context, m Message[T]) error {
+	return q.BinaryQueue.DeadLetter(ctx, withoutValue(m))
+}
+
+// withoutValue returns a [BinaryMessage] that identifies the same delivery as
+// m. Its value is nil, as it is not used to identify the message.
+func withoutValue[T any](m Message[T]) BinaryMessage {
+	return BinaryMessage{
+		ID:      m.ID,
+		Attempt: m.Attempt,
+	}
+}
@@ -0,0 +1,83 @@
+package queue_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/driver/memory/memoryqueue"
+	"github.com/dogmatiq/persistencekit/marshaler"
+	. "github.com/dogmatiq/persistencekit/queue"
+)
+
+func TestNewMarshalingStore(t *testing.T) {
+	store := NewMarshalingStore(
+		&memoryqueue.BinaryStore{},
+		marshaler.NewJSON[int](),
+	)
+
+	q, err := store.Open(t.Context(), "<name>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	for v := range 3 {
+		if err := q.Enqueue(t.Context(), v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for want := range 3 {
+		m, ok, err := q.Dequeue(t.Context(), time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("expected a message to be dequeued")
+		}
+
+		if m.Value != want {
+			t.Fatalf("unexpected value: got %d, want %d", m.Value, want)
+		}
+
+		if want == 0 {
+			// Return the first message to the queue, so that it is
+			// redelivered after the others.
+			if err := q.Nack(t.Context(), m, 0); err != nil {
+				t.Fatal(err)
+			}
+		} else if err := q.Ack(t.Context(), m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m, ok, err := q.Dequeue(t.Context(), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected the returned message to be redelivered")
+	}
+
+	if m.Value != 0 || m.Attempt != 2 {
+		t.Fatalf("unexpected message: got value %d on attempt %d, want value 0 on attempt 2", m.Value, m.Attempt)
+	}
+
+	if err := q.DeadLetter(t.Context(), m); err != nil {
+		t.Fatal(err)
+	}
+
+	dlq, err := store.Open(t.Context(), DeadLetterName("<name>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dlq.Close()
+
+	m, ok, err = dlq.Dequeue(t.Context(), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || m.Value != 0 {
+		t.Fatalf("expected the dead-lettered message to be in the dead-letter queue")
+	}
+}
@@ -0,0 +1,64 @@
+package queue
+
+import (
+	"context"
+	"time"
+)
+
+// Message is a message that has been dequeued from a [Queue].
+type Message[T any] struct {
+	// ID uniquely identifies the message within its queue.
+	ID string
+
+	// Value is the content of the message.
+	Value T
+
+	// Attempt is the number of times the message has been dequeued, including
+	// the dequeue that produced this delivery. It is 1 the first time the
+	// message is delivered.
+	Attempt int
+}
+
+// Queue is a durable queue of messages of type T.
+//
+// Messages are generally delivered in the order that they were enqueued, but
+// ordering is not guaranteed, especially once messages have been redelivered.
+type Queue[T any] interface {
+	// Name returns the name of the queue.
+	Name() string
+
+	// Enqueue adds a message with the given value to the queue.
+	Enqueue(ctx context.Context, v T) error
+
+	// Dequeue returns the next visible message in the queue, and hides it from
+	// other consumers until the visibility timeout elapses.
+	//
+	// ok is false if there are no visible messages. Dequeue does not block
+	// waiting for messages to become available.
+	Dequeue(ctx context.Context, timeout time.Duration) (m Message[T], ok bool, err error)
+
+	// Ack acknowledges successful processing of m, removing it from the queue.
+	//
+	// m is identified by its ID and attempt number; its value is ignored. Ack,
+	// Nack and DeadLetter return a [StaleMessageError] if m has since been
+	// redelivered or removed from the queue.
+	Ack(ctx context.Context, m Message[T]) error
+
+	// Nack returns m to the queue, such that it is redelivered once the given
+	// delay has elapsed.
+	Nack(ctx context.Context, m Message[T], delay time.Duration) error
+
+	// DeadLetter removes m from the queue and adds it to the queue named by
+	// [DeadLetterName], from which it can be inspected and, if necessary,
+	// re-enqueued.
+	DeadLetter(ctx context.Context, m Message[T]) error
+
+	// Close closes the queue.
+	Close() error
+}
+
+// DeadLetterName returns the name of the queue to which [Queue.DeadLetter]
+// moves messages from the named queue.
+func DeadLetterName(name string) string {
+	return name + ".dead-letter"
+}
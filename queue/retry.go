@@ -0,0 +1,40 @@
+package queue
+
+import (
+	"context"
+	"time"
+)
+
+// RetryPolicy determines how [Retry] handles a message that could not be
+// processed.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a message is delivered before it is
+	// moved to the dead-letter queue. If it is zero, messages are retried
+	// indefinitely.
+	MaxAttempts int
+
+	// Delay returns the time to wait before a message is redelivered after
+	// the given (failed) attempt. If it is nil, messages are redelivered
+	// immediately.
+	Delay func(attempt int) time.Duration
+}
+
+// Retry returns m to q to be redelivered, or moves it to the dead-letter queue
+// if it has been delivered p.MaxAttempts times.
+func Retry[T any](
+	ctx context.Context,
+	q Queue[T],
+	m Message[T],
+	p RetryPolicy,
+) error {
+	if p.MaxAttempts > 0 && m.Attempt >= p.MaxAttempts {
+		return q.DeadLetter(ctx, m)
+	}
+
+	var delay time.Duration
+	if p.Delay != nil {
+		delay = p.Delay(m.Attempt)
+	}
+
+	return q.Nack(ctx, m, delay)
+}
@@ -0,0 +1,66 @@
+package queue_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/driver/memory/memoryqueue"
+	. "github.com/dogmatiq/persistencekit/queue"
+)
+
+func TestRetry(t *testing.T) {
+	store := &memoryqueue.BinaryStore{}
+
+	q, err := store.Open(t.Context(), "<name>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	dlq, err := store.Open(t.Context(), DeadLetterName("<name>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dlq.Close()
+
+	if err := q.Enqueue(t.Context(), []byte("<value>")); err != nil {
+		t.Fatal(err)
+	}
+
+	policy := RetryPolicy{
+		MaxAttempts: 3,
+		Delay: func(int) time.Duration {
+			return 0
+		},
+	}
+
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		m, ok, err := q.Dequeue(t.Context(), time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatalf("expected attempt %d to be delivered", attempt)
+		}
+
+		if m.Attempt != attempt {
+			t.Fatalf("unexpected attempt: got %d, want %d", m.Attempt, attempt)
+		}
+
+		if err := Retry(t.Context(), q, m, policy); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, ok, err := q.Dequeue(t.Context(), time.Minute); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatal("expected the message to be removed from the queue")
+	}
+
+	if _, ok, err := dlq.Dequeue(t.Context(), time.Minute); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatal("expected the message to be moved to the dead-letter queue")
+	}
+}
@@ -0,0 +1,15 @@
+package queue
+
+import (
+	"context"
+)
+
+// Store is a collection of queues that contain messages of type T.
+type Store[T any] interface {
+	// Open returns the queue with the given name.
+	Open(ctx context.Context, name string) (Queue[T], error)
+
+	// Provision creates the infrastructure used by the store if it does not
+	// already exist.
+	Provision(ctx context.Context) error
+}
@@ -0,0 +1,263 @@
+package queue
+
+import (
+	"context"
+	"time"
+
+	"github.com/dogmatiq/enginekit/telemetry"
+	"github.com/dogmatiq/persistencekit/internal/x/xtelemetry"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithTelemetry returns a [BinaryStore] that adds telemetry to s.
+func WithTelemetry(
+	s BinaryStore,
+	p trace.TracerProvider,
+	m metric.MeterProvider,
+	l log.LoggerProvider,
+) BinaryStore {
+	return &instrumentedStore{
+		Next: s,
+		Telemetry: telemetry.Provider{
+			TracerProvider: p,
+			MeterProvider:  m,
+			LoggerProvider: l,
+		},
+	}
+}
+
+// instrumentedStore is a decorator that adds instrumentation to a [BinaryStore].
+type instrumentedStore struct {
+	Next      BinaryStore
+	Telemetry telemetry.Provider
+}
+
+func (s *instrumentedStore) Provision(ctx context.Context) error {
+	return s.Next.Provision(ctx)
+}
+
+// Open returns the queue with the given name.
+func (s *instrumentedStore) Open(ctx context.Context, name string) (BinaryQueue, error) {
+	telem := s.Telemetry.Recorder(
+		"github.com/dogmatiq/persistencekit/queue",
+		telemetry.Type("queue.store", s.Next),
+		telemetry.String("queue.name", name),
+		telemetry.String("queue.handle", xtelemetry.HandleID()),
+	)
+
+	q := &instrumentedQueue{
+		Telemetry:     telem,
+		OpenQueues:    telem.UpDownCounter("persistence.queue.open_queues", "{queue}", "The number of queues that are currently open."),
+		Deliveries:    telem.Counter("persistence.queue.deliveries", "{message}", "The number of messages that have been dequeued, including redeliveries."),
+		Redeliveries:  telem.Counter("persistence.queue.redeliveries", "{message}", "The number of messages that have been dequeued after a previous delivery attempt."),
+		DeadLetters:   telem.Counter("persistence.queue.dead_letters", "{message}", "The number of messages that have been moved to a dead-letter queue."),
+		StaleMessages: telem.Counter("persistence.queue.stale_messages", "{error}", "The number of times a message could not be acknowledged because it was no longer current."),
+		ValueIO:       telem.Counter("persistence.queue.value.io", "By", "The cumulative size of the message values that have been operated upon."),
+		ValueSize:     telem.Histogram("persistence.queue.value.size", "By", "The sizes of the message values that have been operated upon."),
+	}
+
+	ctx, span := telem.StartSpan(ctx, "queue.open")
+	defer span.End()
+
+	next, err := s.Next.Open(ctx, name)
+	if err != nil {
+		telem.Error(ctx, "queue.open.error", "unable to open queue", err)
+		return nil, err
+	}
+
+	q.Next = next
+
+	q.OpenQueues(ctx, 1)
+	q.Telemetry.Info(ctx, "queue.open.ok", "opened queue")
+
+	return q, nil
+}
+
+type instrumentedQueue struct {
+	Next      BinaryQueue
+	Telemetry *telemetry.Recorder
+
+	OpenQueues    telemetry.Instrument[int64]
+	Deliveries    telemetry.Instrument[int64]
+	Redeliveries  telemetry.Instrument[int64]
+	DeadLetters   telemetry.Instrument[int64]
+	StaleMessages telemetry.Instrument[int64]
+	ValueIO       telemetry.Instrument[int64]
+	ValueSize     telemetry.Instrument[int64]
+}
+
+func (q *instrumentedQueue) Name() string {
+	return q.Next.Name()
+}
+
+func (q *instrumentedQueue) Enqueue(ctx context.Context, v []byte) error {
+	size := int64(len(v))
+
+	ctx, span := q.Telemetry.StartSpan(
+		ctx,
+		"queue.enqueue",
+		telemetry.Int("value_size", size),
+	)
+	defer span.End()
+
+	q.ValueIO(ctx, size, telemetry.WriteDirection)
+	q.ValueSize(ctx, size, telemetry.WriteDirection)
+
+	if err := q.Next.Enqueue(ctx, v); err != nil {
+		q.Telemetry.Error(ctx, "queue.enqueue.error", "unable to enqueue message", err)
+		return err
+	}
+
+	q.Telemetry.Info(ctx, "queue.enqueue.ok", "enqueued message")
+
+	return nil
+}
+
+func (q *instrumentedQueue) Dequeue(ctx context.Context, timeout time.Duration) (BinaryMessage, bool, error) {
+	ctx, span := q.Telemetry.StartSpan(
+		ctx,
+		"queue.dequeue",
+		telemetry.Duration("visibility_timeout", timeout),
+	)
+	defer span.End()
+
+	m, ok, err := q.Next.Dequeue(ctx, timeout)
+	if err != nil {
+		q.Telemetry.Error(ctx, "queue.dequeue.error", "unable to dequeue message", err)
+		return BinaryMessage{}, false, err
+	}
+
+	span.SetAttributes(
+		telemetry.Bool("message_available", ok),
+	)
+
+	if !ok {
+		q.Telemetry.Info(ctx, "queue.dequeue.empty", "no messages are visible in queue")
+		return BinaryMessage{}, false, nil
+	}
+
+	size := int64(len(m.Value))
+
+	span.SetAttributes(
+		telemetry.String("message_id", m.ID),
+		telemetry.Int("attempt", m.Attempt),
+		telemetry.Int("value_size", size),
+	)
+
+	q.Deliveries(ctx, 1)
+	if m.Attempt > 1 {
+		q.Redeliveries(ctx, 1)
+	}
+
+	q.ValueIO(ctx, size, telemetry.ReadDirection)
+	q.ValueSize(ctx, size, telemetry.ReadDirection)
+
+	q.Telemetry.Info(ctx, "queue.dequeue.ok", "dequeued message")
+
+	return m, true, nil
+}
+
+func (q *instrumentedQueue) Ack(ctx context.Context, m BinaryMessage) error {
+	ctx, span := q.Telemetry.StartSpan(
+		ctx,
+		"queue.ack",
+		telemetry.String("message_id", m.ID),
+		telemetry.Int("attempt", m.Attempt),
+	)
+	defer span.End()
+
+	if err := q.Next.Ack(ctx, m); err != nil {
+		q.error(ctx, span, "queue.ack", "unable to acknowledge message", err)
+		return err
+	}
+
+	q.Telemetry.Info(ctx, "queue.ack.ok", "acknowledged message")
+
+	return nil
+}
+
+func (q *instrumentedQueue) Nack(ctx context.Context, m BinaryMessage, delay time.Duration) error {
+	ctx, span := q.Telemetry.StartSpan(
+		ctx,
+		"queue.nack",
+		telemetry.String("message_id", m.ID),
+		telemetry.Int("attempt", m.Attempt),
+		telemetry.Duration("delay", delay),
+	)
+	defer span.End()
+
+	if err := q.Next.Nack(ctx, m, delay); err != nil {
+		q.error(ctx, span, "queue.nack", "unable to return message to queue", err)
+		return err
+	}
+
+	q.Telemetry.Info(ctx, "queue.nack.ok", "returned message to queue")
+
+	return nil
+}
+
+func (q *instrumentedQueue) DeadLetter(ctx context.Context, m BinaryMessage) error {
+	ctx, span := q.Telemetry.StartSpan(
+		ctx,
+		"queue.dead-letter",
+		telemetry.String("message_id", m.ID),
+		telemetry.Int("attempt", m.Attempt),
+	)
+	defer span.End()
+
+	if err := q.Next.DeadLetter(ctx, m); err != nil {
+		q.error(ctx, span, "queue.dead-letter", "unable to move message to dead-letter queue", err)
+		return err
+	}
+
+	q.DeadLetters(ctx, 1)
+	q.Telemetry.Info(ctx, "queue.dead-letter.ok", "moved message to dead-letter queue")
+
+	return nil
+}
+
+// error records an error that occurred while acknowledging, returning or
+// dead-lettering a message.
+func (q *instrumentedQueue) error(
+	ctx context.Context,
+	span *telemetry.Span,
+	op, message string,
+	err error,
+) {
+	if IsStale(err) {
+		q.Telemetry.Error(ctx, op+".stale", "message is no longer current", err)
+		q.StaleMessages(ctx, 1)
+		span.SetAttributes(telemetry.Bool("stale", true))
+	} else {
+		q.Telemetry.Error(ctx, op+".error", message, err)
+	}
+}
+
+func (q *instrumentedQueue) Close() error {
+	if q.Next == nil {
+		// If the resource has already been closed don't do anything at all,
+		// even log a warning, because we want to allow the caller to defer
+		// closing for safety _and_ close explicitly elsewhere for error
+		// checking.
+		return nil
+	}
+
+	ctx, span := q.Telemetry.StartSpan(context.Background(), "queue.close")
+	defer span.End()
+
+	defer func() {
+		q.Next = nil
+		q.OpenQueues(ctx, -1)
+	}()
+
+	if err := q.Next.Close(); err != nil {
+		q.Telemetry.Error(ctx, "queue.close.error", "unable to close queue cleanly", err)
+		return err
+	}
+
+	q.Telemetry.Info(ctx, "queue.close.ok", "closed queue")
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package queue_test
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/driver/memory/memoryqueue"
+	. "github.com/dogmatiq/persistencekit/queue"
+	nooplog "go.opentelemetry.io/otel/log/noop"
+	noopmetric "go.opentelemetry.io/otel/metric/noop"
+	nooptrace "go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestWithTelemetry(t *testing.T) {
+	RunTests(
+		t,
+		WithTelemetry(
+			&memoryqueue.BinaryStore{},
+			nooptrace.NewTracerProvider(),
+			noopmetric.NewMeterProvider(),
+			nooplog.NewLoggerProvider(),
+		),
+	)
+}
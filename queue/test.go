@@ -0,0 +1,397 @@
+package queue
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+)
+
+// RunTests runs tests that confirm a [BinaryStore] implementation behaves
+// correctly.
+func RunTests(
+	t *testing.T,
+	store BinaryStore,
+) {
+	const (
+		// timeout is a visibility timeout that does not elapse during a test.
+		timeout = time.Minute
+
+		// shortTimeout is a visibility timeout that is allowed to elapse
+		// during a test.
+		shortTimeout = 100 * time.Millisecond
+	)
+
+	open := func(t *testing.T, name string) BinaryQueue {
+		t.Helper()
+
+		q, err := store.Open(t.Context(), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() {
+			if err := q.Close(); err != nil {
+				t.Error(err)
+			}
+		})
+
+		if q.Name() != name {
+			t.Fatalf("unexpected queue name: got %q, want %q", q.Name(), name)
+		}
+
+		return q
+	}
+
+	setup := func(t *testing.T) BinaryQueue {
+		return open(t, xtesting.SequentialName("queue"))
+	}
+
+	enqueue := func(t *testing.T, q BinaryQueue, values ...string) {
+		t.Helper()
+
+		for _, v := range values {
+			if err := q.Enqueue(t.Context(), []byte(v)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	dequeue := func(t *testing.T, q BinaryQueue, timeout time.Duration) BinaryMessage {
+		t.Helper()
+
+		m, ok, err := q.Dequeue(t.Context(), timeout)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("expected a message to be dequeued")
+		}
+
+		return m
+	}
+
+	expectEmpty := func(t *testing.T, q BinaryQueue) {
+		t.Helper()
+
+		m, ok, err := q.Dequeue(t.Context(), timeout)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Fatalf("did not expect a message to be dequeued, got %q", m.Value)
+		}
+	}
+
+	expectMessage := func(t *testing.T, m BinaryMessage, value string, attempt int) {
+		t.Helper()
+
+		if !bytes.Equal(m.Value, []byte(value)) {
+			t.Fatalf("unexpected value: got %q, want %q", m.Value, value)
+		}
+
+		if m.Attempt != attempt {
+			t.Fatalf("unexpected attempt: got %d, want %d", m.Attempt, attempt)
+		}
+	}
+
+	expectStale := func(t *testing.T, err error) {
+		t.Helper()
+
+		if !IsStale(err) {
+			t.Fatalf("expected a stale message error, got %v", err)
+		}
+	}
+
+	expire := func() {
+		time.Sleep(2 * shortTimeout)
+	}
+
+	t.Run("Store", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("Open", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("allows queues to be opened multiple times", func(t *testing.T) {
+				t.Parallel()
+
+				name := xtesting.SequentialName("queue")
+				q1 := open(t, name)
+				q2 := open(t, name)
+
+				enqueue(t, q1, "<value>")
+				m := dequeue(t, q2, timeout)
+				expectMessage(t, m, "<value>", 1)
+			})
+
+			t.Run("does not share messages between queues with different names", func(t *testing.T) {
+				t.Parallel()
+
+				q1 := setup(t)
+				q2 := setup(t)
+
+				enqueue(t, q1, "<value>")
+				expectEmpty(t, q2)
+			})
+		})
+	})
+
+	t.Run("Queue", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("Dequeue", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it returns false if the queue is empty", func(t *testing.T) {
+				t.Parallel()
+
+				q := setup(t)
+				expectEmpty(t, q)
+			})
+
+			t.Run("it returns an enqueued message", func(t *testing.T) {
+				t.Parallel()
+
+				q := setup(t)
+				enqueue(t, q, "<value>")
+
+				m := dequeue(t, q, timeout)
+				expectMessage(t, m, "<value>", 1)
+
+				if m.ID == "" {
+					t.Fatal("expected a non-empty message ID")
+				}
+			})
+
+			t.Run("it returns messages in the order they were enqueued", func(t *testing.T) {
+				t.Parallel()
+
+				q := setup(t)
+				enqueue(t, q, "<value-1>", "<value-2>", "<value-3>")
+
+				for _, want := range []string{"<value-1>", "<value-2>", "<value-3>"} {
+					m := dequeue(t, q, timeout)
+					expectMessage(t, m, want, 1)
+				}
+
+				expectEmpty(t, q)
+			})
+
+			t.Run("it hides a message until its visibility timeout elapses", func(t *testing.T) {
+				t.Parallel()
+
+				q := setup(t)
+				enqueue(t, q, "<value>")
+
+				first := dequeue(t, q, shortTimeout)
+				expectEmpty(t, q)
+
+				expire()
+
+				second := dequeue(t, q, timeout)
+				expectMessage(t, second, "<value>", 2)
+
+				if second.ID != first.ID {
+					t.Fatalf("unexpected message ID: got %q, want %q", second.ID, first.ID)
+				}
+			})
+
+			t.Run("it delivers each message to exactly one of many concurrent consumers", func(t *testing.T) {
+				t.Parallel()
+
+				name := xtesting.SequentialName("queue")
+
+				const count = 20
+				q := open(t, name)
+				for n := range count {
+					enqueue(t, q, fmt.Sprintf("<value-%d>", n))
+				}
+
+				var (
+					g    sync.WaitGroup
+					m    sync.Mutex
+					seen = map[string]int{}
+				)
+
+				for range 5 {
+					g.Go(func() {
+						// Each consumer uses its own handle, as it would if
+						// each were a separate process.
+						q, err := store.Open(t.Context(), name)
+						if err != nil {
+							t.Error(err)
+							return
+						}
+						defer q.Close()
+
+						for {
+							msg, ok, err := q.Dequeue(t.Context(), timeout)
+							if !ok || err != nil {
+								if err != nil {
+									t.Error(err)
+								}
+								return
+							}
+
+							m.Lock()
+							seen[string(msg.Value)]++
+							m.Unlock()
+						}
+					})
+				}
+
+				g.Wait()
+
+				if len(seen) != count {
+					t.Fatalf("unexpected number of distinct messages delivered: got %d, want %d", len(seen), count)
+				}
+
+				for v, n := range seen {
+					if n != 1 {
+						t.Fatalf("message %q was delivered %d times", v, n)
+					}
+				}
+			})
+		})
+
+		t.Run("Ack", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it removes the message from the queue", func(t *testing.T) {
+				t.Parallel()
+
+				q := setup(t)
+				enqueue(t, q, "<value>")
+
+				m := dequeue(t, q, shortTimeout)
+				if err := q.Ack(t.Context(), m); err != nil {
+					t.Fatal(err)
+				}
+
+				expire()
+				expectEmpty(t, q)
+			})
+
+			t.Run("it returns an error if the message has been redelivered", func(t *testing.T) {
+				t.Parallel()
+
+				q := setup(t)
+				enqueue(t, q, "<value>")
+
+				m := dequeue(t, q, shortTimeout)
+				expire()
+				dequeue(t, q, timeout)
+
+				expectStale(t, q.Ack(t.Context(), m))
+			})
+
+			t.Run("it returns an error if the message has already been acknowledged", func(t *testing.T) {
+				t.Parallel()
+
+				q := setup(t)
+				enqueue(t, q, "<value>")
+
+				m := dequeue(t, q, timeout)
+				if err := q.Ack(t.Context(), m); err != nil {
+					t.Fatal(err)
+				}
+
+				expectStale(t, q.Ack(t.Context(), m))
+			})
+		})
+
+		t.Run("Nack", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it makes the message visible again", func(t *testing.T) {
+				t.Parallel()
+
+				q := setup(t)
+				enqueue(t, q, "<value>")
+
+				m := dequeue(t, q, timeout)
+				if err := q.Nack(t.Context(), m, 0); err != nil {
+					t.Fatal(err)
+				}
+
+				m = dequeue(t, q, timeout)
+				expectMessage(t, m, "<value>", 2)
+			})
+
+			t.Run("it hides the message until the delay elapses", func(t *testing.T) {
+				t.Parallel()
+
+				q := setup(t)
+				enqueue(t, q, "<value>")
+
+				m := dequeue(t, q, timeout)
+				if err := q.Nack(t.Context(), m, shortTimeout); err != nil {
+					t.Fatal(err)
+				}
+
+				expectEmpty(t, q)
+				expire()
+
+				m = dequeue(t, q, timeout)
+				expectMessage(t, m, "<value>", 2)
+			})
+
+			t.Run("it returns an error if the message has been redelivered", func(t *testing.T) {
+				t.Parallel()
+
+				q := setup(t)
+				enqueue(t, q, "<value>")
+
+				m := dequeue(t, q, shortTimeout)
+				expire()
+				dequeue(t, q, timeout)
+
+				expectStale(t, q.Nack(t.Context(), m, 0))
+			})
+		})
+
+		t.Run("DeadLetter", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it moves the message to the dead-letter queue", func(t *testing.T) {
+				t.Parallel()
+
+				q := setup(t)
+				dlq := open(t, DeadLetterName(q.Name()))
+
+				enqueue(t, q, "<value>")
+
+				m := dequeue(t, q, shortTimeout)
+				if err := q.DeadLetter(t.Context(), m); err != nil {
+					t.Fatal(err)
+				}
+
+				expire()
+				expectEmpty(t, q)
+
+				dl := dequeue(t, dlq, timeout)
+				expectMessage(t, dl, "<value>", 1)
+			})
+
+			t.Run("it returns an error if the message has been redelivered", func(t *testing.T) {
+				t.Parallel()
+
+				q := setup(t)
+				dlq := open(t, DeadLetterName(q.Name()))
+
+				enqueue(t, q, "<value>")
+
+				m := dequeue(t, q, shortTimeout)
+				expire()
+				dequeue(t, q, timeout)
+
+				expectStale(t, q.DeadLetter(t.Context(), m))
+				expectEmpty(t, dlq)
+			})
+		})
+	})
+}
@@ -0,0 +1,136 @@
+package persistencekit
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// SchemeFunc returns the [Config] for a URL with a specific scheme.
+type SchemeFunc func(ctx context.Context, u *url.URL) (Config, error)
+
+// WrapperSchemeFunc returns the [Config] for a URL with a wrapper scheme, such
+// as "<wrapper>+<scheme>://...".
+//
+// u is the complete URL, including the wrapper's prefix. The function is
+// responsible for removing the prefix, and any query parameters that it
+// consumes, before calling next to obtain the [Config] of the wrapped driver.
+type WrapperSchemeFunc func(ctx context.Context, u *url.URL, next SchemeFunc) (Config, error)
+
+var registry struct {
+	m        sync.RWMutex
+	schemes  map[string]SchemeFunc
+	wrappers map[string]WrapperSchemeFunc
+}
+
+// RegisterScheme makes a driver available to [ParseURL] and [FromURL] for URLs
+// with the given scheme.
+//
+// It panics if the scheme is already registered, or is not a valid scheme
+// name. Scheme names must not contain a '+' character, which separates a
+// wrapper scheme from the scheme that it wraps.
+func RegisterScheme(scheme string, fn SchemeFunc) {
+	if fn == nil {
+		panic("persistencekit: scheme function must not be nil")
+	}
+
+	registry.m.Lock()
+	defer registry.m.Unlock()
+
+	register(&registry.schemes, "scheme", scheme, fn)
+}
+
+// RegisterWrapperScheme makes a driver wrapper available to [ParseURL] and
+// [FromURL] for URLs with a scheme of the form "<wrapper>+<scheme>". Wrapper
+// schemes can be nested to compose several wrappers.
+//
+// It panics if the wrapper is already registered, or is not a valid scheme
+// name.
+func RegisterWrapperScheme(wrapper string, fn WrapperSchemeFunc) {
+	if fn == nil {
+		panic("persistencekit: wrapper scheme function must not be nil")
+	}
+
+	registry.m.Lock()
+	defer registry.m.Unlock()
+
+	register(&registry.wrappers, "wrapper scheme", wrapper, fn)
+}
+
+func register[F any](m *map[string]F, kind, scheme string, fn F) {
+	if !isValidScheme(scheme) {
+		panic(fmt.Sprintf("persistencekit: invalid %s %q", kind, scheme))
+	}
+
+	if _, ok := (*m)[scheme]; ok {
+		panic(fmt.Sprintf("persistencekit: %s %q is already registered", kind, scheme))
+	}
+
+	if *m == nil {
+		*m = map[string]F{}
+	}
+
+	(*m)[scheme] = fn
+}
+
+// isValidScheme returns true if s is a valid URL scheme that does not contain
+// the '+' wrapper separator.
+//
+// URL schemes are case-insensitive, and [url.Parse] normalizes them to lower
+// case, so only lower case schemes are considered valid.
+func isValidScheme(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i, c := range s {
+		switch {
+		case 'a' <= c && c <= 'z':
+		case i > 0 && ('0' <= c && c <= '9' || c == '-' || c == '.'):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func lookupScheme(scheme string) (SchemeFunc, bool) {
+	registry.m.RLock()
+	defer registry.m.RUnlock()
+
+	fn, ok := registry.schemes[scheme]
+	return fn, ok
+}
+
+func lookupWrapperScheme(wrapper string) (WrapperSchemeFunc, bool) {
+	registry.m.RLock()
+	defer registry.m.RUnlock()
+
+	fn, ok := registry.wrappers[wrapper]
+	return fn, ok
+}
+
+// FromURL returns a [Config] for the driver identified by the URL scheme. See
+// [ParseURL] for supported URL formats.
+func FromURL(ctx context.Context, u *url.URL) (Config, error) {
+	if u.Scheme == "" {
+		return nil, fmt.Errorf("persistence driver URL has no scheme: %q", u)
+	}
+
+	if wrapper, _, ok := strings.Cut(u.Scheme, "+"); ok {
+		fn, ok := lookupWrapperScheme(wrapper)
+		if !ok {
+			return nil, fmt.Errorf("unsupported persistence driver wrapper scheme %q", wrapper)
+		}
+		return fn(ctx, u, FromURL)
+	}
+
+	fn, ok := lookupScheme(u.Scheme)
+	if !ok {
+		return nil, fmt.Errorf("unsupported persistence driver scheme %q", u.Scheme)
+	}
+	return fn(ctx, u)
+}
@@ -0,0 +1,158 @@
+package persistencekit_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	. "github.com/dogmatiq/persistencekit"
+	"github.com/dogmatiq/persistencekit/driver/memory"
+)
+
+// wrapperConfig is a [Config] produced by the "test-wrapper" wrapper scheme.
+type wrapperConfig struct {
+	Config
+}
+
+func init() {
+	RegisterScheme(
+		"test-scheme",
+		func(_ context.Context, u *url.URL) (Config, error) {
+			return &memory.Config{Silo: strings.TrimPrefix(u.Path, "/")}, nil
+		},
+	)
+
+	RegisterWrapperScheme(
+		"test-wrapper",
+		func(ctx context.Context, u *url.URL, next SchemeFunc) (Config, error) {
+			inner := *u
+			inner.Scheme = strings.TrimPrefix(u.Scheme, "test-wrapper+")
+
+			cfg, err := next(ctx, &inner)
+			if err != nil {
+				return nil, err
+			}
+
+			return wrapperConfig{cfg}, nil
+		},
+	)
+}
+
+func TestRegisterScheme(t *testing.T) {
+	t.Run("it makes the scheme available to ParseURL", func(t *testing.T) {
+		cfg, err := ParseURL(t.Context(), "test-scheme:///silo")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mem, ok := cfg.(*memory.Config)
+		if !ok {
+			t.Fatalf("unexpected config type: %T", cfg)
+		}
+
+		if mem.Silo != "silo" {
+			t.Fatalf("unexpected silo: got %q, want %q", mem.Silo, "silo")
+		}
+	})
+
+	t.Run("it panics if the scheme is already registered", func(t *testing.T) {
+		expectPanic(
+			t,
+			`persistencekit: scheme "memory" is already registered`,
+			func() {
+				RegisterScheme("memory", func(context.Context, *url.URL) (Config, error) {
+					panic("unexpected call")
+				})
+			},
+		)
+	})
+
+	t.Run("it panics if the scheme is invalid", func(t *testing.T) {
+		for _, scheme := range []string{"", "Upper", "1st", "with+plus", "with space"} {
+			expectPanic(
+				t,
+				"persistencekit: invalid scheme",
+				func() {
+					RegisterScheme(scheme, func(context.Context, *url.URL) (Config, error) {
+						panic("unexpected call")
+					})
+				},
+			)
+		}
+	})
+}
+
+func TestRegisterWrapperScheme(t *testing.T) {
+	t.Run("it makes the wrapper available to ParseURL", func(t *testing.T) {
+		cfg, err := ParseURL(t.Context(), "test-wrapper+memory:///silo")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w, ok := cfg.(wrapperConfig)
+		if !ok {
+			t.Fatalf("unexpected config type: %T", cfg)
+		}
+
+		if _, ok := w.Config.(*memory.Config); !ok {
+			t.Fatalf("unexpected wrapped config type: %T", w.Config)
+		}
+	})
+
+	t.Run("it allows wrappers to be composed", func(t *testing.T) {
+		cfg, err := ParseURL(t.Context(), "test-wrapper+chaos+test-scheme:///silo?error_rate=0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := cfg.(wrapperConfig); !ok {
+			t.Fatalf("unexpected config type: %T", cfg)
+		}
+
+		d, err := cfg.NewDriver(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer d.Close()
+	})
+
+	t.Run("it returns an error if the wrapper is not registered", func(t *testing.T) {
+		_, err := ParseURL(t.Context(), "unknown+memory:///silo")
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+
+		want := `unsupported persistence driver wrapper scheme "unknown"`
+		if err.Error() != want {
+			t.Fatalf("unexpected error: got %q, want %q", err.Error(), want)
+		}
+	})
+
+	t.Run("it panics if the wrapper is already registered", func(t *testing.T) {
+		expectPanic(
+			t,
+			`persistencekit: wrapper scheme "chaos" is already registered`,
+			func() {
+				RegisterWrapperScheme("chaos", func(context.Context, *url.URL, SchemeFunc) (Config, error) {
+					panic("unexpected call")
+				})
+			},
+		)
+	})
+}
+
+func expectPanic(t *testing.T, want string, fn func()) {
+	t.Helper()
+
+	defer func() {
+		t.Helper()
+
+		got, _ := recover().(string)
+		if !strings.HasPrefix(got, want) {
+			t.Fatalf("unexpected panic: got %q, want prefix %q", got, want)
+		}
+	}()
+
+	fn()
+}
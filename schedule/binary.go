@@ -0,0 +1,14 @@
+package schedule
+
+// BinaryStore is a collection of schedules that contain items with opaque
+// binary values.
+type BinaryStore = Store[[]byte]
+
+// A BinarySchedule is a schedule of items with opaque binary values.
+type BinarySchedule = Schedule[[]byte]
+
+// A BinaryItem is an item within a [BinarySchedule].
+type BinaryItem = Item[[]byte]
+
+// BinaryRangeFunc is used to iterate over the due items in a [BinarySchedule].
+type BinaryRangeFunc = RangeFunc[[]byte]
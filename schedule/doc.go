@@ -0,0 +1,8 @@
+// Package schedule provides an abstraction of a persisted collection of items
+// that become due at a specific time, such as timeout messages.
+//
+// Due items are read in the order of their due time. Consumers that share a
+// schedule coordinate by claiming each due item before processing it, which
+// hides the item from other consumers until the claim expires. Once the item
+// has been processed it is completed, removing it from the schedule.
+package schedule
@@ -0,0 +1,91 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/marshaler"
+)
+
+// NewMarshalingStore returns a new [Store] that marshals/unmarshals values of
+// type T to/from an underlying [BinaryStore].
+func NewMarshalingStore[T any](
+	s BinaryStore,
+	m marshaler.Marshaler[T],
+) Store[T] {
+	return &mstore[T]{s, m}
+}
+
+// mstore is an implementation of [Store] that marshals/unmarshals values to/from
+// an underlying [BinaryStore].
+type mstore[T any] struct {
+	BinaryStore
+	m marshaler.Marshaler[T]
+}
+
+func (s *mstore[T]) Open(ctx context.Context, name string) (Schedule[T], error) {
+	sched, err := s.BinaryStore.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mschedule[T]{sched, s.m}, nil
+}
+
+// mschedule is an implementation of [Schedule] that marshals/unmarshals values
+// to/from an underlying [BinarySchedule].
+type mschedule[T any] struct {
+	BinarySchedule
+	m marshaler.Marshaler[T]
+}
+
+func (s *mschedule[T]) Schedule(ctx context.Context, id string, dueAt time.Time, v T) error {
+	data, err := s.m.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return s.BinarySchedule.Schedule(ctx, id, dueAt, data)
+}
+
+func (s *mschedule[T]) RangeDue(ctx context.Context, t time.Time, fn RangeFunc[T]) error {
+	return s.BinarySchedule.RangeDue(
+		ctx,
+		t,
+		func(ctx context.Context, item BinaryItem) (bool, error) {
+			v, err := s.m.Unmarshal(item.Value)
+			if err != nil {
+				return false, err
+			}
+
+			return fn(ctx, Item[T]{
+				ID:    item.ID,
+				DueAt: item.DueAt,
+				Value: v,
+			})
+		},
+	)
+}
+
+func (s *mschedule[T]) Claim(ctx context.Context, item Item[T], timeout time.Duration) (Item[T], bool, error) {
+	claimed, ok, err := s.BinarySchedule.Claim(ctx, withoutValue(item), timeout)
+	if !ok || err != nil {
+		return Item[T]{}, false, err
+	}
+
+	item.DueAt = claimed.DueAt
+	return item, true, nil
+}
+
+func (s *mschedule[T]) Complete(ctx context.Context, item Item[T]) (bool, error) {
+	return s.BinarySchedule.Complete(ctx, withoutValue(item))
+}
+
+// withoutValue returns a [BinaryItem] that identifies the same item as item.
+// Its value is nil, as it is not used to identify the item.
+func withoutValue[T any](item Item[T]) BinaryItem {
+	return BinaryItem{
+		ID:    item.ID,
+		DueAt: item.DueAt,
+	}
+}
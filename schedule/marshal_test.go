@@ -0,0 +1,77 @@
+package schedule_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/driver/memory/memoryschedule"
+	"github.com/dogmatiq/persistencekit/marshaler"
+	. "github.com/dogmatiq/persistencekit/schedule"
+)
+
+func TestNewMarshalingStore(t *testing.T) {
+	store := NewMarshalingStore(
+		&memoryschedule.BinaryStore{},
+		marshaler.NewJSON[int](),
+	)
+
+	s, err := store.Open(t.Context(), "<name>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	dueAt := time.Now().Add(-time.Hour)
+
+	for v := range 3 {
+		if err := s.Schedule(
+			t.Context(),
+			string(rune('a'+v)),
+			dueAt.Add(time.Duration(v)*time.Second),
+			v,
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := 0
+	if err := s.RangeDue(
+		t.Context(),
+		time.Now(),
+		func(ctx context.Context, item Item[int]) (bool, error) {
+			if item.Value != want {
+				t.Fatalf("unexpected value: got %d, want %d", item.Value, want)
+			}
+			want++
+
+			claimed, ok, err := s.Claim(ctx, item, time.Minute)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				t.Fatalf("expected %q to be claimed", item.ID)
+			}
+
+			if claimed.Value != item.Value {
+				t.Fatalf("unexpected claimed value: got %d, want %d", claimed.Value, item.Value)
+			}
+
+			ok, err = s.Complete(ctx, claimed)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				t.Fatalf("expected %q to be completed", item.ID)
+			}
+
+			return true, nil
+		},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if want != 3 {
+		t.Fatalf("unexpected number of due items: got %d, want 3", want)
+	}
+}
@@ -0,0 +1,70 @@
+package schedule
+
+import (
+	"context"
+	"time"
+)
+
+// Item is an item within a [Schedule].
+type Item[T any] struct {
+	// ID uniquely identifies the item within its schedule.
+	ID string
+
+	// DueAt is the time at which the item becomes due.
+	//
+	// It is stored with millisecond precision; items returned by the schedule
+	// always have a due time that is a whole number of milliseconds.
+	DueAt time.Time
+
+	// Value is the content of the item.
+	Value T
+}
+
+// RangeFunc is used to iterate over the due items in a [Schedule].
+//
+// If it returns false, ranging stops and no error is returned.
+type RangeFunc[T any] func(context.Context, Item[T]) (ok bool, err error)
+
+// Schedule is a persisted collection of items with values of type T, each of
+// which becomes due at a specific time.
+type Schedule[T any] interface {
+	// Name returns the name of the schedule.
+	Name() string
+
+	// Schedule adds an item with the given ID that becomes due at dueAt.
+	//
+	// If the schedule already contains an item with the same ID, it is
+	// replaced, including if it has been claimed.
+	Schedule(ctx context.Context, id string, dueAt time.Time, v T) error
+
+	// Cancel removes the item with the given ID from the schedule. It is not an
+	// error to cancel an item that does not exist.
+	Cancel(ctx context.Context, id string) error
+
+	// RangeDue calls fn for each item that is due at or before t, in order of
+	// their due time. Items that are due at the same time are visited in an
+	// unspecified order.
+	//
+	// fn may call Claim, Complete, Schedule and Cancel on the same schedule.
+	// Items that are changed during ranging may or may not be visited.
+	RangeDue(ctx context.Context, t time.Time, fn RangeFunc[T]) error
+
+	// Claim hides item from other consumers until the given timeout elapses,
+	// by changing its due time.
+	//
+	// It returns a copy of item with the new due time. ok is false if the item
+	// is not yet due, or has since been claimed by another consumer,
+	// rescheduled, completed or cancelled.
+	//
+	// Items are identified by their ID and due time; their value is ignored.
+	Claim(ctx context.Context, item Item[T], timeout time.Duration) (claimed Item[T], ok bool, err error)
+
+	// Complete removes item from the schedule once it has been processed.
+	//
+	// ok is false if the item has since been claimed again, rescheduled,
+	// completed or cancelled, in which case it is not removed.
+	Complete(ctx context.Context, item Item[T]) (ok bool, err error)
+
+	// Close closes the schedule.
+	Close() error
+}
@@ -0,0 +1,15 @@
+package schedule
+
+import (
+	"context"
+)
+
+// Store is a collection of schedules that contain items with values of type T.
+type Store[T any] interface {
+	// Open returns the schedule with the given name.
+	Open(ctx context.Context, name string) (Schedule[T], error)
+
+	// Provision creates the infrastructure used by the store if it does not
+	// already exist.
+	Provision(ctx context.Context) error
+}
@@ -0,0 +1,277 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"github.com/dogmatiq/enginekit/telemetry"
+	"github.com/dogmatiq/persistencekit/internal/x/xtelemetry"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithTelemetry returns a [BinaryStore] that adds telemetry to s.
+func WithTelemetry(
+	s BinaryStore,
+	p trace.TracerProvider,
+	m metric.MeterProvider,
+	l log.LoggerProvider,
+) BinaryStore {
+	return &instrumentedStore{
+		Next: s,
+		Telemetry: telemetry.Provider{
+			TracerProvider: p,
+			MeterProvider:  m,
+			LoggerProvider: l,
+		},
+	}
+}
+
+// instrumentedStore is a decorator that adds instrumentation to a [BinaryStore].
+type instrumentedStore struct {
+	Next      BinaryStore
+	Telemetry telemetry.Provider
+}
+
+func (s *instrumentedStore) Provision(ctx context.Context) error {
+	return s.Next.Provision(ctx)
+}
+
+// Open returns the schedule with the given name.
+func (s *instrumentedStore) Open(ctx context.Context, name string) (BinarySchedule, error) {
+	telem := s.Telemetry.Recorder(
+		"github.com/dogmatiq/persistencekit/schedule",
+		telemetry.Type("schedule.store", s.Next),
+		telemetry.String("schedule.name", name),
+		telemetry.String("schedule.handle", xtelemetry.HandleID()),
+	)
+
+	sched := &instrumentedSchedule{
+		Telemetry:     telem,
+		OpenSchedules: telem.UpDownCounter("persistence.schedule.open_schedules", "{schedule}", "The number of schedules that are currently open."),
+		Claims:        telem.Counter("persistence.schedule.claims", "{item}", "The number of items that have been claimed."),
+		LostClaims:    telem.Counter("persistence.schedule.lost_claims", "{item}", "The number of times an item could not be claimed or completed because it had changed."),
+		ValueIO:       telem.Counter("persistence.schedule.value.io", "By", "The cumulative size of the item values that have been operated upon."),
+		ValueSize:     telem.Histogram("persistence.schedule.value.size", "By", "The sizes of the item values that have been operated upon."),
+	}
+
+	ctx, span := telem.StartSpan(ctx, "schedule.open")
+	defer span.End()
+
+	next, err := s.Next.Open(ctx, name)
+	if err != nil {
+		telem.Error(ctx, "schedule.open.error", "unable to open schedule", err)
+		return nil, err
+	}
+
+	sched.Next = next
+
+	sched.OpenSchedules(ctx, 1)
+	sched.Telemetry.Info(ctx, "schedule.open.ok", "opened schedule")
+
+	return sched, nil
+}
+
+type instrumentedSchedule struct {
+	Next      BinarySchedule
+	Telemetry *telemetry.Recorder
+
+	OpenSchedules telemetry.Instrument[int64]
+	Claims        telemetry.Instrument[int64]
+	LostClaims    telemetry.Instrument[int64]
+	ValueIO       telemetry.Instrument[int64]
+	ValueSize     telemetry.Instrument[int64]
+}
+
+func (s *instrumentedSchedule) Name() string {
+	return s.Next.Name()
+}
+
+func (s *instrumentedSchedule) Schedule(ctx context.Context, id string, dueAt time.Time, v []byte) error {
+	size := int64(len(v))
+
+	ctx, span := s.Telemetry.StartSpan(
+		ctx,
+		"schedule.schedule",
+		telemetry.String("item_id", id),
+		telemetry.Time("due_at", dueAt),
+		telemetry.Int("value_size", size),
+	)
+	defer span.End()
+
+	s.ValueIO(ctx, size, telemetry.WriteDirection)
+	s.ValueSize(ctx, size, telemetry.WriteDirection)
+
+	if err := s.Next.Schedule(ctx, id, dueAt, v); err != nil {
+		s.Telemetry.Error(ctx, "schedule.schedule.error", "unable to schedule item", err)
+		return err
+	}
+
+	s.Telemetry.Info(ctx, "schedule.schedule.ok", "scheduled item")
+
+	return nil
+}
+
+func (s *instrumentedSchedule) Cancel(ctx context.Context, id string) error {
+	ctx, span := s.Telemetry.StartSpan(
+		ctx,
+		"schedule.cancel",
+		telemetry.String("item_id", id),
+	)
+	defer span.End()
+
+	if err := s.Next.Cancel(ctx, id); err != nil {
+		s.Telemetry.Error(ctx, "schedule.cancel.error", "unable to cancel item", err)
+		return err
+	}
+
+	s.Telemetry.Info(ctx, "schedule.cancel.ok", "cancelled item")
+
+	return nil
+}
+
+func (s *instrumentedSchedule) RangeDue(ctx context.Context, t time.Time, fn BinaryRangeFunc) error {
+	ctx, span := s.Telemetry.StartSpan(
+		ctx,
+		"schedule.range-due",
+		telemetry.Time("due_before", t),
+	)
+	defer span.End()
+
+	var (
+		count     int64
+		totalSize int64
+		brokeLoop bool
+	)
+
+	s.Telemetry.Info(ctx, "schedule.range-due.start", "reading due items")
+
+	err := s.Next.RangeDue(
+		ctx,
+		t,
+		func(ctx context.Context, item BinaryItem) (bool, error) {
+			count++
+
+			size := int64(len(item.Value))
+			totalSize += size
+
+			s.ValueIO(ctx, size, telemetry.ReadDirection)
+			s.ValueSize(ctx, size, telemetry.ReadDirection)
+
+			ok, err := fn(ctx, item)
+			if ok || err != nil {
+				return ok, err
+			}
+
+			brokeLoop = true
+			return false, nil
+		},
+	)
+
+	span.SetAttributes(
+		telemetry.Int("items_read", count),
+		telemetry.Int("bytes_read", totalSize),
+		telemetry.Bool("reached_end", !brokeLoop && err == nil),
+	)
+
+	if err != nil {
+		s.Telemetry.Error(ctx, "schedule.range-due.error", "unable to range over due items", err)
+		return err
+	}
+
+	if brokeLoop {
+		s.Telemetry.Info(ctx, "schedule.range-due.break", "range aborted cleanly before visiting all due items")
+	} else {
+		s.Telemetry.Info(ctx, "schedule.range-due.end", "range visited all due items")
+	}
+
+	return nil
+}
+
+func (s *instrumentedSchedule) Claim(ctx context.Context, item BinaryItem, timeout time.Duration) (BinaryItem, bool, error) {
+	ctx, span := s.Telemetry.StartSpan(
+		ctx,
+		"schedule.claim",
+		telemetry.String("item_id", item.ID),
+		telemetry.Duration("claim_timeout", timeout),
+	)
+	defer span.End()
+
+	claimed, ok, err := s.Next.Claim(ctx, item, timeout)
+	if err != nil {
+		s.Telemetry.Error(ctx, "schedule.claim.error", "unable to claim item", err)
+		return BinaryItem{}, false, err
+	}
+
+	span.SetAttributes(
+		telemetry.Bool("claimed", ok),
+	)
+
+	if !ok {
+		s.LostClaims(ctx, 1)
+		s.Telemetry.Info(ctx, "schedule.claim.lost", "item has changed since it was read")
+		return BinaryItem{}, false, nil
+	}
+
+	s.Claims(ctx, 1)
+	s.Telemetry.Info(ctx, "schedule.claim.ok", "claimed item")
+
+	return claimed, true, nil
+}
+
+func (s *instrumentedSchedule) Complete(ctx context.Context, item BinaryItem) (bool, error) {
+	ctx, span := s.Telemetry.StartSpan(
+		ctx,
+		"schedule.complete",
+		telemetry.String("item_id", item.ID),
+	)
+	defer span.End()
+
+	ok, err := s.Next.Complete(ctx, item)
+	if err != nil {
+		s.Telemetry.Error(ctx, "schedule.complete.error", "unable to complete item", err)
+		return false, err
+	}
+
+	span.SetAttributes(
+		telemetry.Bool("completed", ok),
+	)
+
+	if !ok {
+		s.LostClaims(ctx, 1)
+		s.Telemetry.Info(ctx, "schedule.complete.lost", "item has changed since it was claimed")
+		return false, nil
+	}
+
+	s.Telemetry.Info(ctx, "schedule.complete.ok", "completed item")
+
+	return true, nil
+}
+
+func (s *instrumentedSchedule) Close() error {
+	if s.Next == nil {
+		// If the resource has already been closed don't do anything at all,
+		// even log a warning, because we want to allow the caller to defer
+		// closing for safety _and_ close explicitly elsewhere for error
+		// checking.
+		return nil
+	}
+
+	ctx, span := s.Telemetry.StartSpan(context.Background(), "schedule.close")
+	defer span.End()
+
+	defer func() {
+		s.Next = nil
+		s.OpenSchedules(ctx, -1)
+	}()
+
+	if err := s.Next.Close(); err != nil {
+		s.Telemetry.Error(ctx, "schedule.close.error", "unable to close schedule cleanly", err)
+		return err
+	}
+
+	s.Telemetry.Info(ctx, "schedule.close.ok", "closed schedule")
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package schedule_test
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/driver/memory/memoryschedule"
+	. "github.com/dogmatiq/persistencekit/schedule"
+	nooplog "go.opentelemetry.io/otel/log/noop"
+	noopmetric "go.opentelemetry.io/otel/metric/noop"
+	nooptrace "go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestWithTelemetry(t *testing.T) {
+	RunTests(
+		t,
+		WithTelemetry(
+			&memoryschedule.BinaryStore{},
+			nooptrace.NewTracerProvider(),
+			noopmetric.NewMeterProvider(),
+			nooplog.NewLoggerProvider(),
+		),
+	)
+}
@@ -0,0 +1,577 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+)
+
+// RunTests runs tests that confirm a [BinaryStore] implementation behaves
+// correctly.
+func RunTests(
+	t *testing.T,
+	store BinaryStore,
+) {
+	const (
+		// timeout is a claim timeout that does not elapse during a test.
+		timeout = time.Minute
+
+		// shortTimeout is a claim timeout that is allowed to elapse during a
+		// test.
+		shortTimeout = 100 * time.Millisecond
+	)
+
+	open := func(t *testing.T, name string) BinarySchedule {
+		t.Helper()
+
+		s, err := store.Open(t.Context(), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() {
+			if err := s.Close(); err != nil {
+				t.Error(err)
+			}
+		})
+
+		if s.Name() != name {
+			t.Fatalf("unexpected schedule name: got %q, want %q", s.Name(), name)
+		}
+
+		return s
+	}
+
+	setup := func(t *testing.T) BinarySchedule {
+		return open(t, xtesting.SequentialName("schedule"))
+	}
+
+	// epoch is a due time in the past, such that items scheduled relative to
+	// it are already due.
+	epoch := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	schedule := func(t *testing.T, s BinarySchedule, id string, dueAt time.Time, v string) {
+		t.Helper()
+
+		if err := s.Schedule(t.Context(), id, dueAt, []byte(v)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	due := func(t *testing.T, s BinarySchedule, at time.Time) []BinaryItem {
+		t.Helper()
+
+		var items []BinaryItem
+		if err := s.RangeDue(
+			t.Context(),
+			at,
+			func(_ context.Context, item BinaryItem) (bool, error) {
+				items = append(items, item)
+				return true, nil
+			},
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		return items
+	}
+
+	expectItems := func(t *testing.T, got []BinaryItem, want ...BinaryItem) {
+		t.Helper()
+
+		if len(got) != len(want) {
+			t.Fatalf("unexpected number of due items: got %d, want %d", len(got), len(want))
+		}
+
+		for i, w := range want {
+			g := got[i]
+
+			if g.ID != w.ID {
+				t.Fatalf("unexpected ID at index %d: got %q, want %q", i, g.ID, w.ID)
+			}
+
+			if !g.DueAt.Equal(w.DueAt) {
+				t.Fatalf("unexpected due time for %q: got %s, want %s", g.ID, g.DueAt, w.DueAt)
+			}
+
+			if !bytes.Equal(g.Value, w.Value) {
+				t.Fatalf("unexpected value for %q: got %q, want %q", g.ID, g.Value, w.Value)
+			}
+		}
+	}
+
+	item := func(id string, dueAt time.Time, v string) BinaryItem {
+		return BinaryItem{
+			ID:    id,
+			DueAt: dueAt,
+			Value: []byte(v),
+		}
+	}
+
+	claim := func(t *testing.T, s BinarySchedule, item BinaryItem, timeout time.Duration) BinaryItem {
+		t.Helper()
+
+		claimed, ok, err := s.Claim(t.Context(), item, timeout)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatalf("expected %q to be claimed", item.ID)
+		}
+
+		return claimed
+	}
+
+	expectNotClaimed := func(t *testing.T, s BinarySchedule, item BinaryItem) {
+		t.Helper()
+
+		_, ok, err := s.Claim(t.Context(), item, timeout)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Fatalf("did not expect %q to be claimed", item.ID)
+		}
+	}
+
+	expire := func() {
+		time.Sleep(2 * shortTimeout)
+	}
+
+	t.Run("Store", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("Open", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("allows schedules to be opened multiple times", func(t *testing.T) {
+				t.Parallel()
+
+				name := xtesting.SequentialName("schedule")
+				s1 := open(t, name)
+				s2 := open(t, name)
+
+				schedule(t, s1, "<id>", epoch, "<value>")
+				expectItems(
+					t,
+					due(t, s2, time.Now()),
+					item("<id>", epoch, "<value>"),
+				)
+			})
+
+			t.Run("does not share items between schedules with different names", func(t *testing.T) {
+				t.Parallel()
+
+				s1 := setup(t)
+				s2 := setup(t)
+
+				schedule(t, s1, "<id>", epoch, "<value>")
+				expectItems(t, due(t, s2, time.Now()))
+			})
+		})
+	})
+
+	t.Run("Schedule", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("RangeDue", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it does not visit any items if the schedule is empty", func(t *testing.T) {
+				t.Parallel()
+
+				s := setup(t)
+				expectItems(t, due(t, s, time.Now()))
+			})
+
+			t.Run("it visits due items in order of their due time", func(t *testing.T) {
+				t.Parallel()
+
+				s := setup(t)
+				schedule(t, s, "<id-2>", epoch.Add(2*time.Second), "<value-2>")
+				schedule(t, s, "<id-3>", epoch.Add(3*time.Second), "<value-3>")
+				schedule(t, s, "<id-1>", epoch.Add(1*time.Second), "<value-1>")
+
+				expectItems(
+					t,
+					due(t, s, time.Now()),
+					item("<id-1>", epoch.Add(1*time.Second), "<value-1>"),
+					item("<id-2>", epoch.Add(2*time.Second), "<value-2>"),
+					item("<id-3>", epoch.Add(3*time.Second), "<value-3>"),
+				)
+			})
+
+			t.Run("it visits items that are due at exactly the given time", func(t *testing.T) {
+				t.Parallel()
+
+				s := setup(t)
+				schedule(t, s, "<id>", epoch, "<value>")
+
+				expectItems(
+					t,
+					due(t, s, epoch),
+					item("<id>", epoch, "<value>"),
+				)
+			})
+
+			t.Run("it does not visit items that are due after the given time", func(t *testing.T) {
+				t.Parallel()
+
+				s := setup(t)
+				schedule(t, s, "<due>", epoch, "<value-1>")
+				schedule(t, s, "<not-due>", epoch.Add(time.Millisecond), "<value-2>")
+				schedule(t, s, "<future>", time.Now().Add(time.Hour), "<value-3>")
+
+				expectItems(
+					t,
+					due(t, s, epoch),
+					item("<due>", epoch, "<value-1>"),
+				)
+			})
+
+			t.Run("it stores due times with millisecond precision", func(t *testing.T) {
+				t.Parallel()
+
+				s := setup(t)
+				schedule(t, s, "<id>", epoch.Add(1234567*time.Nanosecond), "<value>")
+
+				expectItems(
+					t,
+					due(t, s, time.Now()),
+					item("<id>", epoch.Add(time.Millisecond), "<value>"),
+				)
+			})
+
+			t.Run("it stops ranging if the function returns false", func(t *testing.T) {
+				t.Parallel()
+
+				s := setup(t)
+				schedule(t, s, "<id-1>", epoch, "<value-1>")
+				schedule(t, s, "<id-2>", epoch.Add(time.Second), "<value-2>")
+
+				called := 0
+				if err := s.RangeDue(
+					t.Context(),
+					time.Now(),
+					func(context.Context, BinaryItem) (bool, error) {
+						called++
+						return false, nil
+					},
+				); err != nil {
+					t.Fatal(err)
+				}
+
+				if called != 1 {
+					t.Fatalf("unexpected number of calls: got %d, want 1", called)
+				}
+			})
+
+			t.Run("it allows items to be claimed and completed while ranging", func(t *testing.T) {
+				t.Parallel()
+
+				s := setup(t)
+				for n := range 3 {
+					schedule(t, s, fmt.Sprintf("<id-%d>", n), epoch.Add(time.Duration(n)*time.Second), "<value>")
+				}
+
+				if err := s.RangeDue(
+					t.Context(),
+					time.Now(),
+					func(ctx context.Context, item BinaryItem) (bool, error) {
+						claimed, ok, err := s.Claim(ctx, item, timeout)
+						if !ok || err != nil {
+							return false, err
+						}
+
+						_, err = s.Complete(ctx, claimed)
+						return true, err
+					},
+				); err != nil {
+					t.Fatal(err)
+				}
+
+				expectItems(t, due(t, s, time.Now().Add(2*timeout)))
+			})
+		})
+
+		t.Run("Schedule", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it replaces an existing item with the same ID", func(t *testing.T) {
+				t.Parallel()
+
+				s := setup(t)
+				schedule(t, s, "<id>", epoch, "<value-1>")
+				schedule(t, s, "<id>", epoch.Add(time.Second), "<value-2>")
+
+				expectItems(
+					t,
+					due(t, s, time.Now()),
+					item("<id>", epoch.Add(time.Second), "<value-2>"),
+				)
+			})
+
+			t.Run("it replaces a claimed item", func(t *testing.T) {
+				t.Parallel()
+
+				s := setup(t)
+				schedule(t, s, "<id>", epoch, "<value-1>")
+				claimed := claim(t, s, item("<id>", epoch, "<value-1>"), timeout)
+
+				schedule(t, s, "<id>", epoch, "<value-2>")
+
+				expectItems(
+					t,
+					due(t, s, time.Now()),
+					item("<id>", epoch, "<value-2>"),
+				)
+
+				ok, err := s.Complete(t.Context(), claimed)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if ok {
+					t.Fatal("did not expect the replaced claim to be completed")
+				}
+			})
+		})
+
+		t.Run("Cancel", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it removes the item from the schedule", func(t *testing.T) {
+				t.Parallel()
+
+				s := setup(t)
+				schedule(t, s, "<id-1>", epoch, "<value-1>")
+				schedule(t, s, "<id-2>", epoch, "<value-2>")
+
+				if err := s.Cancel(t.Context(), "<id-1>"); err != nil {
+					t.Fatal(err)
+				}
+
+				expectItems(
+					t,
+					due(t, s, time.Now()),
+					item("<id-2>", epoch, "<value-2>"),
+				)
+			})
+
+			t.Run("it does not return an error if the item does not exist", func(t *testing.T) {
+				t.Parallel()
+
+				s := setup(t)
+
+				for range 2 {
+					if err := s.Cancel(t.Context(), "<id>"); err != nil {
+						t.Fatal(err)
+					}
+				}
+			})
+
+			t.Run("it allows an item with the same ID to be scheduled again", func(t *testing.T) {
+				t.Parallel()
+
+				s := setup(t)
+				schedule(t, s, "<id>", epoch, "<value-1>")
+
+				if err := s.Cancel(t.Context(), "<id>"); err != nil {
+					t.Fatal(err)
+				}
+
+				schedule(t, s, "<id>", epoch, "<value-2>")
+
+				expectItems(
+					t,
+					due(t, s, time.Now()),
+					item("<id>", epoch, "<value-2>"),
+				)
+			})
+		})
+
+		t.Run("Claim", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it hides the item until the claim timeout elapses", func(t *testing.T) {
+				t.Parallel()
+
+				s := setup(t)
+				schedule(t, s, "<id>", epoch, "<value>")
+
+				claimed := claim(t, s, item("<id>", epoch, "<value>"), shortTimeout)
+
+				if claimed.ID != "<id>" || !bytes.Equal(claimed.Value, []byte("<value>")) {
+					t.Fatalf("unexpected claimed item: %+v", claimed)
+				}
+
+				if !claimed.DueAt.After(time.Now()) {
+					t.Fatalf("expected the claimed item to be due in the future, got %s", claimed.DueAt)
+				}
+
+				expectItems(t, due(t, s, time.Now()))
+
+				expire()
+
+				expectItems(
+					t,
+					due(t, s, time.Now()),
+					claimed,
+				)
+			})
+
+			t.Run("it returns false if the item has already been claimed", func(t *testing.T) {
+				t.Parallel()
+
+				s := setup(t)
+				schedule(t, s, "<id>", epoch, "<value>")
+
+				claim(t, s, item("<id>", epoch, "<value>"), timeout)
+				expectNotClaimed(t, s, item("<id>", epoch, "<value>"))
+			})
+
+			t.Run("it returns false if the item has been rescheduled", func(t *testing.T) {
+				t.Parallel()
+
+				s := setup(t)
+				schedule(t, s, "<id>", epoch, "<value>")
+				schedule(t, s, "<id>", epoch.Add(time.Second), "<value>")
+
+				expectNotClaimed(t, s, item("<id>", epoch, "<value>"))
+			})
+
+			t.Run("it returns false if the item has been cancelled", func(t *testing.T) {
+				t.Parallel()
+
+				s := setup(t)
+				schedule(t, s, "<id>", epoch, "<value>")
+
+				if err := s.Cancel(t.Context(), "<id>"); err != nil {
+					t.Fatal(err)
+				}
+
+				expectNotClaimed(t, s, item("<id>", epoch, "<value>"))
+			})
+
+			t.Run("it grants the claim to exactly one of many concurrent consumers", func(t *testing.T) {
+				t.Parallel()
+
+				name := xtesting.SequentialName("schedule")
+				schedule(t, open(t, name), "<id>", epoch, "<value>")
+
+				var (
+					g      sync.WaitGroup
+					m      sync.Mutex
+					claims int
+				)
+
+				for range 10 {
+					g.Go(func() {
+						// Each consumer uses its own handle, as it would if
+						// each were a separate process.
+						s, err := store.Open(t.Context(), name)
+						if err != nil {
+							t.Error(err)
+							return
+						}
+						defer s.Close()
+
+						_, ok, err := s.Claim(t.Context(), item("<id>", epoch, "<value>"), timeout)
+						if err != nil {
+							t.Error(err)
+							return
+						}
+
+						if ok {
+							m.Lock()
+							claims++
+							m.Unlock()
+						}
+					})
+				}
+
+				g.Wait()
+
+				if claims != 1 {
+					t.Fatalf("expected exactly one consumer to claim the item, got %d", claims)
+				}
+			})
+		})
+
+		t.Run("Complete", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it removes the item from the schedule", func(t *testing.T) {
+				t.Parallel()
+
+				s := setup(t)
+				schedule(t, s, "<id>", epoch, "<value>")
+
+				claimed := claim(t, s, item("<id>", epoch, "<value>"), shortTimeout)
+
+				ok, err := s.Complete(t.Context(), claimed)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !ok {
+					t.Fatal("expected the item to be completed")
+				}
+
+				expire()
+				expectItems(t, due(t, s, time.Now()))
+			})
+
+			t.Run("it returns false if the item has been claimed again", func(t *testing.T) {
+				t.Parallel()
+
+				s := setup(t)
+				schedule(t, s, "<id>", epoch, "<value>")
+
+				claimed := claim(t, s, item("<id>", epoch, "<value>"), shortTimeout)
+				expire()
+				reclaimed := claim(t, s, claimed, timeout)
+
+				ok, err := s.Complete(t.Context(), claimed)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if ok {
+					t.Fatal("did not expect the stale claim to be completed")
+				}
+
+				ok, err = s.Complete(t.Context(), reclaimed)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !ok {
+					t.Fatal("expected the current claim to be completed")
+				}
+			})
+
+			t.Run("it returns false if the item has been cancelled", func(t *testing.T) {
+				t.Parallel()
+
+				s := setup(t)
+				schedule(t, s, "<id>", epoch, "<value>")
+
+				claimed := claim(t, s, item("<id>", epoch, "<value>"), timeout)
+
+				if err := s.Cancel(t.Context(), "<id>"); err != nil {
+					t.Fatal(err)
+				}
+
+				ok, err := s.Complete(t.Context(), claimed)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if ok {
+					t.Fatal("did not expect a cancelled item to be completed")
+				}
+			})
+		})
+	})
+}
@@ -0,0 +1,311 @@
+package set
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Operation is a set-algebra operation.
+type Operation int
+
+const (
+	// UnionOperation produces the values that are members of at least one set.
+	UnionOperation Operation = iota
+
+	// IntersectionOperation produces the values that are members of every set.
+	IntersectionOperation
+
+	// DifferenceOperation produces the values that are members of the first
+	// set, but not of any of the others.
+	DifferenceOperation
+)
+
+func (op Operation) String() string {
+	switch op {
+	case UnionOperation:
+		return "union"
+	case IntersectionOperation:
+		return "intersection"
+	case DifferenceOperation:
+		return "difference"
+	default:
+		return fmt.Sprintf("Operation(%d)", int(op))
+	}
+}
+
+// AlgebraRanger is an interface for sets that can evaluate set-algebra
+// operations natively, when combined with other sets from the same driver.
+type AlgebraRanger[T any] interface {
+	// RangeAlgebra invokes fn for each value produced by applying op to the set
+	// and others, in that order. Each value is visited once, in an undefined
+	// order.
+	//
+	// If the operation can not be performed natively, for example because one
+	// of others is provided by a different driver, handled is false and fn is
+	// not invoked.
+	RangeAlgebra(
+		ctx context.Context,
+		op Operation,
+		others []Set[T],
+		fn RangeFunc[T],
+	) (handled bool, err error)
+}
+
+// Union invokes fn for each value that is a member of at least one of the
+// given sets. Each value is visited once, in an undefined order.
+//
+// If the sets support it, the union is evaluated natively by the driver.
+// Otherwise, the members of each set are checked against the preceding sets,
+// in batches of up to [AlgebraBatchSize] values.
+func Union[T any](ctx context.Context, sets []Set[T], fn RangeFunc[T]) error {
+	if len(sets) == 0 {
+		return nil
+	}
+
+	if handled, err := rangeAlgebra(ctx, sets[0], UnionOperation, sets[1:], fn); handled || err != nil {
+		return err
+	}
+
+	for i, s := range sets {
+		// Skip values that have already been visited because they are members
+		// of a preceding set.
+		ok, err := rangeBatches(
+			ctx,
+			s,
+			func(ctx context.Context, batch []T) (bool, error) {
+				batch, err := retain(ctx, batch, sets[:i], false)
+				if err != nil {
+					return false, err
+				}
+				return visit(ctx, batch, fn)
+			},
+		)
+		if !ok || err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Intersection invokes fn for each value that is a member of every one of the
+// given sets. Each value is visited once, in an undefined order.
+//
+// If the sets support it, the intersection is evaluated natively by the driver.
+// Otherwise, the members of one set are checked against the other sets in
+// batches of up to [AlgebraBatchSize] values. If every set implements
+// [StatsReporter], the set with the fewest members is ranged over; otherwise,
+// the first set is.
+func Intersection[T any](ctx context.Context, sets []Set[T], fn RangeFunc[T]) error {
+	if len(sets) == 0 {
+		return nil
+	}
+
+	if handled, err := rangeAlgebra(ctx, sets[0], IntersectionOperation, sets[1:], fn); handled || err != nil {
+		return err
+	}
+
+	sets, err := smallestFirst(ctx, sets)
+	if err != nil {
+		return err
+	}
+
+	_, err = rangeBatches(
+		ctx,
+		sets[0],
+		func(ctx context.Context, batch []T) (bool, error) {
+			batch, err := retain(ctx, batch, sets[1:], true)
+			if err != nil {
+				return false, err
+			}
+			return visit(ctx, batch, fn)
+		},
+	)
+
+	return err
+}
+
+// Difference invokes fn for each value that is a member of s, but not a member
+// of any of others. Each value is visited once, in an undefined order.
+//
+// If the sets support it, the difference is evaluated natively by the driver.
+// Otherwise, the members of s are checked against each of others in batches of
+// up to [AlgebraBatchSize] values.
+func Difference[T any](ctx context.Context, s Set[T], others []Set[T], fn RangeFunc[T]) error {
+	if handled, err := rangeAlgebra(ctx, s, DifferenceOperation, others, fn); handled || err != nil {
+		return err
+	}
+
+	_, err := rangeBatches(
+		ctx,
+		s,
+		func(ctx context.Context, batch []T) (bool, error) {
+			batch, err := retain(ctx, batch, others, false)
+			if err != nil {
+				return false, err
+			}
+			return visit(ctx, batch, fn)
+		},
+	)
+
+	return err
+}
+
+// AddTo returns a [RangeFunc] that adds each value it is invoked with to dst.
+//
+// It can be used to materialize the result of a set-algebra operation, such as
+// [Union], [Intersection] or [Difference], into another set.
+func AddTo[T any](dst Set[T]) RangeFunc[T] {
+	return func(ctx context.Context, v T) (bool, error) {
+		return true, dst.Add(ctx, v)
+	}
+}
+
+// rangeAlgebra performs op natively, if s implements [AlgebraRanger].
+func rangeAlgebra[T any](
+	ctx context.Context,
+	s Set[T],
+	op Operation,
+	others []Set[T],
+	fn RangeFunc[T],
+) (bool, error) {
+	if r, ok := s.(AlgebraRanger[T]); ok {
+		return r.RangeAlgebra(ctx, op, others, fn)
+	}
+	return false, nil
+}
+
+// unwrapAll returns the sets that underlie each of sets, as determined by
+// unwrap. ok is false if any of the sets can not be unwrapped.
+func unwrapAll[T, U any](
+	sets []Set[T],
+	unwrap func(Set[T]) (Set[U], bool),
+) (_ []Set[U], ok bool) {
+	result := make([]Set[U], len(sets))
+
+	for i, s := range sets {
+		result[i], ok = unwrap(s)
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return result, true
+}
+
+// AlgebraBatchSize is the maximum number of values that are checked against
+// each set in a single [HasBatch] call when a set-algebra operation can not be
+// evaluated natively.
+const AlgebraBatchSize = 100
+
+// rangeBatches invokes fn with successive batches of the members of s, each
+// containing at most [AlgebraBatchSize] values.
+//
+// The batch passed to fn is only valid until fn returns. ok is false if fn
+// stopped ranging.
+func rangeBatches[T any](
+	ctx context.Context,
+	s Set[T],
+	fn func(ctx context.Context, batch []T) (bool, error),
+) (ok bool, err error) {
+	batch := make([]T, 0, AlgebraBatchSize)
+	ok = true
+
+	if err := s.Range(
+		ctx,
+		func(ctx context.Context, v T) (bool, error) {
+			batch = append(batch, v)
+			if len(batch) < AlgebraBatchSize {
+				return true, nil
+			}
+
+			ok, err = fn(ctx, batch)
+			batch = batch[:0]
+			return ok, err
+		},
+	); err != nil || !ok {
+		return ok, err
+	}
+
+	if len(batch) == 0 {
+		return true, nil
+	}
+
+	return fn(ctx, batch)
+}
+
+// retain returns the values in batch that are members of every one of sets if
+// member is true, or that are not members of any of them if member is false.
+//
+// It modifies batch in place.
+func retain[T any](ctx context.Context, batch []T, sets []Set[T], member bool) ([]T, error) {
+	for _, s := range sets {
+		if len(batch) == 0 {
+			break
+		}
+
+		has, err := HasBatch(ctx, s, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		n := 0
+		for i, v := range batch {
+			if has[i] == member {
+				batch[n] = v
+				n++
+			}
+		}
+		batch = batch[:n]
+	}
+
+	return batch, nil
+}
+
+// visit invokes fn for each value in batch. It returns false if fn stopped
+// ranging.
+func visit[T any](ctx context.Context, batch []T, fn RangeFunc[T]) (bool, error) {
+	for _, v := range batch {
+		if ok, err := fn(ctx, v); !ok || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// smallestFirst returns a copy of sets with the set that has the fewest members
+// moved to the front.
+//
+// Sets are only reordered if every set reports its statistics natively, as
+// ranging over a set to count its members costs more than is saved by choosing
+// the smallest set. Otherwise, sets is returned unchanged.
+func smallestFirst[T any](ctx context.Context, sets []Set[T]) ([]Set[T], error) {
+	if len(sets) < 2 {
+		return sets, nil
+	}
+
+	for _, s := range sets {
+		if !hasNativeStats(s) {
+			return sets, nil
+		}
+	}
+
+	smallest, size := 0, uint64(0)
+
+	for i, s := range sets {
+		stats, err := s.(StatsReporter).Stats(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 || stats.Members < size {
+			smallest, size = i, stats.Members
+		}
+	}
+
+	result := slices.Clone(sets)
+	result[0], result[smallest] = result[smallest], result[0]
+
+	return result, nil
+}
@@ -0,0 +1,38 @@
+package set
+
+import "context"
+
+// BatchHaser is an interface for sets that can check the membership of several
+// values more efficiently than by calling [Set.Has] for each value.
+type BatchHaser[T any] interface {
+	// HasBatch returns a slice containing true at each index i where vs[i] is a
+	// member of the set.
+	HasBatch(ctx context.Context, vs []T) ([]bool, error)
+}
+
+// HasBatch returns a slice containing true at each index i where vs[i] is a
+// member of s.
+//
+// If s implements [BatchHaser] it is used to check all of the values at once,
+// otherwise [Set.Has] is called for each value.
+func HasBatch[T any](ctx context.Context, s Set[T], vs []T) ([]bool, error) {
+	if len(vs) == 0 {
+		return nil, nil
+	}
+
+	if b, ok := s.(BatchHaser[T]); ok {
+		return b.HasBatch(ctx, vs)
+	}
+
+	result := make([]bool, len(vs))
+
+	for i, v := range vs {
+		ok, err := s.Has(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = ok
+	}
+
+	return result, nil
+}
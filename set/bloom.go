@@ -0,0 +1,654 @@
+package set
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dogmatiq/enginekit/telemetry"
+	"github.com/dogmatiq/persistencekit/kv"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// DefaultBloomFilterCapacity is the default number of members that a Bloom
+	// filter is sized to hold.
+	DefaultBloomFilterCapacity = 100_000
+
+	// DefaultBloomFilterFalsePositiveRate is the default target probability of
+	// a Bloom filter reporting that a value may be a member when it is not.
+	DefaultBloomFilterFalsePositiveRate = 0.01
+
+	// DefaultBloomFilterRefreshInterval is the default maximum age of a Bloom
+	// filter before it is rebuilt in the background from the underlying set.
+	DefaultBloomFilterRefreshInterval = 1 * time.Minute
+)
+
+// bloomSnapshotTimeout is the maximum amount of time spent saving a Bloom
+// filter snapshot when the last handle to a set is closed.
+const bloomSnapshotTimeout = 10 * time.Second
+
+// BloomFilterOption is an option that changes the behavior of a store returned
+// by [WithBloomFilter].
+type BloomFilterOption func(*bloomOptions)
+
+type bloomOptions struct {
+	Capacity          int
+	FalsePositiveRate float64
+	RefreshInterval   time.Duration
+	Exclusive         bool
+	Snapshots         kv.BinaryStore
+	Telemetry         telemetry.Provider
+}
+
+// WithBloomFilterCapacity is a [BloomFilterOption] that sizes each Bloom filter
+// to hold n members with a false positive probability of p.
+//
+// The false positive rate increases as the number of members exceeds n.
+func WithBloomFilterCapacity(n int, p float64) BloomFilterOption {
+	if n <= 0 {
+		panic("capacity must be positive")
+	}
+
+	if p <= 0 || p >= 1 {
+		panic("false positive rate must be between 0 and 1 (exclusive)")
+	}
+
+	return func(opts *bloomOptions) {
+		opts.Capacity = n
+		opts.FalsePositiveRate = p
+	}
+}
+
+// WithBloomFilterRefreshInterval is a [BloomFilterOption] that sets the
+// maximum age of each Bloom filter before it is refreshed.
+//
+// When a filter that was last refreshed more than d ago is used, the current
+// members of the underlying set are merged into the filter in the background.
+// Refreshing does not affect the results reported by [Set.Has], which are
+// always confirmed against the underlying set unless access is exclusive, but
+// it keeps the filter, and the metrics derived from it, representative of the
+// set's members.
+func WithBloomFilterRefreshInterval(d time.Duration) BloomFilterOption {
+	if d <= 0 {
+		panic("refresh interval must be positive")
+	}
+
+	return func(opts *bloomOptions) {
+		opts.RefreshInterval = d
+		opts.Exclusive = false
+	}
+}
+
+// WithBloomFilterExclusiveAccess is a [BloomFilterOption] that declares that
+// all modifications to the sets are made via the same store, within the same
+// process.
+//
+// A negative result from the filter is reported without querying the
+// underlying set, and filters are never refreshed. Members added to the sets by
+// any other means may be incorrectly reported as absent.
+//
+// Without exclusive access, the filter can not rule out members added by other
+// processes, so negative results are confirmed against the underlying set.
+func WithBloomFilterExclusiveAccess() BloomFilterOption {
+	return func(opts *bloomOptions) {
+		opts.Exclusive = true
+	}
+}
+
+// WithBloomFilterSnapshots is a [BloomFilterOption] that persists each Bloom
+// filter to a keyspace within s when the last handle to its set is closed, so
+// that it can be loaded instead of re-reading the members of the set the next
+// time it is opened.
+//
+// A snapshot is consumed when it is loaded, such that if the process fails
+// before the filter is saved again, it is rebuilt from the set's members.
+func WithBloomFilterSnapshots(s kv.BinaryStore) BloomFilterOption {
+	return func(opts *bloomOptions) {
+		opts.Snapshots = s
+	}
+}
+
+// WithBloomFilterMeterProvider is a [BloomFilterOption] that records metrics
+// about the effectiveness of each Bloom filter using p.
+//
+// The persistence.set.bloom.lookups counter records the outcome of each call
+// to [Set.Has], from which the filter's false positive rate can be derived.
+// Without exclusive access, it also records the values that the filter reported
+// as absent but that were found in the underlying set, as false negatives.
+func WithBloomFilterMeterProvider(p metric.MeterProvider) BloomFilterOption {
+	return func(opts *bloomOptions) {
+		opts.Telemetry.MeterProvider = p
+	}
+}
+
+// WithBloomFilter returns a [BinaryStore] that maintains an in-memory Bloom
+// filter of the members of each set in s.
+//
+// The filter is built by ranging over the set's members when it is first
+// opened, and is updated by [Set.Add] and [Set.TryAdd]. Removing a member does
+// not update the filter.
+//
+// The filter only answers [Set.Has] without querying the underlying set if
+// [WithBloomFilterExclusiveAccess] is used, in which case a value that is not
+// in the filter is reported as absent. Otherwise, members may have been added
+// to the underlying set by other processes, so every result is confirmed
+// against the underlying set, and the filter is refreshed in the background
+// once it is older than [DefaultBloomFilterRefreshInterval]. See
+// [WithBloomFilterRefreshInterval].
+func WithBloomFilter(s BinaryStore, options ...BloomFilterOption) BinaryStore {
+	opts := bloomOptions{
+		Capacity:          DefaultBloomFilterCapacity,
+		FalsePositiveRate: DefaultBloomFilterFalsePositiveRate,
+		RefreshInterval:   DefaultBloomFilterRefreshInterval,
+	}
+
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	return &bloomStore{
+		Next:    s,
+		options: opts,
+		filters: map[string]*bloomState{},
+		saving:  map[string]chan struct{}{},
+	}
+}
+
+type bloomStore struct {
+	Next    BinaryStore
+	options bloomOptions
+
+	m       sync.Mutex
+	filters map[string]*bloomState
+
+	// saving contains a channel for each set whose snapshot is being saved,
+	// which is closed once the save is complete.
+	saving map[string]chan struct{}
+}
+
+// bloomState is the Bloom filter for a specific set, shared by all open
+// handles to that set.
+type bloomState struct {
+	sync.RWMutex
+	Filter      *bloomFilter
+	RefreshedAt time.Time
+	Refs        int
+
+	// Refreshing is true while the filter is being refreshed in the
+	// background.
+	Refreshing bool
+
+	// ctx is canceled when the last handle to the set is closed, which aborts
+	// any background refresh.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *bloomStore) Provision(ctx context.Context) error {
+	if err := s.Next.Provision(ctx); err != nil {
+		return err
+	}
+
+	if s.options.Snapshots != nil {
+		return s.options.Snapshots.Provision(ctx)
+	}
+
+	return nil
+}
+
+func (s *bloomStore) Capabilities() Capabilities {
+	return CapabilitiesOf(s.Next)
+}
+
+func (s *bloomStore) Open(ctx context.Context, name string) (BinarySet, error) {
+	next, err := s.Next.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := s.acquire(ctx, next)
+	if err != nil {
+		next.Close()
+		return nil, err
+	}
+
+	telem := s.options.Telemetry.Recorder(
+		"github.com/dogmatiq/persistencekit/set",
+		telemetry.String("set.name", name),
+	)
+
+	return &bloomSet{
+		Next:    next,
+		store:   s,
+		state:   state,
+		Lookups: telem.Counter("persistence.set.bloom.lookups", "{lookup}", "The number of membership checks performed using a Bloom filter."),
+	}, nil
+}
+
+// acquire returns the shared filter state for the set, building the filter if
+// this is the first open handle.
+func (s *bloomStore) acquire(ctx context.Context, set BinarySet) (*bloomState, error) {
+	name := set.Name()
+
+	s.m.Lock()
+	state, ok := s.filters[name]
+	if !ok {
+		state = &bloomState{}
+		state.ctx, state.cancel = context.WithCancel(context.Background())
+		s.filters[name] = state
+	}
+	state.Refs++
+	saving := s.saving[name]
+	s.m.Unlock()
+
+	// Wait for any snapshot saved when the set was last closed, so that it is
+	// not overwritten with an older filter after it has been consumed.
+	if saving != nil {
+		select {
+		case <-ctx.Done():
+			s.release(name, state)
+			return nil, ctx.Err()
+		case <-saving:
+		}
+	}
+
+	var err error
+
+	state.Lock()
+	if state.Filter == nil {
+		state.Filter, state.RefreshedAt, err = s.load(ctx, set)
+	}
+	state.Unlock()
+
+	if err != nil {
+		s.release(name, state)
+		return nil, fmt.Errorf("unable to build Bloom filter for the %q set: %w", name, err)
+	}
+
+	return state, nil
+}
+
+// release releases a reference to the shared filter state for the named set.
+// The filter is persisted when the last reference is released.
+func (s *bloomStore) release(name string, state *bloomState) error {
+	s.m.Lock()
+
+	state.Refs--
+	if state.Refs > 0 {
+		s.m.Unlock()
+		return nil
+	}
+
+	delete(s.filters, name)
+	state.cancel()
+
+	if s.options.Snapshots == nil {
+		s.m.Unlock()
+		return nil
+	}
+
+	// Take a copy of the filter while the store's mutex is held, then save it
+	// after the mutex is released. Any attempt to re-open the set waits until
+	// the save is complete.
+	state.RLock()
+	var (
+		data []byte
+		err  error
+	)
+	if state.Filter != nil {
+		data, err = state.Filter.MarshalBinary()
+	}
+	state.RUnlock()
+
+	if data == nil || err != nil {
+		s.m.Unlock()
+		return err
+	}
+
+	done := make(chan struct{})
+	s.saving[name] = done
+	s.m.Unlock()
+
+	defer func() {
+		s.m.Lock()
+		delete(s.saving, name)
+		s.m.Unlock()
+		close(done)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), bloomSnapshotTimeout)
+	defer cancel()
+
+	return s.save(ctx, name, data)
+}
+
+// load returns a Bloom filter containing the members of set, either from a
+// snapshot, or by ranging over its members.
+//
+// It also returns the time at which the filter was last known to contain all
+// of the set's members. The time is zero if the filter was loaded from a
+// snapshot, as the set may have been modified since the snapshot was saved.
+func (s *bloomStore) load(ctx context.Context, set BinarySet) (*bloomFilter, time.Time, error) {
+	f := newBloomFilter(s.options.Capacity, s.options.FalsePositiveRate)
+
+	if s.options.Snapshots != nil {
+		ok, err := s.consume(ctx, set.Name(), f)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		if ok {
+			return f, time.Time{}, nil
+		}
+	}
+
+	now := time.Now()
+
+	if err := set.Range(
+		ctx,
+		func(_ context.Context, v []byte) (bool, error) {
+			f.Add(v)
+			return true, nil
+		},
+	); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return f, now, nil
+}
+
+// refreshIfStale starts refreshing the named set's filter in the background if
+// it was last refreshed more than the refresh interval ago, and is not already
+// being refreshed. Filters are never refreshed if access is exclusive.
+func (s *bloomStore) refreshIfStale(name string, state *bloomState) {
+	if s.options.Exclusive {
+		return
+	}
+
+	state.RLock()
+	stale := !state.Refreshing && time.Since(state.RefreshedAt) >= s.options.RefreshInterval
+	state.RUnlock()
+
+	if !stale {
+		return
+	}
+
+	state.Lock()
+	if state.Refreshing {
+		state.Unlock()
+		return
+	}
+	state.Refreshing = true
+	state.Unlock()
+
+	go s.refresh(name, state)
+}
+
+// refresh merges the current members of the named set into its filter.
+//
+// If the refresh fails, it is attempted again once the refresh interval has
+// elapsed.
+func (s *bloomStore) refresh(name string, state *bloomState) {
+	f := newBloomFilter(s.options.Capacity, s.options.FalsePositiveRate)
+	now := time.Now()
+
+	ok := func() bool {
+		set, err := s.Next.Open(state.ctx, name)
+		if err != nil {
+			return false
+		}
+		defer set.Close()
+
+		return set.Range(
+			state.ctx,
+			func(_ context.Context, v []byte) (bool, error) {
+				f.Add(v)
+				return true, nil
+			},
+		) == nil
+	}()
+
+	state.Lock()
+	defer state.Unlock()
+
+	if ok {
+		state.Filter.Merge(f)
+	}
+
+	state.RefreshedAt = now
+	state.Refreshing = false
+}
+
+// consume loads the snapshot of the named set's filter into f and removes it
+// from the snapshot store. It returns false if there is no usable snapshot.
+func (s *bloomStore) consume(ctx context.Context, name string, f *bloomFilter) (bool, error) {
+	ks, err := s.options.Snapshots.Open(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	defer ks.Close()
+
+	data, r, err := ks.Get(ctx, bloomSnapshotKey)
+	if err != nil || r == "" {
+		return false, err
+	}
+
+	// Remove the snapshot before it is used, so that it is not used again if
+	// the process fails before a new snapshot is saved. If some other process
+	// has already consumed it, fall back to ranging over the set's members.
+	if _, err := ks.Set(ctx, bloomSnapshotKey, nil, r); err != nil {
+		if kv.IsConflict(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	var snapshot bloomFilter
+	if err := snapshot.UnmarshalBinary(data); err != nil || !snapshot.SameShape(f) {
+		// The snapshot is unusable, or was built with different options.
+		return false, nil
+	}
+
+	*f = snapshot
+
+	return true, nil
+}
+
+// save persists data as the snapshot of the named set's filter.
+func (s *bloomStore) save(ctx context.Context, name string, data []byte) error {
+	ks, err := s.options.Snapshots.Open(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer ks.Close()
+
+	if err := ks.SetUnconditional(ctx, bloomSnapshotKey, data); err != nil {
+		return fmt.Errorf("unable to save Bloom filter snapshot for the %q set: %w", name, err)
+	}
+
+	return nil
+}
+
+// bloomSnapshotKey is the key under which a Bloom filter snapshot is stored
+// within the keyspace named after its set.
+var bloomSnapshotKey = []byte("bloom-filter")
+
+type bloomSet struct {
+	Next  BinarySet
+	store *bloomStore
+	state *bloomState
+
+	Lookups telemetry.Instrument[int64]
+}
+
+func (s *bloomSet) Name() string {
+	return s.Next.Name()
+}
+
+func (s *bloomSet) Has(ctx context.Context, v []byte) (bool, error) {
+	mayContain := s.mayContain(v)
+
+	if !mayContain && s.store.options.Exclusive {
+		s.Lookups(ctx, 1, telemetry.String("result", "negative"))
+		return false, nil
+	}
+
+	ok, err := s.Next.Has(ctx, v)
+	if err != nil {
+		return false, err
+	}
+
+	s.recordLookup(ctx, v, mayContain, ok)
+
+	return ok, nil
+}
+
+func (s *bloomSet) HasBatch(ctx context.Context, vs [][]byte) ([]bool, error) {
+	var (
+		candidates [][]byte
+		indices    []int
+		filtered   []bool
+	)
+
+	for i, v := range vs {
+		mayContain := s.mayContain(v)
+
+		if mayContain || !s.store.options.Exclusive {
+			candidates = append(candidates, v)
+			indices = append(indices, i)
+			filtered = append(filtered, mayContain)
+		}
+	}
+
+	result := make([]bool, len(vs))
+
+	if n := len(vs) - len(candidates); n != 0 {
+		s.Lookups(ctx, int64(n), telemetry.String("result", "negative"))
+	}
+
+	if len(candidates) == 0 {
+		return result, nil
+	}
+
+	ok, err := HasBatch(ctx, s.Next, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, index := range indices {
+		result[index] = ok[i]
+		s.recordLookup(ctx, candidates[i], filtered[i], ok[i])
+	}
+
+	return result, nil
+}
+
+// mayContain returns true if v may be a member of the set, according to the
+// filter. It starts refreshing the filter in the background if it is stale.
+func (s *bloomSet) mayContain(v []byte) bool {
+	s.state.RLock()
+	mayContain := s.state.Filter.MayContain(v)
+	s.state.RUnlock()
+
+	s.store.refreshIfStale(s.Next.Name(), s.state)
+
+	return mayContain
+}
+
+// recordLookup records the outcome of a membership check that was confirmed
+// against the underlying set. mayContain is the result reported by the filter
+// and ok is the result reported by the underlying set.
+//
+// If the filter reported a false negative, which is only possible if the
+// member was added by some other process, v is added to the filter.
+func (s *bloomSet) recordLookup(ctx context.Context, v []byte, mayContain, ok bool) {
+	switch {
+	case mayContain && ok:
+		s.Lookups(ctx, 1, telemetry.String("result", "true_positive"))
+	case mayContain:
+		s.Lookups(ctx, 1, telemetry.String("result", "false_positive"))
+	case ok:
+		s.Lookups(ctx, 1, telemetry.String("result", "false_negative"))
+		s.addToFilter(v)
+	default:
+		s.Lookups(ctx, 1, telemetry.String("result", "negative"))
+	}
+}
+
+func (s *bloomSet) Add(ctx context.Context, v []byte) error {
+	s.addToFilter(v)
+	return s.Next.Add(ctx, v)
+}
+
+func (s *bloomSet) TryAdd(ctx context.Context, v []byte) (bool, error) {
+	s.addToFilter(v)
+	return s.Next.TryAdd(ctx, v)
+}
+
+// addToFilter adds v to the filter. It is called before v is added to the
+// underlying set so that the filter never reports a false negative for a
+// member, even if the addition fails.
+func (s *bloomSet) addToFilter(v []byte) {
+	s.state.Lock()
+	s.state.Filter.Add(v)
+	s.state.Unlock()
+}
+
+func (s *bloomSet) Remove(ctx context.Context, v []byte) error {
+	return s.Next.Remove(ctx, v)
+}
+
+func (s *bloomSet) TryRemove(ctx context.Context, v []byte) (bool, error) {
+	return s.Next.TryRemove(ctx, v)
+}
+
+func (s *bloomSet) Range(ctx context.Context, fn BinaryRangeFunc) error {
+	return s.Next.Range(ctx, fn)
+}
+
+func (s *bloomSet) RangeAlgebra(
+	ctx context.Context,
+	op Operation,
+	others []BinarySet,
+	fn BinaryRangeFunc,
+) (bool, error) {
+	next, ok := unwrapAll(
+		others,
+		func(o BinarySet) (BinarySet, bool) {
+			x, ok := o.(*bloomSet)
+			if !ok {
+				return nil, false
+			}
+			return x.Next, true
+		},
+	)
+	if !ok {
+		return false, nil
+	}
+
+	return rangeAlgebra(ctx, s.Next, op, next, fn)
+}
+
+func (s *bloomSet) Stats(ctx context.Context) (Stats, error) {
+	return StatsOf(ctx, s.Next)
+}
+
+func (s *bloomSet) statsSource() any {
+	return s.Next
+}
+
+func (s *bloomSet) Close() error {
+	if s.Next == nil {
+		return nil
+	}
+
+	next := s.Next
+	s.Next = nil
+
+	return errors.Join(
+		s.store.release(next.Name(), s.state),
+		next.Close(),
+	)
+}
@@ -0,0 +1,238 @@
+package set_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/persistencekit/driver/memory/memorykv"
+	"github.com/dogmatiq/persistencekit/driver/memory/memoryset"
+	. "github.com/dogmatiq/persistencekit/set"
+)
+
+func TestWithBloomFilter(t *testing.T) {
+	RunTests(t, WithBloomFilter(&memoryset.BinaryStore{}))
+
+	open := func(t *testing.T, store BinaryStore) BinarySet {
+		t.Helper()
+
+		s, err := store.Open(t.Context(), "<set>")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return s
+	}
+
+	t.Run("it does not report false negatives", func(t *testing.T) {
+		t.Parallel()
+
+		backing := &memoryset.BinaryStore{}
+
+		// Add some members before the filter is built.
+		existing := open(t, backing)
+		defer existing.Close()
+
+		for n := range 500 {
+			if err := existing.Add(t.Context(), fmt.Appendf(nil, "<existing-%d>", n)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		s := open(t, WithBloomFilter(backing, WithBloomFilterCapacity(1000, 0.01)))
+		defer s.Close()
+
+		for n := range 500 {
+			if err := s.Add(t.Context(), fmt.Appendf(nil, "<added-%d>", n)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for _, prefix := range []string{"existing", "added"} {
+			for n := range 500 {
+				v := fmt.Appendf(nil, "<%s-%d>", prefix, n)
+
+				ok, err := s.Has(t.Context(), v)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !ok {
+					t.Fatalf("expected %q to be a member", v)
+				}
+			}
+		}
+	})
+
+	t.Run("it confirms negative results against the underlying set", func(t *testing.T) {
+		t.Parallel()
+
+		backing := &memoryset.BinaryStore{}
+
+		s := open(t, WithBloomFilter(backing))
+		defer s.Close()
+
+		other := open(t, backing)
+		defer other.Close()
+
+		if err := other.Add(t.Context(), []byte("<value>")); err != nil {
+			t.Fatal(err)
+		}
+
+		ok, err := s.Has(t.Context(), []byte("<value>"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !ok {
+			t.Fatal("expected value to be present")
+		}
+
+		batch, err := HasBatch(t.Context(), s, [][]byte{[]byte("<value>"), []byte("<other>")})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !batch[0] || batch[1] {
+			t.Fatalf("unexpected batch result: %v", batch)
+		}
+	})
+
+	t.Run("it refreshes the filter in the background", func(t *testing.T) {
+		t.Parallel()
+
+		backing := &memoryset.BinaryStore{}
+
+		s := open(t, WithBloomFilter(backing, WithBloomFilterRefreshInterval(time.Nanosecond)))
+		defer s.Close()
+
+		other := open(t, backing)
+		defer other.Close()
+
+		if err := other.Add(t.Context(), []byte("<value>")); err != nil {
+			t.Fatal(err)
+		}
+
+		for range 10 {
+			ok, err := s.Has(t.Context(), []byte("<value>"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !ok {
+				t.Fatal("expected value to be present")
+			}
+		}
+	})
+
+	t.Run("it does not refresh the filter if access is exclusive", func(t *testing.T) {
+		t.Parallel()
+
+		backing := &memoryset.BinaryStore{}
+
+		s := open(t, WithBloomFilter(backing, WithBloomFilterExclusiveAccess()))
+		defer s.Close()
+
+		other := open(t, backing)
+		defer other.Close()
+
+		if err := other.Add(t.Context(), []byte("<value>")); err != nil {
+			t.Fatal(err)
+		}
+
+		ok, err := s.Has(t.Context(), []byte("<value>"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ok {
+			t.Fatal("expected the filter to report a negative without querying the set")
+		}
+	})
+
+	t.Run("it shares the filter between handles to the same set", func(t *testing.T) {
+		t.Parallel()
+
+		store := WithBloomFilter(&memoryset.BinaryStore{})
+
+		s1 := open(t, store)
+		defer s1.Close()
+
+		s2 := open(t, store)
+		defer s2.Close()
+
+		if err := s1.Add(t.Context(), []byte("<value>")); err != nil {
+			t.Fatal(err)
+		}
+
+		ok, err := s2.Has(t.Context(), []byte("<value>"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !ok {
+			t.Fatal("expected value to be present")
+		}
+	})
+
+	t.Run("it consumes and saves snapshots", func(t *testing.T) {
+		t.Parallel()
+
+		snapshots := &memorykv.BinaryStore{}
+		store := WithBloomFilter(
+			&memoryset.BinaryStore{},
+			WithBloomFilterSnapshots(snapshots),
+		)
+
+		hasSnapshot := func(t *testing.T) bool {
+			t.Helper()
+
+			ks, err := snapshots.Open(t.Context(), "<set>")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ks.Close()
+
+			ok, err := ks.Has(t.Context(), []byte("bloom-filter"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return ok
+		}
+
+		s := open(t, store)
+		if err := s.Add(t.Context(), []byte("<value>")); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if !hasSnapshot(t) {
+			t.Fatal("expected a snapshot to be saved when the set is closed")
+		}
+
+		s = open(t, store)
+
+		if hasSnapshot(t) {
+			t.Fatal("expected the snapshot to be consumed when the set is opened")
+		}
+
+		ok, err := s.Has(t.Context(), []byte("<value>"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("expected value to be present")
+		}
+
+		if err := s.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if !hasSnapshot(t) {
+			t.Fatal("expected a snapshot to be saved when the set is closed")
+		}
+	})
+}
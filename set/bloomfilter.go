@@ -0,0 +1,151 @@
+package set
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+)
+
+// bloomFilter is a probabilistic set membership structure.
+//
+// It may report that a value is present when it is not, but never reports that
+// a value is absent when it was added.
+type bloomFilter struct {
+	bits   []uint64
+	size   uint64 // number of bits
+	hashes uint64 // number of hash functions
+}
+
+// newBloomFilter returns a Bloom filter sized to hold n values with a false
+// positive probability of p.
+func newBloomFilter(n int, p float64) *bloomFilter {
+	size := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	size = max(size, 64)
+
+	hashes := uint64(math.Round(float64(size) / float64(n) * math.Ln2))
+	hashes = max(hashes, 1)
+
+	return &bloomFilter{
+		bits:   make([]uint64, (size+63)/64),
+		size:   size,
+		hashes: hashes,
+	}
+}
+
+// Add adds v to the filter.
+func (f *bloomFilter) Add(v []byte) {
+	h1, h2 := bloomHash(v)
+	for i := range f.hashes {
+		bit := (h1 + i*h2) % f.size
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// MayContain returns false if v has definitely not been added to the filter.
+func (f *bloomFilter) MayContain(v []byte) bool {
+	h1, h2 := bloomHash(v)
+	for i := range f.hashes {
+		bit := (h1 + i*h2) % f.size
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge adds all of the values in x to f. x must have the same shape as f.
+func (f *bloomFilter) Merge(x *bloomFilter) {
+	if !f.SameShape(x) {
+		panic("cannot merge Bloom filters with different shapes")
+	}
+
+	for i, b := range x.bits {
+		f.bits[i] |= b
+	}
+}
+
+// SameShape returns true if f and x have the same size and number of hash
+// functions.
+func (f *bloomFilter) SameShape(x *bloomFilter) bool {
+	return f.size == x.size && f.hashes == x.hashes
+}
+
+// bloomHash returns the two hash values used to derive the bit positions of v
+// within a filter, using double hashing.
+func bloomHash(v []byte) (uint64, uint64) {
+	h := fnv.New128a()
+	h.Write(v)
+
+	var sum [16]byte
+	h.Sum(sum[:0])
+
+	h1 := binary.BigEndian.Uint64(sum[:8])
+	h2 := binary.BigEndian.Uint64(sum[8:])
+
+	// Ensure that the hashes don't all map to the same bit.
+	return h1, h2 | 1
+}
+
+// bloomFilterVersion is the version of the binary encoding of a Bloom filter.
+const bloomFilterVersion = 1
+
+// MarshalBinary returns the binary representation of the filter.
+//
+// The encoding is a version byte, the number of bits and hash functions as
+// uvarints, followed by the bits as little-endian 64-bit words.
+func (f *bloomFilter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 1+2*binary.MaxVarintLen64+8*len(f.bits))
+	data = append(data, bloomFilterVersion)
+	data = binary.AppendUvarint(data, f.size)
+	data = binary.AppendUvarint(data, f.hashes)
+
+	for _, w := range f.bits {
+		data = binary.LittleEndian.AppendUint64(data, w)
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary parses a filter from its binary representation, as produced
+// by [bloomFilter.MarshalBinary].
+func (f *bloomFilter) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("filter is empty")
+	}
+
+	if data[0] != bloomFilterVersion {
+		return fmt.Errorf("unsupported filter version (%d)", data[0])
+	}
+	data = data[1:]
+
+	malformed := errors.New("filter is malformed")
+
+	size, n := binary.Uvarint(data)
+	if n <= 0 || size == 0 {
+		return malformed
+	}
+	data = data[n:]
+
+	hashes, n := binary.Uvarint(data)
+	if n <= 0 || hashes == 0 {
+		return malformed
+	}
+	data = data[n:]
+
+	words := (size + 63) / 64
+	if uint64(len(data)) != words*8 {
+		return malformed
+	}
+
+	f.size = size
+	f.hashes = hashes
+	f.bits = make([]uint64, words)
+
+	for i := range f.bits {
+		f.bits[i] = binary.LittleEndian.Uint64(data[i*8:])
+	}
+
+	return nil
+}
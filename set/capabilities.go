@@ -0,0 +1,37 @@
+package set
+
+// Capabilities describes the limits and optional features of a [Store]
+// implementation.
+//
+// The zero value makes no claims; it describes a store with no known limits
+// and no optional features.
+type Capabilities struct {
+	// MaxValueSize is the maximum size of a member's binary representation, in
+	// bytes. Zero means that no limit is known.
+	MaxValueSize int
+
+	// OrderedRange is true if [Set.Range] visits the members in ascending
+	// lexicographical order of their binary representation.
+	OrderedRange bool
+
+	// MeteredRequests is true if each request to the store's backend is
+	// billed individually. Callers should prefer fewer, larger operations.
+	MeteredRequests bool
+}
+
+// CapabilityReporter is an interface for stores that describe their
+// [Capabilities].
+type CapabilityReporter interface {
+	// Capabilities returns the limits and optional features of the store.
+	Capabilities() Capabilities
+}
+
+// CapabilitiesOf returns the [Capabilities] of s.
+//
+// If s does not implement [CapabilityReporter], it returns the zero value.
+func CapabilitiesOf[T any](s Store[T]) Capabilities {
+	if r, ok := s.(CapabilityReporter); ok {
+		return r.Capabilities()
+	}
+	return Capabilities{}
+}
@@ -0,0 +1,101 @@
+package set
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+)
+
+// runCapabilityTests runs tests that confirm that the store behaves as
+// described by its [Capabilities]. Tests for capabilities that the store does
+// not report are skipped.
+func runCapabilityTests(t *testing.T, store BinaryStore) {
+	caps := CapabilitiesOf(store)
+
+	setup := func(t *testing.T) BinarySet {
+		s, err := store.Open(t.Context(), xtesting.SequentialName("set"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() {
+			if err := s.Close(); err != nil {
+				t.Error(err)
+			}
+		})
+
+		return s
+	}
+
+	t.Run("Capabilities", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("it accepts members of the maximum size", func(t *testing.T) {
+			t.Parallel()
+
+			if caps.MaxValueSize == 0 {
+				t.Skip("the store does not report a maximum member size")
+			}
+
+			s := setup(t)
+			v := bytes.Repeat([]byte("v"), caps.MaxValueSize)
+
+			if err := s.Add(t.Context(), v); err != nil {
+				t.Fatal(err)
+			}
+
+			ok, err := s.Has(t.Context(), v)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !ok {
+				t.Fatal("expected the member to be present")
+			}
+		})
+
+		t.Run("it ranges over members in order", func(t *testing.T) {
+			t.Parallel()
+
+			if !caps.OrderedRange {
+				t.Skip("the store does not report ordered ranging")
+			}
+
+			s := setup(t)
+
+			want := [][]byte{
+				{0x00},
+				{0x00, 0x00},
+				{0x01},
+				[]byte("a"),
+				[]byte("ab"),
+				[]byte("b"),
+				{0xff},
+			}
+
+			for _, i := range []int{4, 0, 6, 2, 5, 1, 3} {
+				if err := s.Add(t.Context(), want[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var got [][]byte
+			if err := s.Range(
+				t.Context(),
+				func(_ context.Context, v []byte) (bool, error) {
+					got = append(got, v)
+					return true, nil
+				},
+			); err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.EqualFunc(got, want, bytes.Equal) {
+				t.Fatalf("unexpected member order: got %q, want %q", got, want)
+			}
+		})
+	})
+}
@@ -66,6 +66,10 @@ func (s *interceptedStore[T]) Provision(ctx context.Context) error {
 	return s.Next.Provision(ctx)
 }
 
+func (s *interceptedStore[T]) Capabilities() Capabilities {
+	return CapabilitiesOf(s.Next)
+}
+
 func (s *interceptedStore[T]) Open(ctx context.Context, name string) (Set[T], error) {
 	if fn := s.Interceptor.beforeOpen.Load(); fn != nil {
 		if err := fn(name); err != nil {
@@ -99,6 +103,10 @@ func (s *interceptedSet[T]) Has(ctx context.Context, v T) (bool, error) {
 	return s.Next.Has(ctx, v)
 }
 
+func (s *interceptedSet[T]) HasBatch(ctx context.Context, vs []T) ([]bool, error) {
+	return HasBatch(ctx, s.Next, vs)
+}
+
 func (s *interceptedSet[T]) Add(ctx context.Context, v T) error {
 	if fn := s.Interceptor.beforeAdd.Load(); fn != nil {
 		if err := fn(s.set, v); err != nil {
@@ -185,6 +193,37 @@ func (s *interceptedSet[T]) Range(ctx context.Context, fn RangeFunc[T]) error {
 	return s.Next.Range(ctx, fn)
 }
 
+func (s *interceptedSet[T]) RangeAlgebra(
+	ctx context.Context,
+	op Operation,
+	others []Set[T],
+	fn RangeFunc[T],
+) (bool, error) {
+	next, ok := unwrapAll(
+		others,
+		func(o Set[T]) (Set[T], bool) {
+			x, ok := o.(*interceptedSet[T])
+			if !ok {
+				return nil, false
+			}
+			return x.Next, true
+		},
+	)
+	if !ok {
+		return false, nil
+	}
+
+	return rangeAlgebra(ctx, s.Next, op, next, fn)
+}
+
+func (s *interceptedSet[T]) Stats(ctx context.Context) (Stats, error) {
+	return StatsOf(ctx, s.Next)
+}
+
+func (s *interceptedSet[T]) statsSource() any {
+	return s.Next
+}
+
 func (s *interceptedSet[T]) Close() error {
 	return s.Next.Close()
 }
@@ -0,0 +1,143 @@
+package set
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/dogmatiq/persistencekit/internal/linearizability"
+	"github.com/dogmatiq/persistencekit/internal/x/xtesting"
+	"pgregory.net/rapid"
+)
+
+// runLinearizabilityTests runs randomized concurrent operations against sets
+// in the given store, and checks that the resulting histories are
+// linearizable.
+func runLinearizabilityTests(t *testing.T, store BinaryStore) {
+	t.Run("linearizability", func(t *testing.T) {
+		t.Parallel()
+
+		rapid.Check(t, func(t *rapid.T) {
+			name := xtesting.SequentialName("set")
+
+			clients := rapid.IntRange(2, 4).Draw(t, "clients")
+			plans := make([][]setInput, clients)
+			for i := range plans {
+				plans[i] = rapid.SliceOfN(setInputGenerator, 1, 10).Draw(t, fmt.Sprintf("client %d", i))
+			}
+
+			// Each client uses its own handle, as it would if each were a
+			// separate process.
+			sets := make([]BinarySet, clients)
+			for i := range sets {
+				s, err := store.Open(t.Context(), name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer s.Close()
+				sets[i] = s
+			}
+
+			history := linearizability.Run(
+				plans,
+				func(client int, in setInput) setOutput {
+					return in.apply(t, sets[client])
+				},
+			)
+
+			// Operations on different values are independent, so the
+			// history for each value is checked separately.
+			byValue := map[string][]linearizability.Operation[setInput, setOutput]{}
+			for _, op := range history {
+				if op.Output.Err != nil {
+					t.Fatalf("unexpected error: %v => %s\n%s", op.Input, op.Output.Err, linearizability.Describe(history))
+				}
+				byValue[op.Input.Value] = append(byValue[op.Input.Value], op)
+			}
+
+			for v, ops := range byValue {
+				if !linearizability.Check(setModel, ops) {
+					t.Fatalf("history for value %q is not linearizable:\n%s", v, linearizability.Describe(ops))
+				}
+			}
+		})
+	})
+}
+
+// setInput describes an operation performed on a set.
+type setInput struct {
+	Op    string
+	Value string
+}
+
+func (in setInput) String() string {
+	return fmt.Sprintf("%s(%q)", in.Op, in.Value)
+}
+
+// setOutput describes the result of a [setInput] operation.
+type setOutput struct {
+	OK  bool
+	Err error
+}
+
+func (out setOutput) String() string {
+	if out.Err != nil {
+		return out.Err.Error()
+	}
+	return strconv.FormatBool(out.OK)
+}
+
+// setInputGenerator generates operations on a small number of values, such that
+// concurrent operations frequently affect the same value.
+var setInputGenerator = rapid.Custom(func(t *rapid.T) setInput {
+	return setInput{
+		Op:    rapid.SampledFrom([]string{"Has", "Add", "TryAdd", "Remove", "TryRemove"}).Draw(t, "op"),
+		Value: rapid.SampledFrom([]string{"a", "b", "c"}).Draw(t, "value"),
+	}
+})
+
+// apply performs the operation on s.
+func (in setInput) apply(t *rapid.T, s BinarySet) (out setOutput) {
+	ctx := t.Context()
+	v := []byte(in.Value)
+
+	switch in.Op {
+	case "Has":
+		out.OK, out.Err = s.Has(ctx, v)
+	case "Add":
+		out.Err = s.Add(ctx, v)
+	case "TryAdd":
+		out.OK, out.Err = s.TryAdd(ctx, v)
+	case "Remove":
+		out.Err = s.Remove(ctx, v)
+	case "TryRemove":
+		out.OK, out.Err = s.TryRemove(ctx, v)
+	default:
+		panic("unrecognized operation: " + in.Op)
+	}
+
+	return out
+}
+
+// setModel is a sequential model of the membership of a single value within a
+// set.
+var setModel = linearizability.Model[bool, setInput, setOutput]{
+	Init: func() bool { return false },
+	Step: func(present bool, in setInput, out setOutput) (bool, bool) {
+		switch in.Op {
+		case "Has":
+			return present, out.OK == present
+		case "Add":
+			return true, true
+		case "TryAdd":
+			return true, out.OK == !present
+		case "Remove":
+			return false, true
+		case "TryRemove":
+			return false, out.OK == present
+		default:
+			panic("unrecognized operation: " + in.Op)
+		}
+	},
+	Key: strconv.FormatBool,
+}
@@ -22,6 +22,10 @@ type mstore[T any] struct {
 	m marshaler.Marshaler[T]
 }
 
+func (s *mstore[T]) Capabilities() Capabilities {
+	return CapabilitiesOf(s.BinaryStore)
+}
+
 func (s *mstore[T]) Open(ctx context.Context, name string) (Set[T], error) {
 	set, err := s.BinaryStore.Open(ctx, name)
 	if err != nil {
@@ -46,6 +50,18 @@ func (s *mset[T]) Has(ctx context.Context, v T) (bool, error) {
 	return s.BinarySet.Has(ctx, data)
 }
 
+func (s *mset[T]) HasBatch(ctx context.Context, vs []T) ([]bool, error) {
+	data := make([][]byte, len(vs))
+	for i, v := range vs {
+		var err error
+		data[i], err = s.m.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return HasBatch(ctx, s.BinarySet, data)
+}
+
 func (s *mset[T]) Add(ctx context.Context, v T) error {
 	data, err := s.m.Marshal(v)
 	if err != nil {
@@ -93,3 +109,47 @@ func (s *mset[T]) Range(ctx context.Context, fn RangeFunc[T]) error {
 		},
 	)
 }
+
+func (s *mset[T]) RangeAlgebra(
+	ctx context.Context,
+	op Operation,
+	others []Set[T],
+	fn RangeFunc[T],
+) (bool, error) {
+	next, ok := unwrapAll(
+		others,
+		func(o Set[T]) (BinarySet, bool) {
+			x, ok := o.(*mset[T])
+			if !ok {
+				return nil, false
+			}
+			return x.BinarySet, true
+		},
+	)
+	if !ok {
+		return false, nil
+	}
+
+	return rangeAlgebra(
+		ctx,
+		s.BinarySet,
+		op,
+		next,
+		func(ctx context.Context, v []byte) (bool, error) {
+			value, err := s.m.Unmarshal(v)
+			if err != nil {
+				return false, err
+			}
+
+			return fn(ctx, value)
+		},
+	)
+}
+
+func (s *mset[T]) Stats(ctx context.Context) (Stats, error) {
+	return StatsOf(ctx, s.BinarySet)
+}
+
+func (s *mset[T]) statsSource() any {
+	return s.BinarySet
+}
@@ -18,6 +18,10 @@ type nameTransformStore[T any] struct {
 	transform func(string) string
 }
 
+func (s *nameTransformStore[T]) Capabilities() Capabilities {
+	return CapabilitiesOf(s.Store)
+}
+
 func (s *nameTransformStore[T]) Open(ctx context.Context, name string) (Set[T], error) {
 	ks, err := s.Store.Open(ctx, s.transform(name))
 	if err != nil {
@@ -35,3 +39,38 @@ type nameTransformSet[T any] struct {
 func (s *nameTransformSet[T]) Name() string {
 	return s.name
 }
+
+func (s *nameTransformSet[T]) HasBatch(ctx context.Context, vs []T) ([]bool, error) {
+	return HasBatch(ctx, s.Set, vs)
+}
+
+func (s *nameTransformSet[T]) Stats(ctx context.Context) (Stats, error) {
+	return StatsOf(ctx, s.Set)
+}
+
+func (s *nameTransformSet[T]) statsSource() any {
+	return s.Set
+}
+
+func (s *nameTransformSet[T]) RangeAlgebra(
+	ctx context.Context,
+	op Operation,
+	others []Set[T],
+	fn RangeFunc[T],
+) (bool, error) {
+	next, ok := unwrapAll(
+		others,
+		func(o Set[T]) (Set[T], bool) {
+			x, ok := o.(*nameTransformSet[T])
+			if !ok {
+				return nil, false
+			}
+			return x.Set, true
+		},
+	)
+	if !ok {
+		return false, nil
+	}
+
+	return rangeAlgebra(ctx, s.Set, op, next, fn)
+}
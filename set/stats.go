@@ -0,0 +1,72 @@
+package set
+
+import "context"
+
+// Stats contains statistics about the members of a [Set].
+type Stats struct {
+	// Members is the number of members in the set.
+	Members uint64
+
+	// Bytes is the total size of the binary representation of the members of
+	// the set.
+	Bytes uint64
+}
+
+// StatsReporter is an interface for sets that can report their [Stats] more
+// efficiently than by ranging over every member.
+type StatsReporter interface {
+	// Stats returns statistics about the members of the set.
+	Stats(ctx context.Context) (Stats, error)
+}
+
+// StatsOf returns statistics about the members of s.
+//
+// If s implements [StatsReporter] its statistics are used, otherwise they are
+// computed by ranging over every member. When ranging, the size of the members
+// is only known if s is a [BinarySet]; otherwise [Stats.Bytes] is zero.
+//
+// The statistics are a snapshot, and may be outdated by the time they are
+// returned.
+func StatsOf[T any](ctx context.Context, s Set[T]) (Stats, error) {
+	if r, ok := s.(StatsReporter); ok {
+		return r.Stats(ctx)
+	}
+
+	size := func(T) int { return 0 }
+	if _, ok := any(s).(BinarySet); ok {
+		size = func(v T) int { return len(any(v).([]byte)) }
+	}
+
+	var stats Stats
+
+	err := s.Range(
+		ctx,
+		func(_ context.Context, v T) (bool, error) {
+			stats.Members++
+			stats.Bytes += uint64(size(v))
+			return true, nil
+		},
+	)
+
+	return stats, err
+}
+
+// statsForwarder is an interface for the decorators in this package, which
+// implement [StatsReporter] by calling [StatsOf] on the set they decorate.
+type statsForwarder interface {
+	// statsSource returns the set whose statistics are reported.
+	statsSource() any
+}
+
+// hasNativeStats returns true if s implements [StatsReporter], and does not
+// merely forward to a set that must be ranged over to compute its statistics.
+func hasNativeStats(s any) bool {
+	for {
+		f, ok := s.(statsForwarder)
+		if !ok {
+			_, ok := s.(StatsReporter)
+			return ok
+		}
+		s = f.statsSource()
+	}
+}
@@ -13,3 +13,13 @@ type Store[T any] interface {
 	// already exist.
 	Provision(ctx context.Context) error
 }
+
+// NameLister is an interface for stores that record the names of their
+// sets.
+//
+// A name is recorded when the set is first opened, regardless of whether
+// it contains any data.
+type NameLister interface {
+	// Names returns the names of the sets in the store, in lexical order.
+	Names(ctx context.Context) ([]string, error)
+}
@@ -37,6 +37,10 @@ func (s *instrumentedStore) Provision(ctx context.Context) error {
 	return s.Next.Provision(ctx)
 }
 
+func (s *instrumentedStore) Capabilities() Capabilities {
+	return CapabilitiesOf(s.Next)
+}
+
 // Open returns the set with the given name.
 func (s *instrumentedStore) Open(ctx context.Context, name string) (BinarySet, error) {
 	telem := s.Telemetry.Recorder(
@@ -116,6 +120,48 @@ func (s *instrumentedSet) Has(ctx context.Context, v []byte) (bool, error) {
 	return ok, nil
 }
 
+func (s *instrumentedSet) HasBatch(ctx context.Context, vs [][]byte) ([]bool, error) {
+	var size int64
+	for _, v := range vs {
+		size += int64(len(v))
+	}
+
+	ctx, span := s.Telemetry.StartSpan(
+		ctx,
+		"set.has-batch",
+		telemetry.Int("values", len(vs)),
+		telemetry.Int("values_size", size),
+	)
+	defer span.End()
+
+	for _, v := range vs {
+		n := int64(len(v))
+		s.ValueIO(ctx, n, telemetry.WriteDirection)
+		s.ValueSize(ctx, n, telemetry.WriteDirection)
+	}
+
+	result, err := HasBatch(ctx, s.Next, vs)
+	if err != nil {
+		s.Telemetry.Error(ctx, "set.has-batch.error", "unable to check presence of values in set", err)
+		return nil, err
+	}
+
+	var present int
+	for _, ok := range result {
+		if ok {
+			present++
+		}
+	}
+
+	span.SetAttributes(
+		telemetry.Int("values_present", present),
+	)
+
+	s.Telemetry.Info(ctx, "set.has-batch.ok", "checked presence of values in set")
+
+	return result, nil
+}
+
 func (s *instrumentedSet) Add(ctx context.Context, v []byte) error {
 	size := int64(len(v))
 
@@ -282,6 +328,93 @@ func (s *instrumentedSet) Range(ctx context.Context, fn RangeFunc[[]byte]) error
 	return nil
 }
 
+func (s *instrumentedSet) RangeAlgebra(
+	ctx context.Context,
+	op Operation,
+	others []BinarySet,
+	fn BinaryRangeFunc,
+) (bool, error) {
+	next, ok := unwrapAll(
+		others,
+		func(o BinarySet) (BinarySet, bool) {
+			x, ok := o.(*instrumentedSet)
+			if !ok {
+				return nil, false
+			}
+			return x.Next, true
+		},
+	)
+	if !ok {
+		return false, nil
+	}
+
+	ctx, span := s.Telemetry.StartSpan(
+		ctx,
+		"set.range-algebra",
+		telemetry.String("operation", op.String()),
+		telemetry.Int("operands", len(others)+1),
+	)
+	defer span.End()
+
+	var count uint64
+
+	handled, err := rangeAlgebra(
+		ctx,
+		s.Next,
+		op,
+		next,
+		func(ctx context.Context, v []byte) (bool, error) {
+			count++
+
+			size := int64(len(v))
+			s.ValueIO(ctx, size, telemetry.ReadDirection)
+			s.ValueSize(ctx, size, telemetry.ReadDirection)
+
+			return fn(ctx, v)
+		},
+	)
+
+	span.SetAttributes(
+		telemetry.Bool("handled", handled),
+		telemetry.Int("values_read", count),
+	)
+
+	if err != nil {
+		s.Telemetry.Error(ctx, "set.range-algebra.error", "unable to evaluate set-algebra operation", err)
+		return handled, err
+	}
+
+	if handled {
+		s.Telemetry.Info(ctx, "set.range-algebra.ok", "evaluated set-algebra operation natively")
+	}
+
+	return handled, nil
+}
+
+func (s *instrumentedSet) Stats(ctx context.Context) (Stats, error) {
+	ctx, span := s.Telemetry.StartSpan(ctx, "set.stats")
+	defer span.End()
+
+	stats, err := StatsOf(ctx, s.Next)
+	if err != nil {
+		s.Telemetry.Error(ctx, "set.stats.error", "unable to fetch set statistics", err)
+		return Stats{}, err
+	}
+
+	span.SetAttributes(
+		telemetry.Int("members", stats.Members),
+		telemetry.Int("bytes", stats.Bytes),
+	)
+
+	s.Telemetry.Info(ctx, "set.stats.ok", "fetched set statistics")
+
+	return stats, nil
+}
+
+func (s *instrumentedSet) statsSource() any {
+	return s.Next
+}
+
 func (s *instrumentedSet) Close() error {
 	if s.Next == nil {
 		// If the resource has already been closed don't do anything at all,
@@ -3,6 +3,7 @@ package set
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"slices"
 	"testing"
 
@@ -325,6 +326,295 @@ func RunTests(
 			})
 		})
 
+		t.Run("HasBatch", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it returns the membership of each value", func(t *testing.T) {
+				t.Parallel()
+
+				set := setup(t)
+
+				for _, v := range []string{"<value-1>", "<value-2>"} {
+					if err := set.Add(t.Context(), []byte(v)); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				actual, err := HasBatch(
+					t.Context(),
+					set,
+					[][]byte{
+						[]byte("<value-1>"),
+						[]byte("<value-3>"),
+						[]byte("<value-2>"),
+						[]byte("<value-1>"),
+					},
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				expect := []bool{true, false, true, true}
+
+				if !slices.Equal(actual, expect) {
+					t.Fatalf("unexpected membership: got %v, want %v", actual, expect)
+				}
+			})
+		})
+
+		t.Run("StatsOf", func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("it returns zero statistics for an empty set", func(t *testing.T) {
+				t.Parallel()
+
+				set := setup(t)
+
+				stats, err := StatsOf(t.Context(), set)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if stats != (Stats{}) {
+					t.Fatalf("unexpected statistics: got %+v, want zero", stats)
+				}
+			})
+
+			t.Run("it returns the number of members and their size", func(t *testing.T) {
+				t.Parallel()
+
+				set := setup(t)
+
+				for _, v := range []string{"<value-1>", "<value-2>", "<value-3>"} {
+					if err := set.Add(t.Context(), []byte(v)); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				// Adding an existing member or removing a non-member must not
+				// affect the statistics.
+				if err := set.Add(t.Context(), []byte("<value-1>")); err != nil {
+					t.Fatal(err)
+				}
+
+				for range 2 {
+					if err := set.Remove(t.Context(), []byte("<value-3>")); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				stats, err := StatsOf(t.Context(), set)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				expect := Stats{
+					Members: 2,
+					Bytes:   2 * uint64(len("<value-1>")),
+				}
+
+				if stats != expect {
+					t.Fatalf("unexpected statistics: got %+v, want %+v", stats, expect)
+				}
+			})
+		})
+
+		t.Run("algebra", func(t *testing.T) {
+			t.Parallel()
+
+			// setupOperands returns three sets:
+			//
+			//   - a contains 1, 2, 3 and 4
+			//   - b contains 3, 4 and 5
+			//   - c contains 4, 5 and 6
+			setupOperands := func(t *testing.T) (a, b, c BinarySet) {
+				a, b, c = setup(t), setup(t), setup(t)
+
+				for s, members := range map[BinarySet][]string{
+					a: {"1", "2", "3", "4"},
+					b: {"3", "4", "5"},
+					c: {"4", "5", "6"},
+				} {
+					for _, v := range members {
+						if err := s.Add(t.Context(), []byte(v)); err != nil {
+							t.Fatal(err)
+						}
+					}
+				}
+
+				return a, b, c
+			}
+
+			collect := func(
+				t *testing.T,
+				op func(context.Context, BinaryRangeFunc) error,
+			) []string {
+				t.Helper()
+
+				var values []string
+
+				if err := op(
+					t.Context(),
+					func(_ context.Context, v []byte) (bool, error) {
+						values = append(values, string(v))
+						return true, nil
+					},
+				); err != nil {
+					t.Fatal(err)
+				}
+
+				slices.Sort(values)
+				return values
+			}
+
+			cases := []struct {
+				Desc   string
+				Expect []string
+				Op     func(ctx context.Context, a, b, c BinarySet, fn BinaryRangeFunc) error
+			}{
+				{
+					"union",
+					[]string{"1", "2", "3", "4", "5", "6"},
+					func(ctx context.Context, a, b, c BinarySet, fn BinaryRangeFunc) error {
+						return Union(ctx, []BinarySet{a, b, c}, fn)
+					},
+				},
+				{
+					"intersection",
+					[]string{"4"},
+					func(ctx context.Context, a, b, c BinarySet, fn BinaryRangeFunc) error {
+						return Intersection(ctx, []BinarySet{a, b, c}, fn)
+					},
+				},
+				{
+					"intersection with a repeated operand",
+					[]string{"3", "4"},
+					func(ctx context.Context, a, b, _ BinarySet, fn BinaryRangeFunc) error {
+						return Intersection(ctx, []BinarySet{a, b, a}, fn)
+					},
+				},
+				{
+					"difference",
+					[]string{"1", "2"},
+					func(ctx context.Context, a, b, c BinarySet, fn BinaryRangeFunc) error {
+						return Difference(ctx, a, []BinarySet{b, c}, fn)
+					},
+				},
+				{
+					"difference with itself",
+					nil,
+					func(ctx context.Context, a, b, _ BinarySet, fn BinaryRangeFunc) error {
+						return Difference(ctx, a, []BinarySet{b, a}, fn)
+					},
+				},
+				{
+					"difference with no other sets",
+					[]string{"1", "2", "3", "4"},
+					func(ctx context.Context, a, _, _ BinarySet, fn BinaryRangeFunc) error {
+						return Difference(ctx, a, nil, fn)
+					},
+				},
+			}
+
+			for _, tc := range cases {
+				t.Run(tc.Desc, func(t *testing.T) {
+					t.Parallel()
+
+					a, b, c := setupOperands(t)
+
+					actual := collect(
+						t,
+						func(ctx context.Context, fn BinaryRangeFunc) error {
+							return tc.Op(ctx, a, b, c, fn)
+						},
+					)
+
+					if !slices.Equal(actual, tc.Expect) {
+						t.Fatalf("unexpected members: got %q, want %q", actual, tc.Expect)
+					}
+				})
+			}
+
+			t.Run("it evaluates operations on sets larger than a single batch", func(t *testing.T) {
+				t.Parallel()
+
+				a, b := setup(t), setup(t)
+
+				// a contains 0..249 and b contains 125..374, so the
+				// intersection is 125..249.
+				var expect []string
+				for i := range 375 {
+					v := fmt.Sprintf("%03d", i)
+
+					if i < 250 {
+						if err := a.Add(t.Context(), []byte(v)); err != nil {
+							t.Fatal(err)
+						}
+					}
+
+					if i >= 125 {
+						if err := b.Add(t.Context(), []byte(v)); err != nil {
+							t.Fatal(err)
+						}
+					}
+
+					if i >= 125 && i < 250 {
+						expect = append(expect, v)
+					}
+				}
+
+				actual := collect(
+					t,
+					func(ctx context.Context, fn BinaryRangeFunc) error {
+						return Intersection(ctx, []BinarySet{a, b}, fn)
+					},
+				)
+
+				if !slices.Equal(actual, expect) {
+					t.Fatalf("unexpected members: got %q, want %q", actual, expect)
+				}
+			})
+
+			t.Run("it can materialize the result into another set", func(t *testing.T) {
+				t.Parallel()
+
+				a, b, _ := setupOperands(t)
+				dst := setup(t)
+
+				if err := Difference(t.Context(), a, []BinarySet{b}, AddTo(dst)); err != nil {
+					t.Fatal(err)
+				}
+
+				actual := collect(t, dst.Range)
+				expect := []string{"1", "2"}
+
+				if !slices.Equal(actual, expect) {
+					t.Fatalf("unexpected members: got %q, want %q", actual, expect)
+				}
+			})
+
+			t.Run("it stops iterating if the function returns false", func(t *testing.T) {
+				t.Parallel()
+
+				a, b, c := setupOperands(t)
+
+				called := false
+				if err := Union(
+					t.Context(),
+					[]BinarySet{a, b, c},
+					func(context.Context, []byte) (bool, error) {
+						if called {
+							t.Fatal("unexpected call")
+						}
+						called = true
+						return false, nil
+					},
+				); err != nil {
+					t.Fatal(err)
+				}
+			})
+		})
+
 		t.Run("property-based", func(t *testing.T) {
 			t.Parallel()
 
@@ -505,4 +795,7 @@ func RunTests(
 			})
 		})
 	})
+
+	runCapabilityTests(t, store)
+	runLinearizabilityTests(t, store)
 }